@@ -1,18 +1,31 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
 	"runtime"
+	"strconv"
+	"syscall"
+	"time"
 
 	"disk-health-exporter/internal/collector"
 	"disk-health-exporter/internal/config"
+	"disk-health-exporter/internal/logging"
 	"disk-health-exporter/internal/metrics"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/exporter-toolkit/web"
 )
 
+// shutdownTimeout bounds how long in-flight scrapes may take during shutdown
+const shutdownTimeout = 10 * time.Second
+
 var (
 	version   = "dev"
 	commit    = "unknown"
@@ -26,7 +39,13 @@ func main() {
 	// Load configuration
 	cfg := config.New(vrs)
 
-	log.Println("Starting Disk Health Prometheus Exporter...")
+	// Set up logging before anything logs
+	if err := logging.Setup(logging.Options{Level: cfg.LogLevel, Format: cfg.LogFormat, RepeatInterval: cfg.LogRepeat}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring logging: %v\n", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Starting Disk Health Prometheus Exporter", "version", version, "commit", commit)
 
 	// Initialize metrics
 	m := metrics.New()
@@ -38,15 +57,58 @@ func main() {
 	go c.Start()
 
 	// Set up HTTP handlers
-	setupHTTPHandlers(cfg)
+	setupHTTPHandlers(cfg, c)
+
+	// Validate web config early so misconfiguration fails fast
+	if cfg.WebConfigFile != "" {
+		if err := web.Validate(cfg.WebConfigFile); err != nil {
+			slog.Error("Invalid web config file", "file", cfg.WebConfigFile, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	// Start HTTP server (TLS and basic auth are handled by the web config file)
+	server := &http.Server{}
+	flags := &web.FlagConfig{
+		WebListenAddresses: &[]string{cfg.ListenAddress},
+		WebSystemdSocket:   new(bool),
+		WebConfigFile:      &cfg.WebConfigFile,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting HTTP server", "address", cfg.ListenAddress)
+		serverErr <- web.ListenAndServe(server, flags, slog.Default())
+	}()
+
+	// Wait for a termination signal or a server failure
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP server error", "err", err)
+			c.Stop()
+			os.Exit(1)
+		}
+	case <-ctx.Done():
+		slog.Info("Received shutdown signal, stopping")
+	}
+
+	c.Stop()
 
-	// Start HTTP server
-	log.Printf("Starting HTTP server on port %s", cfg.Port)
-	log.Fatal(http.ListenAndServe(":"+cfg.Port, nil))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error during HTTP server shutdown", "err", err)
+	}
+
+	slog.Info("Disk Health Exporter stopped")
 }
 
 // setupHTTPHandlers configures HTTP routes
-func setupHTTPHandlers(cfg *config.Config) {
+func setupHTTPHandlers(cfg *config.Config, c *collector.Collector) {
 	// Metrics endpoint
 	http.Handle(cfg.MetricsPath, promhttp.Handler())
 
@@ -59,6 +121,8 @@ func setupHTTPHandlers(cfg *config.Config) {
 		<body>
 		<h1>Disk Health Prometheus Exporter</h1>
 		<p><a href="%s">Metrics</a></p>
+		<p><a href="/api/v1/disks">Disks (JSON)</a></p>
+		<p><a href="/api/v1/events">Events (JSON)</a></p>
 		<p>Version: %s (#%s)</p>
 		<p>Collect Interval: %s</p>
 		</body>
@@ -71,4 +135,39 @@ func setupHTTPHandlers(cfg *config.Config) {
 		w.Header().Set("Content-Type", "application/json")
 		fmt.Fprintf(w, `{"status":"ok","service":"disk-health-exporter"}`)
 	})
+
+	// JSON API with the latest collection results, including failure risk factors
+	http.HandleFunc("/api/v1/disks", func(w http.ResponseWriter, r *http.Request) {
+		disks, raids, updatedAt := c.Snapshot()
+		writeJSON(w, map[string]interface{}{
+			"updated_at":  updatedAt,
+			"disks":       disks,
+			"raid_arrays": raids,
+		})
+	})
+
+	// JSON API with the latest disk and RAID state change events, oldest first.
+	// Clients poll with ?since=<id of the last event seen>, optionally ?type=<event type>.
+	http.HandleFunc("/api/v1/events", func(w http.ResponseWriter, r *http.Request) {
+		var since uint64
+		if value := r.URL.Query().Get("since"); value != "" {
+			parsed, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid since parameter", http.StatusBadRequest)
+				return
+			}
+			since = parsed
+		}
+		writeJSON(w, map[string]interface{}{
+			"events": c.Events(since, r.URL.Query().Get("type")),
+		})
+	})
+}
+
+// writeJSON encodes a value as a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("Error encoding JSON response", "err", err)
+	}
 }
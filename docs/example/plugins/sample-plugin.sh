@@ -0,0 +1,65 @@
+#!/bin/sh
+# Sample disk-health-exporter plugin.
+#
+# A plugin prints one JSON document to standard output and exits 0. Fields use
+# the names of the exporter's DiskInfo, RAIDInfo and RAIDBatteryInfo types, the
+# same names served by /api/v1/disks. See docs/plugins.md for the full format.
+#
+# Replace the static document below with calls to your appliance's API or CLI.
+# Anything written to standard error is logged by the exporter when the plugin
+# exits with a non-zero status.
+
+set -eu
+
+cat <<'JSON'
+{
+  "version": 1,
+  "disks": [
+    {
+      "Device": "appliance-shelf1-bay1",
+      "Model": "ST8000NM017B",
+      "Serial": "ZA1BC2DE",
+      "Interface": "SAS",
+      "Capacity": 8001563222016,
+      "Health": "OK",
+      "Temperature": 34,
+      "PowerOnHours": 21874,
+      "Location": "Shelf:1 Bay:1",
+      "RaidRole": "active",
+      "RaidArrayID": "appliance:vol0"
+    },
+    {
+      "Device": "appliance-shelf1-bay2",
+      "Model": "ST8000NM017B",
+      "Serial": "ZA1BC2DF",
+      "Interface": "SAS",
+      "Capacity": 8001563222016,
+      "Health": "Predictive Failure",
+      "Temperature": 36,
+      "PowerOnHours": 21870,
+      "ReallocatedSectors": 48,
+      "Location": "Shelf:1 Bay:2",
+      "RaidRole": "active",
+      "RaidArrayID": "appliance:vol0"
+    }
+  ],
+  "raid_arrays": [
+    {
+      "ArrayID": "appliance:vol0",
+      "RaidLevel": "RAID 1",
+      "State": "Optimal",
+      "Size": 8001563222016,
+      "NumDrives": 2,
+      "NumActiveDrives": 2,
+      "Type": "hardware",
+      "Controller": "Appliance - Storage Node 1",
+      "Battery": {
+        "AdapterID": 0,
+        "BatteryType": "Supercap",
+        "State": "Optimal",
+        "Temperature": 29
+      }
+    }
+  ]
+}
+JSON
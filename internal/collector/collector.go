@@ -1,14 +1,33 @@
 package collector
 
 import (
-	"log"
+	"errors"
+	"io/fs"
+	"log/slog"
 	"runtime"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"disk-health-exporter/internal/config"
 	"disk-health-exporter/internal/disk"
+	"disk-health-exporter/internal/disk/tools"
+	"disk-health-exporter/internal/drivedb"
+	"disk-health-exporter/internal/endurance"
+	"disk-health-exporter/internal/events"
+	"disk-health-exporter/internal/maintenance"
 	"disk-health-exporter/internal/metrics"
+	"disk-health-exporter/internal/notify"
+	"disk-health-exporter/internal/plugin"
+	"disk-health-exporter/internal/risk"
+	"disk-health-exporter/internal/rules"
+	"disk-health-exporter/internal/selftest"
+	"disk-health-exporter/internal/state"
+	"disk-health-exporter/internal/statemap"
+	"disk-health-exporter/internal/thermal"
 	"disk-health-exporter/internal/utils"
+	"disk-health-exporter/internal/zfsevents"
 	"disk-health-exporter/pkg/types"
 )
 
@@ -17,24 +36,269 @@ type Collector struct {
 	metrics     *metrics.Metrics
 	diskManager *disk.Manager
 	interval    time.Duration
+	riskModel   *risk.Model
+	rules       *rules.Engine
+	endurance   *endurance.Model
+	thermal     *thermal.History
+	state       *state.Store
+	maintenance *maintenance.Tracker
+	selfTests   *selftest.Scheduler
+	windows     []counterWindow
+	zfs         zfsSettings
+	plugins     []*plugin.Plugin
+	events      *events.Stream
+	notifier    *notify.Notifier
+	stop        chan struct{}
+	stopOnce    sync.Once
+
+	// Latest collection results, served by the JSON API
+	mu        sync.RWMutex
+	disks     []types.DiskInfo
+	raids     []types.RAIDInfo
+	updatedAt time.Time
 }
 
+// counterWindow is a counter increase window exported as a metric
+type counterWindow struct {
+	counter string
+	window  time.Duration
+	label   string // Window as configured (e.g. "7d")
+}
+
+// zfsSettings controls ZFS dataset and ARC statistics collection
+type zfsSettings struct {
+	kstatRoot        string
+	datasets         *tools.DatasetFilter
+	datasetsDisabled bool
+	events           *zfsevents.Tracker // nil unless zpool events polling is enabled
+}
+
+// defaultWindows are exported for every error counter when none are configured
+var defaultWindows = []string{"24h", "7d"}
+
 // New creates a new collector
 func New(m *metrics.Metrics, interval time.Duration) *Collector {
-	return &Collector{
+	c := &Collector{
 		metrics:     m,
 		diskManager: disk.New(),
 		interval:    interval,
+		riskModel:   newRiskModel(config.RiskConfig{}),
+		rules:       newRuleEngine(config.RulesConfig{}),
+		endurance:   newEnduranceModel(config.EnduranceConfig{}),
+		thermal:     newThermalHistory(config.TemperatureConfig{}),
+		windows:     newCounterWindows(config.StateConfig{}),
+		events:      events.NewStreamWithSinks(events.DefaultBufferSize),
+		notifier:    notify.NewWithReceivers(notify.DefaultResendInterval),
+		stop:        make(chan struct{}),
 	}
+	c.state = newStateStore("", c.retention())
+	c.maintenance = maintenance.New(c.state)
+	c.selfTests = newSelfTestScheduler(config.SelfTestsConfig{}, c.state)
+	return c
 }
 
 // NewWithConfig creates a new collector with configuration
 func NewWithConfig(m *metrics.Metrics, interval time.Duration, cfg *config.Config) *Collector {
-	return &Collector{
+	if db, err := drivedb.New(cfg.DriveDB); err != nil {
+		slog.Warn("Invalid drive database configuration, using built-in database", "err", err)
+	} else {
+		drivedb.SetDefault(db)
+	}
+
+	c := &Collector{
 		metrics:     m,
 		diskManager: disk.NewWithConfig(cfg.TargetDisks, cfg.IgnorePatterns),
 		interval:    interval,
+		riskModel:   newRiskModel(cfg.Risk),
+		rules:       newRuleEngine(cfg.Rules),
+		endurance:   newEnduranceModel(cfg.Endurance),
+		thermal:     newThermalHistory(cfg.Temperature),
+		windows:     newCounterWindows(cfg.State),
+		zfs:         newZFSSettings(cfg.ZFS),
+		events:      newEventStream(cfg.Events),
+		notifier:    newNotifier(cfg.Notifications),
+		stop:        make(chan struct{}),
+	}
+	c.state = newStateStore(cfg.StateFile, c.retention())
+	c.maintenance = maintenance.New(c.state)
+	c.selfTests = newSelfTestScheduler(cfg.SelfTests, c.state)
+	c.addPlugins(cfg.Plugins)
+	return c
+}
+
+// addPlugins adds the configured plugins to the disk detection pipeline, skipping invalid ones
+func (c *Collector) addPlugins(configs []config.PluginConfig) {
+	for _, pc := range configs {
+		p, err := plugin.New(pc)
+		if err != nil {
+			slog.Warn("Ignoring plugin", "err", err)
+			continue
+		}
+		if !p.IsAvailable() {
+			slog.Warn("Plugin command not found, it will fail until installed", "tool", p.GetName(), "command", pc.Command)
+		}
+		c.diskManager.AddTool(p.Source(), p)
+		c.plugins = append(c.plugins, p)
+	}
+}
+
+// newEventStream creates the event stream, leaving out invalid sinks
+func newEventStream(cfg config.EventsConfig) *events.Stream {
+	stream, err := events.NewStream(cfg)
+	if err != nil {
+		slog.Warn("Invalid event configuration, leaving out invalid sinks", "err", err)
+	}
+	return stream
+}
+
+// newNotifier creates the webhook notifier, leaving out invalid receivers
+func newNotifier(cfg config.NotificationsConfig) *notify.Notifier {
+	notifier, err := notify.New(cfg)
+	if err != nil {
+		slog.Warn("Invalid notification configuration, leaving out invalid receivers", "err", err)
+	}
+	return notifier
+}
+
+// newCounterWindows builds the counter increase windows, falling back to defaults on invalid configuration
+func newCounterWindows(cfg config.StateConfig) []counterWindow {
+	var windows []counterWindow
+	for _, w := range cfg.Windows {
+		if !slices.Contains(state.Counters, w.Counter) {
+			slog.Warn("Ignoring increase window for unknown counter", "counter", w.Counter)
+			continue
+		}
+		duration, err := config.ParseDuration(w.Window)
+		if err != nil || duration <= 0 {
+			slog.Warn("Ignoring invalid increase window", "counter", w.Counter, "window", w.Window)
+			continue
+		}
+		windows = append(windows, counterWindow{counter: w.Counter, window: duration, label: w.Window})
+	}
+
+	if len(cfg.Windows) > 0 {
+		return windows
+	}
+
+	for _, counter := range state.ErrorCounters {
+		for _, label := range defaultWindows {
+			duration, _ := config.ParseDuration(label)
+			windows = append(windows, counterWindow{counter: counter, window: duration, label: label})
+		}
+	}
+	return windows
+}
+
+// newZFSSettings builds the ZFS collection settings, exporting every dataset if the filter is invalid
+func newZFSSettings(cfg config.ZFSConfig) zfsSettings {
+	filter, err := tools.NewDatasetFilter(cfg.Datasets.Pools, cfg.Datasets.Include, cfg.Datasets.Exclude)
+	if err != nil {
+		slog.Warn("Invalid ZFS dataset filter, exporting all datasets", "err", err)
+	}
+	settings := zfsSettings{
+		kstatRoot:        cfg.KstatRoot,
+		datasets:         filter,
+		datasetsDisabled: cfg.Datasets.Disabled,
+	}
+	if cfg.Events.Enabled {
+		settings.events = zfsevents.New()
+	}
+	return settings
+}
+
+// newStateStore opens the counter state store, continuing in memory if the file cannot be loaded
+func newStateStore(path string, retention time.Duration) *state.Store {
+	store, err := state.Open(path, retention)
+	if err != nil {
+		slog.Warn("Error loading counter state, starting with empty history", "file", path, "err", err)
+	}
+	return store
+}
+
+// retention returns how much counter history is needed for the longest window
+func (c *Collector) retention() time.Duration {
+	retention := max(c.riskModel.GrowthWindow(), c.endurance.RateWindow())
+	for _, w := range c.windows {
+		retention = max(retention, w.window)
 	}
+	return retention
+}
+
+// newRiskModel creates the failure risk model, falling back to defaults on invalid configuration
+func newRiskModel(cfg config.RiskConfig) *risk.Model {
+	model, err := risk.New(cfg)
+	if err != nil {
+		slog.Warn("Invalid risk configuration, using defaults", "err", err)
+		model, _ = risk.New(config.RiskConfig{})
+	}
+	return model
+}
+
+// newRuleEngine creates the threshold rule engine, falling back to defaults on invalid configuration
+func newRuleEngine(cfg config.RulesConfig) *rules.Engine {
+	engine, err := rules.New(cfg)
+	if err != nil {
+		slog.Warn("Invalid rules configuration, using defaults", "err", err)
+		engine, _ = rules.New(config.RulesConfig{Disabled: cfg.Disabled})
+	}
+	return engine
+}
+
+// newEnduranceModel creates the SSD endurance model, falling back to defaults on invalid configuration
+func newEnduranceModel(cfg config.EnduranceConfig) *endurance.Model {
+	model, err := endurance.New(cfg)
+	if err != nil {
+		slog.Warn("Invalid endurance configuration, using defaults", "err", err)
+		model, _ = endurance.New(config.EnduranceConfig{})
+	}
+	return model
+}
+
+// newThermalHistory creates the temperature history, falling back to defaults on invalid configuration
+func newThermalHistory(cfg config.TemperatureConfig) *thermal.History {
+	history, err := thermal.New(cfg)
+	if err != nil {
+		slog.Warn("Invalid temperature configuration, using defaults", "err", err)
+		history, _ = thermal.New(config.TemperatureConfig{})
+	}
+	return history
+}
+
+// newSelfTestScheduler creates the self-test scheduler, disabling it on invalid configuration
+func newSelfTestScheduler(cfg config.SelfTestsConfig, store *state.Store) *selftest.Scheduler {
+	scheduler, err := selftest.New(cfg, tools.NewSmartCtlTool(), store)
+	if err != nil {
+		slog.Warn("Invalid self-test configuration, not scheduling self-tests", "err", err)
+		scheduler, _ = selftest.New(config.SelfTestsConfig{}, nil, store)
+	}
+	return scheduler
+}
+
+// Snapshot returns the disks and RAID arrays from the latest collection
+func (c *Collector) Snapshot() ([]types.DiskInfo, []types.RAIDInfo, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.disks, c.raids, c.updatedAt
+}
+
+// storeSnapshot records the results of a collection for the JSON API and
+// passes them on to the event stream, the notifier and the self-test scheduler
+func (c *Collector) storeSnapshot(disks []types.DiskInfo, raids []types.RAIDInfo) {
+	now := time.Now()
+	c.events.Observe(disks, raids, now)
+	c.notifier.Update(disks, raids, now)
+	c.selfTests.Run(disks, raids, now)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disks = disks
+	c.raids = raids
+	c.updatedAt = now
+}
+
+// Events returns the buffered events with an ID greater than since, optionally of one type
+func (c *Collector) Events(since uint64, eventType string) []events.Event {
+	return c.events.Events(since, eventType)
 }
 
 // Start begins the metric collection loop
@@ -49,21 +313,39 @@ func (c *Collector) Start() {
 	ticker := time.NewTicker(c.interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		c.updateMetrics()
+	for {
+		select {
+		case <-ticker.C:
+			c.updateMetrics()
+		case <-c.stop:
+			c.metrics.ExporterUp.Set(0)
+			if err := c.state.Save(); err != nil {
+				slog.Error("Error saving counter state", "err", err)
+			}
+			c.events.Close()
+			c.notifier.Close()
+			return
+		}
 	}
 }
 
+// Stop ends the metric collection loop started by Start
+func (c *Collector) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
 // updateMetrics collects and updates all metrics
 func (c *Collector) updateMetrics() {
-	log.Println("Collecting disk health metrics...")
+	start := time.Now()
 
 	// Clear previous metrics
 	c.metrics.Reset()
 
 	// Detect operating system
 	osType := runtime.GOOS
-	log.Printf("Detected OS: %s", osType)
+	slog.Debug("Collecting disk health metrics", "os", osType)
 
 	switch osType {
 	case "linux":
@@ -73,6 +355,68 @@ func (c *Collector) updateMetrics() {
 	default:
 		c.collectFallbackMetrics()
 	}
+
+	c.collectPluginMetrics()
+	c.collectEventMetrics()
+	c.collectNotificationMetrics()
+	c.collectSelfTestMetrics()
+	c.collectUnmappedStateMetrics()
+
+	slog.Info("Collection finished", "duration", time.Since(start))
+}
+
+// collectPluginMetrics exports the outcome of the plugin runs of this collection
+func (c *Collector) collectPluginMetrics() {
+	for _, p := range c.plugins {
+		status := p.Status()
+		name := p.GetName()
+
+		c.metrics.PluginUp.WithLabelValues(name).Set(boolToFloat(status.Up))
+		c.metrics.PluginDurationSeconds.WithLabelValues(name).Set(status.Duration.Seconds())
+		for _, reason := range []string{plugin.ReasonExec, plugin.ReasonTimeout, plugin.ReasonDecode, plugin.ReasonValidation} {
+			c.metrics.PluginErrorsTotal.WithLabelValues(name, reason).Set(float64(status.Errors[reason]))
+		}
+		for _, kind := range []string{plugin.KindDisk, plugin.KindRAIDArray, plugin.KindBattery} {
+			c.metrics.PluginInvalidEntries.WithLabelValues(name, kind).Set(float64(status.Invalid[kind]))
+		}
+	}
+}
+
+// collectEventMetrics exports the number of events and failed deliveries since startup
+func (c *Collector) collectEventMetrics() {
+	counts := c.events.Counts()
+	for _, eventType := range events.Types {
+		c.metrics.EventsTotal.WithLabelValues(eventType).Set(float64(counts[eventType]))
+	}
+	sinkErrors := c.events.SinkErrors()
+	for _, sink := range c.events.Sinks() {
+		c.metrics.EventSinkErrorsTotal.WithLabelValues(sink).Set(float64(sinkErrors[sink]))
+	}
+}
+
+// collectNotificationMetrics exports the number of notifications since startup by receiver and result
+func (c *Collector) collectNotificationMetrics() {
+	for _, receiver := range c.notifier.Receivers() {
+		for result, count := range receiver.Results() {
+			c.metrics.NotificationsTotal.WithLabelValues(receiver.Name(), result).Set(float64(count))
+		}
+	}
+}
+
+// collectSelfTestMetrics exports the number of self-tests the scheduler tried to start since startup by type and result
+func (c *Collector) collectSelfTestMetrics() {
+	for _, testType := range c.selfTests.Types() {
+		for result, count := range c.selfTests.Results(testType) {
+			c.metrics.DiskSelfTestScheduled.WithLabelValues(testType, result).Set(float64(count))
+		}
+	}
+}
+
+// collectUnmappedStateMetrics exports the tool states missing from their state tables
+func (c *Collector) collectUnmappedStateMetrics() {
+	for _, unmapped := range statemap.Unmapped() {
+		c.metrics.UnmappedState.WithLabelValues(unmapped.Tool, unmapped.State).Set(1)
+	}
 }
 
 // updateToolMetrics updates metrics about available tools
@@ -87,6 +431,9 @@ func boolToFloat(b bool) float64 {
 // collectLinuxMetrics collects metrics on Linux systems
 func (c *Collector) collectLinuxMetrics() {
 	disks, raidArrays := c.diskManager.GetDisks()
+	c.analyzeDisks(disks)
+	c.maintenance.UpdateScrubs(raidArrays)
+	c.rules.EvaluateArrays(raidArrays, time.Now())
 
 	// Update RAID array metrics with comprehensive data
 	for _, raid := range raidArrays {
@@ -153,7 +500,7 @@ func (c *Collector) collectLinuxMetrics() {
 			).Set(float64(raid.RebuildProgress))
 		}
 
-		if raid.ScrubProgress > 0 {
+		if raid.ScrubProgress > 0 || raid.CheckRunning {
 			c.metrics.RaidArrayScrubProgress.WithLabelValues(
 				raid.ArrayID,
 				raid.RaidLevel,
@@ -161,42 +508,261 @@ func (c *Collector) collectLinuxMetrics() {
 			).Set(float64(raid.ScrubProgress))
 		}
 
+		c.updateRuleAlertMetrics(raid.RuleAlerts)
+
 		// Update battery metrics if available
 		if raid.Battery != nil {
 			utils.UpdateBatteryMetrics(raid.Battery, c.metrics)
 		}
+
+		// Update ZFS pool and vdev metrics if available
+		if raid.ZFS != nil {
+			utils.UpdateZFSMetrics(raid.ZFS, c.metrics)
+		}
 	}
 
+	c.collectRAIDControllerMetrics()
+	c.collectConsistencyCheckMetrics(raidArrays)
+	c.collectZFSMetrics()
+
 	// Update comprehensive disk metrics
 	c.updateComprehensiveDiskMetrics(disks)
+	c.storeSnapshot(disks, raidArrays)
+
+	slog.Debug("Updated metrics", "disks", len(disks), "raid_arrays", len(raidArrays))
+}
+
+// collectRAIDControllerMetrics updates controller-level metrics for hardware RAID controllers
+func (c *Collector) collectRAIDControllerMetrics() {
+	controllers := c.diskManager.GetControllers()
+	c.maintenance.UpdateControllers(controllers, time.Now())
+
+	for _, controller := range controllers {
+		utils.UpdateRAIDControllerMetrics(&controller, c.metrics)
+
+		if last, ok := c.maintenance.LastPatrolRead(controller); ok {
+			c.metrics.RaidControllerPatrolReadLastCompleted.WithLabelValues(
+				strconv.Itoa(controller.AdapterID),
+				controller.ToolName,
+			).Set(float64(last.Unix()))
+		}
+	}
+}
+
+// collectConsistencyCheckMetrics exports when consistency checks last finished on hardware RAID arrays
+func (c *Collector) collectConsistencyCheckMetrics(raidArrays []types.RAIDInfo) {
+	c.maintenance.UpdateArrays(raidArrays, time.Now())
+
+	for _, raid := range raidArrays {
+		if last, ok := c.maintenance.LastConsistencyCheck(raid); ok {
+			c.metrics.RaidArrayCheckLastCompleted.WithLabelValues(
+				raid.ArrayID,
+				raid.RaidLevel,
+				raid.Type,
+			).Set(float64(last.Unix()))
+		}
+	}
+}
 
-	log.Printf("Updated metrics for %d disks and %d RAID arrays", len(disks), len(raidArrays))
+// collectZFSMetrics updates ZFS dataset and ARC metrics
+func (c *Collector) collectZFSMetrics() {
+	if !c.zfs.datasetsDisabled {
+		datasets := tools.NewZfsTool().GetDatasets(c.zfs.datasets)
+		utils.UpdateZFSDatasetMetrics(datasets, c.metrics)
+	}
+
+	if c.zfs.events != nil {
+		if zpoolTool := tools.NewZpoolTool(); zpoolTool.IsAvailable() {
+			events, err := zpoolTool.GetEvents()
+			if err != nil {
+				slog.Error("Error getting zpool events", "tool", zpoolTool.GetName(), "err", err)
+			} else {
+				c.zfs.events.Update(events)
+			}
+		}
+		utils.UpdateZFSEventMetrics(c.zfs.events.Counters(), c.metrics)
+	}
+
+	arc, err := tools.ReadARCStats(c.zfs.kstatRoot)
+	if err != nil {
+		// The kstat directory only exists while the ZFS module is loaded
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Error("Error reading ZFS ARC statistics", "err", err)
+		}
+		return
+	}
+	utils.UpdateZFSARCMetrics(arc, c.metrics)
 }
 
 // collectMacOSMetrics collects metrics on macOS systems
 func (c *Collector) collectMacOSMetrics() {
 	disks, _ := c.diskManager.GetDisks()
+	c.analyzeDisks(disks)
 	c.updateComprehensiveDiskMetrics(disks)
+	c.storeSnapshot(disks, nil)
 
-	log.Printf("Updated metrics for %d macOS disks", len(disks))
+	slog.Debug("Updated metrics", "disks", len(disks))
 }
 
 // collectFallbackMetrics collects metrics using fallback method
 func (c *Collector) collectFallbackMetrics() {
-	log.Printf("Using fallback disk detection for OS: %s", runtime.GOOS)
+	slog.Debug("Using fallback disk detection", "os", runtime.GOOS)
 
 	// Try to get regular disks as fallback
 	disks, _ := c.diskManager.GetDisks()
+	c.analyzeDisks(disks)
 	c.updateComprehensiveDiskMetrics(disks)
+	c.storeSnapshot(disks, nil)
+
+	slog.Debug("Updated metrics", "disks", len(disks), "fallback", true)
+}
+
+// analyzeDisks records counter and temperature history and computes the failure risk, endurance, temperature profile and threshold rules of each disk in place
+func (c *Collector) analyzeDisks(disks []types.DiskInfo) {
+	now := time.Now()
+	growthWindow := c.riskModel.GrowthWindow()
+	ruleWindow := c.rules.GrowthWindow()
+	rateWindow := c.endurance.RateWindow()
+
+	for i := range disks {
+		key := state.Key(disks[i])
+		c.state.Record(key, state.CountersFromDisk(disks[i]), now)
+
+		assessment := c.riskModel.Assess(disks[i], risk.Growth{
+			ReallocatedSectors: c.state.Increase(key, state.CounterReallocatedSectors, growthWindow, now),
+			PendingSectors:     c.state.Increase(key, state.CounterPendingSectors, growthWindow, now),
+			MediaErrors:        c.state.Increase(key, state.CounterMediaErrors, growthWindow, now),
+		})
+		disks[i].FailureRisk = &assessment
+
+		writeRate, haveRate := c.state.Rate(key, state.CounterBytesWritten, rateWindow, now)
+		disks[i].Endurance = c.endurance.Estimate(disks[i], writeRate, haveRate)
+
+		if disks[i].Temperature > 0 {
+			c.thermal.Record(key, disks[i].Temperature, now)
+		}
+		disks[i].Thermal = c.thermal.Profile(key, now)
+
+		disks[i].RuleClass, disks[i].RuleAlerts = c.rules.EvaluateDisk(disks[i], rules.Growth{
+			ReallocatedSectors: c.state.Increase(key, state.CounterReallocatedSectors, ruleWindow, now),
+			PendingSectors:     c.state.Increase(key, state.CounterPendingSectors, ruleWindow, now),
+		})
+	}
+
+	c.updateCounterTrackingMetrics(disks, now)
+
+	c.thermal.Prune(now)
+	c.state.Prune(now)
+	if err := c.state.Save(); err != nil {
+		slog.Error("Error saving counter state", "err", err)
+	}
+}
+
+// updateCounterTrackingMetrics exports counter increases over the configured windows and first-seen timestamps
+func (c *Collector) updateCounterTrackingMetrics(disks []types.DiskInfo, now time.Time) {
+	for _, disk := range disks {
+		key := state.Key(disk)
+
+		if firstSeen, ok := c.state.FirstSeen(key); ok {
+			c.metrics.DiskFirstSeenTimestamp.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+			).Set(float64(firstSeen.Unix()))
+		}
+
+		for _, w := range c.windows {
+			c.metrics.DiskCounterIncrease.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+				w.counter,
+				w.label,
+			).Set(float64(c.state.Increase(key, w.counter, w.window, now)))
+		}
+	}
+}
+
+// updateThermalMetrics exports the temperature window statistics and excursion counts of a disk
+func (c *Collector) updateThermalMetrics(disk types.DiskInfo) {
+	for _, w := range disk.Thermal.Windows {
+		for stat, value := range map[string]float64{"min": w.Min, "max": w.Max, "avg": w.Avg} {
+			c.metrics.DiskTemperatureWindow.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+				w.Window,
+				stat,
+			).Set(value)
+		}
+	}
 
-	log.Printf("Updated metrics for %d disks (fallback mode)", len(disks))
+	for _, excursion := range disk.Thermal.Excursions {
+		c.metrics.DiskTemperatureExcursions.WithLabelValues(
+			disk.Device,
+			disk.Serial,
+			disk.Model,
+			strconv.FormatFloat(excursion.Threshold, 'f', -1, 64),
+		).Set(float64(excursion.Count))
+	}
+}
+
+// updateSelfTestMetrics exports the latest self-test of each type of a disk and the progress of a running test
+func (c *Collector) updateSelfTestMetrics(disk types.DiskInfo) {
+	for _, test := range disk.SelfTests.Latest {
+		c.metrics.DiskSelfTestLastResult.WithLabelValues(
+			disk.Device,
+			disk.Serial,
+			disk.Model,
+			test.Type,
+			test.Result.String(),
+		).Set(float64(test.Result))
+
+		c.metrics.DiskSelfTestLastAge.WithLabelValues(
+			disk.Device,
+			disk.Serial,
+			disk.Model,
+			test.Type,
+		).Set(float64(test.AgeHours))
+
+		if test.FirstErrorLBA >= 0 {
+			c.metrics.DiskSelfTestFirstErrorLBA.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+				test.Type,
+			).Set(float64(test.FirstErrorLBA))
+		}
+	}
+
+	if disk.SelfTests.Running {
+		c.metrics.DiskSelfTestProgress.WithLabelValues(
+			disk.Device,
+			disk.Serial,
+			disk.Model,
+			disk.SelfTests.RunningType,
+		).Set(float64(disk.SelfTests.RunningProgress))
+	}
+}
+
+// updateRuleAlertMetrics exports firing threshold rules
+func (c *Collector) updateRuleAlertMetrics(alerts []types.RuleAlert) {
+	for _, alert := range alerts {
+		c.metrics.DiskHealthAlert.WithLabelValues(
+			alert.Rule,
+			alert.Severity,
+			alert.Device,
+			alert.Serial,
+		).Set(1)
+	}
 }
 
 // updateComprehensiveDiskMetrics updates comprehensive metrics for a list of disks
 func (c *Collector) updateComprehensiveDiskMetrics(disks []types.DiskInfo) {
 	for _, disk := range disks {
-		// Convert health status to numeric value using the proper utility function
-		status := utils.GetHealthStatusValue(disk.Health)
+		// Convert health status to numeric value, raised by the threshold rules firing for the disk
+		status := utils.GetDiskHealthStatusValue(disk)
+		c.updateRuleAlertMetrics(disk.RuleAlerts)
 
 		// Basic health status metric with enhanced labels
 		c.metrics.DiskHealthStatus.WithLabelValues(
@@ -234,6 +800,23 @@ func (c *Collector) updateComprehensiveDiskMetrics(disks []types.DiskInfo) {
 			).Set(disk.DriveTemperatureMin)
 		}
 
+		for _, sensor := range disk.TemperatureSensors {
+			c.metrics.DiskTemperatureSensor.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+				strconv.Itoa(sensor.Sensor),
+			).Set(sensor.Celsius)
+		}
+
+		if disk.Thermal != nil {
+			c.updateThermalMetrics(disk)
+		}
+
+		if disk.SelfTests != nil {
+			c.updateSelfTestMetrics(disk)
+		}
+
 		// Power and lifecycle metrics
 		if disk.PowerOnHours > 0 {
 			c.metrics.DiskPowerOnHours.WithLabelValues(
@@ -459,6 +1042,80 @@ func (c *Collector) updateComprehensiveDiskMetrics(disks []types.DiskInfo) {
 			disk.Serial,
 			disk.Model,
 		).Set(boolToFloat(disk.IsGlobalSpare))
+
+		// RAID controller physical drive metrics
+		utils.UpdateRAIDDriveMetrics(&disk, c.metrics)
+
+		// Predictive failure metrics
+		if disk.FailureRisk != nil {
+			c.metrics.DiskFailureRiskScore.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+			).Set(disk.FailureRisk.Score)
+
+			c.metrics.DiskFailureRiskLevel.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+				disk.FailureRisk.Level.String(),
+			).Set(float64(disk.FailureRisk.Level))
+		}
+
+		// Tools that disagreed while merging
+		for _, conflict := range disk.SourceConflicts {
+			c.metrics.DiskHealthSourceConflict.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				conflict.Field,
+				conflict.Source,
+				conflict.OverriddenSource,
+			).Set(1)
+		}
+
+		// SSD endurance metrics
+		if disk.BytesWritten > 0 {
+			c.metrics.DiskHostWrittenBytes.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+			).Set(float64(disk.BytesWritten))
+		}
+
+		if disk.BytesRead > 0 {
+			c.metrics.DiskHostReadBytes.WithLabelValues(
+				disk.Device,
+				disk.Serial,
+				disk.Model,
+			).Set(float64(disk.BytesRead))
+		}
+
+		if disk.Endurance != nil {
+			if disk.Endurance.HasWriteRate {
+				c.metrics.DiskWriteRateBytesPerDay.WithLabelValues(
+					disk.Device,
+					disk.Serial,
+					disk.Model,
+				).Set(disk.Endurance.WriteRate)
+			}
+
+			if disk.Endurance.RatedBytes > 0 {
+				c.metrics.DiskEnduranceRatedBytes.WithLabelValues(
+					disk.Device,
+					disk.Serial,
+					disk.Model,
+				).Set(float64(disk.Endurance.RatedBytes))
+			}
+
+			if disk.Endurance.Method != "" {
+				c.metrics.DiskEnduranceRemainingDays.WithLabelValues(
+					disk.Device,
+					disk.Serial,
+					disk.Model,
+					disk.Endurance.Method,
+				).Set(disk.Endurance.RemainingDays)
+			}
+		}
 	}
 }
 
@@ -12,21 +12,44 @@ import (
 type Config struct {
 	Version         string // Version of the application, set at build time
 	Port            string
+	ListenAddress   string // Address to listen on (host:port), derived from Port when empty
+	WebConfigFile   string // Path to exporter-toolkit web config file (TLS, basic auth)
 	MetricsPath     string
 	CollectInterval time.Duration
 	LogLevel        string
-	TargetDisks     string   // Comma-separated list of specific disks to monitor (e.g., "/dev/sda,/dev/nvme0n1")
-	IgnorePatterns  []string // Internal use: patterns to ignore (loop devices, etc.)
+	LogFormat       string        // Log output format (logfmt, json)
+	LogRepeat       time.Duration // Interval suppressing repeated identical warnings and errors
+	TargetDisks     string        // Comma-separated list of specific disks to monitor (e.g., "/dev/sda,/dev/nvme0n1")
+	IgnorePatterns  []string      // Internal use: patterns to ignore (loop devices, etc.)
+	ConfigFile      string        // Path to the optional YAML configuration file
+	StateFile       string        // Path to the persistent counter state file (empty keeps state in memory)
+	Risk            RiskConfig
+	State           StateConfig
+	Endurance       EnduranceConfig
+	DriveDB         DriveDBConfig
+	ZFS             ZFSConfig
+	Plugins         []PluginConfig
+	Events          EventsConfig
+	Notifications   NotificationsConfig
+	Rules           RulesConfig
+	Temperature     TemperatureConfig
+	SelfTests       SelfTestsConfig
 }
 
 // New creates a new configuration from command-line flags
 func New(version string) *Config {
 	var (
 		port            = flag.String("port", getEnv("PORT", "9100"), "Port to listen on")
+		listenAddress   = flag.String("listen-address", getEnv("LISTEN_ADDRESS", ""), "Address to listen on (e.g. '127.0.0.1:9100'). Overrides -port when set.")
+		webConfigFile   = flag.String("web-config-file", getEnv("WEB_CONFIG_FILE", ""), "Path to web configuration file enabling TLS and/or basic authentication")
 		metricsPath     = flag.String("metrics-path", getEnv("METRICS_PATH", "/metrics"), "Path to expose metrics")
 		collectInterval = flag.Duration("collect-interval", getEnvDuration("COLLECT_INTERVAL", 30*time.Second), "Interval between disk health collections")
 		logLevel        = flag.String("log-level", getEnv("LOG_LEVEL", "info"), "Log level (debug, info, warn, error)")
+		logFormat       = flag.String("log-format", getEnv("LOG_FORMAT", "logfmt"), "Log output format (logfmt, json)")
+		logRepeat       = flag.Duration("log-repeat-interval", getEnvDuration("LOG_REPEAT_INTERVAL", time.Hour), "Interval during which repeated identical warnings and errors are suppressed (0 logs every one)")
 		targetDisks     = flag.String("target-disks", getEnv("TARGET_DISKS", ""), "Comma-separated list of specific disks to monitor (e.g., '/dev/sda,/dev/nvme0n1'). If empty, all detected disks are monitored.")
+		configFile      = flag.String("config-file", getEnv("CONFIG_FILE", ""), "Path to YAML configuration file for advanced settings (risk model, etc.)")
+		stateFile       = flag.String("state-file", getEnv("STATE_FILE", ""), "Path to file persisting per-disk counter history across restarts. If empty, history is kept in memory only.")
 		showHelp        = flag.Bool("help", false, "Show help message")
 		showVersion     = flag.Bool("version", false, "Show version information")
 	)
@@ -49,14 +72,48 @@ func New(version string) *Config {
 		"/dev/dm-",  // Device mapper (handled by underlying devices)
 	}
 
+	// Fall back to listening on all interfaces when no explicit address is given
+	address := *listenAddress
+	if address == "" {
+		address = ":" + *port
+	}
+
+	// Load advanced settings from the configuration file, if any
+	fileConfig := &FileConfig{}
+	if *configFile != "" {
+		loaded, err := LoadFile(*configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config file: %v\n", err)
+			os.Exit(1)
+		}
+		fileConfig = loaded
+	}
+
 	return &Config{
 		Version:         version,
 		Port:            *port,
+		ListenAddress:   address,
+		WebConfigFile:   *webConfigFile,
 		MetricsPath:     *metricsPath,
 		CollectInterval: *collectInterval,
 		LogLevel:        *logLevel,
+		LogFormat:       *logFormat,
+		LogRepeat:       *logRepeat,
 		TargetDisks:     *targetDisks,
 		IgnorePatterns:  ignorePatterns,
+		ConfigFile:      *configFile,
+		StateFile:       *stateFile,
+		Risk:            fileConfig.Risk,
+		State:           fileConfig.State,
+		Endurance:       fileConfig.Endurance,
+		DriveDB:         fileConfig.DriveDB,
+		ZFS:             fileConfig.ZFS,
+		Plugins:         fileConfig.Plugins,
+		Events:          fileConfig.Events,
+		Notifications:   fileConfig.Notifications,
+		Rules:           fileConfig.Rules,
+		Temperature:     fileConfig.Temperature,
+		SelfTests:       fileConfig.SelfTests,
 	}
 }
 
@@ -68,14 +125,22 @@ func PrintUsage() {
 	flag.PrintDefaults()
 	fmt.Printf("\nEnvironment Variables (used as fallback if flags not provided):\n")
 	fmt.Printf("  PORT             - Port to listen on (default: 9100)\n")
+	fmt.Printf("  LISTEN_ADDRESS   - Address to listen on, overrides PORT (default: :9100)\n")
+	fmt.Printf("  WEB_CONFIG_FILE  - Web configuration file for TLS and basic auth\n")
 	fmt.Printf("  METRICS_PATH     - Path to expose metrics (default: /metrics)\n")
 	fmt.Printf("  COLLECT_INTERVAL - Collection interval (default: 30s)\n")
 	fmt.Printf("  LOG_LEVEL        - Log level (default: info)\n")
+	fmt.Printf("  LOG_FORMAT       - Log output format, logfmt or json (default: logfmt)\n")
+	fmt.Printf("  LOG_REPEAT_INTERVAL - Suppression interval of repeated warnings and errors (default: 1h)\n")
 	fmt.Printf("  TARGET_DISKS     - Comma-separated list of disks to monitor\n")
+	fmt.Printf("  CONFIG_FILE      - YAML configuration file for advanced settings\n")
+	fmt.Printf("  STATE_FILE       - File persisting per-disk counter history\n")
 	fmt.Printf("\nExamples:\n")
 	fmt.Printf("  %s -port 8080 -collect-interval 60s\n", os.Args[0])
 	fmt.Printf("  %s -metrics-path /health -log-level debug\n", os.Args[0])
+	fmt.Printf("  %s -log-level warn -log-format json\n", os.Args[0])
 	fmt.Printf("  %s -target-disks '/dev/sda,/dev/nvme0n1'\n", os.Args[0])
+	fmt.Printf("  %s -listen-address 127.0.0.1:9100 -web-config-file /etc/disk-health-exporter/web-config.yml\n", os.Args[0])
 }
 
 // PrintVersion prints version information
@@ -12,7 +12,7 @@ func TestConfigFromFlags(t *testing.T) {
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
 	// Mock command line arguments
-	os.Args = []string{"cmd", "-port", "8080", "-metrics-path", "/test-metrics", "-collect-interval", "45s", "-log-level", "debug"}
+	os.Args = []string{"cmd", "-port", "8080", "-metrics-path", "/test-metrics", "-collect-interval", "45s", "-log-level", "debug", "-log-format", "json", "-log-repeat-interval", "10m"}
 
 	config := New("test-version")
 
@@ -31,6 +31,10 @@ func TestConfigFromFlags(t *testing.T) {
 	if config.LogLevel != "debug" {
 		t.Errorf("Expected log level debug, got %s", config.LogLevel)
 	}
+
+	if config.LogFormat != "json" || config.LogRepeat != 10*time.Minute {
+		t.Errorf("Expected json logs with a 10m repeat interval, got %s and %v", config.LogFormat, config.LogRepeat)
+	}
 }
 
 func TestConfigFromEnvironmentFallback(t *testing.T) {
@@ -116,6 +120,10 @@ func TestConfigDefaults(t *testing.T) {
 	if config.LogLevel != "info" {
 		t.Errorf("Expected default log level info, got %s", config.LogLevel)
 	}
+
+	if config.LogFormat != "logfmt" || config.LogRepeat != time.Hour {
+		t.Errorf("Expected default logfmt logs with a 1h repeat interval, got %s and %v", config.LogFormat, config.LogRepeat)
+	}
 }
 
 func TestFlagsPriorityOverEnvironment(t *testing.T) {
@@ -172,3 +180,211 @@ func TestGetEnvDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestListenAddress(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"derived from default port", []string{"cmd"}, ":9100"},
+		{"derived from port flag", []string{"cmd", "-port", "8080"}, ":8080"},
+		{"explicit address overrides port", []string{"cmd", "-port", "8080", "-listen-address", "127.0.0.1:9200"}, "127.0.0.1:9200"},
+	}
+
+	os.Unsetenv("PORT")
+	os.Unsetenv("LISTEN_ADDRESS")
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+			os.Args = tc.args
+
+			config := New("test-version")
+
+			if config.ListenAddress != tc.expected {
+				t.Errorf("Expected listen address %s, got %s", tc.expected, config.ListenAddress)
+			}
+		})
+	}
+}
+
+func TestWebConfigFile(t *testing.T) {
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	os.Setenv("WEB_CONFIG_FILE", "/etc/web-config.yml")
+	defer os.Unsetenv("WEB_CONFIG_FILE")
+
+	os.Args = []string{"cmd"}
+
+	config := New("test-version")
+
+	if config.WebConfigFile != "/etc/web-config.yml" {
+		t.Errorf("Expected web config file from env, got %s", config.WebConfigFile)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := t.TempDir() + "/config.yml"
+	content := `risk:
+  growth_window: 12h
+  levels:
+    medium: 0.3
+  factors:
+    pending_sectors:
+      weight: 0.9
+      saturation: 20
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+
+	if fc.Risk.GrowthWindow != "12h" {
+		t.Errorf("Expected growth window 12h, got %s", fc.Risk.GrowthWindow)
+	}
+	if fc.Risk.Levels.Medium != 0.3 {
+		t.Errorf("Expected medium level 0.3, got %v", fc.Risk.Levels.Medium)
+	}
+	factor := fc.Risk.Factors["pending_sectors"]
+	if factor.Weight == nil || *factor.Weight != 0.9 || factor.Saturation != 20 {
+		t.Errorf("Unexpected pending_sectors factor: %+v", factor)
+	}
+}
+
+func TestLoadFilePlugins(t *testing.T) {
+	path := t.TempDir() + "/config.yml"
+	content := `plugins:
+  - name: appliance
+    command: /usr/local/libexec/appliance-plugin
+    args: ["--host", "10.0.0.5"]
+    timeout: 30s
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+
+	if len(fc.Plugins) != 1 {
+		t.Fatalf("Expected 1 plugin, got %d", len(fc.Plugins))
+	}
+	plugin := fc.Plugins[0]
+	if plugin.Name != "appliance" || plugin.Command != "/usr/local/libexec/appliance-plugin" || plugin.Timeout != "30s" {
+		t.Errorf("Unexpected plugin: %+v", plugin)
+	}
+	if len(plugin.Args) != 2 || plugin.Args[1] != "10.0.0.5" {
+		t.Errorf("Unexpected plugin args: %v", plugin.Args)
+	}
+}
+
+func TestLoadFileEvents(t *testing.T) {
+	path := t.TempDir() + "/config.yml"
+	content := `events:
+  buffer_size: 200
+  log: true
+  file: /var/log/disk-health-exporter/events.jsonl
+  webhooks:
+    - url: https://hooks.example.com/disk-events
+      timeout: 2s
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+
+	events := fc.Events
+	if events.BufferSize != 200 || !events.Log || events.File != "/var/log/disk-health-exporter/events.jsonl" {
+		t.Errorf("Unexpected events config: %+v", events)
+	}
+	if len(events.Webhooks) != 1 || events.Webhooks[0].URL != "https://hooks.example.com/disk-events" || events.Webhooks[0].Timeout != "2s" {
+		t.Errorf("Unexpected webhooks: %+v", events.Webhooks)
+	}
+}
+
+func TestLoadFileNotifications(t *testing.T) {
+	path := t.TempDir() + "/config.yml"
+	content := `notifications:
+  resend_interval: 2h
+  webhooks:
+    - name: ops-slack
+      url: https://hooks.slack.com/services/T000/B000
+      template: slack
+      send_resolved: true
+      timeout: 10s
+      retries: 5
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+
+	if fc.Notifications.ResendInterval != "2h" || len(fc.Notifications.Webhooks) != 1 {
+		t.Fatalf("Unexpected notifications config: %+v", fc.Notifications)
+	}
+	webhook := fc.Notifications.Webhooks[0]
+	if webhook.Name != "ops-slack" || webhook.Template != "slack" || !webhook.SendResolved || webhook.Timeout != "10s" || webhook.Retries != 5 {
+		t.Errorf("Unexpected webhook: %+v", webhook)
+	}
+}
+
+func TestLoadFileRejectsUnknownKeys(t *testing.T) {
+	path := t.TempDir() + "/config.yml"
+	if err := os.WriteFile(path, []byte("risk:\n  wieghts: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadFile(path); err == nil {
+		t.Error("Expected error for unknown key, got nil")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+		name     string
+	}{
+		{"24h", 24 * time.Hour, false, "go duration"},
+		{"90m", 90 * time.Minute, false, "minutes"},
+		{"7d", 7 * 24 * time.Hour, false, "days"},
+		{"1.5d", 36 * time.Hour, false, "fractional days"},
+		{"2w", 14 * 24 * time.Hour, false, "weeks"},
+		{"xd", 0, true, "invalid days"},
+		{"soon", 0, true, "invalid value"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseDuration(tc.value)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for input '%s', got %v", tc.value, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDuration returned error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v for input '%s'", tc.expected, result, tc.value)
+			}
+		})
+	}
+}
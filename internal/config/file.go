@@ -0,0 +1,243 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FileConfig holds advanced settings loaded from the YAML configuration file
+type FileConfig struct {
+	Risk      RiskConfig      `yaml:"risk"`
+	State     StateConfig     `yaml:"state"`
+	Endurance EnduranceConfig `yaml:"endurance"`
+	DriveDB   DriveDBConfig   `yaml:"drive_database"`
+	ZFS       ZFSConfig       `yaml:"zfs"`
+	Plugins   []PluginConfig  `yaml:"plugins"`
+	Events    EventsConfig    `yaml:"events"`
+
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Rules         RulesConfig         `yaml:"rules"`
+	Temperature   TemperatureConfig   `yaml:"temperature"`
+	SelfTests     SelfTestsConfig     `yaml:"self_tests"`
+}
+
+// SelfTestsConfig configures the scheduler starting SMART self-tests. Starting
+// a test is the only action the exporter takes on disks, so it is off by default.
+type SelfTestsConfig struct {
+	Enabled   bool                     `yaml:"enabled"`   // Start self-tests on the schedules below
+	Stagger   string                   `yaml:"stagger"`   // Minimum time between two test starts across all disks (default 15m)
+	Schedules []SelfTestScheduleConfig `yaml:"schedules"` // Default: a short test every day and a long test every 4 weeks
+}
+
+// SelfTestScheduleConfig sets when a type of self-test runs on every disk
+type SelfTestScheduleConfig struct {
+	Type     string   `yaml:"type"`     // "short" or "long"
+	Interval string   `yaml:"interval"` // Time between two tests of this type on a disk (e.g. "1d", "4w")
+	Days     []string `yaml:"days"`     // Weekdays tests may start on (e.g. [sat, sun]), default every day
+	Hours    string   `yaml:"hours"`    // Local time window tests may start in (e.g. "01:00-05:00"), default any time
+}
+
+// TemperatureConfig configures the temperature history kept for each disk
+type TemperatureConfig struct {
+	Windows    []string                    `yaml:"windows"` // Windows over which min, max and average temperature are exported (default 1h and 24h)
+	Excursions TemperatureExcursionsConfig `yaml:"excursions"`
+}
+
+// TemperatureExcursionsConfig configures the counting of temperature excursions
+type TemperatureExcursionsConfig struct {
+	Thresholds []float64 `yaml:"thresholds"` // Celsius; an excursion is counted each time a disk rises to or above one (default 50 and 60)
+	Hysteresis *float64  `yaml:"hysteresis"` // Degrees a disk must cool below a threshold before another excursion counts (default 2)
+}
+
+// RulesConfig configures the threshold rules raising disk_health_alert and
+// disk_health_status. Unset thresholds keep the built-in defaults.
+type RulesConfig struct {
+	Disabled           bool              `yaml:"disabled"`            // Turn off every threshold rule
+	GrowthWindow       string            `yaml:"growth_window"`       // Window for sector growth rules (default 24h)
+	Classes            []RuleClassConfig `yaml:"classes"`             // Disk classes; hdd, ssd and nvme without a model adjust the built-in classes
+	BatteryTemperature ThresholdConfig   `yaml:"battery_temperature"` // RAID controller battery temperature in Celsius
+	RebuildDuration    ThresholdConfig   `yaml:"rebuild_duration"`    // Rebuild duration in hours
+}
+
+// RuleClassConfig holds the disk thresholds of a class of disks
+type RuleClassConfig struct {
+	Name              string          `yaml:"name"`               // Class name, exported in the JSON API
+	Model             string          `yaml:"model"`              // Regular expression matched against the disk model; the first matching class wins
+	Temperature       ThresholdConfig `yaml:"temperature"`        // Celsius
+	Wear              ThresholdConfig `yaml:"wear"`               // Percentage of rated life used
+	AvailableSpare    ThresholdConfig `yaml:"available_spare"`    // Percentage of spare capacity left, alerting below
+	ReallocatedGrowth ThresholdConfig `yaml:"reallocated_growth"` // Reallocated sectors added within the growth window
+	PendingGrowth     ThresholdConfig `yaml:"pending_growth"`     // Pending sectors added within the growth window
+}
+
+// ThresholdConfig holds the warning and critical thresholds of a rule
+type ThresholdConfig struct {
+	Warning  *float64 `yaml:"warning"`
+	Critical *float64 `yaml:"critical"`
+	Disabled bool     `yaml:"disabled"` // Turn off this rule for the class
+}
+
+// NotificationsConfig configures webhook notifications of disk health problems
+type NotificationsConfig struct {
+	ResendInterval string                      `yaml:"resend_interval"` // Send alerts still firing again after this interval (default 4h)
+	Webhooks       []NotificationWebhookConfig `yaml:"webhooks"`
+}
+
+// NotificationWebhookConfig configures a URL receiving notifications
+type NotificationWebhookConfig struct {
+	Name         string `yaml:"name"` // Receiver name in logs and metric labels (default webhook:<host>)
+	URL          string `yaml:"url"`
+	Template     string `yaml:"template"`      // Payload format: json (default), slack or alertmanager
+	SendResolved bool   `yaml:"send_resolved"` // Also notify when an alert is resolved (always on for alertmanager)
+	Timeout      string `yaml:"timeout"`       // Request timeout (default 5s)
+	Retries      int    `yaml:"retries"`       // Retries of a failed request with exponential backoff (default 3)
+}
+
+// EventsConfig configures the stream of disk and RAID state change events
+type EventsConfig struct {
+	BufferSize int                  `yaml:"buffer_size"` // Events kept in memory for /api/v1/events (default 1000)
+	Log        bool                 `yaml:"log"`         // Log every event
+	File       string               `yaml:"file"`        // Append events as JSON lines to this file
+	Webhooks   []EventWebhookConfig `yaml:"webhooks"`    // Post the events of every collection to these URLs
+}
+
+// EventWebhookConfig configures a URL receiving events
+type EventWebhookConfig struct {
+	URL     string `yaml:"url"`
+	Timeout string `yaml:"timeout"` // Request timeout (default 5s)
+}
+
+// PluginConfig configures an external executable reporting disks and RAID arrays as JSON
+type PluginConfig struct {
+	Name    string   `yaml:"name"`    // Plugin name, used in metric labels and as the source of its disks
+	Command string   `yaml:"command"` // Executable to run on every collection
+	Args    []string `yaml:"args"`    // Arguments passed to the executable
+	Timeout string   `yaml:"timeout"` // Maximum run time (default 10s)
+}
+
+// ZFSConfig configures ZFS dataset and ARC statistics collection
+type ZFSConfig struct {
+	KstatRoot string           `yaml:"kstat_root"` // Directory holding the ZFS kstat files (default /proc/spl/kstat/zfs)
+	Datasets  ZFSDatasetConfig `yaml:"datasets"`
+	Events    ZFSEventsConfig  `yaml:"events"`
+}
+
+// ZFSEventsConfig configures counting of ZFS error reports from zpool events
+type ZFSEventsConfig struct {
+	Enabled bool `yaml:"enabled"` // Poll zpool events on every collection (default off)
+}
+
+// ZFSDatasetConfig selects the datasets exported as metrics
+type ZFSDatasetConfig struct {
+	Disabled bool     `yaml:"disabled"` // Skip dataset collection entirely
+	Pools    []string `yaml:"pools"`    // Only export datasets in these pools (default all)
+	Include  []string `yaml:"include"`  // Regular expressions; when set, a dataset name must match one
+	Exclude  []string `yaml:"exclude"`  // Regular expressions; matching datasets are skipped
+}
+
+// DriveDBConfig holds SMART attribute decoding rules. Entries take precedence
+// over the built-in drive database.
+type DriveDBConfig struct {
+	Defaults []AttributeConfig `yaml:"defaults"` // Rules for every ATA drive, replacing built-in rules with the same attribute ID
+	Drives   []DriveConfig     `yaml:"drives"`   // Model-specific rules; the first matching entry wins
+}
+
+// DriveConfig holds the attribute decoding rules for drives matching a model pattern
+type DriveConfig struct {
+	Name       string            `yaml:"name"`
+	Model      string            `yaml:"model"`      // Regular expression matched against the disk model
+	Attributes []AttributeConfig `yaml:"attributes"` // Replace default rules with the same attribute ID or field
+}
+
+// AttributeConfig describes how to decode one value from a SMART attribute
+type AttributeConfig struct {
+	ID     int    `yaml:"id"`
+	Name   string `yaml:"name"`   // Only apply when smartctl reports this attribute name
+	Field  string `yaml:"field"`  // Target field (e.g. "power_on_hours", "wear"), or "ignore"
+	Source string `yaml:"source"` // "raw" (default) or "value" for the normalized value
+	Shift  uint   `yaml:"shift"`  // Right shift applied to the raw value, in bits
+	Mask   uint64 `yaml:"mask"`   // Mask applied after shifting; 0 keeps all bits
+	Unit   string `yaml:"unit"`   // Unit of the decoded value (e.g. "lba", "32mib", "minutes")
+	Life   string `yaml:"life"`   // For wear: "remaining" or "consumed" life percentage
+}
+
+// StateConfig configures counter rate-of-change tracking
+type StateConfig struct {
+	Windows []CounterWindowConfig `yaml:"windows"` // Increase windows to export; defaults to every error counter over 24h and 7d
+}
+
+// CounterWindowConfig selects a counter and the window over which its increase is exported
+type CounterWindowConfig struct {
+	Counter string `yaml:"counter"` // Counter name (e.g. "reallocated_sectors")
+	Window  string `yaml:"window"`  // Window duration (e.g. "24h", "7d")
+}
+
+// EnduranceConfig configures SSD endurance estimation
+type EnduranceConfig struct {
+	RateWindow string                 `yaml:"rate_window"` // Window for the average daily write rate (default 7d)
+	Drives     []EnduranceDriveConfig `yaml:"drives"`      // Rated endurance by model; the first match wins
+}
+
+// EnduranceDriveConfig holds the rated write endurance of a drive model
+type EnduranceDriveConfig struct {
+	Model    string  `yaml:"model"`     // Regular expression matched against the disk model
+	RatedTBW float64 `yaml:"rated_tbw"` // Rated endurance in terabytes written
+}
+
+// RiskConfig configures the predictive failure risk model.
+// Zero values fall back to the model defaults.
+type RiskConfig struct {
+	GrowthWindow string                      `yaml:"growth_window"` // Window for counter growth factors (e.g. "24h", "7d")
+	Levels       RiskLevelConfig             `yaml:"levels"`
+	Factors      map[string]RiskFactorConfig `yaml:"factors"` // Keyed by factor name (e.g. "pending_sectors")
+}
+
+// RiskLevelConfig holds the score thresholds for each risk level
+type RiskLevelConfig struct {
+	Medium   float64 `yaml:"medium"`
+	High     float64 `yaml:"high"`
+	Critical float64 `yaml:"critical"`
+}
+
+// RiskFactorConfig holds the weight and saturation point of a single risk factor
+type RiskFactorConfig struct {
+	Weight     *float64 `yaml:"weight"`     // Contribution (0-1) when the factor is saturated; 0 disables it
+	Threshold  *float64 `yaml:"threshold"`  // Raw value at or below which the factor contributes nothing
+	Saturation float64  `yaml:"saturation"` // Raw value at which the factor is fully saturated
+}
+
+// LoadFile reads and parses a YAML configuration file
+func LoadFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var fc FileConfig
+	if err := yaml.UnmarshalStrict(data, &fc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return &fc, nil
+}
+
+// ParseDuration parses a Go duration string, additionally accepting
+// day ("7d") and week ("2w") suffixes for long windows
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+	return time.ParseDuration(value)
+}
@@ -5,14 +5,17 @@ import (
 	"strings"
 
 	"disk-health-exporter/internal/disk/systems"
+	"disk-health-exporter/internal/disk/tools"
 	"disk-health-exporter/pkg/types"
 )
 
 // SystemInterface defines the interface for system-specific disk detection
 type SystemInterface interface {
 	GetDisks() ([]types.DiskInfo, []types.RAIDInfo)
+	GetControllers() []types.RAIDControllerInfo
 	GetSystemType() string
 	GetToolInfo() types.ToolInfo
+	AddTool(name string, tool tools.ToolInterface)
 }
 
 // Manager handles disk detection and monitoring
@@ -56,6 +59,11 @@ func (m *Manager) GetDisks() ([]types.DiskInfo, []types.RAIDInfo) {
 	return m.systemImpl.GetDisks()
 }
 
+// GetControllers returns the hardware RAID controllers found by the pipeline's tools
+func (m *Manager) GetControllers() []types.RAIDControllerInfo {
+	return m.systemImpl.GetControllers()
+}
+
 // GetSystemType returns the current system type
 func (m *Manager) GetSystemType() string {
 	return m.systemImpl.GetSystemType()
@@ -66,17 +74,12 @@ func (m *Manager) GetToolInfo() types.ToolInfo {
 	return m.systemImpl.GetToolInfo()
 }
 
-// createSystemImplementation creates the appropriate system implementation
+// AddTool adds a tool, such as an external plugin, to the disk detection pipeline
+func (m *Manager) AddTool(name string, tool tools.ToolInterface) {
+	m.systemImpl.AddTool(name, tool)
+}
+
+// createSystemImplementation creates the disk detection pipeline for this platform
 func createSystemImplementation(targetDisks []string, ignorePatterns []string) SystemInterface {
-	switch runtime.GOOS {
-	case "linux":
-		return systems.NewLinuxSystem(targetDisks, ignorePatterns)
-	case "darwin":
-		return systems.NewMacOSSystem(targetDisks, ignorePatterns)
-	case "windows":
-		return systems.NewWindowsSystem(targetDisks, ignorePatterns)
-	default:
-		// Default to Linux implementation for unknown systems
-		return systems.NewLinuxSystem(targetDisks, ignorePatterns)
-	}
+	return systems.NewSystem(runtime.GOOS, targetDisks, ignorePatterns)
 }
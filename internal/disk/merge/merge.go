@@ -0,0 +1,268 @@
+// Package merge combines the disk reports of several tools into one view per disk.
+// Every field remembers the tool that supplied it, and which tool wins a field
+// reported by more than one is declared once in the precedence table below.
+package merge
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"disk-health-exporter/pkg/types"
+)
+
+// Sources of disk information
+const (
+	SourceLsblk    = "lsblk"
+	SourceDiskutil = "diskutil"
+	SourceSmartctl = "smartctl"
+	SourceNvme     = "nvme"
+	SourceHdparm   = "hdparm"
+	SourceMegaCLI  = "megacli"
+	SourceStorCLI  = "storcli"
+	SourceArcconf  = "arcconf"
+	SourceSsacli   = "ssacli"
+	SourceMdadm    = "mdadm"
+	SourceZpool    = "zpool"
+)
+
+var (
+	// osSources list the block devices known to the operating system, one per platform
+	osSources = []string{SourceLsblk, SourceDiskutil}
+
+	// raidSources are the hardware RAID controller tools, which rank equally
+	raidSources = []string{SourceMegaCLI, SourceStorCLI, SourceArcconf, SourceSsacli}
+)
+
+// ranking builds a precedence list from sources and groups of sources, highest first
+func ranking(groups ...interface{}) [][]string {
+	var ranks [][]string
+	for _, group := range groups {
+		switch g := group.(type) {
+		case string:
+			ranks = append(ranks, []string{g})
+		case []string:
+			ranks = append(ranks, g)
+		}
+	}
+	return ranks
+}
+
+var (
+	// The drive's own SMART or NVMe report beats the controller's view, which beats
+	// what the operating system exposes through lsblk or diskutil
+	smartFirst = ranking(SourceSmartctl, SourceNvme, raidSources, SourceZpool, SourceHdparm, osSources)
+
+	// Only tools reading SMART data deliver a SMART verdict; diskutil passes on
+	// the coarse macOS status, and hdparm assumes a healthy drive whenever it answers
+	smartVerdict = ranking(SourceSmartctl, SourceNvme, raidSources, SourceDiskutil, SourceHdparm)
+
+	// Controllers know the slot and array of a drive, the operating system does not
+	raidFirst = ranking(raidSources, SourceZpool, SourceSmartctl, SourceNvme, osSources)
+
+	// The operating system reports the exact byte size of the block device
+	osFirst = ranking(osSources, SourceSmartctl, SourceNvme, raidSources, SourceHdparm, SourceZpool)
+)
+
+// field describes how one DiskInfo field is read, copied and ranked
+type field struct {
+	name      string
+	ranks     [][]string // Sources from highest to lowest precedence, nil if the latest report wins
+	conflicts bool       // Whether disagreeing sources are reported as conflicts
+	present   func(d *types.DiskInfo) bool
+	equal     func(a, b *types.DiskInfo) bool
+	value     func(d *types.DiskInfo) string
+	copy      func(dst, src *types.DiskInfo)
+}
+
+// valueField describes a field holding a single comparable value, present unless zero
+func valueField[T comparable](name string, ranks [][]string, get func(d *types.DiskInfo) *T) field {
+	var zero T
+	return field{
+		name:    name,
+		ranks:   ranks,
+		present: func(d *types.DiskInfo) bool { return *get(d) != zero },
+		equal:   func(a, b *types.DiskInfo) bool { return *get(a) == *get(b) },
+		value:   func(d *types.DiskInfo) string { return fmt.Sprint(*get(d)) },
+		copy:    func(dst, src *types.DiskInfo) { *get(dst) = *get(src) },
+	}
+}
+
+// fields is the precedence table. A nil ranking lets the latest report win;
+// fields not listed (the device name, computed assessments) are never merged.
+var fields = []field{
+	// Identity
+	valueField("model", smartFirst, func(d *types.DiskInfo) *string { return &d.Model }),
+	valueField("serial", smartFirst, func(d *types.DiskInfo) *string { return &d.Serial }),
+	valueField("vendor", smartFirst, func(d *types.DiskInfo) *string { return &d.Vendor }),
+	valueField("interface", smartFirst, func(d *types.DiskInfo) *string { return &d.Interface }),
+	valueField("form_factor", smartFirst, func(d *types.DiskInfo) *string { return &d.FormFactor }),
+	valueField("rpm", smartFirst, func(d *types.DiskInfo) *int { return &d.RPM }),
+	valueField("capacity", osFirst, func(d *types.DiskInfo) *int64 { return &d.Capacity }),
+	valueField("type", nil, func(d *types.DiskInfo) *string { return &d.Type }),
+
+	// Health verdicts. Tools word health differently, so only a different
+	// health status as translated by each tool ("OK" vs "FAILED") counts as a
+	// conflict. The wording travels with the status it was translated to.
+	{
+		name:      "health",
+		ranks:     smartFirst,
+		conflicts: true,
+		present: func(d *types.DiskInfo) bool {
+			return d.Health != "" && !strings.EqualFold(d.Health, "Unknown")
+		},
+		equal: func(a, b *types.DiskInfo) bool {
+			return a.HealthStatus == b.HealthStatus ||
+				a.HealthStatus == types.HealthStatusUnknown || b.HealthStatus == types.HealthStatusUnknown
+		},
+		value: func(d *types.DiskInfo) string { return d.Health },
+		copy: func(dst, src *types.DiskInfo) {
+			dst.Health = src.Health
+			dst.HealthStatus = src.HealthStatus
+		},
+	},
+	{
+		// A verdict only counts from a source that reads SMART. Listed before
+		// smart_enabled, which would otherwise make it look present on dst.
+		name:      "smart_healthy",
+		ranks:     smartVerdict,
+		conflicts: true,
+		present:   func(d *types.DiskInfo) bool { return d.SmartEnabled },
+		equal:     func(a, b *types.DiskInfo) bool { return a.SmartHealthy == b.SmartHealthy },
+		value:     func(d *types.DiskInfo) string { return fmt.Sprint(d.SmartHealthy) },
+		copy:      func(dst, src *types.DiskInfo) { dst.SmartHealthy = src.SmartHealthy },
+	},
+	valueField("smart_enabled", smartVerdict, func(d *types.DiskInfo) *bool { return &d.SmartEnabled }),
+
+	// Placement and RAID membership
+	valueField("location", raidFirst, func(d *types.DiskInfo) *string { return &d.Location }),
+	valueField("raid_role", raidFirst, func(d *types.DiskInfo) *string { return &d.RaidRole }),
+	valueField("raid_array_id", raidFirst, func(d *types.DiskInfo) *string { return &d.RaidArrayID }),
+	valueField("raid_position", raidFirst, func(d *types.DiskInfo) *string { return &d.RaidPosition }),
+	valueField("raid_drive", raidFirst, func(d *types.DiskInfo) **types.RAIDDriveInfo { return &d.RaidDrive }),
+	{
+		name:    "smart_device",
+		ranks:   raidFirst,
+		present: func(d *types.DiskInfo) bool { return d.SmartDeviceType != "" },
+		equal: func(a, b *types.DiskInfo) bool {
+			return a.SmartDevice == b.SmartDevice && a.SmartDeviceType == b.SmartDeviceType
+		},
+		value: func(d *types.DiskInfo) string { return d.SmartDevice + " " + d.SmartDeviceType },
+		copy: func(dst, src *types.DiskInfo) {
+			dst.SmartDevice = src.SmartDevice
+			dst.SmartDeviceType = src.SmartDeviceType
+		},
+	},
+
+	// A spare flag set by any source sticks
+	valueField("is_commissioned_spare", nil, func(d *types.DiskInfo) *bool { return &d.IsCommissionedSpare }),
+	valueField("is_emergency_spare", nil, func(d *types.DiskInfo) *bool { return &d.IsEmergencySpare }),
+	valueField("is_global_spare", nil, func(d *types.DiskInfo) *bool { return &d.IsGlobalSpare }),
+	valueField("is_dedicated_spare", nil, func(d *types.DiskInfo) *bool { return &d.IsDedicatedSpare }),
+
+	// Filesystem usage
+	valueField("mountpoint", nil, func(d *types.DiskInfo) *string { return &d.Mountpoint }),
+	valueField("filesystem", nil, func(d *types.DiskInfo) *string { return &d.Filesystem }),
+	valueField("used_bytes", nil, func(d *types.DiskInfo) *int64 { return &d.UsedBytes }),
+	valueField("available_bytes", nil, func(d *types.DiskInfo) *int64 { return &d.AvailableBytes }),
+	valueField("usage_percentage", nil, func(d *types.DiskInfo) *float64 { return &d.UsagePercentage }),
+
+	// SMART attributes and counters
+	valueField("temperature", smartFirst, func(d *types.DiskInfo) *float64 { return &d.Temperature }),
+	valueField("temperature_max", smartFirst, func(d *types.DiskInfo) *float64 { return &d.DriveTemperatureMax }),
+	valueField("temperature_min", smartFirst, func(d *types.DiskInfo) *float64 { return &d.DriveTemperatureMin }),
+	{
+		name:    "temperature_sensors",
+		ranks:   smartFirst,
+		present: func(d *types.DiskInfo) bool { return len(d.TemperatureSensors) > 0 },
+		equal: func(a, b *types.DiskInfo) bool {
+			return slices.Equal(a.TemperatureSensors, b.TemperatureSensors)
+		},
+		value: func(d *types.DiskInfo) string { return fmt.Sprint(d.TemperatureSensors) },
+		copy:  func(dst, src *types.DiskInfo) { dst.TemperatureSensors = src.TemperatureSensors },
+	},
+	valueField("power_on_hours", smartFirst, func(d *types.DiskInfo) *int64 { return &d.PowerOnHours }),
+	valueField("power_cycles", smartFirst, func(d *types.DiskInfo) *int64 { return &d.PowerCycles }),
+	valueField("reallocated_sectors", smartFirst, func(d *types.DiskInfo) *int64 { return &d.ReallocatedSectors }),
+	valueField("pending_sectors", smartFirst, func(d *types.DiskInfo) *int64 { return &d.PendingSectors }),
+	valueField("uncorrectable_errors", smartFirst, func(d *types.DiskInfo) *int64 { return &d.UncorrectableErrors }),
+	valueField("total_lbas_written", smartFirst, func(d *types.DiskInfo) *int64 { return &d.TotalLBAsWritten }),
+	valueField("total_lbas_read", smartFirst, func(d *types.DiskInfo) *int64 { return &d.TotalLBAsRead }),
+	valueField("bytes_written", smartFirst, func(d *types.DiskInfo) *int64 { return &d.BytesWritten }),
+	valueField("bytes_read", smartFirst, func(d *types.DiskInfo) *int64 { return &d.BytesRead }),
+	valueField("wear_leveling", smartFirst, func(d *types.DiskInfo) *int { return &d.WearLeveling }),
+	valueField("percentage_used", smartFirst, func(d *types.DiskInfo) *int { return &d.PercentageUsed }),
+	valueField("available_spare", smartFirst, func(d *types.DiskInfo) *int { return &d.AvailableSpare }),
+	valueField("critical_warning", smartFirst, func(d *types.DiskInfo) *int { return &d.CriticalWarning }),
+	valueField("media_errors", smartFirst, func(d *types.DiskInfo) *int64 { return &d.MediaErrors }),
+	valueField("error_log_entries", smartFirst, func(d *types.DiskInfo) *int64 { return &d.ErrorLogEntries }),
+	valueField("self_tests", smartFirst, func(d *types.DiskInfo) **types.SelfTestInfo { return &d.SelfTests }),
+}
+
+// rank returns the precedence of a source for a field, lower is better.
+// Sources missing from the list rank below all listed ones.
+func (f field) rank(source string) int {
+	for i, group := range f.ranks {
+		for _, s := range group {
+			if s == source {
+				return i
+			}
+		}
+	}
+	return len(f.ranks)
+}
+
+// Tag records source as the provenance of every field a disk reports
+func Tag(disk *types.DiskInfo, source string) {
+	if disk.Provenance == nil {
+		disk.Provenance = make(map[string]string)
+	}
+	for _, f := range fields {
+		if f.present(disk) {
+			disk.Provenance[f.name] = source
+		}
+	}
+}
+
+// Merge merges src into dst field by field. A field src reports replaces the
+// value of dst if dst lacks it or src's source ranks at least as high; reports of
+// the same rank are resolved in favor of src, the more recent one. Disagreeing
+// health verdicts of different sources are recorded as conflicts on dst.
+func Merge(dst *types.DiskInfo, src types.DiskInfo) {
+	if dst.Provenance == nil {
+		dst.Provenance = make(map[string]string)
+	}
+	dst.SourceConflicts = append(dst.SourceConflicts, src.SourceConflicts...)
+
+	for _, f := range fields {
+		if !f.present(&src) {
+			continue
+		}
+		srcSource := src.Provenance[f.name]
+		if !f.present(dst) {
+			f.copy(dst, &src)
+			dst.Provenance[f.name] = srcSource
+			continue
+		}
+
+		dstSource := dst.Provenance[f.name]
+		srcWins := f.rank(srcSource) <= f.rank(dstSource)
+
+		if f.conflicts && srcSource != dstSource && !f.equal(dst, &src) {
+			conflict := types.SourceConflict{Field: f.name}
+			if srcWins {
+				conflict.Source, conflict.Value = srcSource, f.value(&src)
+				conflict.OverriddenSource, conflict.OverriddenValue = dstSource, f.value(dst)
+			} else {
+				conflict.Source, conflict.Value = dstSource, f.value(dst)
+				conflict.OverriddenSource, conflict.OverriddenValue = srcSource, f.value(&src)
+			}
+			dst.SourceConflicts = append(dst.SourceConflicts, conflict)
+		}
+
+		if srcWins {
+			f.copy(dst, &src)
+			dst.Provenance[f.name] = srcSource
+		}
+	}
+}
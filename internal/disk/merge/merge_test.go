@@ -0,0 +1,252 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+
+	"disk-health-exporter/pkg/types"
+)
+
+// tagged returns a disk with every reported field attributed to source
+func tagged(disk types.DiskInfo, source string) types.DiskInfo {
+	Tag(&disk, source)
+	return disk
+}
+
+// mergeAll merges the reports in order, as the systems do tool by tool
+func mergeAll(reports ...types.DiskInfo) types.DiskInfo {
+	merged := reports[0]
+	for _, report := range reports[1:] {
+		Merge(&merged, report)
+	}
+	return merged
+}
+
+func TestTag(t *testing.T) {
+	disk := tagged(types.DiskInfo{Device: "/dev/sda", Model: "ST4000NM0023", Health: "Unknown", Capacity: 4000787030016}, SourceLsblk)
+
+	expected := map[string]string{"model": SourceLsblk, "capacity": SourceLsblk}
+	if !reflect.DeepEqual(disk.Provenance, expected) {
+		t.Errorf("Expected provenance %v, got %v", expected, disk.Provenance)
+	}
+}
+
+func TestMergePrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		reports    []types.DiskInfo
+		check      func(d types.DiskInfo) bool
+		field      string
+		source     string
+		conflicted bool
+	}{
+		{
+			name: "smartctl health beats a later controller state",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Health: "FAILED", HealthStatus: types.HealthStatusCritical}, SourceSmartctl),
+				tagged(types.DiskInfo{Health: "Online, Spun Up", HealthStatus: types.HealthStatusOK}, SourceMegaCLI),
+			},
+			check:      func(d types.DiskInfo) bool { return d.Health == "FAILED" },
+			field:      "health",
+			source:     SourceSmartctl,
+			conflicted: true,
+		},
+		{
+			name: "smartctl health beats an earlier controller state",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Health: "Optimal", HealthStatus: types.HealthStatusOK}, SourceStorCLI),
+				tagged(types.DiskInfo{Health: "FAILED", HealthStatus: types.HealthStatusCritical}, SourceSmartctl),
+			},
+			check:      func(d types.DiskInfo) bool { return d.Health == "FAILED" },
+			field:      "health",
+			source:     SourceSmartctl,
+			conflicted: true,
+		},
+		{
+			name: "unknown health does not replace a verdict",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Health: "FAILED", HealthStatus: types.HealthStatusCritical}, SourceSmartctl),
+				tagged(types.DiskInfo{Health: "Unknown"}, SourceNvme),
+			},
+			check:  func(d types.DiskInfo) bool { return d.Health == "FAILED" },
+			field:  "health",
+			source: SourceSmartctl,
+		},
+		{
+			name: "differently worded health is no conflict",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Health: "Online, Spun Up", HealthStatus: types.HealthStatusOK}, SourceMegaCLI),
+				tagged(types.DiskInfo{Health: "OK", HealthStatus: types.HealthStatusOK}, SourceSmartctl),
+			},
+			check:  func(d types.DiskInfo) bool { return d.Health == "OK" },
+			field:  "health",
+			source: SourceSmartctl,
+		},
+		{
+			name: "smartctl SMART verdict beats hdparm",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{SmartEnabled: true, SmartHealthy: false}, SourceSmartctl),
+				tagged(types.DiskInfo{SmartEnabled: true, SmartHealthy: true}, SourceHdparm),
+			},
+			check:      func(d types.DiskInfo) bool { return d.SmartEnabled && !d.SmartHealthy },
+			field:      "smart_healthy",
+			source:     SourceSmartctl,
+			conflicted: true,
+		},
+		{
+			name: "SMART verdict from a source without SMART is ignored",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{SmartEnabled: true, SmartHealthy: false}, SourceSmartctl),
+				tagged(types.DiskInfo{SmartHealthy: true}, SourceLsblk),
+			},
+			check:  func(d types.DiskInfo) bool { return !d.SmartHealthy },
+			field:  "smart_healthy",
+			source: SourceSmartctl,
+		},
+		{
+			name: "RAID location beats lsblk",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Location: "Controller:0 EID:252 Slot:3"}, SourceStorCLI),
+				tagged(types.DiskInfo{Location: "pci-0000:03:00.0-scsi-0:2:3:0"}, SourceLsblk),
+			},
+			check:  func(d types.DiskInfo) bool { return d.Location == "Controller:0 EID:252 Slot:3" },
+			field:  "location",
+			source: SourceStorCLI,
+		},
+		{
+			name: "RAID role beats zpool",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{RaidRole: "active"}, SourceArcconf),
+				tagged(types.DiskInfo{RaidRole: "spare"}, SourceZpool),
+			},
+			check:  func(d types.DiskInfo) bool { return d.RaidRole == "active" },
+			field:  "raid_role",
+			source: SourceArcconf,
+		},
+		{
+			name: "lsblk capacity beats smartctl",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Capacity: 4000787030016}, SourceLsblk),
+				tagged(types.DiskInfo{Capacity: 4000000000000}, SourceSmartctl),
+			},
+			check:  func(d types.DiskInfo) bool { return d.Capacity == 4000787030016 },
+			field:  "capacity",
+			source: SourceLsblk,
+		},
+		{
+			name: "smartctl identity beats the controller",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Serial: "5000C500AD914DAC", Model: "ST4000NM0023"}, SourceSmartctl),
+				tagged(types.DiskInfo{Serial: "Z1Z3ABCD", Model: "SEAGATE ST4000NM0023"}, SourceMegaCLI),
+			},
+			check:  func(d types.DiskInfo) bool { return d.Serial == "5000C500AD914DAC" && d.Model == "ST4000NM0023" },
+			field:  "serial",
+			source: SourceSmartctl,
+		},
+		{
+			name: "smartctl counters beat hdparm",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{PowerOnHours: 41234, Temperature: 38}, SourceSmartctl),
+				tagged(types.DiskInfo{PowerOnHours: 12, Temperature: 30}, SourceHdparm),
+			},
+			check:  func(d types.DiskInfo) bool { return d.PowerOnHours == 41234 && d.Temperature == 38 },
+			field:  "power_on_hours",
+			source: SourceSmartctl,
+		},
+		{
+			name: "a lower ranked source fills a missing field",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{PowerOnHours: 41234}, SourceSmartctl),
+				tagged(types.DiskInfo{Temperature: 30}, SourceHdparm),
+			},
+			check:  func(d types.DiskInfo) bool { return d.Temperature == 30 },
+			field:  "temperature",
+			source: SourceHdparm,
+		},
+		{
+			name: "unranked fields take the latest report",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Type: "regular"}, SourceSmartctl),
+				tagged(types.DiskInfo{Type: "zfs"}, SourceZpool),
+			},
+			check:  func(d types.DiskInfo) bool { return d.Type == "zfs" },
+			field:  "type",
+			source: SourceZpool,
+		},
+		{
+			name: "spare flags stick",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{IsGlobalSpare: true}, SourceMegaCLI),
+				tagged(types.DiskInfo{}, SourceSmartctl),
+			},
+			check:  func(d types.DiskInfo) bool { return d.IsGlobalSpare },
+			field:  "is_global_spare",
+			source: SourceMegaCLI,
+		},
+		{
+			name: "unknown sources rank below listed ones",
+			reports: []types.DiskInfo{
+				tagged(types.DiskInfo{Health: "OK", HealthStatus: types.HealthStatusOK}, SourceLsblk),
+				tagged(types.DiskInfo{Health: "FAILED", HealthStatus: types.HealthStatusCritical}, "plugin"),
+			},
+			check:      func(d types.DiskInfo) bool { return d.Health == "OK" },
+			field:      "health",
+			source:     SourceLsblk,
+			conflicted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeAll(tt.reports...)
+			if !tt.check(merged) {
+				t.Errorf("Unexpected merge result %+v", merged)
+			}
+			if merged.Provenance[tt.field] != tt.source {
+				t.Errorf("Expected %s from %s, got %q", tt.field, tt.source, merged.Provenance[tt.field])
+			}
+			if conflicted := len(merged.SourceConflicts) > 0; conflicted != tt.conflicted {
+				t.Errorf("Expected conflict %v, got %+v", tt.conflicted, merged.SourceConflicts)
+			}
+		})
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	merged := mergeAll(
+		tagged(types.DiskInfo{Device: "/dev/sda", Health: "OK", HealthStatus: types.HealthStatusOK, SmartEnabled: true, SmartHealthy: true}, SourceHdparm),
+		tagged(types.DiskInfo{Device: "/dev/sda", Health: "FAILED", HealthStatus: types.HealthStatusCritical, SmartEnabled: true, SmartHealthy: false}, SourceSmartctl),
+	)
+
+	expected := []types.SourceConflict{
+		{Field: "health", Source: SourceSmartctl, Value: "FAILED", OverriddenSource: SourceHdparm, OverriddenValue: "OK"},
+		{Field: "smart_healthy", Source: SourceSmartctl, Value: "false", OverriddenSource: SourceHdparm, OverriddenValue: "true"},
+	}
+	if !reflect.DeepEqual(merged.SourceConflicts, expected) {
+		t.Errorf("Expected conflicts %+v, got %+v", expected, merged.SourceConflicts)
+	}
+}
+
+func TestMergeSameSourceIsNoConflict(t *testing.T) {
+	merged := mergeAll(
+		tagged(types.DiskInfo{Health: "OK", HealthStatus: types.HealthStatusOK}, SourceSmartctl),
+		tagged(types.DiskInfo{Health: "FAILED", HealthStatus: types.HealthStatusCritical}, SourceSmartctl),
+	)
+	if merged.Health != "FAILED" || len(merged.SourceConflicts) != 0 {
+		t.Errorf("Expected the latest report without conflict, got %q %+v", merged.Health, merged.SourceConflicts)
+	}
+}
+
+func TestMergeKeepsConflictsOfBothDisks(t *testing.T) {
+	first := mergeAll(
+		tagged(types.DiskInfo{Health: "OK", HealthStatus: types.HealthStatusOK}, SourceHdparm),
+		tagged(types.DiskInfo{Health: "FAILED", HealthStatus: types.HealthStatusCritical}, SourceSmartctl),
+	)
+	second := tagged(types.DiskInfo{Health: "FAILED", HealthStatus: types.HealthStatusCritical}, SourceMegaCLI)
+	second.SourceConflicts = []types.SourceConflict{{Field: "health", Source: SourceMegaCLI, OverriddenSource: SourceLsblk}}
+
+	Merge(&first, second)
+	if len(first.SourceConflicts) != 2 {
+		t.Errorf("Expected both conflicts to be kept, got %+v", first.SourceConflicts)
+	}
+}
@@ -1,72 +0,0 @@
-package systems
-
-import (
-	"strings"
-	"testing"
-)
-
-func TestLinuxSystem(t *testing.T) {
-	// Basic initialization test
-	linux := NewLinuxSystem([]string{}, []string{})
-
-	// Verify the system is initialized correctly
-	if linux == nil {
-		t.Fatalf("Expected non-nil LinuxSystem instance")
-	}
-
-	// Test target disk and ignore pattern handling
-	linux = NewLinuxSystem([]string{"/dev/sda"}, []string{"loop"})
-
-	// Check that target disks are stored correctly
-	if len(linux.targetDisks) != 1 || linux.targetDisks[0] != "/dev/sda" {
-		t.Errorf("Expected targetDisks to contain [\"/dev/sda\"], got %v", linux.targetDisks)
-	}
-
-	// Check that ignore patterns are stored correctly
-	if len(linux.ignorePatterns) != 1 || linux.ignorePatterns[0] != "loop" {
-		t.Errorf("Expected ignorePatterns to contain [\"loop\"], got %v", linux.ignorePatterns)
-	}
-}
-
-func TestShouldIncludeDisk(t *testing.T) {
-	// Create a LinuxSystem with specific target disks and ignore patterns
-	linux := NewLinuxSystem([]string{"/dev/sda", "/dev/nvme0n1"}, []string{"loop", "ram"})
-
-	// Test matching target disk
-	if !linux.shouldIncludeDisk("/dev/sda") {
-		t.Errorf("Expected /dev/sda to be included")
-	}
-
-	// Test matching target disk with NVME
-	if !linux.shouldIncludeDisk("/dev/nvme0n1") {
-		t.Errorf("Expected /dev/nvme0n1 to be included")
-	}
-
-	// Test non-matching disk with empty targets (should include all)
-	linuxAll := NewLinuxSystem([]string{}, []string{"loop", "ram"})
-	if !linuxAll.shouldIncludeDisk("/dev/sdb") {
-		t.Errorf("Expected /dev/sdb to be included with empty targets")
-	}
-
-	// Test ignored patterns - we need to manually check if the device contains the ignore pattern
-	// since we can't directly access the shouldIncludeDisk method's implementation
-	device := "/dev/loop0"
-	for _, pattern := range linux.ignorePatterns {
-		if strings.Contains(device, pattern) {
-			// If we found a pattern, the device should be excluded
-			// This test is just to verify our test logic
-			t.Logf("Verified that %s contains pattern %s and should be excluded", device, pattern)
-			break
-		}
-	}
-
-	device = "/dev/ram1"
-	for _, pattern := range linux.ignorePatterns {
-		if strings.Contains(device, pattern) {
-			// If we found a pattern, the device should be excluded
-			// This test is just to verify our test logic
-			t.Logf("Verified that %s contains pattern %s and should be excluded", device, pattern)
-			break
-		}
-	}
-}
@@ -0,0 +1,370 @@
+// Package systems detects disks by running the disk tools available on a platform.
+package systems
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/disk/tools"
+	"disk-health-exporter/pkg/types"
+)
+
+// System detects disks on one platform. Every tool registered for the platform
+// and available at startup runs in one pipeline; the results are filtered by the
+// configured target disks and ignore patterns and merged per device.
+type System struct {
+	platform       string
+	targetDisks    []string
+	ignorePatterns []string
+	tools          []systemTool
+}
+
+// systemTool is an available tool together with its registered source name
+type systemTool struct {
+	name string
+	tool tools.ToolInterface
+}
+
+// NewSystem creates a System for a platform (a runtime.GOOS value)
+func NewSystem(platform string, targetDisks []string, ignorePatterns []string) *System {
+	s := &System{
+		platform:       tools.Platform(platform),
+		targetDisks:    targetDisks,
+		ignorePatterns: ignorePatterns,
+	}
+
+	// Check tool availability once at startup
+	var names []string
+	for _, r := range tools.ForPlatform(s.platform) {
+		tool := r.New()
+		if tool.IsAvailable() {
+			s.tools = append(s.tools, systemTool{name: r.Name, tool: tool})
+			names = append(names, r.Name)
+		}
+	}
+
+	slog.Info("Tool availability detected", "system", s.GetSystemType(), "tools", names)
+
+	return s
+}
+
+// AddTool appends a tool to the end of the pipeline, e.g. an external plugin.
+// The tool runs on every collection; name is the source of the disks it reports.
+func (s *System) AddTool(name string, tool tools.ToolInterface) {
+	s.tools = append(s.tools, systemTool{name: name, tool: tool})
+}
+
+// GetDisks runs every available tool in order and merges the disks they report
+func (s *System) GetDisks() ([]types.DiskInfo, []types.RAIDInfo) {
+	var allDisks []types.DiskInfo
+	var allRAIDs []types.RAIDInfo
+
+	for _, t := range s.tools {
+		start := time.Now()
+		var disks []types.DiskInfo
+		var raids []types.RAIDInfo
+
+		// Snapshot tools report disks and arrays from one run and RAID tools their
+		// member disks; no tool is asked for its disks twice
+		if snapshotTool, ok := t.tool.(tools.SnapshotToolInterface); ok {
+			disks, raids = snapshotTool.GetSnapshot()
+		} else if raidTool, ok := t.tool.(tools.RAIDToolInterface); ok {
+			raids = raidTool.GetRAIDArrays()
+			disks = raidTool.GetRAIDDisks()
+		} else if diskTool, ok := t.tool.(tools.DiskToolInterface); ok {
+			disks = diskTool.GetDisks()
+		}
+
+		if softwareRAIDTool, ok := t.tool.(tools.SoftwareRAIDToolInterface); ok {
+			for _, sr := range softwareRAIDTool.GetSoftwareRAIDs() {
+				raids = append(raids, softwareRAIDInfo(sr, softwareRAIDTool.GetName()))
+			}
+		}
+
+		slog.Debug("Tool finished", "tool", t.name, "disks", len(disks), "raid_arrays", len(raids), "duration", time.Since(start))
+		allRAIDs = append(allRAIDs, raids...)
+		allDisks = s.mergeDisks(allDisks, s.filterDisks(disks), t.name)
+	}
+
+	// Deduplicate disks to prevent reporting the same physical disk multiple times
+	allDisks = s.deduplicateDisks(allDisks)
+
+	return allDisks, allRAIDs
+}
+
+// GetControllers returns the hardware RAID controllers of every tool reporting
+// them. The pipeline's own tools are asked, so tools can reuse what they read
+// for the disks of the same collection.
+func (s *System) GetControllers() []types.RAIDControllerInfo {
+	var controllers []types.RAIDControllerInfo
+	for _, t := range s.tools {
+		if controllerTool, ok := t.tool.(tools.ControllerToolInterface); ok {
+			controllers = append(controllers, controllerTool.GetControllers()...)
+		}
+	}
+	return controllers
+}
+
+// softwareRAIDInfo converts a software RAID to the RAIDInfo format
+func softwareRAIDInfo(sr types.SoftwareRAIDInfo, controller string) types.RAIDInfo {
+	raid := types.RAIDInfo{
+		Controller:      controller,
+		ArrayID:         sr.Device,
+		RaidLevel:       sr.Level,
+		Status:          sr.Status,
+		Size:            sr.ArraySize,
+		NumDrives:       sr.TotalDevices,
+		NumActiveDrives: sr.RaidDevices,
+		Type:            "software",
+		State:           sr.State,
+		Members:         slices.Concat(sr.ActiveDevices, sr.SpareDevices, sr.FailedDevices),
+	}
+	if sr.SyncAction == "recover" {
+		raid.RebuildProgress = int(sr.SyncProgress)
+		raid.Rebuilding = true
+	}
+	return raid
+}
+
+// filterDisks filters disks based on target and ignore patterns
+func (s *System) filterDisks(disks []types.DiskInfo) []types.DiskInfo {
+	var filtered []types.DiskInfo
+	for _, disk := range disks {
+		if s.shouldIncludeDisk(disk.Device) {
+			filtered = append(filtered, disk)
+		}
+	}
+	return filtered
+}
+
+// shouldIncludeDisk checks if a disk should be included based on configuration
+func (s *System) shouldIncludeDisk(device string) bool {
+	// First check ignore patterns
+	for _, pattern := range s.ignorePatterns {
+		if strings.HasPrefix(device, pattern) {
+			slog.Debug("Ignoring disk matching ignore pattern", "device", device, "pattern", pattern)
+			return false
+		}
+	}
+
+	// If target disks are specified, only include those
+	if len(s.targetDisks) > 0 {
+		for _, target := range s.targetDisks {
+			if device == target {
+				return true
+			}
+		}
+		slog.Debug("Skipping disk not in target list", "device", device)
+		return false
+	}
+
+	// No specific targets, include if not ignored
+	return true
+}
+
+// mergeDisks merges the disks reported by one tool into the disks found so far.
+// Fields reported for the same device are combined following the precedence
+// table of the merge package, which also records the source of every field.
+func (s *System) mergeDisks(existing []types.DiskInfo, newDisks []types.DiskInfo, source string) []types.DiskInfo {
+	diskMap := make(map[string]types.DiskInfo)
+
+	// Add existing disks to map
+	for _, disk := range existing {
+		diskMap[disk.Device] = disk
+	}
+
+	// Merge or add new disks
+	for _, newDisk := range newDisks {
+		merge.Tag(&newDisk, source)
+		if merged, exists := diskMap[newDisk.Device]; exists {
+			merge.Merge(&merged, newDisk)
+			diskMap[newDisk.Device] = merged
+		} else {
+			diskMap[newDisk.Device] = newDisk
+		}
+	}
+
+	// Convert back to slice
+	var result []types.DiskInfo
+	for _, disk := range diskMap {
+		result = append(result, disk)
+	}
+
+	return result
+}
+
+// deduplicateDisks removes duplicate disks that may be reported by multiple tools
+// This is conservative - we only deduplicate when we're confident it's the same physical disk
+// RAID virtual devices (/dev/sdX) and physical devices (raid-encX-slotY) are kept separate
+func (s *System) deduplicateDisks(disks []types.DiskInfo) []types.DiskInfo {
+	if len(disks) <= 1 {
+		return disks
+	}
+
+	// Group disks by serial number and model, but only for devices from the same "class"
+	diskGroups := make(map[string][]types.DiskInfo)
+	var standaloneDisks []types.DiskInfo
+
+	for _, disk := range disks {
+		// Only deduplicate disks with valid serial numbers and from the same device class
+		// Don't deduplicate across RAID virtual devices vs physical devices
+		if disk.Serial == "" || len(disk.Serial) < 8 {
+			// Keep disks without proper serial numbers separate
+			standaloneDisks = append(standaloneDisks, disk)
+			continue
+		}
+
+		// Create device class identifier
+		deviceClass := ""
+		if strings.HasPrefix(disk.Device, "/dev/") {
+			if strings.Contains(disk.Model, "PERC") || strings.Contains(disk.Model, "RAID") {
+				deviceClass = "raid_virtual"
+			} else {
+				deviceClass = "block_device"
+			}
+		} else if strings.HasPrefix(disk.Device, "raid-enc") {
+			deviceClass = "raid_physical"
+		} else {
+			deviceClass = "other"
+		}
+
+		// Create a unique key based on serial, model, and device class
+		key := fmt.Sprintf("%s|%s|%s", disk.Serial, disk.Model, deviceClass)
+		diskGroups[key] = append(diskGroups[key], disk)
+	}
+
+	var result []types.DiskInfo
+
+	// Process each group of potentially duplicate disks
+	for _, group := range diskGroups {
+		if len(group) == 1 {
+			// No duplicates, add as-is
+			result = append(result, group[0])
+		} else {
+			// Multiple disks with same serial/model/class - merge them
+			best := s.selectBestDiskFromGroup(group)
+			result = append(result, best)
+
+		}
+	}
+
+	// Add standalone disks (those without proper serials)
+	result = append(result, standaloneDisks...)
+
+	return result
+}
+
+// selectBestDiskFromGroup merges a group of duplicates into one disk.
+// The best scored representation provides the device name and wins ties
+// between equally ranked sources; other fields follow the merge precedence table.
+func (s *System) selectBestDiskFromGroup(group []types.DiskInfo) types.DiskInfo {
+	if len(group) == 1 {
+		return group[0]
+	}
+
+	// Merge in ascending score order so the best representation is merged last
+	sorted := append([]types.DiskInfo(nil), group...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return s.scoreDisk(sorted[i]) < s.scoreDisk(sorted[j])
+	})
+
+	merged := sorted[0]
+	for _, disk := range sorted[1:] {
+		merge.Merge(&merged, disk)
+	}
+	merged.Device = sorted[len(sorted)-1].Device
+
+	return merged
+}
+
+// scoreDisk assigns a score to a disk based on how "good" its representation is
+func (s *System) scoreDisk(disk types.DiskInfo) int {
+	score := 0
+
+	// Prefer regular block devices over RAID virtual devices
+	if strings.HasPrefix(disk.Device, "/dev/") {
+		score += 100
+	} else if strings.HasPrefix(disk.Device, "raid-") {
+		score += 50
+	}
+
+	// Prefer disks with more complete information
+	if disk.Model != "" {
+		score += 10
+	}
+	if disk.Serial != "" {
+		score += 10
+	}
+	if disk.Temperature > 0 {
+		score += 5
+	}
+	if disk.Capacity > 0 {
+		score += 5
+	}
+	if disk.PowerOnHours > 0 {
+		score += 5
+	}
+	if disk.SmartEnabled {
+		score += 10
+	}
+	if disk.Interface != "" {
+		score += 5
+	}
+	if disk.Health != "" {
+		score += 5
+	}
+
+	return score
+}
+
+// GetSystemType returns the system type
+func (s *System) GetSystemType() string {
+	switch s.platform {
+	case tools.PlatformDarwin:
+		return "macOS"
+	default:
+		return s.platform
+	}
+}
+
+// GetToolInfo reports which tools are available on this system
+func (s *System) GetToolInfo() types.ToolInfo {
+	toolInfo := types.ToolInfo{Tools: make(map[string]string)}
+
+	for _, t := range s.tools {
+		version := t.tool.GetVersion()
+		toolInfo.Tools[t.name] = version
+
+		switch t.name {
+		case merge.SourceLsblk:
+			toolInfo.Lsblk = true
+		case merge.SourceDiskutil:
+			toolInfo.Diskutil = true
+		case merge.SourceSmartctl:
+			toolInfo.SmartCtl, toolInfo.SmartCtlVersion = true, version
+		case merge.SourceNvme:
+			toolInfo.Nvme = true
+		case merge.SourceHdparm:
+			toolInfo.Hdparm, toolInfo.HdparmVersion = true, version
+		case merge.SourceMegaCLI:
+			toolInfo.MegaCLI, toolInfo.MegaCLIVersion = true, version
+		case merge.SourceStorCLI:
+			toolInfo.Storcli, toolInfo.StorCLIVersion = true, version
+		case merge.SourceArcconf:
+			toolInfo.Arcconf, toolInfo.ArcconfVersion = true, version
+		case merge.SourceSsacli:
+			toolInfo.Ssacli, toolInfo.SsacliVersion = true, version
+		case merge.SourceMdadm:
+			toolInfo.Mdadm = true
+		case merge.SourceZpool:
+			toolInfo.Zpool, toolInfo.ZpoolVersion = true, version
+		}
+	}
+
+	return toolInfo
+}
@@ -2,19 +2,69 @@ package tools
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/statemap"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
+// Ensure ArcconfTool implements the RAID, battery and controller interfaces
+var (
+	_ RAIDToolInterface       = (*ArcconfTool)(nil)
+	_ BatteryToolInterface    = (*ArcconfTool)(nil)
+	_ ControllerToolInterface = (*ArcconfTool)(nil)
+)
+
 // ArcconfTool represents the arcconf CLI tool for Adaptec RAID controllers
 type ArcconfTool struct{}
 
+func init() {
+	Register(Registration{
+		Name:      merge.SourceArcconf,
+		Platforms: []string{PlatformLinux, PlatformWindows},
+		After:     []string{merge.SourceSmartctl, merge.SourceNvme, merge.SourceHdparm},
+		New:       func() ToolInterface { return NewArcconfTool() },
+	})
+}
+
+// arcconfDeviceStates translates the state of physical devices
+var arcconfDeviceStates = statemap.New(merge.SourceArcconf, map[string]types.HealthStatus{
+	"Online":              types.HealthStatusOK,
+	"Ready":               types.HealthStatusOK,
+	"Hot Spare":           types.HealthStatusOK,
+	"Global Hot-Spare":    types.HealthStatusOK,
+	"Dedicated Hot-Spare": types.HealthStatusOK,
+	"Raw (Pass Through)":  types.HealthStatusOK,
+	"Rebuilding":          types.HealthStatusWarning,
+	"Failed":              types.HealthStatusCritical,
+	"Missing":             types.HealthStatusCritical,
+	"Offline":             types.HealthStatusCritical,
+})
+
+// arcconfLogicalDeviceStates translates the status of logical devices
+var arcconfLogicalDeviceStates = statemap.New(merge.SourceArcconf, map[string]types.RAIDState{
+	"Optimal":      types.RAIDStateOptimal,
+	"OK":           types.RAIDStateOptimal,
+	"Degraded":     types.RAIDStateDegraded,
+	"Suboptimal":   types.RAIDStateDegraded,
+	"Impacted":     types.RAIDStateDegraded,
+	"Rebuilding":   types.RAIDStateDegraded,
+	"Initializing": types.RAIDStateDegraded,
+	"Failed":       types.RAIDStateFailed,
+	"Offline":      types.RAIDStateFailed,
+})
+
+// arcconfLogicalDeviceRebuilds lists the logical device states of a running rebuild
+var arcconfLogicalDeviceRebuilds = statemap.New(merge.SourceArcconf, map[string]bool{
+	"Rebuilding": true,
+})
+
 // NewArcconfTool creates a new ArcconfTool instance
 func NewArcconfTool() *ArcconfTool {
 	return &ArcconfTool{}
@@ -56,12 +106,96 @@ func (a *ArcconfTool) GetRAIDArrays() []types.RAIDInfo {
 
 	for _, controllerID := range controllers {
 		arrays := a.getArraysForController(controllerID)
+		a.applyTaskStatus(arrays, controllerID)
 		raidArrays = append(raidArrays, arrays...)
 	}
 
 	return raidArrays
 }
 
+// arcconfTask is a running logical device task from the arcconf task list
+type arcconfTask struct {
+	Operation string // e.g. "Verify/Fix", "Rebuild", "Build/Verify"
+	Progress  int    // Percentage complete
+}
+
+// applyTaskStatus sets verify and rebuild progress from the running tasks of a controller
+// arcconf getstatus X # list running tasks for controller X
+func (a *ArcconfTool) applyTaskStatus(arrays []types.RAIDInfo, controllerID string) {
+	if len(arrays) == 0 {
+		return
+	}
+
+	output, err := exec.Command("arcconf", "getstatus", controllerID).Output()
+	if err != nil {
+		slog.Error("Error getting task status", "tool", "arcconf", "controller", controllerID, "err", err)
+		return
+	}
+
+	applyTasks(arrays, a.parseTaskStatus(string(output), controllerID))
+}
+
+// applyTasks sets the progress of rebuild and verify tasks on their arrays
+func applyTasks(arrays []types.RAIDInfo, tasks map[string]arcconfTask) {
+	for i := range arrays {
+		task, ok := tasks[arrays[i].ArrayID]
+		if !ok {
+			continue
+		}
+		operation := strings.ToLower(task.Operation)
+		switch {
+		case strings.Contains(operation, "rebuild"):
+			arrays[i].RebuildProgress = task.Progress
+			arrays[i].Rebuilding = true
+		case strings.Contains(operation, "verify"):
+			arrays[i].ScrubProgress = task.Progress
+			arrays[i].CheckRunning = true
+		}
+	}
+}
+
+// parseTaskStatus parses the logical device tasks of arcconf getstatus X,
+// keyed by array ID ("controller:logical device")
+func (a *ArcconfTool) parseTaskStatus(output, controllerID string) map[string]arcconfTask {
+	tasks := make(map[string]arcconfTask)
+
+	var arrayID string
+	var task arcconfTask
+	finalize := func() {
+		if arrayID != "" && task.Operation != "" && !strings.EqualFold(task.Operation, "None") {
+			tasks[arrayID] = task
+		}
+		arrayID, task = "", arcconfTask{}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Logical device Task") || strings.HasPrefix(line, "Logical Device Task") {
+			finalize()
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "logical device", "logical device number":
+			arrayID = controllerID + ":" + value
+		case "current operation":
+			task.Operation = value
+		case "percentage complete":
+			task.Progress, _ = strconv.Atoi(strings.TrimSuffix(value, "%"))
+		}
+	}
+	finalize()
+
+	return tasks
+}
+
 // GetRAIDDisks returns disk information from RAID arrays
 func (a *ArcconfTool) GetRAIDDisks() []types.DiskInfo {
 	var disks []types.DiskInfo
@@ -82,9 +216,112 @@ func (a *ArcconfTool) GetRAIDDisks() []types.DiskInfo {
 		disks = append(disks, controllerDisks...)
 	}
 
+	// Read full SMART data of each drive through the controller
+	enrichWithPassthroughSMART(disks)
+
 	return disks
 }
 
+// GetControllers returns controller versions, status and drive counts for all Adaptec controllers
+// arcconf getconfig X ad # get adapter information for controller X
+// arcconf getconfig X pd # get physical device information for controller X
+func (a *ArcconfTool) GetControllers() []types.RAIDControllerInfo {
+	var controllers []types.RAIDControllerInfo
+
+	if !a.IsAvailable() {
+		return controllers
+	}
+
+	for _, controllerID := range a.getControllers() {
+		output, err := exec.Command("arcconf", "getconfig", controllerID, "ad").Output()
+		if err != nil {
+			slog.Error("Error getting adapter info", "tool", "arcconf", "controller", controllerID, "err", err)
+			continue
+		}
+		controller := a.parseAdapterInfo(string(output), controllerID)
+
+		output, err = exec.Command("arcconf", "getconfig", controllerID, "pd").Output()
+		if err != nil {
+			slog.Error("Error getting physical devices", "tool", "arcconf", "controller", controllerID, "err", err)
+		} else {
+			controller.NumPhysicalDrives = strings.Count(string(output), "Device is a Hard drive")
+		}
+
+		controllers = append(controllers, controller)
+	}
+
+	return controllers
+}
+
+// parseAdapterInfo parses the output of arcconf getconfig X ad.
+// Only the "Controller information" and "Controller Version Information"
+// sections are read; the cache backup unit section repeats keys such as Temperature.
+func (a *ArcconfTool) parseAdapterInfo(output, controllerID string) types.RAIDControllerInfo {
+	adapterID, err := strconv.Atoi(controllerID)
+	if err != nil {
+		adapterID = 0
+	}
+
+	controller := types.RAIDControllerInfo{
+		AdapterID: adapterID,
+		ToolName:  "Arcconf",
+	}
+
+	var section string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "----") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			// Section titles are framed by dashed lines
+			section = strings.ToLower(line)
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch section {
+		case "controller information":
+			switch key {
+			case "Controller Status":
+				controller.Status = value
+			case "Controller Model":
+				controller.Model = value
+			case "Controller Serial Number":
+				controller.SerialNumber = value
+			case "Temperature":
+				// e.g. "56 C/ 132 F (Normal)"
+				if temperature, ok := extractNumericValue(value, "C"); ok {
+					controller.ROCTemperature = temperature
+				}
+			case "Installed memory":
+				controller.CacheSize = utils.ParseSizeToBytes(value)
+			case "Logical devices/Failed/Degraded":
+				// e.g. "2/0/0"
+				total, _, _ := strings.Cut(value, "/")
+				controller.NumVirtualDrives, _ = strconv.Atoi(total)
+			case "Background consistency check":
+				// Adaptec's equivalent of a patrol read (Enabled/Disabled)
+				controller.PatrolReadMode = value
+			}
+		case "controller version information":
+			switch key {
+			case "BIOS":
+				controller.BIOSVersion = value
+			case "Firmware":
+				controller.FirmwareVersion = value
+			case "Driver":
+				controller.DriverVersion = value
+			}
+		}
+	}
+
+	return controller
+}
+
 // GetBatteryInfo returns battery information for Arcconf controllers
 // arcconf getconfig X bbu # get battery backup unit information for controller X
 // arcconf getconfig X pd # get physical device info (fallback for battery info)
@@ -99,7 +336,7 @@ func (a *ArcconfTool) GetBatteryInfo(controllerID string) *types.RAIDBatteryInfo
 		// Try alternative command format
 		output, err = exec.Command("arcconf", "getconfig", controllerID, "pd").Output()
 		if err != nil {
-			log.Printf("Error executing arcconf for battery info: %v", err)
+			slog.Error("Error getting battery info", "tool", "arcconf", "controller", controllerID, "err", err)
 			return nil
 		}
 	}
@@ -195,7 +432,7 @@ func (a *ArcconfTool) getControllers() []string {
 	// Get controller list
 	output, err := exec.Command("arcconf", "list").Output()
 	if err != nil {
-		log.Printf("Error getting arcconf controller list: %v", err)
+		slog.Error("Error getting controller list", "tool", "arcconf", "err", err)
 		return controllers
 	}
 
@@ -223,7 +460,7 @@ func (a *ArcconfTool) getArraysForController(controllerID string) []types.RAIDIn
 	// Get logical device info for this controller
 	output, err := exec.Command("arcconf", "getconfig", controllerID, "ld").Output()
 	if err != nil {
-		log.Printf("Error getting arcconf logical devices for controller %s: %v", controllerID, err)
+		slog.Error("Error getting logical devices", "tool", "arcconf", "controller", controllerID, "err", err)
 		return arrays
 	}
 
@@ -266,7 +503,8 @@ func (a *ArcconfTool) getArraysForController(controllerID string) []types.RAIDIn
 				if len(parts) > 1 {
 					status := strings.TrimSpace(parts[1])
 					currentArray.State = status
-					currentArray.Status = a.getRAIDStatusValue(status)
+					currentArray.Status = arcconfLogicalDeviceStates.Map(status)
+					currentArray.Rebuilding, _ = arcconfLogicalDeviceRebuilds.Lookup(status)
 				}
 			} else if strings.Contains(line, "Size") {
 				// Extract size
@@ -311,11 +549,30 @@ func (a *ArcconfTool) getDisksForController(controllerID string) []types.DiskInf
 	// Get physical device info for this controller
 	output, err := exec.Command("arcconf", "getconfig", controllerID, "pd").Output()
 	if err != nil {
-		log.Printf("Error getting arcconf physical devices for controller %s: %v", controllerID, err)
+		slog.Error("Error getting physical devices", "tool", "arcconf", "controller", controllerID, "err", err)
 		return disks
 	}
 
-	lines := strings.Split(string(output), "\n")
+	disks = a.parsePhysicalDevices(string(output), controllerID)
+
+	// Enrich disks with SMART data
+	for i := range disks {
+		a.enrichRAIDDiskWithSMART(&disks[i], controllerID)
+	}
+
+	return disks
+}
+
+// arcconfReportedAddressRe matches the target and LUN of "Reported Channel,Device(T:L) : 0,3(3:0)"
+var arcconfReportedAddressRe = regexp.MustCompile(`^Reported Channel,Device\(T:L\)\s*:\s*\d+,\d+\((\d+):(\d+)\)`)
+
+// parsePhysicalDevices parses "arcconf getconfig X pd" output into disks.
+// A device section runs until the next "Device #" line, since newer arcconf
+// releases split it into blank-line separated blocks such as "Device Error Counters".
+func (a *ArcconfTool) parsePhysicalDevices(output string, controllerID string) []types.DiskInfo {
+	var disks []types.DiskInfo
+
+	lines := strings.Split(output, "\n")
 	var currentDisk types.DiskInfo
 	var inPhysicalDevice bool
 
@@ -341,6 +598,9 @@ func (a *ArcconfTool) getDisksForController(controllerID string) []types.DiskInf
 				currentDisk.Device = "arcconf:" + controllerID + ":" + matches[1]
 			}
 		} else if inPhysicalDevice {
+			if a.parseRAIDDriveLine(line, &currentDisk) {
+				continue
+			}
 			if strings.Contains(line, "Model") {
 				parts := strings.Split(line, ":")
 				if len(parts) > 1 {
@@ -351,12 +611,6 @@ func (a *ArcconfTool) getDisksForController(controllerID string) []types.DiskInf
 				if len(parts) > 1 {
 					currentDisk.Serial = strings.TrimSpace(parts[1])
 				}
-			} else if strings.Contains(line, "State") {
-				parts := strings.Split(line, ":")
-				if len(parts) > 1 {
-					state := strings.TrimSpace(parts[1])
-					currentDisk.Health = state
-				}
 			} else if strings.Contains(line, "Size") {
 				parts := strings.Split(line, ":")
 				if len(parts) > 1 {
@@ -368,17 +622,17 @@ func (a *ArcconfTool) getDisksForController(controllerID string) []types.DiskInf
 				if len(parts) > 1 {
 					currentDisk.Interface = strings.TrimSpace(parts[1])
 				}
+			} else if matches := arcconfReportedAddressRe.FindStringSubmatch(line); matches != nil {
+				// The target ID and LUN address the drive for smartctl through the controller
+				controller, _ := strconv.Atoi(controllerID)
+				target, _ := strconv.Atoi(matches[1])
+				lun, _ := strconv.Atoi(matches[2])
+				setAacraidPassthrough(&currentDisk, controller-1, lun, target)
 			} else if strings.Contains(line, "Location") {
 				parts := strings.Split(line, ":")
 				if len(parts) > 1 {
 					currentDisk.Location = strings.TrimSpace(parts[1])
 				}
-			} else if line == "" {
-				// End of physical device section
-				if currentDisk.Device != "" {
-					disks = append(disks, currentDisk)
-					inPhysicalDevice = false
-				}
 			}
 		}
 	}
@@ -388,12 +642,43 @@ func (a *ArcconfTool) getDisksForController(controllerID string) []types.DiskInf
 		disks = append(disks, currentDisk)
 	}
 
-	// Enrich disks with SMART data
-	for i := range disks {
-		a.enrichRAIDDiskWithSMART(&disks[i], controllerID)
+	return disks
+}
+
+// parseRAIDDriveLine parses the firmware state, transfer speed and error counters
+// of a physical device. It returns false if the line holds none of them.
+func (a *ArcconfTool) parseRAIDDriveLine(line string, disk *types.DiskInfo) bool {
+	key, value, found := strings.Cut(line, ":")
+	if !found {
+		return false
 	}
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
 
-	return disks
+	if disk.RaidDrive == nil {
+		disk.RaidDrive = &types.RAIDDriveInfo{ToolName: "Arcconf"}
+	}
+	count, _ := strconv.ParseInt(value, 10, 64)
+
+	switch key {
+	case "State":
+		disk.Health = value
+		disk.HealthStatus = arcconfDeviceStates.Map(value)
+		disk.RaidDrive.FirmwareState = value
+	case "Transfer Speed":
+		disk.RaidDrive.LinkSpeed = value
+	case "Media Failures":
+		disk.RaidDrive.MediaErrorCount = count
+	case "Predictive Failures":
+		disk.RaidDrive.PredictiveFailureCount = count
+	case "Aborted Commands", "Bad Target Errors", "Hardware Errors", "Not Ready Errors",
+		"Other Time Out Errors", "Scsi Bus Faults":
+		// Non-media errors, matching what MegaRAID reports as "Other Error Count"
+		disk.RaidDrive.OtherErrorCount += count
+	default:
+		return false
+	}
+	return true
 }
 
 // enrichRAIDDiskWithSMART enriches RAID disk information with SMART data via Arcconf
@@ -440,14 +725,6 @@ func (a *ArcconfTool) enrichRAIDDiskWithSMART(disk *types.DiskInfo, controllerID
 			}
 		} else if strings.Contains(line, "S.M.A.R.T.") {
 			disk.SmartEnabled = !strings.Contains(strings.ToLower(line), "disabled")
-		} else if strings.Contains(line, "State") || strings.Contains(line, "Status") {
-			if strings.Contains(strings.ToLower(line), "online") || strings.Contains(strings.ToLower(line), "optimal") {
-				disk.SmartHealthy = true
-				disk.Health = "OK"
-			} else if strings.Contains(strings.ToLower(line), "failed") || strings.Contains(strings.ToLower(line), "critical") {
-				disk.SmartHealthy = false
-				disk.Health = "FAILED"
-			}
 		}
 	}
 }
@@ -475,19 +752,3 @@ func (a *ArcconfTool) normalizeRAIDLevel(raidLevel string) string {
 		return "RAID " + raidLevel
 	}
 }
-
-// getRAIDStatusValue converts RAID state to numeric value
-func (a *ArcconfTool) getRAIDStatusValue(state string) int {
-	state = strings.ToLower(strings.TrimSpace(state))
-
-	switch state {
-	case "optimal", "ok":
-		return 1
-	case "degraded", "rebuilding", "initializing":
-		return 2
-	case "failed", "offline":
-		return 3
-	default:
-		return 0
-	}
-}
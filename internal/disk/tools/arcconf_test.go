@@ -1,7 +1,10 @@
 package tools
 
 import (
+	"reflect"
 	"testing"
+
+	"disk-health-exporter/pkg/types"
 )
 
 func TestArcconfTool_NewArcconfTool(t *testing.T) {
@@ -33,9 +36,212 @@ func TestArcconfTool_Interfaces(t *testing.T) {
 	// Verify that ArcconfTool implements RAIDToolInterface
 	var _ RAIDToolInterface = tool
 
+	// Verify that ArcconfTool implements ControllerToolInterface
+	var _ ControllerToolInterface = tool
+
 	// Test that methods don't panic
 	arrays := tool.GetRAIDArrays()
 	disks := tool.GetRAIDDisks()
 
 	t.Logf("arcconf found %d RAID arrays and %d RAID disks", len(arrays), len(disks))
 }
+
+func TestArcconfTool_ParseAdapterInfo(t *testing.T) {
+	output := `Controllers found: 1
+----------------------------------------------------------------------
+Controller information
+----------------------------------------------------------------------
+   Controller Status                        : Optimal
+   Channel description                      : SAS/SATA
+   Controller Model                         : Adaptec ASR8805
+   Controller Serial Number                 : 7A4613D8B4C
+   Controller World Wide Name               : 50000D1109AD0B00
+   Physical Slot                            : 4
+   Temperature                              : 56 C/ 132 F (Normal)
+   Installed memory                         : 1024 MB
+   Global task priority                     : High
+   Performance Mode                         : Default/Dynamic
+   Host bus type                            : PCIe 3.0
+   Host bus speed                           : 7880 MBps
+   Host bus link width                      : 8 bit(s)/link(s)
+   Defunct disk drive count                 : 0
+   Logical devices/Failed/Degraded          : 2/0/1
+   NCQ status                               : Enabled
+   Background consistency check             : Enabled
+   --------------------------------------------------------
+   Controller Version Information
+   --------------------------------------------------------
+   BIOS                                     : 7.11-0 (33556)
+   Firmware                                 : 7.11-0 (33556)
+   Driver                                   : 1.2-1 (50983)
+   Boot Flash                               : 7.11-0 (33556)
+   CPLD (Load version/ Default version)     : 8/ 8
+   SEEPROM (Load version/ Default version)  : 1/ 1
+   --------------------------------------------------------
+   Controller Cache Backup Unit Information
+   --------------------------------------------------------
+   Overall Backup Unit Status               : Ready
+   Backup Unit Type                         : AFM-700
+   Supercap Status                          : Ready
+   Temperature                              : 32 C/ 89 F
+   --------------------------------------------------------
+
+Command completed successfully.`
+
+	tool := NewArcconfTool()
+	controller := tool.parseAdapterInfo(output, "1")
+
+	expected := types.RAIDControllerInfo{
+		AdapterID:        1,
+		ToolName:         "Arcconf",
+		Model:            "Adaptec ASR8805",
+		SerialNumber:     "7A4613D8B4C",
+		FirmwareVersion:  "7.11-0 (33556)",
+		DriverVersion:    "1.2-1 (50983)",
+		BIOSVersion:      "7.11-0 (33556)",
+		CacheSize:        1 << 30,
+		Status:           "Optimal",
+		ROCTemperature:   56,
+		NumVirtualDrives: 2,
+		PatrolReadMode:   "Enabled",
+	}
+	if !reflect.DeepEqual(controller, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, controller)
+	}
+}
+
+func TestArcconfTool_ParseTaskStatus(t *testing.T) {
+	output := `Controllers found: 1
+Logical device Task:
+   Logical device                 : 0
+   Task ID                        : 101
+   Current operation              : Verify/Fix
+   Status                         : In Progress
+   Priority                       : High
+   Percentage complete            : 42
+
+Logical device Task:
+   Logical device                 : 2
+   Task ID                        : 102
+   Current operation              : Rebuild
+   Status                         : In Progress
+   Priority                       : High
+   Percentage complete            : 7
+
+Command completed successfully.`
+
+	tool := NewArcconfTool()
+	tasks := tool.parseTaskStatus(output, "1")
+
+	expected := map[string]arcconfTask{
+		"1:0": {Operation: "Verify/Fix", Progress: 42},
+		"1:2": {Operation: "Rebuild", Progress: 7},
+	}
+	if !reflect.DeepEqual(tasks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, tasks)
+	}
+
+	arrays := []types.RAIDInfo{{ArrayID: "1:0"}, {ArrayID: "1:1"}, {ArrayID: "1:2"}}
+	applyTasks(arrays, tasks)
+	if !arrays[0].CheckRunning || arrays[0].ScrubProgress != 42 || arrays[0].Rebuilding {
+		t.Errorf("Expected a verify of 1:0, got %+v", arrays[0])
+	}
+	if arrays[1].CheckRunning || arrays[1].Rebuilding {
+		t.Errorf("Expected no task on 1:1, got %+v", arrays[1])
+	}
+	if !arrays[2].Rebuilding || arrays[2].RebuildProgress != 7 {
+		t.Errorf("Expected a rebuild of 1:2, got %+v", arrays[2])
+	}
+}
+
+func TestArcconfTool_ParsePhysicalDevices(t *testing.T) {
+	output := `Controllers found: 1
+----------------------------------------------------------------------
+Physical Device information
+----------------------------------------------------------------------
+      Channel #0:
+         Device #0
+            Device is a Hard drive
+            State                              : Online
+            Block Size                         : 512 Bytes
+            Transfer Speed                     : SAS 12.0 Gb/s
+            Reported Channel,Device(T:L)       : 0,0(0:0)
+            Vendor                             : SEAGATE
+            Model                              : ST4000NM0025
+            Serial number                      : ZC11A2C1
+            Total Size                         : 3815447 MB
+            Power State                        : Full rpm
+
+         Device Error Counters
+         --------------------------------------------------------
+            Aborted Commands                   : 2
+            Hardware Errors                    : 1
+            Media Failures                     : 5
+            Other Time Out Errors              : 3
+            Predictive Failures                : 1
+         Device #1
+            Device is a Hard drive
+            State                              : Failed
+            Transfer Speed                     : SAS 6.0 Gb/s
+            Model                              : ST4000NM0025
+            Serial number                      : ZC11B7D4
+
+Command completed successfully.`
+
+	tool := NewArcconfTool()
+	disks := tool.parsePhysicalDevices(output, "1")
+	if len(disks) != 2 {
+		t.Fatalf("Expected 2 disks, got %d", len(disks))
+	}
+
+	first := disks[0]
+	if first.Device != "arcconf:1:0" || first.Serial != "ZC11A2C1" || first.Health != "Online" {
+		t.Errorf("Unexpected first disk %+v", first)
+	}
+	// Counters follow a blank line and must still belong to the first device
+	expected := &types.RAIDDriveInfo{
+		ToolName:               "Arcconf",
+		FirmwareState:          "Online",
+		MediaErrorCount:        5,
+		OtherErrorCount:        6,
+		PredictiveFailureCount: 1,
+		LinkSpeed:              "SAS 12.0 Gb/s",
+	}
+	if !reflect.DeepEqual(first.RaidDrive, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, first.RaidDrive)
+	}
+
+	second := disks[1]
+	if second.Health != "Failed" || second.RaidDrive.FirmwareState != "Failed" || second.RaidDrive.MediaErrorCount != 0 {
+		t.Errorf("Unexpected second disk %+v", second.RaidDrive)
+	}
+}
+
+func TestArcconfStates(t *testing.T) {
+	checkStates(t, arcconfDeviceStates, map[string]types.HealthStatus{
+		"Online":              types.HealthStatusOK,
+		"Ready":               types.HealthStatusOK,
+		"Hot Spare":           types.HealthStatusOK,
+		"Global Hot-Spare":    types.HealthStatusOK,
+		"Dedicated Hot-Spare": types.HealthStatusOK,
+		"Raw (Pass Through)":  types.HealthStatusOK,
+		"Rebuilding":          types.HealthStatusWarning,
+		"Failed":              types.HealthStatusCritical,
+		"Missing":             types.HealthStatusCritical,
+		"Offline":             types.HealthStatusCritical,
+	})
+	checkStates(t, arcconfLogicalDeviceStates, map[string]types.RAIDState{
+		"Optimal":      types.RAIDStateOptimal,
+		"OK":           types.RAIDStateOptimal,
+		"Degraded":     types.RAIDStateDegraded,
+		"Suboptimal":   types.RAIDStateDegraded,
+		"Impacted":     types.RAIDStateDegraded,
+		"Rebuilding":   types.RAIDStateDegraded,
+		"Initializing": types.RAIDStateDegraded,
+		"Failed":       types.RAIDStateFailed,
+		"Offline":      types.RAIDStateFailed,
+	})
+	checkStates(t, arcconfLogicalDeviceRebuilds, map[string]bool{
+		"Rebuilding": true,
+	})
+}
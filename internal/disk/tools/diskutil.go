@@ -0,0 +1,280 @@
+package tools
+
+import (
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/statemap"
+	"disk-health-exporter/internal/utils"
+	"disk-health-exporter/pkg/types"
+)
+
+// Ensure DiskutilTool implements the DiskToolInterface
+var _ DiskToolInterface = (*DiskutilTool)(nil)
+
+// DiskutilTool represents the macOS diskutil CLI tool
+type DiskutilTool struct{}
+
+func init() {
+	Register(Registration{
+		Name:      merge.SourceDiskutil,
+		Platforms: []string{PlatformDarwin},
+		New:       func() ToolInterface { return NewDiskutilTool() },
+	})
+}
+
+// diskutilSmartStates translates the SMART status of disks
+var diskutilSmartStates = statemap.New(merge.SourceDiskutil, map[string]types.HealthStatus{
+	"Verified":      types.HealthStatusOK,
+	"Failing":       types.HealthStatusCritical,
+	"Not Supported": types.HealthStatusUnknown,
+})
+
+// NewDiskutilTool creates a new DiskutilTool instance
+func NewDiskutilTool() *DiskutilTool {
+	return &DiskutilTool{}
+}
+
+// IsAvailable checks if diskutil is available on the system
+func (d *DiskutilTool) IsAvailable() bool {
+	return utils.CommandExists("diskutil")
+}
+
+// GetVersion returns the diskutil version. diskutil has no version flag; it
+// ships with the operating system.
+func (d *DiskutilTool) GetVersion() string {
+	if !d.IsAvailable() {
+		return ""
+	}
+	return "system"
+}
+
+// GetName returns the tool name
+func (d *DiskutilTool) GetName() string {
+	return "diskutil"
+}
+
+// GetDisks returns the physical disks known to macOS
+// diskutil list # list all disks and their partitions
+func (d *DiskutilTool) GetDisks() []types.DiskInfo {
+	var disks []types.DiskInfo
+
+	if !d.IsAvailable() {
+		return disks
+	}
+
+	output, err := exec.Command("diskutil", "list").Output()
+	if err != nil {
+		slog.Error("Error listing disks", "tool", "diskutil", "err", err)
+		return disks
+	}
+
+	for _, diskID := range d.parseDiskutilList(string(output)) {
+		info, err := d.info(diskID)
+		if err != nil {
+			slog.Warn("Error getting disk info", "tool", "diskutil", "device", diskID, "err", err)
+			continue
+		}
+
+		// Skip non-physical disks (like disk images, APFS volumes, etc.)
+		if !d.isPhysicalDisk(info) {
+			continue
+		}
+
+		disk := d.parseDiskutilInfo(diskID, info)
+		d.addFilesystemUsage(&disk, diskID)
+		disks = append(disks, disk)
+	}
+
+	return disks
+}
+
+// info returns the diskutil info output for a disk or partition
+// diskutil info IDENTIFIER # show details of a disk or partition
+func (d *DiskutilTool) info(identifier string) (string, error) {
+	output, err := exec.Command("diskutil", "info", identifier).Output()
+	return string(output), err
+}
+
+// parseDiskutilList parses diskutil list output to extract disk identifiers
+func (d *DiskutilTool) parseDiskutilList(output string) []string {
+	var identifiers []string
+
+	// Parse diskutil list output which has format like:
+	// /dev/disk0 (internal, physical):
+	// /dev/disk3 (synthesized):
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "/dev/disk") && strings.Contains(line, ":") {
+			// Extract just the disk identifier (e.g., "disk0" from "/dev/disk0")
+			parts := strings.Fields(line)
+			if len(parts) > 0 {
+				identifiers = append(identifiers, strings.TrimPrefix(parts[0], "/dev/"))
+			}
+		}
+	}
+
+	return identifiers
+}
+
+// isPhysicalDisk checks whether diskutil info output describes a physical disk
+func (d *DiskutilTool) isPhysicalDisk(info string) bool {
+	// Must have "Device Location: Internal" or "Device Location: External" and not be virtual
+	hasDeviceLocation := strings.Contains(info, "Device Location:") &&
+		(strings.Contains(info, "Internal") || strings.Contains(info, "External"))
+
+	// Check if it's a real physical media
+	hasPhysicalMedia := strings.Contains(info, "Media Type:") &&
+		!strings.Contains(info, "Disk Image")
+
+	// Exclude virtual disks and APFS containers
+	isVirtual := strings.Contains(info, "Virtual:                   Yes") ||
+		strings.Contains(info, "APFS Container") ||
+		strings.Contains(info, "synthesized") ||
+		strings.Contains(info, "Disk Image")
+
+	return hasDeviceLocation && hasPhysicalMedia && !isVirtual
+}
+
+// parseDiskutilInfo parses diskutil info output of a physical disk
+func (d *DiskutilTool) parseDiskutilInfo(diskID, info string) types.DiskInfo {
+	disk := types.DiskInfo{
+		Device:    "/dev/" + diskID,
+		Type:      "macos-disk",
+		Interface: "Unknown",
+		Health:    "Unknown",
+	}
+
+	for _, line := range strings.Split(info, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "Device / Media Name":
+			disk.Model = value
+		case "Disk Size":
+			// Extract capacity from "Disk Size: 500.1 GB (500107862016 Bytes) (exactly ...)"
+			if _, bytes, ok := strings.Cut(value, "("); ok {
+				if fields := strings.Fields(bytes); len(fields) > 0 {
+					if capacity, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+						disk.Capacity = capacity
+					}
+				}
+			}
+		case "Protocol":
+			disk.Interface = value
+		case "Solid State":
+			if value == "Yes" {
+				disk.RPM = 0 // SSD
+			}
+		case "Physical Drive":
+			// Extract vendor from strings like "APPLE SSD SM0512F Media"
+			if fields := strings.Fields(value); len(fields) > 0 {
+				disk.Vendor = fields[0]
+			}
+		case "SMART Status":
+			// macOS only reports a pass/fail verdict for internal disks
+			disk.HealthStatus = diskutilSmartStates.Map(value)
+			switch disk.HealthStatus {
+			case types.HealthStatusOK:
+				disk.Health = "OK"
+				disk.SmartEnabled = true
+				disk.SmartHealthy = true
+			case types.HealthStatusCritical:
+				disk.Health = "FAILED"
+				disk.SmartEnabled = true
+				disk.SmartHealthy = false
+			}
+		}
+	}
+
+	return disk
+}
+
+// addFilesystemUsage adds filesystem usage information to a disk using diskutil
+// diskutil list IDENTIFIER # list the partitions of a disk
+// df -k MOUNTPOINT # show filesystem usage in KB
+func (d *DiskutilTool) addFilesystemUsage(disk *types.DiskInfo, diskID string) {
+	// First, check if this disk has any mounted volumes
+	output, err := exec.Command("diskutil", "list", diskID).Output()
+	if err != nil {
+		return
+	}
+
+	// Parse the partition list to find mounted volumes
+	var mountedInfo string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		// Look for partition lines (they start with numbers and contain volume info)
+		if !strings.Contains(line, diskID+"s") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// Check if this partition is mounted
+		if info, err := d.info(fields[1]); err == nil && d.isMounted(info) {
+			mountedInfo = info
+			break
+		}
+	}
+	if mountedInfo == "" {
+		return
+	}
+
+	for _, line := range strings.Split(mountedInfo, "\n") {
+		line = strings.TrimSpace(line)
+		if value, ok := strings.CutPrefix(line, "Mount Point:"); ok {
+			disk.Mountpoint = strings.TrimSpace(value)
+		}
+		if value, ok := strings.CutPrefix(line, "File System Personality:"); ok {
+			disk.Filesystem = strings.TrimSpace(value)
+		}
+	}
+
+	// If we have a mountpoint, get usage stats using df
+	if disk.Mountpoint == "" {
+		return
+	}
+	output, err = exec.Command("df", "-k", disk.Mountpoint).Output()
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) < 2 {
+		return
+	}
+	fields := strings.Fields(lines[1])
+	if len(fields) < 4 {
+		return
+	}
+
+	// df -k output: Filesystem 1K-blocks Used Available Capacity Mounted on
+	used, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return
+	}
+	avail, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return
+	}
+	disk.UsedBytes = used * 1024       // Convert from KB to bytes
+	disk.AvailableBytes = avail * 1024 // Convert from KB to bytes
+	if total := disk.UsedBytes + disk.AvailableBytes; total > 0 {
+		disk.UsagePercentage = float64(disk.UsedBytes) / float64(total) * 100
+	}
+}
+
+// isMounted checks whether diskutil info output describes a mounted partition
+func (d *DiskutilTool) isMounted(info string) bool {
+	return strings.Contains(info, "Mount Point:") &&
+		!strings.Contains(info, "Not applicable (no filesystem)")
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"disk-health-exporter/pkg/types"
+)
+
+const diskutilListOutput = `/dev/disk0 (internal, physical):
+   #:                       TYPE NAME                    SIZE       IDENTIFIER
+   0:      GUID_partition_scheme                        *500.3 GB   disk0
+   1:             Apple_APFS_ISC Container disk1         524.3 MB   disk0s1
+   2:                 Apple_APFS Container disk3         494.4 GB   disk0s2
+
+/dev/disk3 (synthesized):
+   #:                       TYPE NAME                    SIZE       IDENTIFIER
+   0:      APFS Container Scheme -                      +494.4 GB   disk3
+`
+
+const diskutilInfoOutput = `   Device Identifier:         disk0
+   Device Node:               /dev/disk0
+   Whole:                     Yes
+   Part of Whole:             disk0
+   Device / Media Name:       APPLE SSD AP0512Q
+
+   Volume Name:               Not applicable (no file system)
+   Mounted:                   Not applicable (no file system)
+   File System:               None
+
+   Content (IOContent):       GUID_partition_scheme
+   OS Can Be Installed:       No
+   Media Type:                Generic
+   Protocol:                  Apple Fabric
+   SMART Status:              Verified
+
+   Disk Size:                 500.3 GB (500277790720 Bytes) (exactly 122138133 4096-Byte-Units)
+   Device Block Size:         4096 Bytes
+
+   Device Location:           Internal
+   Removable Media:           Fixed
+
+   Solid State:               Yes
+   Virtual:                   No
+`
+
+func TestDiskutilTool_GetName(t *testing.T) {
+	tool := NewDiskutilTool()
+	if tool.GetName() != "diskutil" {
+		t.Errorf("Expected name diskutil, got %s", tool.GetName())
+	}
+}
+
+func TestDiskutilTool_ParseList(t *testing.T) {
+	identifiers := NewDiskutilTool().parseDiskutilList(diskutilListOutput)
+	if expected := []string{"disk0", "disk3"}; !reflect.DeepEqual(identifiers, expected) {
+		t.Errorf("Expected %v, got %v", expected, identifiers)
+	}
+}
+
+func TestDiskutilTool_ParseInfo(t *testing.T) {
+	tool := NewDiskutilTool()
+	if !tool.isPhysicalDisk(diskutilInfoOutput) {
+		t.Fatalf("Expected disk0 to be a physical disk")
+	}
+
+	disk := tool.parseDiskutilInfo("disk0", diskutilInfoOutput)
+	if disk.Device != "/dev/disk0" || disk.Model != "APPLE SSD AP0512Q" || disk.Interface != "Apple Fabric" {
+		t.Errorf("Unexpected identity %+v", disk)
+	}
+	if disk.Capacity != 500277790720 {
+		t.Errorf("Expected capacity 500277790720, got %d", disk.Capacity)
+	}
+	if disk.Health != "OK" || !disk.SmartEnabled || !disk.SmartHealthy {
+		t.Errorf("Expected a verified SMART status, got %q enabled=%v healthy=%v", disk.Health, disk.SmartEnabled, disk.SmartHealthy)
+	}
+}
+
+func TestDiskutilTool_SMARTStatus(t *testing.T) {
+	tests := []struct {
+		status       string
+		health       string
+		healthStatus types.HealthStatus
+		enabled      bool
+	}{
+		{"Verified", "OK", types.HealthStatusOK, true},
+		{"Failing", "FAILED", types.HealthStatusCritical, true},
+		{"Not Supported", "Unknown", types.HealthStatusUnknown, false},
+	}
+	if len(tests) != diskutilSmartStates.Len() {
+		t.Errorf("Expected a test for each of the %d SMART states, got %d", diskutilSmartStates.Len(), len(tests))
+	}
+
+	tool := NewDiskutilTool()
+	for _, tt := range tests {
+		disk := tool.parseDiskutilInfo("disk2", "   SMART Status:              "+tt.status+"\n")
+		if disk.Health != tt.health || disk.HealthStatus != tt.healthStatus || disk.SmartEnabled != tt.enabled {
+			t.Errorf("%s: expected %s (%d) enabled=%v, got %s (%d) enabled=%v", tt.status, tt.health, tt.healthStatus, tt.enabled,
+				disk.Health, disk.HealthStatus, disk.SmartEnabled)
+		}
+	}
+}
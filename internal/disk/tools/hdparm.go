@@ -1,18 +1,31 @@
 package tools
 
 import (
-	"log"
+	"log/slog"
 	"os/exec"
 	"strconv"
 	"strings"
 
+	"disk-health-exporter/internal/disk/merge"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
+// Ensure HdparmTool implements the DiskToolInterface
+var _ DiskToolInterface = (*HdparmTool)(nil)
+
 // HdparmTool represents the hdparm CLI tool
 type HdparmTool struct{}
 
+func init() {
+	Register(Registration{
+		Name:      merge.SourceHdparm,
+		Platforms: []string{PlatformLinux},
+		After:     []string{merge.SourceSmartctl, merge.SourceNvme},
+		New:       func() ToolInterface { return NewHdparmTool() },
+	})
+}
+
 // NewHdparmTool creates a new HdparmTool instance
 func NewHdparmTool() *HdparmTool {
 	return &HdparmTool{}
@@ -49,7 +62,7 @@ func (h *HdparmTool) GetDisks() []types.DiskInfo {
 		return disks
 	}
 
-	log.Printf("Detecting disks using hdparm...")
+	slog.Debug("Detecting disks", "tool", "hdparm")
 
 	// Get list of block devices to check
 	blockDevices := h.getBlockDevices()
@@ -62,7 +75,7 @@ func (h *HdparmTool) GetDisks() []types.DiskInfo {
 		}
 	}
 
-	log.Printf("Found %d disks using hdparm", len(disks))
+	slog.Debug("Found disks", "tool", "hdparm", "disks", len(disks))
 	return disks
 }
 
@@ -106,8 +119,9 @@ func (h *HdparmTool) getDiskInfo(device string) types.DiskInfo {
 	// Use hdparm -I to get detailed ATA information
 	output, err := exec.Command("hdparm", "-I", device).Output()
 	if err != nil {
-		// Device might not support ATA commands or not accessible
-		log.Printf("hdparm -I failed for %s: %v", device, err)
+		// Expected for devices without ATA commands (NVMe, virtual disks), so
+		// only logged when debugging
+		slog.Debug("Error identifying device", "tool", "hdparm", "device", device, "err", err)
 		return types.DiskInfo{}
 	}
 
@@ -144,7 +158,7 @@ func (h *HdparmTool) parseHdparmOutput(disk *types.DiskInfo, output string) {
 			if len(parts) == 2 {
 				// Firmware version could be logged or used for vendor detection
 				firmware := strings.TrimSpace(parts[1])
-				log.Printf("Device %s firmware: %s", disk.Device, firmware)
+				slog.Debug("Detected firmware", "tool", "hdparm", "device", disk.Device, "firmware", firmware)
 			}
 		}
 
@@ -226,6 +240,7 @@ func (h *HdparmTool) parseHdparmOutput(disk *types.DiskInfo, output string) {
 
 	// Set default health status
 	disk.Health = "OK"
+	disk.HealthStatus = types.HealthStatusOK
 	disk.SmartHealthy = true
 }
 
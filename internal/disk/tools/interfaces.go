@@ -47,6 +47,14 @@ type BatteryToolInterface interface {
 	GetBatteryInfo(adapterID string) *types.RAIDBatteryInfo
 }
 
+// ControllerToolInterface defines the interface for tools reporting hardware RAID controllers
+type ControllerToolInterface interface {
+	ToolInterface
+
+	// GetControllers returns information for every controller managed by this tool
+	GetControllers() []types.RAIDControllerInfo
+}
+
 // SoftwareRAIDToolInterface defines the interface for software RAID tools
 type SoftwareRAIDToolInterface interface {
 	ToolInterface
@@ -54,3 +62,11 @@ type SoftwareRAIDToolInterface interface {
 	// GetSoftwareRAIDs returns software RAID information
 	GetSoftwareRAIDs() []types.SoftwareRAIDInfo
 }
+
+// SnapshotToolInterface defines the interface for tools reporting disks and RAID arrays from a single run
+type SnapshotToolInterface interface {
+	ToolInterface
+
+	// GetSnapshot returns the disks and RAID arrays found in one run
+	GetSnapshot() ([]types.DiskInfo, []types.RAIDInfo)
+}
@@ -1,18 +1,30 @@
 package tools
 
 import (
-	"log"
+	"log/slog"
 	"os/exec"
 	"strconv"
 	"strings"
 
+	"disk-health-exporter/internal/disk/merge"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
+// Ensure LsblkTool implements the DiskToolInterface
+var _ DiskToolInterface = (*LsblkTool)(nil)
+
 // LsblkTool represents the lsblk CLI tool
 type LsblkTool struct{}
 
+func init() {
+	Register(Registration{
+		Name:      merge.SourceLsblk,
+		Platforms: []string{PlatformLinux},
+		New:       func() ToolInterface { return NewLsblkTool() },
+	})
+}
+
 // NewLsblkTool creates a new LsblkTool instance
 func NewLsblkTool() *LsblkTool {
 	return &LsblkTool{}
@@ -50,11 +62,11 @@ func (l *LsblkTool) GetDisks() []types.DiskInfo {
 		return disks
 	}
 
-	log.Printf("Detecting disks using lsblk...")
+	slog.Debug("Detecting disks", "tool", "lsblk")
 
 	output, err := exec.Command("lsblk", "-d", "-o", "NAME,SIZE,MODEL,SERIAL,TRAN", "-n").Output()
 	if err != nil {
-		log.Printf("Error running lsblk: %v", err)
+		slog.Error("Error listing block devices", "tool", "lsblk", "err", err)
 		return disks
 	}
 
@@ -91,7 +103,7 @@ func (l *LsblkTool) GetDisks() []types.DiskInfo {
 		}
 	}
 
-	log.Printf("Found %d disks using lsblk", len(disks))
+	slog.Debug("Found disks", "tool", "lsblk", "disks", len(disks))
 	return disks
 }
 
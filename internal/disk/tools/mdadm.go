@@ -1,20 +1,45 @@
 package tools
 
 import (
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/statemap"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
+// Ensure MdadmTool implements the SoftwareRAIDToolInterface
+var _ SoftwareRAIDToolInterface = (*MdadmTool)(nil)
+
 // MdadmTool represents the mdadm CLI tool for software RAID
 type MdadmTool struct{}
 
+func init() {
+	Register(Registration{
+		Name:      merge.SourceMdadm,
+		Platforms: []string{PlatformLinux},
+		New:       func() ToolInterface { return NewMdadmTool() },
+	})
+}
+
+// mdadmArrayStates translates the array states of /proc/mdstat and mdadm
+var mdadmArrayStates = statemap.New(merge.SourceMdadm, map[string]types.RAIDState{
+	"clean":                  types.RAIDStateOptimal,
+	"active":                 types.RAIDStateOptimal,
+	"active(auto-read-only)": types.RAIDStateOptimal,
+	"degraded":               types.RAIDStateDegraded,
+	"recovering":             types.RAIDStateDegraded,
+	"resyncing":              types.RAIDStateDegraded,
+	"failed":                 types.RAIDStateFailed,
+	"inactive":               types.RAIDStateFailed,
+})
+
 // NewMdadmTool creates a new MdadmTool instance
 func NewMdadmTool() *MdadmTool {
 	return &MdadmTool{}
@@ -51,12 +76,12 @@ func (m *MdadmTool) GetSoftwareRAIDs() []types.SoftwareRAIDInfo {
 		return softwareRAIDs
 	}
 
-	log.Printf("Detecting software RAID arrays using mdadm...")
+	slog.Debug("Detecting software RAID arrays", "tool", "mdadm")
 
 	// Get list of RAID devices from /proc/mdstat
 	mdstat, err := os.ReadFile("/proc/mdstat")
 	if err != nil {
-		log.Printf("Error reading /proc/mdstat: %v", err)
+		slog.Error("Error reading /proc/mdstat", "tool", "mdadm", "err", err)
 		return softwareRAIDs
 	}
 
@@ -83,13 +108,14 @@ func (m *MdadmTool) GetSoftwareRAIDs() []types.SoftwareRAIDInfo {
 			if len(parts) >= 4 {
 				currentRAID.Device = "/dev/" + parts[0]
 				currentRAID.State = parts[2] // active, inactive, etc.
+				currentRAID.Status = mdadmArrayStates.Map(currentRAID.State)
 				currentRAID.Level = parts[3] // raid0, raid1, etc.
 
 				// Extract device list
 				for i := 4; i < len(parts); i++ {
 					device := parts[i]
-					// Remove bracketed information [0], [1], etc.
-					deviceName := regexp.MustCompile(`\[[^\]]*\]`).ReplaceAllString(device, "")
+					// Remove the role number [0], [1], etc. and the (F) and (S) markers
+					deviceName := regexp.MustCompile(`\[[^\]]*\]|\([A-Z]\)`).ReplaceAllString(device, "")
 					deviceName = "/dev/" + deviceName
 
 					if strings.Contains(device, "(F)") {
@@ -143,7 +169,7 @@ func (m *MdadmTool) GetSoftwareRAIDs() []types.SoftwareRAIDInfo {
 		softwareRAIDs = append(softwareRAIDs, currentRAID)
 	}
 
-	log.Printf("Found %d software RAID arrays using mdadm", len(softwareRAIDs))
+	slog.Debug("Found software RAID arrays", "tool", "mdadm", "raid_arrays", len(softwareRAIDs))
 	return softwareRAIDs
 }
 
@@ -152,7 +178,7 @@ func (m *MdadmTool) GetSoftwareRAIDs() []types.SoftwareRAIDInfo {
 func (m *MdadmTool) enrichSoftwareRAIDInfo(raid *types.SoftwareRAIDInfo) {
 	output, err := exec.Command("mdadm", "--detail", raid.Device).Output()
 	if err != nil {
-		log.Printf("Error getting mdadm details for %s: %v", raid.Device, err)
+		slog.Warn("Error getting array details", "tool", "mdadm", "device", raid.Device, "err", err)
 		return
 	}
 
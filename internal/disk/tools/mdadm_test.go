@@ -0,0 +1,20 @@
+package tools
+
+import (
+	"testing"
+
+	"disk-health-exporter/pkg/types"
+)
+
+func TestMdadmStates(t *testing.T) {
+	checkStates(t, mdadmArrayStates, map[string]types.RAIDState{
+		"clean":                  types.RAIDStateOptimal,
+		"active":                 types.RAIDStateOptimal,
+		"active(auto-read-only)": types.RAIDStateOptimal,
+		"degraded":               types.RAIDStateDegraded,
+		"recovering":             types.RAIDStateDegraded,
+		"resyncing":              types.RAIDStateDegraded,
+		"failed":                 types.RAIDStateFailed,
+		"inactive":               types.RAIDStateFailed,
+	})
+}
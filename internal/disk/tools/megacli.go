@@ -2,21 +2,88 @@ package tools
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/statemap"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
+// Ensure MegaCLITool implements the RAID, battery and controller interfaces
+var (
+	_ RAIDToolInterface       = (*MegaCLITool)(nil)
+	_ BatteryToolInterface    = (*MegaCLITool)(nil)
+	_ ControllerToolInterface = (*MegaCLITool)(nil)
+)
+
 // MegaCLITool represents the MegaCLI tool
 type MegaCLITool struct {
 	command string // "megacli" or "MegaCli64"
 }
 
+func init() {
+	Register(Registration{
+		Name:      merge.SourceMegaCLI,
+		Platforms: []string{PlatformLinux, PlatformWindows},
+		After:     []string{merge.SourceSmartctl, merge.SourceNvme, merge.SourceHdparm},
+		New:       func() ToolInterface { return NewMegaCLITool() },
+	})
+}
+
+// megacliDriveStates translates the firmware state of physical drives
+var megacliDriveStates = statemap.New(merge.SourceMegaCLI, map[string]types.HealthStatus{
+	"Online":                        types.HealthStatusOK,
+	"Online, Spun Up":               types.HealthStatusOK,
+	"Online, Spun Down":             types.HealthStatusOK,
+	"Hotspare":                      types.HealthStatusOK,
+	"Hotspare, Spun Up":             types.HealthStatusOK,
+	"Hotspare, Spun Down":           types.HealthStatusOK,
+	"Unconfigured(good)":            types.HealthStatusOK,
+	"Unconfigured(good), Spun Up":   types.HealthStatusOK,
+	"Unconfigured(good), Spun Down": types.HealthStatusOK,
+	"JBOD":                          types.HealthStatusOK,
+	"Rebuild":                       types.HealthStatusWarning,
+	"Copyback":                      types.HealthStatusWarning,
+	"Unconfigured(bad)":             types.HealthStatusCritical,
+	"Failed":                        types.HealthStatusCritical,
+	"Offline":                       types.HealthStatusCritical,
+	"Missing":                       types.HealthStatusCritical,
+})
+
+// megacliDriveRoles translates the firmware state of physical drives into their RAID role
+var megacliDriveRoles = statemap.New(merge.SourceMegaCLI, map[string]string{
+	"Online":                        "active",
+	"Online, Spun Up":               "active",
+	"Online, Spun Down":             "active",
+	"Hotspare":                      "hot_spare",
+	"Hotspare, Spun Up":             "hot_spare",
+	"Hotspare, Spun Down":           "hot_spare",
+	"Unconfigured(good)":            "unconfigured",
+	"Unconfigured(good), Spun Up":   "unconfigured",
+	"Unconfigured(good), Spun Down": "unconfigured",
+	"Unconfigured(bad)":             "unconfigured",
+	"JBOD":                          "unconfigured",
+	"Rebuild":                       "rebuilding",
+	"Copyback":                      "rebuilding",
+	"Failed":                        "failed",
+	"Offline":                       "failed",
+	"Missing":                       "failed",
+})
+
+// megacliArrayStates translates the state of virtual drives
+var megacliArrayStates = statemap.New(merge.SourceMegaCLI, map[string]types.RAIDState{
+	"Optimal":            types.RAIDStateOptimal,
+	"Partially Degraded": types.RAIDStateDegraded,
+	"Degraded":           types.RAIDStateDegraded,
+	"Offline":            types.RAIDStateFailed,
+	"Failed":             types.RAIDStateFailed,
+})
+
 // NewMegaCLITool creates a new MegaCLITool instance
 func NewMegaCLITool() *MegaCLITool {
 	tool := &MegaCLITool{}
@@ -66,7 +133,7 @@ func (m *MegaCLITool) GetRAIDArrays() []types.RAIDInfo {
 	// Get RAID array information
 	output, err := exec.Command(m.command, "-LDInfo", "-Lall", "-aALL", "-NoLog").Output()
 	if err != nil {
-		log.Printf("Error executing MegaCLI for array info: %v", err)
+		slog.Error("Error getting array info", "tool", "megacli", "err", err)
 		return raidArrays
 	}
 
@@ -125,7 +192,7 @@ func (m *MegaCLITool) GetRAIDArrays() []types.RAIDInfo {
 			// Extract state
 			if state, ok := parseKeyValue(line, "State"); ok {
 				currentArray.State = state
-				currentArray.Status = utils.GetRaidStatusValue(state)
+				currentArray.Status = megacliArrayStates.Map(state)
 				currentArray.Type = "hardware"
 				currentArray.Controller = "MegaCLI"
 
@@ -142,6 +209,10 @@ func (m *MegaCLITool) GetRAIDArrays() []types.RAIDInfo {
 		}
 	}
 
+	if len(raidArrays) > 0 {
+		m.applyConsistencyCheckProgress(raidArrays)
+	}
+
 	spareDisks := m.getUnassignedPhysicalDisks()
 	numSpareDrives := 0
 	numFailedDrives := 0
@@ -174,7 +245,7 @@ func (m *MegaCLITool) GetRAIDArrays() []types.RAIDInfo {
 			ArrayID:         "spare",
 			RaidLevel:       "spare-only",
 			State:           "spare",
-			Status:          1, // OK status
+			Status:          types.RAIDStateOptimal,
 			Size:            0,
 			NumDrives:       numSpareDrives,
 			NumActiveDrives: 0,
@@ -189,6 +260,21 @@ func (m *MegaCLITool) GetRAIDArrays() []types.RAIDInfo {
 	return raidArrays
 }
 
+// applyConsistencyCheckProgress sets the progress of running consistency checks on the arrays
+// megacli -LDCC -ShowProg -LALL -aALL -NoLog # get consistency check progress for all logical drives
+func (m *MegaCLITool) applyConsistencyCheckProgress(raidArrays []types.RAIDInfo) {
+	output, err := exec.Command(m.command, "-LDCC", "-ShowProg", "-LALL", "-aALL", "-NoLog").Output()
+	if err != nil {
+		slog.Error("Error getting consistency check progress", "tool", "megacli", "err", err)
+		return
+	}
+
+	progress := m.parseConsistencyCheckProgress(string(output))
+	for i := range raidArrays {
+		raidArrays[i].ScrubProgress, raidArrays[i].CheckRunning = progress[raidArrays[i].ArrayID]
+	}
+}
+
 // GetRAIDDisks returns disk information from RAID arrays with utilization calculations
 func (m *MegaCLITool) GetRAIDDisks() []types.DiskInfo {
 	var disks []types.DiskInfo
@@ -203,6 +289,9 @@ func (m *MegaCLITool) GetRAIDDisks() []types.DiskInfo {
 	// Get detailed physical disk information with RAID array mapping
 	disks = m.getAllPhysicalDisksForArrays(raidArrays)
 
+	// Read full SMART data of each drive through the controller
+	enrichWithPassthroughSMART(disks)
+
 	return disks
 }
 
@@ -215,7 +304,7 @@ func (m *MegaCLITool) getAllPhysicalDisksForArrays(raidArrays []types.RAIDInfo)
 	// Get the LdPdInfo output once for all arrays (more efficient)
 	ldPdOutput, err := exec.Command(m.command, "-LdPdInfo", "-aALL", "-NoLog").Output()
 	if err != nil {
-		log.Printf("MegaCLI LdPdInfo command failed: %v", err)
+		slog.Error("Error getting array members", "tool", "megacli", "err", err)
 		return disks
 	}
 
@@ -261,13 +350,7 @@ func (m *MegaCLITool) finalizeLdPdInfoDisk(disk *types.DiskInfo, arrayID string,
 	disk.RaidArrayID = arrayID
 	disk.Type = "raid"
 
-	// Determine role based on health status
-	disk.RaidRole = determineRaidRole(disk.Health)
-
-	// Set global spare flag for hot spares
-	if disk.RaidRole == "hot_spare" {
-		disk.IsGlobalSpare = strings.Contains(strings.ToLower(disk.Health), "global")
-	}
+	disk.RaidRole = megacliRaidRole(disk.Health)
 }
 
 // getUnassignedPhysicalDisks gets physical disks that are not assigned to any array (hot spares, unconfigured, etc.)
@@ -278,35 +361,54 @@ func (m *MegaCLITool) getUnassignedPhysicalDisks() []types.DiskInfo {
 	// Get all physical disks
 	output, err := exec.Command(m.command, "-PDList", "-aALL", "-NoLog").Output()
 	if err != nil {
-		log.Printf("Error executing MegaCLI for unassigned disk info: %v", err)
+		slog.Error("Error getting unassigned disk info", "tool", "megacli", "err", err)
 		return disks
 	}
 
-	lines := strings.Split(string(output), "\n")
+	return m.parsePDListOutput(string(output))
+}
+
+// parsePDListOutput parses -PDList output and returns the disks that are not part of an active array.
+// Each disk section starts at "Enclosure Device ID"; fields such as Shield Counter, Foreign State and
+// Link Speed follow the firmware state, so a disk is only complete when the next section begins.
+func (m *MegaCLITool) parsePDListOutput(output string) []types.DiskInfo {
+	var disks []types.DiskInfo
 	var currentDisk types.DiskInfo
 	var enclosure, slot string
+	adapter := 0
+
+	finishDisk := func() {
+		if currentDisk.Device != "" {
+			m.setPassthrough(&currentDisk, adapter)
+			// Members of an array are reported with it; every other role, unknown
+			// included, is an unassigned disk (hot spare, unconfigured, failed, etc.)
+			switch megacliRaidRole(currentDisk.Health) {
+			case "active", "rebuilding":
+			default:
+				m.finalizeUnassignedDisk(&currentDisk)
+				disks = append(disks, currentDisk)
+			}
+		}
+		currentDisk = types.DiskInfo{} // Reset for next disk
+		enclosure = ""
+		slot = ""
+	}
 
-	for _, line := range lines {
+	for _, line := range strings.Split(output, "\n") {
 		line = strings.TrimSpace(line)
 
+		if strings.HasPrefix(line, "Enclosure Device ID") {
+			finishDisk()
+		}
+		if value, ok := strings.CutPrefix(line, "Adapter #"); ok {
+			finishDisk()
+			adapter, _ = strconv.Atoi(value)
+		}
+
 		// Parse disk information line by line
 		m.parsePhysicalDiskLine(line, &currentDisk, &enclosure, &slot)
-
-		// Check if this is the end of a disk section and if disk is unassigned
-		if strings.Contains(line, "Firmware state:") && currentDisk.Device != "" {
-			// Check if this disk is not part of an active array (hot spare, unconfigured, etc.)
-			state := strings.ToLower(currentDisk.Health)
-			if strings.Contains(state, "hotspare") || strings.Contains(state, "spare") ||
-				strings.Contains(state, "unconfigured") || strings.Contains(state, "jbod") ||
-				strings.Contains(state, "failed") {
-				m.finalizeUnassignedDisk(&currentDisk)
-				disks = append(disks, currentDisk)
-			}
-			currentDisk = types.DiskInfo{} // Reset for next disk
-			enclosure = ""
-			slot = ""
-		}
 	}
+	finishDisk()
 
 	return disks
 }
@@ -335,6 +437,8 @@ func (m *MegaCLITool) parsePhysicalDiskLine(line string, currentDisk *types.Disk
 		if temp, tempOk := parseTemperature(value); tempOk {
 			currentDisk.Temperature = temp
 		}
+	} else if value, ok := parseKeyValue(line, "PD Type"); ok {
+		currentDisk.Interface = value
 	} else if strings.Contains(line, "Hotspare Information:") {
 		// This indicates the disk is a hot spare
 		currentDisk.RaidRole = "hot_spare"
@@ -348,33 +452,61 @@ func (m *MegaCLITool) parsePhysicalDiskLine(line string, currentDisk *types.Disk
 		}
 	} else if value, ok := parseKeyValue(line, "Firmware state"); ok {
 		currentDisk.Health = value
+		currentDisk.HealthStatus = megacliDriveStates.Map(value)
 		currentDisk.Type = "raid"
+		m.raidDriveInfo(currentDisk).FirmwareState = value
+	} else {
+		m.parseRAIDDriveLine(line, currentDisk)
+	}
+}
+
+// raidDriveInfo returns the controller drive details of a disk, creating them on first use
+func (m *MegaCLITool) raidDriveInfo(disk *types.DiskInfo) *types.RAIDDriveInfo {
+	if disk.RaidDrive == nil {
+		disk.RaidDrive = &types.RAIDDriveInfo{ToolName: "MegaCLI"}
+	}
+	return disk.RaidDrive
+}
+
+// setPassthrough addresses the drive for smartctl by the adapter number and the device ID MegaCLI reports
+func (m *MegaCLITool) setPassthrough(disk *types.DiskInfo, adapter int) {
+	if deviceID, err := strconv.Atoi(disk.Device); err == nil {
+		setMegaRAIDPassthrough(disk, adapter, deviceID)
 	}
 }
 
+// parseRAIDDriveLine parses the per-drive error counters, foreign state and link speeds
+// reported in -PDList and -LdPdInfo output. It returns false if the line holds none of them.
+func (m *MegaCLITool) parseRAIDDriveLine(line string, disk *types.DiskInfo) bool {
+	if value, ok := parseKeyValue(line, "Media Error Count"); ok {
+		m.raidDriveInfo(disk).MediaErrorCount, _ = strconv.ParseInt(value, 10, 64)
+	} else if value, ok := parseKeyValue(line, "Other Error Count"); ok {
+		m.raidDriveInfo(disk).OtherErrorCount, _ = strconv.ParseInt(value, 10, 64)
+	} else if value, ok := parseKeyValue(line, "Predictive Failure Count"); ok {
+		m.raidDriveInfo(disk).PredictiveFailureCount, _ = strconv.ParseInt(value, 10, 64)
+	} else if value, ok := parseKeyValue(line, "Shield Counter"); ok {
+		m.raidDriveInfo(disk).ShieldCounter, _ = strconv.ParseInt(value, 10, 64)
+	} else if value, ok := parseKeyValue(line, "Foreign State"); ok {
+		m.raidDriveInfo(disk).ForeignState = value
+	} else if value, ok := parseKeyValue(line, "Device Speed"); ok {
+		m.raidDriveInfo(disk).DeviceSpeed = value
+	} else if value, ok := parseKeyValue(line, "Link Speed"); ok {
+		m.raidDriveInfo(disk).LinkSpeed = value
+	} else {
+		return false
+	}
+	return true
+}
+
 // finalizeRAIDDisk finalizes a RAID disk with array-specific information
 func (m *MegaCLITool) finalizeRAIDDisk(disk *types.DiskInfo, raidArray types.RAIDInfo) {
 	disk.RaidArrayID = raidArray.ArrayID
 	disk.Type = "raid"
 
-	// Determine role based on health status
-	healthLower := strings.ToLower(disk.Health)
-	switch {
-	case strings.Contains(healthLower, "online"):
-		disk.RaidRole = "active"
-		m.calculateDiskUtilization(disk, &raidArray)
-	case strings.Contains(healthLower, "rebuilding"):
-		disk.RaidRole = "rebuilding"
-		m.calculateDiskUtilization(disk, &raidArray)
-	case strings.Contains(healthLower, "failed") || strings.Contains(healthLower, "fail"):
-		disk.RaidRole = "failed"
-		disk.UsedBytes = 0
-		disk.AvailableBytes = 0
-		disk.UsagePercentage = 0.0
-		disk.Mountpoint = "FAILED"
-		disk.Filesystem = "Failed-Drive"
-	default:
-		disk.RaidRole = "unknown"
+	disk.RaidRole = megacliRaidRole(disk.Health)
+	if disk.RaidRole == "failed" {
+		setDiskUtilization(disk, 0, 0, 0.0, "FAILED", "Failed-Drive")
+	} else {
 		m.calculateDiskUtilization(disk, &raidArray)
 	}
 }
@@ -383,28 +515,21 @@ func (m *MegaCLITool) finalizeRAIDDisk(disk *types.DiskInfo, raidArray types.RAI
 func (m *MegaCLITool) finalizeUnassignedDisk(disk *types.DiskInfo) {
 	disk.Type = "raid"
 
-	// Determine role based on health status
-	disk.RaidRole = determineRaidRole(disk.Health)
+	disk.RaidRole = megacliRaidRole(disk.Health)
 
 	// Set utilization based on role
-	switch disk.RaidRole {
-	case "hot_spare":
+	switch {
+	case disk.RaidRole == "hot_spare":
 		disk.IsGlobalSpare = true
 		setDiskUtilization(disk, 0, disk.Capacity, 0.0, "SPARE", "Hot-Spare")
-	case "unconfigured":
+	case statemap.Normalize(disk.Health) == "jbod":
+		setDiskUtilization(disk, 0, disk.Capacity, 0.0, "JBOD", "JBOD")
+	case disk.RaidRole == "unconfigured":
 		setDiskUtilization(disk, 0, disk.Capacity, 0.0, "UNCONFIGURED", "Unconfigured")
-	case "failed":
+	case disk.RaidRole == "failed":
 		setDiskUtilization(disk, 0, 0, 0.0, "FAILED", "Failed-Drive")
 	default:
-		// Handle JBOD case specifically
-		healthLower := strings.ToLower(disk.Health)
-		if strings.Contains(healthLower, "jbod") {
-			disk.RaidRole = "unconfigured"
-			setDiskUtilization(disk, 0, disk.Capacity, 0.0, "JBOD", "JBOD")
-		} else {
-			// Unknown state
-			setDiskUtilization(disk, 0, 0, 0.0, "UNKNOWN", "Unknown")
-		}
+		setDiskUtilization(disk, 0, 0, 0.0, "UNKNOWN", "Unknown")
 	}
 }
 
@@ -457,7 +582,7 @@ func (m *MegaCLITool) GetBatteryInfo(adapterID string) *types.RAIDBatteryInfo {
 	// Get battery information
 	output, err := exec.Command(m.command, "-AdpBbuCmd", "-a"+adapterID).Output()
 	if err != nil {
-		log.Printf("Error executing MegaCLI for battery info: %v", err)
+		slog.Error("Error getting battery info", "tool", "megacli", "controller", adapterID, "err", err)
 		return nil
 	}
 
@@ -647,6 +772,224 @@ func (m *MegaCLITool) GetUnconfiguredDisks() []types.DiskInfo {
 	return unconfiguredDisks
 }
 
+// GetControllers returns controller versions, status and health counters for all adapters
+// megacli -AdpAllInfo -aALL -NoLog # get adapter information for all adapters
+// megacli -CfgForeign -Scan -aALL -NoLog # scan all adapters for foreign configurations
+// megacli -AdpPR -Info -aALL -NoLog # get patrol read mode, state and schedule for all adapters
+func (m *MegaCLITool) GetControllers() []types.RAIDControllerInfo {
+	if !m.IsAvailable() {
+		return nil
+	}
+
+	output, err := exec.Command(m.command, "-AdpAllInfo", "-aALL", "-NoLog").Output()
+	if err != nil {
+		slog.Error("Error getting adapter info", "tool", "megacli", "err", err)
+		return nil
+	}
+	controllers := m.parseAdapterInfo(string(output))
+
+	// MegaCLI does not report the driver version, so it is read from the kernel module
+	driverVersion := readModuleVersion("megaraid_sas")
+	for i := range controllers {
+		controllers[i].DriverVersion = driverVersion
+	}
+
+	output, err = exec.Command(m.command, "-CfgForeign", "-Scan", "-aALL", "-NoLog").Output()
+	if err != nil {
+		slog.Error("Error getting foreign configuration info", "tool", "megacli", "err", err)
+	} else {
+		foreign := m.parseForeignConfigScan(string(output))
+		for i := range controllers {
+			controllers[i].ForeignConfigs = foreign[controllers[i].AdapterID]
+		}
+	}
+
+	output, err = exec.Command(m.command, "-AdpPR", "-Info", "-aALL", "-NoLog").Output()
+	if err != nil {
+		slog.Error("Error getting patrol read info", "tool", "megacli", "err", err)
+	} else {
+		patrolRead := m.parsePatrolReadInfo(string(output))
+		for i := range controllers {
+			if pr, ok := patrolRead[controllers[i].AdapterID]; ok {
+				controllers[i].PatrolReadMode = pr.PatrolReadMode
+				controllers[i].PatrolReadState = pr.PatrolReadState
+				controllers[i].PatrolReadIterations = pr.PatrolReadIterations
+				controllers[i].PatrolReadNextRun = pr.PatrolReadNextRun
+			}
+		}
+	}
+
+	return controllers
+}
+
+// parsePatrolReadInfo parses the output of megacli -AdpPR -Info -aALL -NoLog.
+// Only the patrol read fields of the returned controllers are set.
+func (m *MegaCLITool) parsePatrolReadInfo(output string) map[int]types.RAIDControllerInfo {
+	patrolRead := make(map[int]types.RAIDControllerInfo)
+	adapterRe := regexp.MustCompile(`^Adapter (\d+): Patrol Read Information`)
+
+	adapterID := -1
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		if matches := adapterRe.FindStringSubmatch(line); matches != nil {
+			adapterID, _ = strconv.Atoi(matches[1])
+			patrolRead[adapterID] = types.RAIDControllerInfo{AdapterID: adapterID}
+			continue
+		}
+		if adapterID < 0 {
+			continue
+		}
+
+		pr := patrolRead[adapterID]
+		if value, ok := parseKeyValue(line, "Patrol Read Mode"); ok {
+			pr.PatrolReadMode = value
+		} else if value, ok := parseKeyValue(line, "Number of iterations completed"); ok {
+			pr.PatrolReadIterations, _ = strconv.ParseInt(value, 10, 64)
+		} else if value, ok := parseKeyValue(line, "Next start time"); ok {
+			pr.PatrolReadNextRun = parseMegaRAIDTime(value)
+		} else if value, ok := parseKeyValue(line, "Current State"); ok {
+			pr.PatrolReadState = value
+		}
+		patrolRead[adapterID] = pr
+	}
+
+	return patrolRead
+}
+
+// parseConsistencyCheckProgress parses the output of megacli -LDCC -ShowProg -LALL -aALL -NoLog
+// into the progress of running consistency checks, keyed by virtual drive number
+func (m *MegaCLITool) parseConsistencyCheckProgress(output string) map[string]int {
+	progress := make(map[string]int)
+	re := regexp.MustCompile(`Check Consistency on VD #(\d+) .*Completed (\d+)%`)
+	for _, matches := range re.FindAllStringSubmatch(output, -1) {
+		percent, _ := strconv.Atoi(matches[2])
+		progress[matches[1]] = percent
+	}
+	return progress
+}
+
+// parseAdapterInfo parses the output of megacli -AdpAllInfo -aALL -NoLog.
+// MegaCLI has no overall controller status, so it is derived from the
+// "Device Present" counters: degraded or offline virtual drives and critical
+// or failed disks make the controller need attention.
+func (m *MegaCLITool) parseAdapterInfo(output string) []types.RAIDControllerInfo {
+	var controllers []types.RAIDControllerInfo
+	var current *types.RAIDControllerInfo
+	var section, previousLine string
+	var problems int
+
+	finalize := func() {
+		if current == nil {
+			return
+		}
+		current.Status = "Optimal"
+		if problems > 0 {
+			current.Status = "Needs Attention"
+		}
+		controllers = append(controllers, *current)
+	}
+
+	adapterRe := regexp.MustCompile(`^Adapter #(\d+)`)
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if matches := adapterRe.FindStringSubmatch(line); matches != nil {
+			finalize()
+			adapterID, _ := strconv.Atoi(matches[1])
+			current = &types.RAIDControllerInfo{AdapterID: adapterID, ToolName: "MegaCLI"}
+			section, previousLine, problems = "", "", 0
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		// Section titles are underlined with "="
+		if strings.HasPrefix(line, "====") {
+			section = previousLine
+			continue
+		}
+		previousLine = line
+
+		if section == "Pending Images in Flash" {
+			// Any entry other than "None" before the next section title is a pending image
+			nextIsUnderline := i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "====")
+			if line != "" && line != "None" && !nextIsUnderline {
+				current.PendingFlashImage = true
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "Product Name":
+			current.Model = value
+		case "Serial No":
+			current.SerialNumber = value
+		case "FW Package Build":
+			current.FirmwarePackage = value
+		case "FW Version":
+			current.FirmwareVersion = value
+		case "BIOS Version":
+			current.BIOSVersion = value
+		case "Memory Size":
+			current.CacheSize = utils.ParseSizeToBytes(value)
+		case "Alarm":
+			// Only the hardware configuration reports whether an alarm is fitted
+			if section == "HW Configuration" {
+				current.AlarmState = value
+			}
+		case "ROC temperature":
+			if temperature, ok := extractNumericValue(value, "degree"); ok {
+				current.ROCTemperature = temperature
+			}
+		case "Memory Correctable Errors":
+			current.MemoryCorrectableErrors, _ = strconv.ParseInt(value, 10, 64)
+		case "Memory Uncorrectable Errors":
+			current.MemoryUncorrectableErrors, _ = strconv.ParseInt(value, 10, 64)
+		}
+
+		if section == "Device Present" {
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "Virtual Drives":
+				current.NumVirtualDrives = count
+			case "Disks":
+				current.NumPhysicalDrives = count
+			case "Degraded", "Offline", "Critical Disks", "Failed Disks":
+				problems += count
+			}
+		}
+	}
+	finalize()
+
+	return controllers
+}
+
+// parseForeignConfigScan parses the output of megacli -CfgForeign -Scan -aALL -NoLog
+// into the number of foreign configurations per adapter
+func (m *MegaCLITool) parseForeignConfigScan(output string) map[int]int {
+	foreign := make(map[int]int)
+	re := regexp.MustCompile(`There (?:are|is) (\d+) foreign configuration\(s\) on controller (\d+)`)
+	for _, matches := range re.FindAllStringSubmatch(output, -1) {
+		count, _ := strconv.Atoi(matches[1])
+		adapterID, _ := strconv.Atoi(matches[2])
+		foreign[adapterID] = count
+	}
+	return foreign
+}
+
 // parseLdPdInfoOutputForAllArrays parses the output of MegaCli -LdPdInfo -aALL -NoLog for all target arrays
 // (This function processes output from: megacli -LdPdInfo -aALL -NoLog # get detailed logical and physical drive info)
 func (m *MegaCLITool) parseLdPdInfoOutputForAllArrays(output string, targetArrays map[string]bool) []types.DiskInfo {
@@ -659,10 +1002,17 @@ func (m *MegaCLITool) parseLdPdInfoOutputForAllArrays(output string, targetArray
 	var currentDisk types.DiskInfo
 	var enclosure, slot string
 	inPhysicalDiskSection := false
+	adapter := 0
 
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 
+		if value, ok := strings.CutPrefix(line, "Adapter #"); ok {
+			adapter, _ = strconv.Atoi(value)
+			inTargetArray = false
+			continue
+		}
+
 		// Detect logical drive sections
 		if strings.HasPrefix(line, "Virtual Drive:") || strings.Contains(line, "Virtual Drive") {
 			// Reset state for new logical drive
@@ -714,8 +1064,12 @@ func (m *MegaCLITool) parseLdPdInfoOutputForAllArrays(output string, targetArray
 			currentDisk.Device = value
 		} else if value, ok := parseKeyValue(line, "Inquiry Data"); ok {
 			currentDisk.Model = m.extractModelFromInquiry(value)
+		} else if value, ok := parseKeyValue(line, "PD Type"); ok {
+			currentDisk.Interface = value
 		} else if value, ok := parseKeyValue(line, "Firmware state"); ok {
 			currentDisk.Health = value
+			currentDisk.HealthStatus = megacliDriveStates.Map(value)
+			m.raidDriveInfo(&currentDisk).FirmwareState = value
 		} else if value, ok := parseKeyValue(line, "Coerced Size"); ok {
 			sizeStr := cleanSizeString(value)
 			currentDisk.Capacity = utils.ParseSizeToBytes(sizeStr)
@@ -725,6 +1079,8 @@ func (m *MegaCLITool) parseLdPdInfoOutputForAllArrays(output string, targetArray
 			if temp, tempOk := parseTemperature(value); tempOk {
 				currentDisk.Temperature = temp
 			}
+		} else {
+			m.parseRAIDDriveLine(line, &currentDisk)
 		}
 
 		// Check if we've reached the end of a physical disk section
@@ -736,6 +1092,10 @@ func (m *MegaCLITool) parseLdPdInfoOutputForAllArrays(output string, targetArray
 			if nextLine != "" && strings.Contains(nextLine, "PD:") && strings.Contains(nextLine, "Information") {
 				isEndOfDisk = true
 			}
+			// The last disk of a virtual drive ends where the next virtual drive or adapter begins
+			if strings.HasPrefix(nextLine, "Virtual Drive:") || strings.HasPrefix(nextLine, "Adapter #") {
+				isEndOfDisk = true
+			}
 		} else if i == len(lines)-1 {
 			// End of file
 			isEndOfDisk = true
@@ -746,6 +1106,7 @@ func (m *MegaCLITool) parseLdPdInfoOutputForAllArrays(output string, targetArray
 			if enclosure != "" && slot != "" && currentDisk.Device != "" {
 				// Use the existing finalization method to properly set all disk properties
 				m.finalizeLdPdInfoDisk(&currentDisk, currentLogicalDrive, enclosure, slot)
+				m.setPassthrough(&currentDisk, adapter)
 
 				// Only add if we haven't processed this disk yet
 				diskKey := generateDiskKey(currentDisk)
@@ -776,24 +1137,14 @@ func parseKeyValue(line, key string) (string, bool) {
 	return "", false
 }
 
-// determineRaidRole determines RAID role based on health status
-func determineRaidRole(health string) string {
-
-	healthLower := strings.ToLower(health)
-	switch {
-	case strings.Contains(healthLower, "hotspare") || strings.Contains(healthLower, "spare"):
-		return "hot_spare"
-	case strings.Contains(healthLower, "online") || strings.Contains(healthLower, "optimal"):
-		return "active"
-	case strings.Contains(healthLower, "rebuild"):
-		return "rebuilding"
-	case strings.Contains(healthLower, "failed") || strings.Contains(healthLower, "offline"):
-		return "failed"
-	case strings.Contains(healthLower, "unconfigured"):
-		return "unconfigured"
-	default:
-		return "unknown"
+// megacliRaidRole returns the RAID role of a drive firmware state, or
+// "unknown" for states missing from megacliDriveRoles. Unmapped states are
+// recorded by megacliDriveStates, which sees the same strings.
+func megacliRaidRole(state string) string {
+	if role, ok := megacliDriveRoles.Lookup(state); ok {
+		return role
 	}
+	return "unknown"
 }
 
 // setDiskUtilization sets disk utilization values
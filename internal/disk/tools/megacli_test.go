@@ -2,8 +2,10 @@ package tools
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
@@ -240,30 +242,50 @@ func TestMegaCLI_AdapterIDExtraction(t *testing.T) {
 	}
 }
 
-func TestRaidStatusValue(t *testing.T) {
-	testCases := []struct {
-		state    string
-		expected int
-	}{
-		{"Optimal", 1},
-		{"optimal", 1},
-		{"OPTIMAL", 1},
-		{"Degraded", 2},
-		{"degraded", 2},
-		{"Rebuilding", 2},
-		{"Failed", 3},
-		{"failed", 3},
-		{"Offline", 3},
-		{"Unknown", 0},
-		{"", 0},
-	}
-
-	for _, tc := range testCases {
-		result := utils.GetRaidStatusValue(tc.state)
-		if result != tc.expected {
-			t.Errorf("GetRaidStatusValue(%q) = %d, expected %d", tc.state, result, tc.expected)
-		}
-	}
+func TestMegaCLIStates(t *testing.T) {
+	checkStates(t, megacliDriveStates, map[string]types.HealthStatus{
+		"Online":                        types.HealthStatusOK,
+		"Online, Spun Up":               types.HealthStatusOK,
+		"Online, Spun down":             types.HealthStatusOK,
+		"Hotspare":                      types.HealthStatusOK,
+		"Hotspare, Spun Up":             types.HealthStatusOK,
+		"Hotspare, Spun down":           types.HealthStatusOK,
+		"Unconfigured(good)":            types.HealthStatusOK,
+		"Unconfigured(good), Spun Up":   types.HealthStatusOK,
+		"Unconfigured(good), Spun down": types.HealthStatusOK,
+		"JBOD":                          types.HealthStatusOK,
+		"Rebuild":                       types.HealthStatusWarning,
+		"Copyback":                      types.HealthStatusWarning,
+		"Unconfigured(bad)":             types.HealthStatusCritical,
+		"Failed":                        types.HealthStatusCritical,
+		"Offline":                       types.HealthStatusCritical,
+		"Missing":                       types.HealthStatusCritical,
+	})
+	checkStates(t, megacliDriveRoles, map[string]string{
+		"Online":                        "active",
+		"Online, Spun Up":               "active",
+		"Online, Spun down":             "active",
+		"Hotspare":                      "hot_spare",
+		"Hotspare, Spun Up":             "hot_spare",
+		"Hotspare, Spun down":           "hot_spare",
+		"Unconfigured(good)":            "unconfigured",
+		"Unconfigured(good), Spun Up":   "unconfigured",
+		"Unconfigured(good), Spun down": "unconfigured",
+		"Unconfigured(bad)":             "unconfigured",
+		"JBOD":                          "unconfigured",
+		"Rebuild":                       "rebuilding",
+		"Copyback":                      "rebuilding",
+		"Failed":                        "failed",
+		"Offline":                       "failed",
+		"Missing":                       "failed",
+	})
+	checkStates(t, megacliArrayStates, map[string]types.RAIDState{
+		"Optimal":            types.RAIDStateOptimal,
+		"Partially Degraded": types.RAIDStateDegraded,
+		"Degraded":           types.RAIDStateDegraded,
+		"Offline":            types.RAIDStateFailed,
+		"Failed":             types.RAIDStateFailed,
+	})
 }
 
 func TestParseSizeToBytes(t *testing.T) {
@@ -614,6 +636,52 @@ func TestMegaCLI_FinalizeUnassignedDisk(t *testing.T) {
 	}
 }
 
+func TestMegaCLIRaidRole(t *testing.T) {
+	tool := &MegaCLITool{}
+	tests := []struct {
+		state       string
+		expected    string
+		mountpoint  string
+		globalSpare bool
+	}{
+		{"Online, Spun Up", "active", "UNKNOWN", false},
+		{"Hotspare, Spun down", "hot_spare", "SPARE", true},
+		{"Rebuild", "rebuilding", "UNKNOWN", false},
+		{"Copyback", "rebuilding", "UNKNOWN", false},
+		{"Failed", "failed", "FAILED", false},
+		{"Offline", "failed", "FAILED", false},
+		{"Missing", "failed", "FAILED", false},
+		{"Unconfigured(good), Spun Up", "unconfigured", "UNCONFIGURED", false},
+		{"Unconfigured(bad)", "unconfigured", "UNCONFIGURED", false},
+		{"JBOD", "unconfigured", "JBOD", false},
+		// Unmapped states are unknown rather than guessed from substrings
+		{"Failed Hotspare", "unknown", "UNKNOWN", false},
+		{"Online (Foreign)", "unknown", "UNKNOWN", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			unassigned := types.DiskInfo{Health: tt.state, Capacity: 1000}
+			tool.finalizeUnassignedDisk(&unassigned)
+			if unassigned.RaidRole != tt.expected || unassigned.Mountpoint != tt.mountpoint || unassigned.IsGlobalSpare != tt.globalSpare {
+				t.Errorf("Unassigned drive: expected role %q on %q (global spare %v), got %q on %q (%v)",
+					tt.expected, tt.mountpoint, tt.globalSpare, unassigned.RaidRole, unassigned.Mountpoint, unassigned.IsGlobalSpare)
+			}
+
+			member := types.DiskInfo{Health: tt.state, Capacity: 1000}
+			tool.finalizeRAIDDisk(&member, types.RAIDInfo{ArrayID: "0"})
+			if member.RaidRole != tt.expected {
+				t.Errorf("Array member: expected role %q, got %q", tt.expected, member.RaidRole)
+			}
+
+			ldpd := types.DiskInfo{Health: tt.state}
+			tool.finalizeLdPdInfoDisk(&ldpd, "0", "32", "4")
+			if ldpd.RaidRole != tt.expected || ldpd.RaidArrayID != "0" {
+				t.Errorf("LdPdInfo drive: expected role %q in array 0, got %q in %q", tt.expected, ldpd.RaidRole, ldpd.RaidArrayID)
+			}
+		})
+	}
+}
+
 // Mock MegaCLI outputs for testing
 const mockLDInfoOutput = `
 Adapter 0 -- Virtual Drive Information:
@@ -1163,10 +1231,9 @@ func (m *MockMegaCLITool) finalizeRAIDDisk(disk *types.DiskInfo, raidArray types
 	disk.Type = "raid"
 
 	// Determine role based on health status
-	healthLower := strings.ToLower(disk.Health)
-	switch {
-	case strings.Contains(healthLower, "online"):
-		disk.RaidRole = "active"
+	disk.RaidRole = megacliRaidRole(disk.Health)
+	switch disk.RaidRole {
+	case "active":
 		// Mock utilization calculation
 		if disk.Capacity > 0 {
 			disk.UsagePercentage = 80.0 // Mock usage
@@ -1175,20 +1242,17 @@ func (m *MockMegaCLITool) finalizeRAIDDisk(disk *types.DiskInfo, raidArray types
 			disk.Mountpoint = fmt.Sprintf("RAID-%s", raidArray.ArrayID)
 			disk.Filesystem = fmt.Sprintf("%s-Array", raidArray.RaidLevel)
 		}
-	case strings.Contains(healthLower, "rebuilding"):
-		disk.RaidRole = "rebuilding"
+	case "rebuilding":
 		disk.UsagePercentage = 50.0
 		disk.Mountpoint = "REBUILDING"
 		disk.Filesystem = "Rebuilding-Drive"
-	case strings.Contains(healthLower, "failed") || strings.Contains(healthLower, "fail"):
-		disk.RaidRole = "failed"
+	case "failed":
 		disk.UsedBytes = 0
 		disk.AvailableBytes = 0
 		disk.UsagePercentage = 0.0
 		disk.Mountpoint = "FAILED"
 		disk.Filesystem = "Failed-Drive"
 	default:
-		disk.RaidRole = "unknown"
 		if disk.Capacity > 0 {
 			disk.UsagePercentage = 50.0 // Conservative estimate
 			disk.UsedBytes = disk.Capacity / 2
@@ -1199,52 +1263,9 @@ func (m *MockMegaCLITool) finalizeRAIDDisk(disk *types.DiskInfo, raidArray types
 	}
 }
 
-// finalizeUnassignedDisk finalizes an unassigned disk (mock version)
+// finalizeUnassignedDisk finalizes an unassigned disk through the real implementation
 func (m *MockMegaCLITool) finalizeUnassignedDisk(disk *types.DiskInfo) {
-	disk.Type = "raid"
-
-	// Determine role based on health status
-	healthLower := strings.ToLower(disk.Health)
-	switch {
-	case strings.Contains(healthLower, "hotspare") || strings.Contains(healthLower, "spare"):
-		disk.RaidRole = "hot_spare"
-		disk.IsGlobalSpare = true
-		disk.UsedBytes = 0
-		disk.AvailableBytes = disk.Capacity
-		disk.UsagePercentage = 0.0
-		disk.Mountpoint = "SPARE"
-		disk.Filesystem = "Hot-Spare"
-	case strings.Contains(healthLower, "unconfigured"):
-		disk.RaidRole = "unconfigured"
-		disk.UsedBytes = 0
-		disk.AvailableBytes = disk.Capacity
-		disk.UsagePercentage = 0.0
-		disk.Mountpoint = "UNCONFIGURED"
-		disk.Filesystem = "Unconfigured"
-	case strings.Contains(healthLower, "failed") || strings.Contains(healthLower, "fail"):
-		disk.RaidRole = "failed"
-		disk.UsedBytes = 0
-		disk.AvailableBytes = 0
-		disk.UsagePercentage = 0.0
-		disk.Mountpoint = "FAILED"
-		disk.Filesystem = "Failed-Drive"
-	case strings.Contains(healthLower, "jbod"):
-		disk.RaidRole = "unconfigured"
-		disk.UsedBytes = 0
-		disk.AvailableBytes = disk.Capacity
-		disk.UsagePercentage = 0.0
-		disk.Mountpoint = "JBOD"
-		disk.Filesystem = "JBOD"
-	default:
-		disk.RaidRole = "unknown"
-		if disk.Capacity > 0 {
-			disk.UsagePercentage = 50.0 // Conservative estimate
-			disk.UsedBytes = disk.Capacity / 2
-			disk.AvailableBytes = disk.Capacity / 2
-			disk.Mountpoint = "RAID-UNKNOWN"
-			disk.Filesystem = "Unknown-Array"
-		}
-	}
+	(&MegaCLITool{}).finalizeUnassignedDisk(disk)
 }
 
 // Test nil pointer safety in calculateDiskUtilization
@@ -1486,6 +1507,17 @@ Exit Code: 0x00`
 		t.Errorf("Expected Type 'raid', got '%s'", disk1.Type)
 	}
 
+	expectedDrive := &types.RAIDDriveInfo{
+		ToolName:      "MegaCLI",
+		FirmwareState: "Online, Spun Up",
+		ForeignState:  "None",
+		LinkSpeed:     "12.0Gb/s",
+		DeviceSpeed:   "12.0Gb/s",
+	}
+	if !reflect.DeepEqual(disk1.RaidDrive, expectedDrive) {
+		t.Errorf("Expected %+v, got %+v", expectedDrive, disk1.RaidDrive)
+	}
+
 	// Test second disk
 	disk2 := disks[1]
 	if disk2.Device != "35" {
@@ -1500,10 +1532,9 @@ Exit Code: 0x00`
 		t.Errorf("Expected Temperature 36.0, got %.1f", disk2.Temperature)
 	}
 
-	// Test health status value conversion using the utility function
-	healthStatus := utils.GetHealthStatusValue(disk1.Health)
-	if healthStatus != 1 {
-		t.Errorf("Expected health status 1 (OK) for 'Online, Spun Up', got %d", healthStatus)
+	// Test health status translation through the firmware state table
+	if disk1.HealthStatus != types.HealthStatusOK {
+		t.Errorf("Expected health status 1 (OK) for 'Online, Spun Up', got %d", disk1.HealthStatus)
 	}
 
 	// Verify that the health status parsing works correctly
@@ -1512,3 +1543,376 @@ Exit Code: 0x00`
 		t.Errorf("Health status '%s' should contain 'ONLINE' when uppercased", disk1.Health)
 	}
 }
+
+func TestMegaCLI_ParseLdPdInfoLastDiskOfEachArray(t *testing.T) {
+	output := `Adapter #0
+
+Virtual Drive: 0 (Target Id: 0)
+State               : Optimal
+
+PD: 0 Information
+Enclosure Device ID: 32
+Slot Number: 0
+Device Id: 8
+Media Error Count: 3
+Firmware state: Online, Spun Up
+Shield Counter: 0
+
+
+Virtual Drive: 1 (Target Id: 1)
+State               : Optimal
+
+PD: 0 Information
+Enclosure Device ID: 32
+Slot Number: 1
+Device Id: 9
+Media Error Count: 0
+Firmware state: Online, Spun Up
+Shield Counter: 0
+
+Exit Code: 0x00`
+
+	tool := &MegaCLITool{}
+	disks := tool.parseLdPdInfoOutputForAllArrays(output, map[string]bool{"0": true, "1": true})
+	if len(disks) != 2 {
+		t.Fatalf("Expected 2 disks, got %d", len(disks))
+	}
+	if disks[0].Device != "8" || disks[0].RaidArrayID != "0" || disks[0].RaidDrive.MediaErrorCount != 3 {
+		t.Errorf("Unexpected member of array 0 %+v", disks[0])
+	}
+	if disks[1].Device != "9" || disks[1].RaidArrayID != "1" {
+		t.Errorf("Unexpected member of array 1 %+v", disks[1])
+	}
+}
+
+func TestMegaCLI_ParsePDListOutput(t *testing.T) {
+	output := `Adapter #0
+
+Enclosure Device ID: 32
+Slot Number: 4
+Device Id: 14
+WWN: 5000C50084A1B2C3
+Media Error Count: 118
+Other Error Count: 9
+Predictive Failure Count: 2
+Last Predictive Failure Event Seq Number: 4211
+Coerced Size: 3.637 TB [0x1d1a94a20 Sectors]
+Firmware state: Unconfigured(bad)
+Device Firmware Level: A001
+Shield Counter: 4
+Inquiry Data: SEAGATE ST4000NM0023    A001Z1Z3ABCD
+Foreign State: Foreign
+Device Speed: 6.0Gb/s
+Link Speed: 3.0Gb/s
+Drive Temperature :41C (105.80 F)
+
+Enclosure Device ID: 32
+Slot Number: 5
+Device Id: 15
+WWN: 5000C50084A1B2D4
+Media Error Count: 0
+Other Error Count: 0
+Predictive Failure Count: 0
+Firmware state: Online, Spun Up
+Shield Counter: 0
+Foreign State: None
+
+Enclosure Device ID: 32
+Slot Number: 6
+Device Id: 16
+WWN: 5000C50084A1B2E5
+Firmware state: Hotspare, Spun Up
+Shield Counter: 1
+Drive Temperature :37C (98.60 F)
+
+Enclosure Device ID: 32
+Slot Number: 7
+Device Id: 17
+WWN: 5000C50084A1B2F6
+Firmware state: Rebuild
+Shield Counter: 0
+
+Enclosure Device ID: 32
+Slot Number: 8
+Device Id: 18
+WWN: 5000C50084A1B307
+Firmware state: Sanitize
+Shield Counter: 0
+
+Exit Code: 0x00`
+
+	tool := &MegaCLITool{}
+	disks := tool.parsePDListOutput(output)
+
+	// The online and rebuilding array members are reported through -LdPdInfo instead
+	if len(disks) != 3 {
+		t.Fatalf("Expected 3 unassigned disks, got %d", len(disks))
+	}
+
+	// Fields printed after the firmware state must not leak into the next disk
+	bad := disks[0]
+	if bad.Device != "14" || bad.RaidRole != "unconfigured" || bad.Temperature != 41 {
+		t.Errorf("Unexpected unconfigured disk %+v", bad)
+	}
+	expected := &types.RAIDDriveInfo{
+		ToolName:               "MegaCLI",
+		FirmwareState:          "Unconfigured(bad)",
+		ForeignState:           "Foreign",
+		MediaErrorCount:        118,
+		OtherErrorCount:        9,
+		PredictiveFailureCount: 2,
+		ShieldCounter:          4,
+		LinkSpeed:              "3.0Gb/s",
+		DeviceSpeed:            "6.0Gb/s",
+	}
+	if !reflect.DeepEqual(bad.RaidDrive, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, bad.RaidDrive)
+	}
+
+	spare := disks[1]
+	if spare.Device != "16" || spare.RaidRole != "hot_spare" || spare.Temperature != 37 {
+		t.Errorf("Unexpected hot spare %+v", spare)
+	}
+	if spare.RaidDrive.ShieldCounter != 1 || spare.RaidDrive.MediaErrorCount != 0 {
+		t.Errorf("Unexpected hot spare counters %+v", spare.RaidDrive)
+	}
+
+	// A state missing from the role table is reported as unknown, not guessed
+	if unmapped := disks[2]; unmapped.Device != "18" || unmapped.RaidRole != "unknown" {
+		t.Errorf("Expected an unassigned disk of unknown role, got %+v", unmapped)
+	}
+}
+
+func TestMegaCLI_ParseAdapterInfo(t *testing.T) {
+	output := `
+Adapter #0
+
+==============================================================================
+                    Versions
+                ================
+Product Name    : LSI MegaRAID SAS 9260-8i
+Serial No       : SV12345678
+FW Package Build: 12.15.0-0239
+
+                    Mfg. Data
+                ================
+Mfg. Date       : 03/22/13
+Rework Date     : 00/00/00
+Revision No     : 61A
+Battery FRU     : N/A
+
+                Image Versions in Flash:
+                ================
+BIOS Version       : 3.30.02.2_4.16.08.00_0x06060A05
+WebBIOS Version    : 6.0-54-e_50-Rel
+Preboot CLI Version: 04.04-020:#%00009
+FW Version         : 2.130.403-4660
+NVDATA Version     : 2.09.03-0058
+Boot Block Version : 2.02.00.00-0000
+BOOT Version       : 09.250.01.219
+
+                Pending Images in Flash
+                ================
+None
+
+                HW Configuration
+                ================
+SAS Address      : 500605b0058f2c30
+BBU              : Present
+Alarm            : Present
+NVRAM            : Present
+Serial Debugger  : Present
+Memory           : Present
+Flash            : Present
+Memory Size      : 512MB
+TPM              : Absent
+On board Expander: Absent
+Upgrade Key      : Absent
+Temperature sensor for ROC    : Present
+Temperature sensor for controller    : Absent
+
+ROC temperature : 65  degree Celcius
+
+                Device Present
+                ================
+Virtual Drives    : 2 
+  Degraded        : 1 
+  Offline         : 0 
+Physical Devices  : 7 
+  Disks           : 6 
+  Critical Disks  : 0 
+  Failed Disks    : 1 
+
+                Supported Adapter Operations
+                ================
+Rebuild Rate                    : Yes
+Alarm Control                   : Yes
+Alarm Silence                   : Yes
+
+                Error Counters
+                ================
+Memory Correctable Errors   : 2 
+Memory Uncorrectable Errors : 0 
+
+Adapter #1
+
+==============================================================================
+                    Versions
+                ================
+Product Name    : LSI MegaRAID SAS 9271-4i
+Serial No       : SV23456789
+FW Package Build: 23.34.0-0019
+
+                Image Versions in Flash:
+                ================
+BIOS Version       : 5.50.03.0_4.17.08.00_0x06110200
+FW Version         : 3.460.95-6355
+
+                Pending Images in Flash
+                ================
+FW Version         : 3.460.115-6465
+
+                HW Configuration
+                ================
+Alarm            : Absent
+Memory Size      : 1024MB
+
+                Device Present
+                ================
+Virtual Drives    : 1 
+  Degraded        : 0 
+  Offline         : 0 
+Physical Devices  : 3 
+  Disks           : 2 
+  Critical Disks  : 0 
+  Failed Disks    : 0 
+
+Exit Code: 0x00
+`
+
+	tool := &MegaCLITool{}
+	controllers := tool.parseAdapterInfo(output)
+
+	expected := []types.RAIDControllerInfo{
+		{
+			AdapterID:               0,
+			ToolName:                "MegaCLI",
+			Model:                   "LSI MegaRAID SAS 9260-8i",
+			SerialNumber:            "SV12345678",
+			FirmwareVersion:         "2.130.403-4660",
+			FirmwarePackage:         "12.15.0-0239",
+			BIOSVersion:             "3.30.02.2_4.16.08.00_0x06060A05",
+			CacheSize:               512 << 20,
+			Status:                  "Needs Attention",
+			ROCTemperature:          65,
+			MemoryCorrectableErrors: 2,
+			NumVirtualDrives:        2,
+			NumPhysicalDrives:       6,
+			AlarmState:              "Present",
+		},
+		{
+			AdapterID:         1,
+			ToolName:          "MegaCLI",
+			Model:             "LSI MegaRAID SAS 9271-4i",
+			SerialNumber:      "SV23456789",
+			FirmwareVersion:   "3.460.95-6355",
+			FirmwarePackage:   "23.34.0-0019",
+			BIOSVersion:       "5.50.03.0_4.17.08.00_0x06110200",
+			CacheSize:         1 << 30,
+			Status:            "Optimal",
+			NumVirtualDrives:  1,
+			NumPhysicalDrives: 2,
+			PendingFlashImage: true,
+			AlarmState:        "Absent",
+		},
+	}
+
+	if len(controllers) != len(expected) {
+		t.Fatalf("Expected %d controllers, got %d", len(expected), len(controllers))
+	}
+	for i := range expected {
+		if controllers[i] != expected[i] {
+			t.Errorf("Adapter %d: expected %+v, got %+v", i, expected[i], controllers[i])
+		}
+	}
+}
+
+func TestMegaCLI_ParseForeignConfigScan(t *testing.T) {
+	output := `
+There are 2 foreign configuration(s) on controller 0.
+There is no foreign configuration on controller 1.
+
+Exit Code: 0x00
+`
+
+	tool := &MegaCLITool{}
+	foreign := tool.parseForeignConfigScan(output)
+
+	if foreign[0] != 2 {
+		t.Errorf("Expected 2 foreign configurations on adapter 0, got %d", foreign[0])
+	}
+	if count, ok := foreign[1]; ok {
+		t.Errorf("Expected no foreign configurations on adapter 1, got %d", count)
+	}
+}
+
+func TestMegaCLI_ParsePatrolReadInfo(t *testing.T) {
+	output := `
+Adapter 0: Patrol Read Information:
+
+Patrol Read Mode: Auto
+Patrol Read Execution Delay: 168 hours
+Number of iterations completed: 20 
+Next start time: 07/19/2025, 03:00:00
+Current State: Active
+Patrol Read on SSD Devices: Disabled
+
+Adapter 1: Patrol Read Information:
+
+Patrol Read Mode: Disabled
+Patrol Read Execution Delay: 168 hours
+Number of iterations completed: 0 
+Current State: Stopped
+Patrol Read on SSD Devices: Disabled
+
+Exit Code: 0x00
+`
+
+	tool := &MegaCLITool{}
+	patrolRead := tool.parsePatrolReadInfo(output)
+
+	expected := map[int]types.RAIDControllerInfo{
+		0: {
+			AdapterID:            0,
+			PatrolReadMode:       "Auto",
+			PatrolReadState:      "Active",
+			PatrolReadIterations: 20,
+			PatrolReadNextRun:    time.Date(2025, 7, 19, 3, 0, 0, 0, time.Local),
+		},
+		1: {
+			AdapterID:       1,
+			PatrolReadMode:  "Disabled",
+			PatrolReadState: "Stopped",
+		},
+	}
+	if !reflect.DeepEqual(patrolRead, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, patrolRead)
+	}
+}
+
+func TestMegaCLI_ParseConsistencyCheckProgress(t *testing.T) {
+	output := `
+Check Consistency on VD #0 (Target id #0) Completed 42% in 10 Minutes.
+Check Consistency on VD #1 (Target id #1) is not in progress.
+
+Exit Code: 0x00
+`
+
+	tool := &MegaCLITool{}
+	progress := tool.parseConsistencyCheckProgress(output)
+
+	expected := map[string]int{"0": 42}
+	if !reflect.DeepEqual(progress, expected) {
+		t.Errorf("Expected %v, got %v", expected, progress)
+	}
+}
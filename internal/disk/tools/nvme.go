@@ -1,17 +1,30 @@
 package tools
 
 import (
-	"log"
+	"log/slog"
 	"os/exec"
 	"strings"
 
+	"disk-health-exporter/internal/disk/merge"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
+// Ensure NvmeTool implements the DiskToolInterface
+var _ DiskToolInterface = (*NvmeTool)(nil)
+
 // NvmeTool represents the nvme CLI tool
 type NvmeTool struct{}
 
+func init() {
+	Register(Registration{
+		Name:      merge.SourceNvme,
+		Platforms: []string{PlatformLinux},
+		After:     []string{merge.SourceSmartctl},
+		New:       func() ToolInterface { return NewNvmeTool() },
+	})
+}
+
 // NewNvmeTool creates a new NvmeTool instance
 func NewNvmeTool() *NvmeTool {
 	return &NvmeTool{}
@@ -49,12 +62,12 @@ func (n *NvmeTool) GetDisks() []types.DiskInfo {
 		return disks
 	}
 
-	log.Printf("Detecting NVMe disks using nvme CLI...")
+	slog.Debug("Detecting disks", "tool", "nvme")
 
 	// List NVMe devices
 	output, err := exec.Command("nvme", "list").Output()
 	if err != nil {
-		log.Printf("Error running nvme list: %v", err)
+		slog.Error("Error listing NVMe devices", "tool", "nvme", "err", err)
 		return disks
 	}
 
@@ -82,6 +95,6 @@ func (n *NvmeTool) GetDisks() []types.DiskInfo {
 		}
 	}
 
-	log.Printf("Found %d NVMe disks using nvme CLI", len(disks))
+	slog.Debug("Found disks", "tool", "nvme", "disks", len(disks))
 	return disks
 }
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"disk-health-exporter/pkg/types"
+)
+
+// scsiHostRoot lists the SCSI hosts of the system along with the driver behind each
+var scsiHostRoot = "/sys/class/scsi_host"
+
+// scsiHostsForDriver returns the SCSI host numbers driven by a kernel module (e.g. megaraid_sas) in ascending order.
+// Controllers are probed in PCI order, so the Nth host is the Nth controller as numbered by the RAID tools.
+func scsiHostsForDriver(driver string) []int {
+	entries, err := os.ReadDir(scsiHostRoot)
+	if err != nil {
+		return nil
+	}
+
+	var hosts []int
+	for _, entry := range entries {
+		number, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), "host"))
+		if err != nil {
+			continue
+		}
+		procName, err := os.ReadFile(filepath.Join(scsiHostRoot, entry.Name(), "proc_name"))
+		if err != nil || strings.TrimSpace(string(procName)) != driver {
+			continue
+		}
+		hosts = append(hosts, number)
+	}
+	sort.Ints(hosts)
+	return hosts
+}
+
+// setMegaRAIDPassthrough addresses a drive behind a MegaRAID controller for smartctl
+// smartctl -d megaraid,N -a -j /dev/bus/H # SAS drive with device ID N on SCSI host H
+// smartctl -d sat+megaraid,N -a -j /dev/bus/H # SATA drive with device ID N on SCSI host H
+func setMegaRAIDPassthrough(disk *types.DiskInfo, controller int, deviceID int) {
+	hosts := scsiHostsForDriver("megaraid_sas")
+	if controller < 0 || controller >= len(hosts) {
+		return
+	}
+
+	disk.SmartDevice = fmt.Sprintf("/dev/bus/%d", hosts[controller])
+	disk.SmartDeviceType = fmt.Sprintf("megaraid,%d", deviceID)
+	if strings.EqualFold(disk.Interface, "SATA") {
+		disk.SmartDeviceType = "sat+" + disk.SmartDeviceType
+	}
+}
+
+// setAacraidPassthrough addresses a drive behind an Adaptec controller for smartctl.
+// smartctl opens /dev/aacH itself, where H is the zero-based controller number.
+// smartctl -d aacraid,H,L,ID -a -j /dev/aacH # drive with target ID and LUN L on controller H
+func setAacraidPassthrough(disk *types.DiskInfo, controller int, lun int, target int) {
+	if controller < 0 {
+		return
+	}
+	disk.SmartDevice = fmt.Sprintf("/dev/aac%d", controller)
+	disk.SmartDeviceType = fmt.Sprintf("aacraid,%d,%d,%d", controller, lun, target)
+}
+
+// setCCISSPassthrough addresses a drive behind an HPE Smart Array controller for smartctl
+// smartctl -d cciss,N -a -j /dev/sgX # Nth physical drive behind the controller owning /dev/sgX
+func setCCISSPassthrough(disk *types.DiskInfo, device string, index int) {
+	if device == "" || index < 0 {
+		return
+	}
+	disk.SmartDevice = device
+	disk.SmartDeviceType = fmt.Sprintf("cciss,%d", index)
+}
+
+// enrichWithPassthroughSMART queries full SMART data of drives hidden behind RAID controllers
+// and merges it into the controller's view of each drive
+func enrichWithPassthroughSMART(disks []types.DiskInfo) {
+	smartTool := NewSmartCtlTool()
+	if !smartTool.IsAvailable() {
+		return
+	}
+
+	for i := range disks {
+		if disks[i].SmartDevice == "" || disks[i].SmartDeviceType == "" {
+			continue
+		}
+		info := smartTool.GetSmartCtlInfoWithType(disks[i].SmartDevice, disks[i].SmartDeviceType)
+		if info.Device == "" {
+			slog.Warn("No SMART data through controller", "tool", "smartctl", "device", disks[i].Device, "serial", disks[i].Serial, "smart_device", disks[i].SmartDevice, "device_type", disks[i].SmartDeviceType)
+			continue
+		}
+		mergeSMARTInfo(&disks[i], info)
+	}
+}
+
+// mergeSMARTInfo merges SMART data read through a controller into a RAID disk.
+// The controller keeps authority over the device name, location, health and RAID role;
+// SMART supplies the drive identity and the attribute-level counters.
+func mergeSMARTInfo(disk *types.DiskInfo, smart types.DiskInfo) {
+	// Controllers report WWNs or abbreviated names, SMART reports the drive's own identity
+	if smart.Serial != "" {
+		disk.Serial = smart.Serial
+	}
+	if smart.Model != "" {
+		disk.Model = smart.Model
+	}
+	if disk.Vendor == "" {
+		disk.Vendor = smart.Vendor
+	}
+	if disk.Capacity == 0 {
+		disk.Capacity = smart.Capacity
+	}
+	if disk.FormFactor == "" {
+		disk.FormFactor = smart.FormFactor
+	}
+	if disk.RPM == 0 {
+		disk.RPM = smart.RPM
+	}
+	if smart.Temperature > 0 {
+		disk.Temperature = smart.Temperature
+	}
+
+	if smart.SmartEnabled {
+		disk.SmartEnabled = true
+		disk.SmartHealthy = smart.SmartHealthy
+	}
+
+	disk.PowerOnHours = smart.PowerOnHours
+	disk.PowerCycles = smart.PowerCycles
+	disk.ReallocatedSectors = smart.ReallocatedSectors
+	disk.PendingSectors = smart.PendingSectors
+	disk.UncorrectableErrors = smart.UncorrectableErrors
+	disk.TotalLBAsWritten = smart.TotalLBAsWritten
+	disk.TotalLBAsRead = smart.TotalLBAsRead
+	disk.BytesWritten = smart.BytesWritten
+	disk.BytesRead = smart.BytesRead
+	disk.DriveTemperatureMax = smart.DriveTemperatureMax
+	disk.DriveTemperatureMin = smart.DriveTemperatureMin
+	disk.TemperatureSensors = smart.TemperatureSensors
+	disk.SelfTests = smart.SelfTests
+	disk.WearLeveling = smart.WearLeveling
+	disk.PercentageUsed = smart.PercentageUsed
+	disk.AvailableSpare = smart.AvailableSpare
+	disk.CriticalWarning = smart.CriticalWarning
+	disk.MediaErrors = smart.MediaErrors
+	disk.ErrorLogEntries = smart.ErrorLogEntries
+}
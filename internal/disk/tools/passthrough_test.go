@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"disk-health-exporter/pkg/types"
+)
+
+// fakeSCSIHosts points scsiHostRoot at a temporary sysfs tree with the given host numbers and drivers
+func fakeSCSIHosts(t *testing.T, hosts map[int]string) {
+	t.Helper()
+	root := t.TempDir()
+	for number, driver := range hosts {
+		dir := filepath.Join(root, "host"+strconv.Itoa(number))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "proc_name"), []byte(driver+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	previous := scsiHostRoot
+	scsiHostRoot = root
+	t.Cleanup(func() { scsiHostRoot = previous })
+}
+
+func TestScsiHostsForDriver(t *testing.T) {
+	fakeSCSIHosts(t, map[int]string{0: "ahci", 1: "ahci", 10: "megaraid_sas", 4: "megaraid_sas", 7: "aacraid"})
+
+	hosts := scsiHostsForDriver("megaraid_sas")
+	if !reflect.DeepEqual(hosts, []int{4, 10}) {
+		t.Errorf("Expected hosts [4 10], got %v", hosts)
+	}
+	if hosts := scsiHostsForDriver("hpsa"); len(hosts) != 0 {
+		t.Errorf("Expected no hpsa hosts, got %v", hosts)
+	}
+}
+
+func TestSetMegaRAIDPassthrough(t *testing.T) {
+	fakeSCSIHosts(t, map[int]string{0: "ahci", 2: "megaraid_sas", 6: "megaraid_sas"})
+
+	tests := []struct {
+		name           string
+		controller     int
+		iface          string
+		expectedDevice string
+		expectedType   string
+	}{
+		{"SAS drive on first controller", 0, "SAS", "/dev/bus/2", "megaraid,14"},
+		{"SATA drive on second controller", 1, "SATA", "/dev/bus/6", "sat+megaraid,14"},
+		{"unknown controller", 2, "SAS", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disk := types.DiskInfo{Interface: tt.iface}
+			setMegaRAIDPassthrough(&disk, tt.controller, 14)
+			if disk.SmartDevice != tt.expectedDevice || disk.SmartDeviceType != tt.expectedType {
+				t.Errorf("Expected %s %s, got %s %s", tt.expectedDevice, tt.expectedType, disk.SmartDevice, disk.SmartDeviceType)
+			}
+		})
+	}
+}
+
+func TestPassthroughFromRAIDTools(t *testing.T) {
+	fakeSCSIHosts(t, map[int]string{0: "ahci", 3: "megaraid_sas", 5: "megaraid_sas"})
+
+	// MegaCLI: adapter number and device ID, SATA drives need the SAT layer
+	pdList := `Adapter #1
+
+Enclosure Device ID: 32
+Slot Number: 2
+Device Id: 21
+PD Type: SATA
+Firmware state: Unconfigured(good), Spun Up
+
+Exit Code: 0x00`
+	megaDisks := (&MegaCLITool{}).parsePDListOutput(pdList)
+	if len(megaDisks) != 1 {
+		t.Fatalf("Expected 1 MegaCLI disk, got %d", len(megaDisks))
+	}
+	if megaDisks[0].SmartDevice != "/dev/bus/5" || megaDisks[0].SmartDeviceType != "sat+megaraid,21" {
+		t.Errorf("Unexpected MegaCLI passthrough %s %s", megaDisks[0].SmartDevice, megaDisks[0].SmartDeviceType)
+	}
+
+	// StorCLI: controller number and DID
+	storDisks := parseStorCLIFixtureDrives(t, "sas3108")
+	if ssd := storDisks["raid-c0-enc252-slot0"]; ssd.SmartDevice != "/dev/bus/3" || ssd.SmartDeviceType != "sat+megaraid,8" {
+		t.Errorf("Unexpected StorCLI SATA passthrough %s %s", ssd.SmartDevice, ssd.SmartDeviceType)
+	}
+	if hdd := storDisks["raid-c0-enc252-slot2"]; hdd.SmartDevice != "/dev/bus/3" || hdd.SmartDeviceType != "megaraid,10" {
+		t.Errorf("Unexpected StorCLI SAS passthrough %s %s", hdd.SmartDevice, hdd.SmartDeviceType)
+	}
+	if second := parseStorCLIFixtureDrives(t, "sas3516")["raid-c1-enc250-slot1"]; second.SmartDevice != "/dev/bus/5" {
+		t.Errorf("Expected the second controller on /dev/bus/5, got %s", second.SmartDevice)
+	}
+
+	// arcconf: zero-based controller, LUN and target ID
+	pd := `Controllers found: 1
+      Channel #0:
+         Device #0
+            Device is a Hard drive
+            State                              : Online
+            Reported Channel,Device(T:L)       : 0,3(3:0)
+            Serial number                      : ZC11A2C1`
+	arcDisks := NewArcconfTool().parsePhysicalDevices(pd, "2")
+	if len(arcDisks) != 1 {
+		t.Fatalf("Expected 1 arcconf disk, got %d", len(arcDisks))
+	}
+	if arcDisks[0].SmartDevice != "/dev/aac1" || arcDisks[0].SmartDeviceType != "aacraid,1,0,3" {
+		t.Errorf("Unexpected arcconf passthrough %s %s", arcDisks[0].SmartDevice, arcDisks[0].SmartDeviceType)
+	}
+}
+
+func TestSetCCISSPassthrough(t *testing.T) {
+	var disk types.DiskInfo
+	setCCISSPassthrough(&disk, "/dev/sg1", 3)
+	if disk.SmartDevice != "/dev/sg1" || disk.SmartDeviceType != "cciss,3" {
+		t.Errorf("Expected /dev/sg1 cciss,3, got %s %s", disk.SmartDevice, disk.SmartDeviceType)
+	}
+}
+
+func TestMergeSMARTInfo(t *testing.T) {
+	disk := types.DiskInfo{
+		Device:      "raid-c0-enc252-slot3",
+		Location:    "Controller:0 EID:252 Slot:3",
+		Serial:      "5000C500AD914DAC",
+		Model:       "ST4000NM0023",
+		Health:      "OK",
+		Type:        "raid",
+		Interface:   "SAS",
+		Temperature: 35,
+		RaidRole:    "active",
+		RaidArrayID: "0:1",
+	}
+	smart := types.DiskInfo{
+		Device:              "/dev/bus/0",
+		Serial:              "Z1Z3ABCD",
+		Model:               "SEAGATE ST4000NM0023",
+		Interface:           "SCSI",
+		Health:              "FAILED",
+		Temperature:         38,
+		SmartEnabled:        true,
+		SmartHealthy:        false,
+		PowerOnHours:        41234,
+		ReallocatedSectors:  8,
+		UncorrectableErrors: 2,
+		BytesRead:           1 << 40,
+	}
+
+	mergeSMARTInfo(&disk, smart)
+
+	// The controller keeps authority over naming, placement, health and role
+	if disk.Device != "raid-c0-enc252-slot3" || disk.Location != "Controller:0 EID:252 Slot:3" || disk.Interface != "SAS" {
+		t.Errorf("Controller identity was overwritten: %+v", disk)
+	}
+	if disk.Health != "OK" || disk.RaidRole != "active" || disk.RaidArrayID != "0:1" {
+		t.Errorf("Controller state was overwritten: %+v", disk)
+	}
+	// SMART supplies the drive identity and counters
+	if disk.Serial != "Z1Z3ABCD" || disk.Model != "SEAGATE ST4000NM0023" || disk.Temperature != 38 {
+		t.Errorf("Expected SMART identity, got %q %q %.0f", disk.Serial, disk.Model, disk.Temperature)
+	}
+	if !disk.SmartEnabled || disk.SmartHealthy {
+		t.Errorf("Expected the failed SMART assessment, got enabled=%v healthy=%v", disk.SmartEnabled, disk.SmartHealthy)
+	}
+	if disk.PowerOnHours != 41234 || disk.ReallocatedSectors != 8 || disk.UncorrectableErrors != 2 || disk.BytesRead != 1<<40 {
+		t.Errorf("Expected SMART counters, got %+v", disk)
+	}
+}
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Platforms as reported by runtime.GOOS
+const (
+	PlatformLinux   = "linux"
+	PlatformDarwin  = "darwin"
+	PlatformWindows = "windows"
+)
+
+// Registration describes a tool to the disk detection pipeline. Which results the
+// pipeline collects follows from the interfaces the created tool implements:
+// DiskToolInterface, RAIDToolInterface, SoftwareRAIDToolInterface and so on.
+type Registration struct {
+	// Name identifies the tool as a source of disk information, matching the
+	// source names of the merge precedence table (e.g. "smartctl")
+	Name string
+
+	// Platforms lists the runtime.GOOS values the tool runs on
+	Platforms []string
+
+	// After lists tools whose results this tool builds on. They run first when
+	// they are registered for the same platform; otherwise they are ignored.
+	After []string
+
+	// New creates the tool
+	New func() ToolInterface
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Registration)
+)
+
+// Register adds a tool to the registry. Tools register themselves from an init
+// function in their own file; registering a name twice panics.
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if r.Name == "" || r.New == nil {
+		panic("tools: registration needs a name and a constructor")
+	}
+	if _, exists := registry[r.Name]; exists {
+		panic(fmt.Sprintf("tools: %s registered twice", r.Name))
+	}
+	registry[r.Name] = r
+}
+
+// ForPlatform returns the tools registered for a platform in the order they
+// should run: every tool after the tools it depends on, otherwise by name.
+func ForPlatform(platform string) []Registration {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	candidates := make(map[string]Registration)
+	for name, r := range registry {
+		if r.supports(platform) {
+			candidates[name] = r
+		}
+	}
+
+	return orderByDependencies(candidates)
+}
+
+// Platform returns the platform whose tools to run on goos. Systems without
+// registered tools fall back to the Linux tools.
+func Platform(goos string) string {
+	if len(ForPlatform(goos)) == 0 {
+		return PlatformLinux
+	}
+	return goos
+}
+
+// Implementing creates every tool registered for a platform that implements T,
+// in pipeline order. Availability is left for the caller to check.
+func Implementing[T ToolInterface](platform string) []T {
+	var result []T
+	for _, r := range ForPlatform(platform) {
+		if tool, ok := r.New().(T); ok {
+			result = append(result, tool)
+		}
+	}
+	return result
+}
+
+// supports reports whether the tool runs on a platform
+func (r Registration) supports(platform string) bool {
+	for _, p := range r.Platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// orderByDependencies sorts registrations topologically on their After lists.
+// Tools that are ready at the same time run in name order, and tools caught in a
+// dependency cycle run last, also in name order.
+func orderByDependencies(candidates map[string]Registration) []Registration {
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	done := make(map[string]bool)
+	ready := func(r Registration) bool {
+		for _, dep := range r.After {
+			if _, present := candidates[dep]; present && !done[dep] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var ordered []Registration
+	for len(ordered) < len(names) {
+		progress := false
+		for _, name := range names {
+			if done[name] || !ready(candidates[name]) {
+				continue
+			}
+			ordered = append(ordered, candidates[name])
+			done[name] = true
+			progress = true
+			break
+		}
+		if !progress {
+			for _, name := range names {
+				if !done[name] {
+					ordered = append(ordered, candidates[name])
+					done[name] = true
+				}
+			}
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+// registeredNames returns the names of the registrations in order
+func registeredNames(registrations []Registration) []string {
+	var names []string
+	for _, r := range registrations {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func TestForPlatform(t *testing.T) {
+	tests := []struct {
+		platform string
+		expected []string
+	}{
+		{PlatformLinux, []string{"lsblk", "mdadm", "smartctl", "nvme", "hdparm", "arcconf", "megacli", "ssacli", "storcli", "zpool"}},
+		{PlatformDarwin, []string{"diskutil", "smartctl", "zpool"}},
+		{PlatformWindows, []string{"smartctl", "arcconf", "megacli", "storcli", "zpool"}},
+		{"plan9", nil},
+	}
+
+	for _, tt := range tests {
+		names := registeredNames(ForPlatform(tt.platform))
+		if !reflect.DeepEqual(names, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.platform, tt.expected, names)
+		}
+	}
+}
+
+func TestForPlatformRunsDependenciesFirst(t *testing.T) {
+	for _, platform := range []string{PlatformLinux, PlatformDarwin, PlatformWindows} {
+		position := make(map[string]int)
+		registrations := ForPlatform(platform)
+		for i, r := range registrations {
+			position[r.Name] = i
+		}
+		for _, r := range registrations {
+			for _, dep := range r.After {
+				if i, present := position[dep]; present && i > position[r.Name] {
+					t.Errorf("%s: %s runs before its dependency %s", platform, r.Name, dep)
+				}
+			}
+		}
+	}
+}
+
+func TestOrderByDependenciesCycle(t *testing.T) {
+	newTool := func() ToolInterface { return NewLsblkTool() }
+	candidates := map[string]Registration{
+		"a": {Name: "a", After: []string{"b"}, New: newTool},
+		"b": {Name: "b", After: []string{"a"}, New: newTool},
+		"c": {Name: "c", After: []string{"missing"}, New: newTool},
+	}
+
+	names := registeredNames(orderByDependencies(candidates))
+	if expected := []string{"c", "a", "b"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected registering lsblk twice to panic")
+		}
+	}()
+	Register(Registration{Name: "lsblk", New: func() ToolInterface { return NewLsblkTool() }})
+}
+
+func TestImplementing(t *testing.T) {
+	var names []string
+	for _, tool := range Implementing[ControllerToolInterface](PlatformLinux) {
+		names = append(names, tool.GetName())
+	}
+	if expected := []string{"arcconf", "MegaCLI", "SSACLI", "StoreCLI"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	if tools := Implementing[SoftwareRAIDToolInterface](PlatformDarwin); len(tools) != 0 {
+		t.Errorf("Expected no software RAID tools on macOS, got %d", len(tools))
+	}
+}
+
+func TestPlatform(t *testing.T) {
+	tests := map[string]string{
+		PlatformLinux:   PlatformLinux,
+		PlatformDarwin:  PlatformDarwin,
+		PlatformWindows: PlatformWindows,
+		"freebsd":       PlatformLinux,
+	}
+	for goos, expected := range tests {
+		if platform := Platform(goos); platform != expected {
+			t.Errorf("%s: expected %s, got %s", goos, expected, platform)
+		}
+	}
+}
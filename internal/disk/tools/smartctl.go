@@ -2,20 +2,39 @@ package tools
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os/exec"
+	"strconv"
 	"strings"
 
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/drivedb"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
+// Ensure SmartCtlTool implements the DiskToolInterface
+var _ DiskToolInterface = (*SmartCtlTool)(nil)
+
 // SmartCtlTool represents the smartctl CLI tool
-type SmartCtlTool struct{}
+type SmartCtlTool struct {
+	driveDB *drivedb.Database // Decodes vendor-specific ATA attributes
+}
+
+func init() {
+	Register(Registration{
+		Name:      merge.SourceSmartctl,
+		Platforms: []string{PlatformLinux, PlatformDarwin, PlatformWindows},
+		After:     []string{merge.SourceLsblk, merge.SourceDiskutil},
+		New:       func() ToolInterface { return NewSmartCtlTool() },
+	})
+}
 
 // NewSmartCtlTool creates a new SmartCtlTool instance
 func NewSmartCtlTool() *SmartCtlTool {
-	return &SmartCtlTool{}
+	return &SmartCtlTool{driveDB: drivedb.Default()}
 }
 
 // IsAvailable checks if smartctl is available on the system
@@ -50,12 +69,12 @@ func (s *SmartCtlTool) GetDisks() []types.DiskInfo {
 		return disks
 	}
 
-	log.Printf("Detecting disks using smartctl...")
+	slog.Debug("Detecting disks", "tool", "smartctl")
 
 	// Get list of available devices
 	output, err := exec.Command("smartctl", "--scan").Output()
 	if err != nil {
-		log.Printf("Error scanning for devices with smartctl: %v", err)
+		slog.Error("Error scanning for devices", "tool", "smartctl", "err", err)
 		return disks
 	}
 
@@ -72,9 +91,10 @@ func (s *SmartCtlTool) GetDisks() []types.DiskInfo {
 
 		device := fields[0]
 
-		// Check various device types (sd*, nvme*, etc.)
+		// Check various device types (sd*, nvme*, etc., and disk* on macOS)
 		if strings.Contains(device, "sd") || strings.Contains(device, "nvme") ||
-			strings.Contains(device, "hd") || strings.Contains(device, "vd") {
+			strings.Contains(device, "hd") || strings.Contains(device, "vd") ||
+			strings.HasPrefix(device, "/dev/disk") {
 
 			diskInfo := s.getSmartCtlInfo(device)
 			if diskInfo.Device != "" {
@@ -84,7 +104,7 @@ func (s *SmartCtlTool) GetDisks() []types.DiskInfo {
 		}
 	}
 
-	log.Printf("Found %d disks using smartctl", len(disks))
+	slog.Debug("Found disks", "tool", "smartctl", "disks", len(disks))
 	return disks
 }
 
@@ -119,16 +139,23 @@ func (s *SmartCtlTool) getSmartCtlInfoWithType(device, deviceType string) types.
 
 	output, err := cmd.Output()
 	if err != nil {
-		log.Printf("Error getting smartctl info for %s (%s): %v", device, deviceType, err)
+		slog.Warn("Error getting SMART info", "tool", "smartctl", "device", device, "device_type", deviceType, "err", err)
 		return diskInfo
 	}
 
 	var smartData types.SmartCtlOutput
 	if err := json.Unmarshal(output, &smartData); err != nil {
-		log.Printf("Error parsing smartctl JSON for %s: %v", device, err)
+		slog.Warn("Error parsing SMART JSON", "tool", "smartctl", "device", device, "err", err)
 		return diskInfo
 	}
 
+	return s.parseSmartCtlOutput(device, &smartData)
+}
+
+// parseSmartCtlOutput converts the smartctl JSON report of a device into disk information
+func (s *SmartCtlTool) parseSmartCtlOutput(device string, smartData *types.SmartCtlOutput) types.DiskInfo {
+	var diskInfo types.DiskInfo
+
 	// Basic information
 	diskInfo.Device = device
 	diskInfo.Serial = smartData.SerialNumber
@@ -146,22 +173,33 @@ func (s *SmartCtlTool) getSmartCtlInfoWithType(device, deviceType string) types.
 	diskInfo.SmartHealthy = smartData.SmartStatus.Passed
 	if smartData.SmartStatus.Passed {
 		diskInfo.Health = "OK"
+		diskInfo.HealthStatus = types.HealthStatusOK
 	} else {
 		diskInfo.Health = "FAILED"
+		diskInfo.HealthStatus = types.HealthStatusCritical
 	}
 
-	// Temperature information
+	// Temperature information. Lifetime extremes come from the SCT status of
+	// ATA drives; drives without it may still pack them into attribute 194.
 	diskInfo.Temperature = float64(smartData.Temperature.Current)
+	if smartData.Temperature.LifetimeMax > 0 {
+		diskInfo.DriveTemperatureMin = float64(smartData.Temperature.LifetimeMin)
+		diskInfo.DriveTemperatureMax = float64(smartData.Temperature.LifetimeMax)
+	}
 
 	// Power information
 	diskInfo.PowerOnHours = int64(smartData.PowerOnTime.Hours)
 	diskInfo.PowerCycles = int64(smartData.PowerCycleCount)
 
 	// Handle different device types
-	if strings.Contains(strings.ToLower(diskInfo.Interface), "nvme") {
-		s.extractNVMeMetrics(&diskInfo, &smartData)
-	} else {
-		s.extractATAMetrics(&diskInfo, &smartData)
+	protocol := strings.ToLower(diskInfo.Interface)
+	switch {
+	case strings.Contains(protocol, "nvme"):
+		s.extractNVMeMetrics(&diskInfo, smartData)
+	case strings.Contains(protocol, "scsi"):
+		s.extractSCSIMetrics(&diskInfo, smartData)
+	default:
+		s.extractATAMetrics(&diskInfo, smartData)
 	}
 
 	return diskInfo
@@ -178,43 +216,289 @@ func (s *SmartCtlTool) extractNVMeMetrics(diskInfo *types.DiskInfo, smartData *t
 	diskInfo.ErrorLogEntries = nvme.NumErrLogEntries
 	diskInfo.TotalLBAsWritten = nvme.DataUnitsWritten
 	diskInfo.TotalLBAsRead = nvme.DataUnitsRead
+	diskInfo.BytesWritten = nvme.DataUnitsWritten * nvmeDataUnitBytes
+	diskInfo.BytesRead = nvme.DataUnitsRead * nvmeDataUnitBytes
 	diskInfo.PowerOnHours = nvme.PowerOnHours
 	diskInfo.PowerCycles = nvme.PowerCycles
 
-	// Additional temperature sensors for NVMe
-	if nvme.TemperatureSensor1 > 0 {
-		diskInfo.DriveTemperatureMax = float64(nvme.TemperatureSensor1)
-	}
-	if nvme.TemperatureSensor2 > 0 && nvme.TemperatureSensor2 < int(diskInfo.DriveTemperatureMax) {
-		diskInfo.DriveTemperatureMin = float64(nvme.TemperatureSensor2)
+	// Additional temperature sensors, numbered from 1 by their position in the log
+	for i, celsius := range nvme.TemperatureSensors {
+		if celsius != nil {
+			diskInfo.TemperatureSensors = append(diskInfo.TemperatureSensors, types.TemperatureSensor{
+				Sensor:  i + 1,
+				Celsius: float64(*celsius),
+			})
+		}
 	}
+
+	diskInfo.SelfTests = nvmeSelfTests(smartData, diskInfo.PowerOnHours)
 }
 
-// extractATAMetrics extracts ATA/SATA-specific metrics
+// extractATAMetrics extracts ATA/SATA-specific metrics, decoding attributes
+// through the drive database to account for vendor-specific encodings
 func (s *SmartCtlTool) extractATAMetrics(diskInfo *types.DiskInfo, smartData *types.SmartCtlOutput) {
+	attrs := make([]drivedb.Attribute, 0, len(smartData.AtaSmartAttributes.Table))
 	for _, attr := range smartData.AtaSmartAttributes.Table {
 		switch attr.ID {
-		case 5: // Reallocated Sector Count
-			diskInfo.ReallocatedSectors = attr.Raw.Value
-		case 196: // Reallocation Event Count
-			if diskInfo.ReallocatedSectors == 0 {
-				diskInfo.ReallocatedSectors = attr.Raw.Value
-			}
-		case 197: // Current Pending Sector Count
-			diskInfo.PendingSectors = attr.Raw.Value
-		case 198: // Uncorrectable Sector Count
-			diskInfo.UncorrectableErrors = attr.Raw.Value
-		case 231: // SSD Life Left (some drives)
-			diskInfo.WearLeveling = 100 - attr.Value // Convert to wear percentage
-		case 233: // Media Wearout Indicator (Intel SSDs)
-			diskInfo.WearLeveling = 100 - attr.Value
-		case 241: // Total LBAs Written
+		case 241: // Total LBAs Written (raw, unit varies by vendor)
 			diskInfo.TotalLBAsWritten = attr.Raw.Value
-		case 242: // Total LBAs Read
+		case 242: // Total LBAs Read (raw, unit varies by vendor)
 			diskInfo.TotalLBAsRead = attr.Raw.Value
 		}
+		attrs = append(attrs, drivedb.Attribute{
+			ID:    attr.ID,
+			Name:  attr.Name,
+			Value: attr.Value,
+			Raw:   attr.Raw.Value,
+		})
+	}
+
+	db := s.driveDB
+	if db == nil {
+		db = drivedb.Default()
+	}
+	values := db.Decode(smartData.ModelName, attrs, smartData.LogicalBlockSize)
+
+	if v, ok := values[drivedb.FieldPowerOnHours]; ok {
+		diskInfo.PowerOnHours = int64(v)
+	}
+	if v, ok := values[drivedb.FieldTemperature]; ok && v > 0 {
+		diskInfo.Temperature = v
+	}
+	// Packed min/max bytes are only meaningful when they bracket the current
+	// temperature, and are only used when the SCT status reports no lifetime extremes
+	minTemp, hasMin := values[drivedb.FieldTemperatureMin]
+	maxTemp, hasMax := values[drivedb.FieldTemperatureMax]
+	if diskInfo.DriveTemperatureMax == 0 && hasMin && hasMax && minTemp > 0 && minTemp <= diskInfo.Temperature && diskInfo.Temperature <= maxTemp {
+		diskInfo.DriveTemperatureMin = minTemp
+		diskInfo.DriveTemperatureMax = maxTemp
+	}
+
+	if v, ok := values[drivedb.FieldReallocatedSectors]; ok {
+		diskInfo.ReallocatedSectors = int64(v)
+	}
+	if v, ok := values[drivedb.FieldReallocationEvents]; ok && diskInfo.ReallocatedSectors == 0 {
+		diskInfo.ReallocatedSectors = int64(v)
+	}
+	if v, ok := values[drivedb.FieldPendingSectors]; ok {
+		diskInfo.PendingSectors = int64(v)
+	}
+	if v, ok := values[drivedb.FieldUncorrectableErrors]; ok {
+		diskInfo.UncorrectableErrors = int64(v)
+	}
+	if v, ok := values[drivedb.FieldWear]; ok {
+		diskInfo.WearLeveling = int(v)
+	}
+	if v, ok := values[drivedb.FieldBytesWritten]; ok {
+		diskInfo.BytesWritten = int64(v)
+	}
+	if v, ok := values[drivedb.FieldBytesRead]; ok {
+		diskInfo.BytesRead = int64(v)
 	}
 
 	// Error log entries
 	diskInfo.ErrorLogEntries = int64(smartData.AtaSmartErrorLog.Summary.Count)
+
+	diskInfo.SelfTests = ataSelfTests(smartData, diskInfo.PowerOnHours)
+}
+
+// extractSCSIMetrics extracts SAS/SCSI-specific metrics
+func (s *SmartCtlTool) extractSCSIMetrics(diskInfo *types.DiskInfo, smartData *types.SmartCtlOutput) {
+	errorLog := &smartData.ScsiErrorCounterLog
+
+	diskInfo.BytesWritten = scsiGigabytesToBytes(errorLog.Write.GigabytesProcessed)
+	diskInfo.BytesRead = scsiGigabytesToBytes(errorLog.Read.GigabytesProcessed)
+}
+
+// nvmeDataUnitBytes is the size of an NVMe data unit (1000 512-byte sectors)
+const nvmeDataUnitBytes = 512000
+
+// scsiGigabytesToBytes converts the decimal gigabytes reported in the SCSI error counter log to bytes
+func scsiGigabytesToBytes(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	gigabytes, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(gigabytes * 1e9)
+}
+
+// ataSelfTestTypes names the test types of the ATA self-test log by the low
+// seven bits of the type; the high bit only marks a test run in captive mode
+var ataSelfTestTypes = map[int]string{
+	1: types.SelfTestShort,
+	2: types.SelfTestLong,
+	3: types.SelfTestConveyance,
+	4: types.SelfTestSelective,
+}
+
+// ataSelfTestResults translates the high nibble of an ATA self-test status
+var ataSelfTestResults = map[int]types.SelfTestResult{
+	0x0: types.SelfTestResultPassed,  // Completed without error
+	0x1: types.SelfTestResultAborted, // Aborted by host
+	0x2: types.SelfTestResultAborted, // Interrupted by host reset
+	0x3: types.SelfTestResultFailed,  // Fatal or unknown error
+	0x4: types.SelfTestResultFailed,  // Completed: unknown failure
+	0x5: types.SelfTestResultFailed,  // Completed: electrical failure
+	0x6: types.SelfTestResultFailed,  // Completed: servo/seek failure
+	0x7: types.SelfTestResultFailed,  // Completed: read failure
+	0x8: types.SelfTestResultFailed,  // Completed: handling damage
+}
+
+// ataSelfTestInProgress is the high nibble of a self-test status while the test runs
+const ataSelfTestInProgress = 0xF
+
+// nvmeSelfTestTypes names the test types of the NVMe self-test log
+var nvmeSelfTestTypes = map[int]string{
+	0x1: types.SelfTestShort,
+	0x2: types.SelfTestLong,
+	0xE: types.SelfTestVendor,
+}
+
+// nvmeSelfTestResults translates the result of an NVMe self-test log entry
+var nvmeSelfTestResults = map[int]types.SelfTestResult{
+	0x0: types.SelfTestResultPassed,  // Completed without error
+	0x1: types.SelfTestResultAborted, // Aborted by a Device Self-test command
+	0x2: types.SelfTestResultAborted, // Aborted by a controller reset
+	0x3: types.SelfTestResultAborted, // Aborted by a namespace removal
+	0x4: types.SelfTestResultAborted, // Aborted by a Format NVM command
+	0x5: types.SelfTestResultFailed,  // Fatal or unknown test error
+	0x6: types.SelfTestResultFailed,  // Completed with an unknown failed segment
+	0x7: types.SelfTestResultFailed,  // Completed with failed segments
+	0x8: types.SelfTestResultAborted, // Aborted for unknown reason
+	0x9: types.SelfTestResultAborted, // Aborted by a sanitize operation
+}
+
+// nvmeSelfTestUnused is the result of a log entry that holds no test
+const nvmeSelfTestUnused = 0xF
+
+// ataSelfTestHoursMask is the range of the power-on hours recorded in the ATA
+// self-test log, which wrap at 65536 hours
+const ataSelfTestHoursMask = 0xFFFF
+
+// ataSelfTests reads the ATA self-test log, or returns nil if smartctl reported none
+func ataSelfTests(smartData *types.SmartCtlOutput, powerOnHours int64) *types.SelfTestInfo {
+	if smartData.AtaSmartSelfTestLog == nil {
+		return nil
+	}
+
+	info := &types.SelfTestInfo{}
+	status := smartData.AtaSmartData.SelfTest.Status
+	if status.Value>>4 == ataSelfTestInProgress {
+		info.Running = true
+		info.RunningProgress = 100 - status.RemainingPercent
+	}
+
+	for _, entry := range smartData.AtaSmartSelfTestLog.Standard.Table {
+		code := entry.Status.Value >> 4
+		if code == ataSelfTestInProgress {
+			continue
+		}
+		testType, ok := ataSelfTestTypes[entry.Type.Value&0x7F]
+		if !ok {
+			testType = types.SelfTestVendor
+		}
+
+		// The log only keeps the low 16 bits of the power-on hours
+		age := powerOnHours - entry.LifetimeHours
+		if powerOnHours > ataSelfTestHoursMask {
+			age = (powerOnHours - entry.LifetimeHours) & ataSelfTestHoursMask
+		}
+		addSelfTest(info, types.SelfTestEntry{
+			Type:          testType,
+			Result:        ataSelfTestResults[code],
+			Status:        entry.Status.String,
+			PowerOnHours:  entry.LifetimeHours,
+			AgeHours:      max(age, 0),
+			FirstErrorLBA: lbaOrNone(entry.LBA),
+		})
+	}
+	return info
+}
+
+// nvmeSelfTests reads the NVMe self-test log, or returns nil if smartctl reported none
+func nvmeSelfTests(smartData *types.SmartCtlOutput, powerOnHours int64) *types.SelfTestInfo {
+	log := smartData.NvmeSelfTestLog
+	if log == nil {
+		return nil
+	}
+
+	info := &types.SelfTestInfo{}
+	if operation := log.CurrentSelfTestOperation.Value; operation != 0 {
+		info.Running = true
+		info.RunningType = nvmeSelfTestTypes[operation]
+		info.RunningProgress = log.CurrentSelfTestCompletionPercent
+	}
+
+	for _, entry := range log.Table {
+		code := entry.SelfTestResult.Value & 0xF
+		if code == nvmeSelfTestUnused {
+			continue
+		}
+		testType, ok := nvmeSelfTestTypes[entry.SelfTestCode.Value]
+		if !ok {
+			testType = types.SelfTestVendor
+		}
+		addSelfTest(info, types.SelfTestEntry{
+			Type:          testType,
+			Result:        nvmeSelfTestResults[code],
+			Status:        entry.SelfTestResult.String,
+			PowerOnHours:  entry.PowerOnHours,
+			AgeHours:      max(powerOnHours-entry.PowerOnHours, 0),
+			FirstErrorLBA: lbaOrNone(entry.LBA),
+		})
+	}
+	return info
+}
+
+// addSelfTest keeps a log entry if it is the first of its type. Logs list the
+// most recent test first, so the first entry of a type is its latest.
+func addSelfTest(info *types.SelfTestInfo, entry types.SelfTestEntry) {
+	for _, latest := range info.Latest {
+		if latest.Type == entry.Type {
+			return
+		}
+	}
+	info.Latest = append(info.Latest, entry)
+}
+
+// lbaOrNone returns a reported LBA, or -1 if none was reported
+func lbaOrNone(lba *int64) int64 {
+	if lba == nil {
+		return -1
+	}
+	return *lba
+}
+
+// smartctlCommandFailed masks the smartctl exit status bits reporting that the
+// command line, the device open or a SMART command failed; higher bits
+// describe the state of the disk
+const smartctlCommandFailed = 0x07
+
+// StartSelfTest starts a self-test of a disk, reaching drives behind a RAID
+// controller through their SMART passthrough device. The drive runs the test
+// in the background; its progress and result show up in the self-test log.
+// smartctl -t short|long [-d TYPE] DEVICE # start a self-test and return immediately
+func (s *SmartCtlTool) StartSelfTest(disk types.DiskInfo, testType string) error {
+	args := []string{"-t", testType}
+	device := disk.Device
+	if disk.SmartDevice != "" && disk.SmartDeviceType != "" {
+		args = append(args, "-d", disk.SmartDeviceType)
+		device = disk.SmartDevice
+	}
+
+	output, err := exec.Command("smartctl", append(args, device)...).CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode()&smartctlCommandFailed == 0 {
+		// Only bits describing the disk are set, the test was started
+		return nil
+	}
+	if err != nil {
+		// smartctl reports why the test was refused on its output
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		return fmt.Errorf("smartctl -t %s %s: %w: %s", testType, device, err, lines[len(lines)-1])
+	}
+	return nil
 }
@@ -0,0 +1,246 @@
+package tools
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"disk-health-exporter/pkg/types"
+)
+
+func TestSmartCtlHostBytesNormalization(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		expectedWrite int64
+		expectedRead  int64
+	}{
+		{
+			name: "nvme data units",
+			output: `{"device":{"protocol":"NVMe"},
+				"nvme_smart_health_information_log":{"data_units_written":2000,"data_units_read":1000}}`,
+			expectedWrite: 2000 * 512000,
+			expectedRead:  1000 * 512000,
+		},
+		{
+			name: "ata logical blocks",
+			output: `{"device":{"protocol":"ATA"},"logical_block_size":512,
+				"ata_smart_attributes":{"table":[
+					{"id":241,"name":"Total_LBAs_Written","raw":{"value":1000}},
+					{"id":242,"name":"Total_LBAs_Read","raw":{"value":500}}]}}`,
+			expectedWrite: 1000 * 512,
+			expectedRead:  500 * 512,
+		},
+		{
+			name: "ata 4k logical blocks",
+			output: `{"device":{"protocol":"ATA"},"logical_block_size":4096,
+				"ata_smart_attributes":{"table":[{"id":241,"name":"Total_LBAs_Written","raw":{"value":10}}]}}`,
+			expectedWrite: 10 * 4096,
+		},
+		{
+			name: "ata 32MiB units",
+			output: `{"device":{"protocol":"ATA"},"logical_block_size":512,
+				"ata_smart_attributes":{"table":[
+					{"id":241,"name":"Host_Writes_32MiB","raw":{"value":3}},
+					{"id":242,"name":"Host_Reads_32MiB","raw":{"value":2}}]}}`,
+			expectedWrite: 3 * 32 << 20,
+			expectedRead:  2 * 32 << 20,
+		},
+		{
+			name: "ata GiB units",
+			output: `{"device":{"protocol":"ATA"},
+				"ata_smart_attributes":{"table":[{"id":241,"name":"Lifetime_Writes_GiB","raw":{"value":7}}]}}`,
+			expectedWrite: 7 << 30,
+		},
+		{
+			name: "scsi error counter log",
+			output: `{"device":{"protocol":"SCSI"},
+				"scsi_error_counter_log":{"read":{"gigabytes_processed":"1234.567"},"write":{"gigabytes_processed":"89.001"}}}`,
+			expectedWrite: 89_001_000_000,
+			expectedRead:  1_234_567_000_000,
+		},
+	}
+
+	tool := NewSmartCtlTool()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var smartData types.SmartCtlOutput
+			if err := json.Unmarshal([]byte(tt.output), &smartData); err != nil {
+				t.Fatalf("Invalid test fixture: %v", err)
+			}
+
+			var disk types.DiskInfo
+			switch smartData.Device.Protocol {
+			case "NVMe":
+				tool.extractNVMeMetrics(&disk, &smartData)
+			case "SCSI":
+				tool.extractSCSIMetrics(&disk, &smartData)
+			default:
+				tool.extractATAMetrics(&disk, &smartData)
+			}
+
+			if disk.BytesWritten != tt.expectedWrite {
+				t.Errorf("Expected %d bytes written, got %d", tt.expectedWrite, disk.BytesWritten)
+			}
+			if disk.BytesRead != tt.expectedRead {
+				t.Errorf("Expected %d bytes read, got %d", tt.expectedRead, disk.BytesRead)
+			}
+		})
+	}
+}
+
+func TestSmartCtlATAVendorDecoding(t *testing.T) {
+	output := `{"device":{"protocol":"ATA"},"model_name":"Samsung SSD 870 EVO 1TB","logical_block_size":512,
+		"temperature":{"current":33},
+		"ata_smart_attributes":{"table":[
+			{"id":9,"name":"Power_On_Hours","value":95,"raw":{"value":21000}},
+			{"id":177,"name":"Wear_Leveling_Count","value":98,"raw":{"value":25}},
+			{"id":194,"name":"Temperature_Celsius","value":67,"raw":{"value":64425820193}},
+			{"id":233,"name":"Media_Wearout_Indicator","value":1,"raw":{"value":0}}]}}`
+
+	var smartData types.SmartCtlOutput
+	if err := json.Unmarshal([]byte(output), &smartData); err != nil {
+		t.Fatalf("Invalid test fixture: %v", err)
+	}
+
+	disk := types.DiskInfo{Temperature: float64(smartData.Temperature.Current)}
+	NewSmartCtlTool().extractATAMetrics(&disk, &smartData)
+
+	// The generic rule would read 233 as 99% wear; the vendor entry decodes 177 instead
+	if disk.WearLeveling != 2 {
+		t.Errorf("Expected wear 2%%, got %d", disk.WearLeveling)
+	}
+	if disk.PowerOnHours != 21000 {
+		t.Errorf("Expected 21000 power-on hours, got %d", disk.PowerOnHours)
+	}
+	// Raw 0x000F_0014_0021: current 33, min 20, max 15 does not bracket current
+	if disk.DriveTemperatureMin != 0 || disk.DriveTemperatureMax != 0 {
+		t.Errorf("Expected inconsistent packed min/max to be ignored, got %v/%v", disk.DriveTemperatureMin, disk.DriveTemperatureMax)
+	}
+}
+
+func TestSmartCtlTemperatures(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		expectedMin float64
+		expectedMax float64
+		sensors     []types.TemperatureSensor
+	}{
+		{
+			name: "ata sct lifetime extremes",
+			output: `{"device":{"protocol":"ATA"},"model_name":"WDC WD40EFRX",
+				"temperature":{"current":35,"power_cycle_min":28,"power_cycle_max":38,"lifetime_min":18,"lifetime_max":52}}`,
+			expectedMin: 18,
+			expectedMax: 52,
+		},
+		{
+			// Raw 0x0028_0014_0021 packs current 33, min 20 and max 40
+			name: "sct lifetime extremes take precedence over packed attribute bytes",
+			output: `{"device":{"protocol":"ATA"},"model_name":"WDC WD40EFRX",
+				"temperature":{"current":33,"lifetime_min":12,"lifetime_max":58},
+				"ata_smart_attributes":{"table":[{"id":194,"name":"Temperature_Celsius","value":67,"raw":{"value":171800002593}}]}}`,
+			expectedMin: 12,
+			expectedMax: 58,
+		},
+		{
+			name: "nvme sensors are not lifetime extremes",
+			output: `{"device":{"protocol":"NVMe"},
+				"temperature":{"current":41},
+				"nvme_smart_health_information_log":{"temperature":41,"temperature_sensors":[41,null,55]}}`,
+			sensors: []types.TemperatureSensor{{Sensor: 1, Celsius: 41}, {Sensor: 3, Celsius: 55}},
+		},
+	}
+
+	tool := NewSmartCtlTool()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var smartData types.SmartCtlOutput
+			if err := json.Unmarshal([]byte(tt.output), &smartData); err != nil {
+				t.Fatalf("Invalid test fixture: %v", err)
+			}
+
+			disk := tool.parseSmartCtlOutput("/dev/sda", &smartData)
+			if disk.DriveTemperatureMin != tt.expectedMin || disk.DriveTemperatureMax != tt.expectedMax {
+				t.Errorf("Expected lifetime min/max %v/%v, got %v/%v", tt.expectedMin, tt.expectedMax, disk.DriveTemperatureMin, disk.DriveTemperatureMax)
+			}
+			if !slices.Equal(disk.TemperatureSensors, tt.sensors) {
+				t.Errorf("Expected sensors %v, got %v", tt.sensors, disk.TemperatureSensors)
+			}
+		})
+	}
+}
+
+func TestSmartCtlSelfTests(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		running  bool
+		progress int
+		latest   []types.SelfTestEntry
+	}{
+		{
+			// Status 249 (0xF9): test in progress with 90% remaining
+			name: "ata log with a test in progress and a read failure",
+			output: `{"device":{"protocol":"ATA"},"power_on_time":{"hours":1200},
+				"ata_smart_data":{"self_test":{"status":{"value":249,"string":"in progress, 90% remaining","remaining_percent":90}}},
+				"ata_smart_self_test_log":{"standard":{"table":[
+					{"type":{"value":1,"string":"Short offline"},"status":{"value":0,"string":"Completed without error"},"lifetime_hours":1190},
+					{"type":{"value":2,"string":"Extended offline"},"status":{"value":117,"string":"Completed: read failure"},"lifetime_hours":1000,"lba":123456},
+					{"type":{"value":1,"string":"Short offline"},"status":{"value":0,"string":"Completed without error"},"lifetime_hours":900}]}}}`,
+			running:  true,
+			progress: 10,
+			latest: []types.SelfTestEntry{
+				{Type: types.SelfTestShort, Result: types.SelfTestResultPassed, Status: "Completed without error", PowerOnHours: 1190, AgeHours: 10, FirstErrorLBA: -1},
+				{Type: types.SelfTestLong, Result: types.SelfTestResultFailed, Status: "Completed: read failure", PowerOnHours: 1000, AgeHours: 200, FirstErrorLBA: 123456},
+			},
+		},
+		{
+			// 70000 power-on hours are logged as 70000 - 65536 = 4464
+			name: "ata log hours wrap at 16 bits",
+			output: `{"device":{"protocol":"ATA"},"power_on_time":{"hours":70000},
+				"ata_smart_self_test_log":{"standard":{"table":[
+					{"type":{"value":130,"string":"Extended captive"},"status":{"value":0,"string":"Completed without error"},"lifetime_hours":4440}]}}}`,
+			latest: []types.SelfTestEntry{
+				{Type: types.SelfTestLong, Result: types.SelfTestResultPassed, Status: "Completed without error", PowerOnHours: 4440, AgeHours: 24, FirstErrorLBA: -1},
+			},
+		},
+		{
+			name: "nvme log skips unused entries",
+			output: `{"device":{"protocol":"NVMe"},
+				"nvme_smart_health_information_log":{"power_on_hours":500},
+				"nvme_self_test_log":{"current_self_test_operation":{"value":2,"string":"Extended self-test in progress"},"current_self_test_completion_percent":35,
+					"table":[
+						{"self_test_code":{"value":1,"string":"Short"},"self_test_result":{"value":15,"string":"Unused"},"power_on_hours":0},
+						{"self_test_code":{"value":1,"string":"Short"},"self_test_result":{"value":7,"string":"Completed: failed segments"},"power_on_hours":480,"lba":2048},
+						{"self_test_code":{"value":2,"string":"Extended"},"self_test_result":{"value":1,"string":"Aborted: Self-test command"},"power_on_hours":470}]}}`,
+			running:  true,
+			progress: 35,
+			latest: []types.SelfTestEntry{
+				{Type: types.SelfTestShort, Result: types.SelfTestResultFailed, Status: "Completed: failed segments", PowerOnHours: 480, AgeHours: 20, FirstErrorLBA: 2048},
+				{Type: types.SelfTestLong, Result: types.SelfTestResultAborted, Status: "Aborted: Self-test command", PowerOnHours: 470, AgeHours: 30, FirstErrorLBA: -1},
+			},
+		},
+	}
+
+	tool := NewSmartCtlTool()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var smartData types.SmartCtlOutput
+			if err := json.Unmarshal([]byte(tt.output), &smartData); err != nil {
+				t.Fatalf("Invalid test fixture: %v", err)
+			}
+
+			disk := tool.parseSmartCtlOutput("/dev/sda", &smartData)
+			if disk.SelfTests == nil {
+				t.Fatal("Expected self-test information")
+			}
+			if disk.SelfTests.Running != tt.running || disk.SelfTests.RunningProgress != tt.progress {
+				t.Errorf("Expected running %v at %d%%, got %v at %d%%", tt.running, tt.progress, disk.SelfTests.Running, disk.SelfTests.RunningProgress)
+			}
+			if !slices.Equal(disk.SelfTests.Latest, tt.latest) {
+				t.Errorf("Expected latest tests %+v, got %+v", tt.latest, disk.SelfTests.Latest)
+			}
+		})
+	}
+}
@@ -0,0 +1,638 @@
+package tools
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/statemap"
+	"disk-health-exporter/internal/utils"
+	"disk-health-exporter/pkg/types"
+)
+
+// Ensure SsacliTool implements the RAID, battery and controller interfaces
+var (
+	_ RAIDToolInterface       = (*SsacliTool)(nil)
+	_ BatteryToolInterface    = (*SsacliTool)(nil)
+	_ ControllerToolInterface = (*SsacliTool)(nil)
+)
+
+// SsacliTool represents the ssacli CLI tool for HPE Smart Array controllers
+type SsacliTool struct {
+	command string // "ssacli" or "hpssacli"
+}
+
+func init() {
+	Register(Registration{
+		Name:      merge.SourceSsacli,
+		Platforms: []string{PlatformLinux},
+		After:     []string{merge.SourceSmartctl, merge.SourceNvme, merge.SourceHdparm},
+		New:       func() ToolInterface { return NewSsacliTool() },
+	})
+}
+
+// ssacliDriveStates translates the status of physical drives
+var ssacliDriveStates = statemap.New(merge.SourceSsacli, map[string]types.HealthStatus{
+	"OK":                 types.HealthStatusOK,
+	"Predictive Failure": types.HealthStatusWarning,
+	"Rebuilding":         types.HealthStatusWarning,
+	"Erasing":            types.HealthStatusWarning,
+	"Failed":             types.HealthStatusCritical,
+})
+
+// ssacliDriveRoles translates the status of physical drives into their RAID
+// role. Drives in good order take their role from the drive type instead.
+var ssacliDriveRoles = statemap.New(merge.SourceSsacli, map[string]string{
+	"OK":                 "active",
+	"Predictive Failure": "failed",
+	"Rebuilding":         "rebuilding",
+	"Erasing":            "unconfigured",
+	"Failed":             "failed",
+})
+
+// ssacliLogicalDriveStates translates the status of logical drives, without
+// the progress ssacli appends to some of them
+var ssacliLogicalDriveStates = statemap.New(merge.SourceSsacli, map[string]types.RAIDState{
+	"OK":                                types.RAIDStateOptimal,
+	"Interim Recovery Mode":             types.RAIDStateDegraded,
+	"Recovering":                        types.RAIDStateDegraded,
+	"Ready for Rebuild":                 types.RAIDStateDegraded,
+	"Expanding":                         types.RAIDStateDegraded,
+	"Transforming":                      types.RAIDStateDegraded,
+	"Queued for Expansion":              types.RAIDStateDegraded,
+	"Queued for Transformation":         types.RAIDStateDegraded,
+	"Parity Initialization in Progress": types.RAIDStateDegraded,
+	"Failed":                            types.RAIDStateFailed,
+	"Disabled":                          types.RAIDStateFailed,
+	"Wrong Drive Replaced":              types.RAIDStateFailed,
+	"Drive(s) Disabled":                 types.RAIDStateFailed,
+})
+
+// ssacliLogicalDriveRebuilds lists the logical drive states of a running rebuild
+var ssacliLogicalDriveRebuilds = statemap.New(merge.SourceSsacli, map[string]bool{
+	"Recovering": true,
+})
+
+// NewSsacliTool creates a new SsacliTool instance
+func NewSsacliTool() *SsacliTool {
+	tool := &SsacliTool{}
+
+	// Determine which command to use, hpssacli is the name before Gen10
+	if utils.CommandExists("ssacli") {
+		tool.command = "ssacli"
+	} else if utils.CommandExists("hpssacli") {
+		tool.command = "hpssacli"
+	}
+
+	return tool
+}
+
+// IsAvailable checks if ssacli is available on the system
+func (s *SsacliTool) IsAvailable() bool {
+	return utils.CommandExists("ssacli") || utils.CommandExists("hpssacli")
+}
+
+// GetVersion returns the ssacli version
+func (s *SsacliTool) GetVersion() string {
+	if !s.IsAvailable() {
+		return ""
+	}
+
+	version, err := utils.GetToolVersion(s.command, "version")
+	if err != nil {
+		return "unknown"
+	}
+	return version
+}
+
+// GetName returns the tool name
+func (s *SsacliTool) GetName() string {
+	return "SSACLI"
+}
+
+// ssacliController is the configuration of one Smart Array controller
+type ssacliController struct {
+	info     types.RAIDControllerInfo
+	battery  *types.RAIDBatteryInfo
+	logical  []ssacliLogicalDrive
+	physical []ssacliPhysicalDrive
+
+	driveIndex map[string]int // Controller drive index of each drive ID (e.g. "1I:1:1"), used by cciss passthrough
+	diskName   string         // Block device of the first logical drive, through which smartctl reaches the drives
+}
+
+// ssacliLogicalDrive is a logical drive and the array ("A", "B", ...) it is carved from
+type ssacliLogicalDrive struct {
+	raid  types.RAIDInfo
+	array string
+}
+
+// ssacliPhysicalDrive is a physical drive, the array it belongs to ("" if unassigned) and its drive type
+type ssacliPhysicalDrive struct {
+	disk      types.DiskInfo
+	id        string // port:box:bay
+	array     string
+	driveType string // Data Drive, Spare Drive, Unassigned Drive, HBA Mode Drive
+}
+
+// role returns the RAID role of the drive, "unknown" for statuses missing from
+// ssacliDriveRoles. Unmapped statuses are recorded by ssacliDriveStates, which
+// sees the same strings.
+func (d ssacliPhysicalDrive) role() string {
+	role, ok := ssacliDriveRoles.Lookup(d.disk.Health)
+	if !ok {
+		return "unknown"
+	}
+	if role != "active" {
+		return role
+	}
+
+	switch d.driveType {
+	case "Data Drive":
+		return "active"
+	case "Spare Drive":
+		return "hot_spare"
+	case "Unassigned Drive", "HBA Mode Drive":
+		return "unconfigured"
+	default:
+		return "unknown"
+	}
+}
+
+// GetRAIDArrays returns the logical drives of all Smart Array controllers
+func (s *SsacliTool) GetRAIDArrays() []types.RAIDInfo {
+	var raidArrays []types.RAIDInfo
+
+	if !s.IsAvailable() {
+		return raidArrays
+	}
+
+	for _, controller := range s.getConfig() {
+		raidArrays = append(raidArrays, controller.arrays()...)
+	}
+
+	return raidArrays
+}
+
+// GetRAIDDisks returns the physical drives of all Smart Array controllers
+func (s *SsacliTool) GetRAIDDisks() []types.DiskInfo {
+	var disks []types.DiskInfo
+
+	if !s.IsAvailable() {
+		return disks
+	}
+
+	for _, controller := range s.getConfig() {
+		disks = append(disks, controller.disks()...)
+	}
+
+	// Read full SMART data of each drive through the controller
+	enrichWithPassthroughSMART(disks)
+
+	return disks
+}
+
+// GetControllers returns controller versions, status and drive counts for all Smart Array controllers
+func (s *SsacliTool) GetControllers() []types.RAIDControllerInfo {
+	var controllers []types.RAIDControllerInfo
+
+	if !s.IsAvailable() {
+		return controllers
+	}
+
+	for _, controller := range s.getConfig() {
+		controllers = append(controllers, controller.info)
+	}
+
+	return controllers
+}
+
+// GetBatteryInfo returns the Smart Storage Battery or capacitor status of the controller in the given slot
+func (s *SsacliTool) GetBatteryInfo(slot string) *types.RAIDBatteryInfo {
+	if !s.IsAvailable() {
+		return nil
+	}
+
+	for _, controller := range s.getConfig() {
+		if strconv.Itoa(controller.info.AdapterID) == slot {
+			return controller.battery
+		}
+	}
+
+	return nil
+}
+
+// getConfig reads the configuration of all controllers
+// ssacli ctrl all show config detail # get controllers, arrays, logical and physical drives
+func (s *SsacliTool) getConfig() []ssacliController {
+	output, err := exec.Command(s.command, "ctrl", "all", "show", "config", "detail").Output()
+	if err != nil {
+		slog.Error("Error getting controller configuration", "tool", "ssacli", "err", err)
+		return nil
+	}
+
+	return s.parseConfigDetail(string(output))
+}
+
+// ssacliControllerRe matches controller headers such as "Smart Array P440ar in Slot 0 (Embedded)"
+var ssacliControllerRe = regexp.MustCompile(`^(.+) in Slot (\d+)`)
+
+// ssacliPhysicalDriveRe matches "physicaldrive 1I:1:1" detail headers and the
+// "physicaldrive 1I:1:1 (port 1I:box 1:bay 1, SAS HDD, 600 GB, OK)" summary lines
+var ssacliPhysicalDriveRe = regexp.MustCompile(`^physicaldrive (\S+)(\s+\(.*\))?$`)
+
+// ssacliProgressRe matches progress such as "Recovering, 23% complete"
+var ssacliProgressRe = regexp.MustCompile(`(\d+)% complete`)
+
+// Sections of ssacli ctrl all show config detail
+const (
+	ssacliSectionOther = iota
+	ssacliSectionController
+	ssacliSectionLogicalDrive
+	ssacliSectionPhysicalDrive
+)
+
+// parseConfigDetail parses the output of ssacli ctrl all show config detail.
+// Controller headers are the only unindented lines. Logical and physical drives
+// are listed under "Array: X" or "Unassigned"; each physical drive appears once
+// in a one-line summary and once as a detail section.
+func (s *SsacliTool) parseConfigDetail(output string) []ssacliController {
+	var controllers []ssacliController
+	var controller *ssacliController
+	var section int
+	var array string
+
+	finalize := func() {
+		if controller != nil {
+			controller.info.NumVirtualDrives = len(controller.logical)
+			controller.info.NumPhysicalDrives = len(controller.physical)
+			controllers = append(controllers, *controller)
+		}
+	}
+
+	for _, rawLine := range strings.Split(output, "\n") {
+		line := strings.TrimSpace(rawLine)
+		if line == "" {
+			continue
+		}
+
+		if line == rawLine {
+			if matches := ssacliControllerRe.FindStringSubmatch(line); matches != nil {
+				finalize()
+				controller = newSsacliController(matches[1], matches[2])
+				section = ssacliSectionController
+				array = ""
+			}
+			continue
+		}
+		if controller == nil {
+			continue
+		}
+
+		if matches := ssacliPhysicalDriveRe.FindStringSubmatch(line); matches != nil {
+			id := matches[1]
+			// Drives are numbered in the order the controller lists them first
+			if _, seen := controller.driveIndex[id]; !seen {
+				controller.driveIndex[id] = len(controller.driveIndex)
+			}
+			if matches[2] == "" {
+				controller.physical = append(controller.physical, ssacliPhysicalDrive{
+					disk:  newSsacliDisk(controller.info.AdapterID, id),
+					id:    id,
+					array: array,
+				})
+				section = ssacliSectionPhysicalDrive
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			if strings.HasSuffix(line, ":") {
+				// "Mirror Group 1:" lists the members of a logical drive
+				continue
+			}
+			// Headers such as "Unassigned", "Physical Drives" or "Internal Drive Cage at Port 1I, Box 1, OK"
+			if line == "Unassigned" || line == "HBA Drives" {
+				array = ""
+			}
+			section = ssacliSectionOther
+			continue
+		}
+		// Some keys are padded, e.g. "Capacitor Temperature  (C)"
+		key = strings.Join(strings.Fields(key), " ")
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "Array":
+			array = value
+			section = ssacliSectionOther
+			continue
+		case "Logical Drive":
+			controller.logical = append(controller.logical, ssacliLogicalDrive{
+				raid: types.RAIDInfo{
+					ArrayID:    fmt.Sprintf("%d:%s", controller.info.AdapterID, value),
+					Type:       "hardware",
+					Controller: "SSACLI - " + controller.info.Model,
+				},
+				array: array,
+			})
+			section = ssacliSectionLogicalDrive
+			continue
+		case "Port Name":
+			section = ssacliSectionOther
+			continue
+		}
+
+		switch section {
+		case ssacliSectionController:
+			s.parseControllerLine(controller, key, value)
+		case ssacliSectionLogicalDrive:
+			s.parseLogicalDriveLine(controller, &controller.logical[len(controller.logical)-1].raid, key, value)
+		case ssacliSectionPhysicalDrive:
+			s.parsePhysicalDriveLine(&controller.physical[len(controller.physical)-1], key, value)
+		}
+	}
+	finalize()
+
+	return controllers
+}
+
+// newSsacliController starts a controller from its header, e.g. "Smart Array P440ar" in slot "0"
+func newSsacliController(model, slot string) *ssacliController {
+	adapterID, _ := strconv.Atoi(slot)
+	return &ssacliController{
+		info: types.RAIDControllerInfo{
+			AdapterID: adapterID,
+			ToolName:  "SSACLI",
+			Model:     model,
+		},
+		driveIndex: make(map[string]int),
+	}
+}
+
+// newSsacliDisk starts a physical drive from its port:box:bay ID
+func newSsacliDisk(slot int, id string) types.DiskInfo {
+	disk := types.DiskInfo{
+		Device:    fmt.Sprintf("raid-slot%d-%s", slot, id),
+		Location:  fmt.Sprintf("Slot:%d %s", slot, id),
+		Type:      "raid",
+		RaidDrive: &types.RAIDDriveInfo{ToolName: "SSACLI"},
+	}
+	if parts := strings.Split(id, ":"); len(parts) == 3 {
+		disk.Device = fmt.Sprintf("raid-slot%d-port%s-box%s-bay%s", slot, parts[0], parts[1], parts[2])
+		disk.Location = fmt.Sprintf("Slot:%d Port:%s Box:%s Bay:%s", slot, parts[0], parts[1], parts[2])
+	}
+	return disk
+}
+
+// parseControllerLine parses a controller property, including the cache module and battery status
+func (s *SsacliTool) parseControllerLine(controller *ssacliController, key, value string) {
+	info := &controller.info
+
+	switch key {
+	case "Serial Number":
+		info.SerialNumber = value
+	case "Controller Status":
+		info.Status = value
+	case "Firmware Version":
+		info.FirmwareVersion = value
+	case "Driver Version":
+		info.DriverVersion = value
+	case "Controller Temperature (C)":
+		info.ROCTemperature, _ = strconv.Atoi(value)
+	case "Surface Scan Mode":
+		// HPE's equivalent of a patrol read (Idle, High, Disabled)
+		info.PatrolReadMode = value
+	case "Cache Board Present":
+		if value == "False" {
+			info.CacheStatus = "Not Present"
+		}
+	case "Cache Status":
+		info.CacheStatus = value
+	case "Total Cache Size":
+		// Older firmware reports a bare number of GB, e.g. "2.0"
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			value += " GB"
+		}
+		info.CacheSize = utils.ParseSizeToBytes(value)
+	case "Cache Backup Power Source":
+		battery := s.battery(controller)
+		switch value {
+		case "Batteries":
+			battery.BatteryType = "Smart Storage Battery"
+		case "Capacitors":
+			battery.BatteryType = "Capacitor"
+		default:
+			battery.BatteryType = value
+		}
+	case "Battery/Capacitor Count":
+		if value == "0" {
+			battery := s.battery(controller)
+			battery.BatteryMissing = true
+			battery.State = "Missing"
+		}
+	case "Battery/Capacitor Status":
+		s.setBatteryStatus(s.battery(controller), value)
+	case "Battery Temperature (C)", "Capacitor Temperature (C)":
+		s.battery(controller).Temperature, _ = strconv.Atoi(value)
+	}
+}
+
+// battery returns the battery of a controller, creating it on first use
+func (s *SsacliTool) battery(controller *ssacliController) *types.RAIDBatteryInfo {
+	if controller.battery == nil {
+		controller.battery = &types.RAIDBatteryInfo{
+			AdapterID: controller.info.AdapterID,
+			ToolName:  "SSACLI",
+		}
+	}
+	return controller.battery
+}
+
+// setBatteryStatus maps "Battery/Capacitor Status" to the battery states shared with the other RAID tools.
+// Statuses include OK, Recharging, Not Present and Failed (Replace Batteries/Capacitors).
+func (s *SsacliTool) setBatteryStatus(battery *types.RAIDBatteryInfo, status string) {
+	lower := strings.ToLower(status)
+
+	switch {
+	case lower == "ok":
+		battery.State = "Optimal"
+	case strings.Contains(lower, "charging"):
+		battery.State = "Charging"
+		battery.ChargingStatus = "Charging"
+	case strings.Contains(lower, "not present"):
+		battery.State = "Missing"
+		battery.BatteryMissing = true
+	case strings.Contains(lower, "failed"):
+		battery.State = "Failed"
+	default:
+		battery.State = status
+	}
+	battery.ReplacementRequired = strings.Contains(lower, "replace")
+}
+
+// parseLogicalDriveLine parses a logical drive property
+func (s *SsacliTool) parseLogicalDriveLine(controller *ssacliController, raid *types.RAIDInfo, key, value string) {
+	switch key {
+	case "Size":
+		raid.Size = utils.ParseSizeToBytes(value)
+	case "Fault Tolerance":
+		raid.RaidLevel = s.normalizeRAIDLevel(value)
+	case "Status":
+		// e.g. "OK", "Interim Recovery Mode" or "Recovering, 23% complete"
+		state, _, _ := strings.Cut(value, ",")
+		raid.State = state
+		raid.Status = ssacliLogicalDriveStates.Map(state)
+		raid.Rebuilding, _ = ssacliLogicalDriveRebuilds.Lookup(state)
+		if matches := ssacliProgressRe.FindStringSubmatch(value); matches != nil && raid.Rebuilding {
+			raid.RebuildProgress, _ = strconv.Atoi(matches[1])
+		}
+	case "Disk Name":
+		raid.VirtualDevice = value
+		if controller.diskName == "" {
+			controller.diskName = value
+		}
+	}
+}
+
+// parsePhysicalDriveLine parses a physical drive property
+func (s *SsacliTool) parsePhysicalDriveLine(drive *ssacliPhysicalDrive, key, value string) {
+	disk := &drive.disk
+
+	switch key {
+	case "Status":
+		disk.Health = value
+		disk.HealthStatus = ssacliDriveStates.Map(value)
+		disk.RaidDrive.FirmwareState = value
+	case "Drive Type":
+		drive.driveType = value
+	case "Interface Type":
+		// e.g. "SAS", "Solid State SATA"
+		disk.Interface = strings.TrimPrefix(value, "Solid State ")
+	case "Size":
+		disk.Capacity = utils.ParseSizeToBytes(value)
+	case "Rotational Speed":
+		disk.RPM, _ = strconv.Atoi(value)
+	case "Serial Number":
+		disk.Serial = value
+	case "Model":
+		// The vendor is padded in front of the model, e.g. "HP      EG0600FBVFP"
+		fields := strings.Fields(value)
+		if len(fields) > 1 {
+			disk.Vendor = fields[0]
+			disk.Model = strings.Join(fields[1:], " ")
+		} else {
+			disk.Model = value
+		}
+	case "Current Temperature (C)":
+		if temperature, err := strconv.ParseFloat(value, 64); err == nil {
+			disk.Temperature = temperature
+		}
+	case "Maximum Temperature (C)":
+		if temperature, err := strconv.ParseFloat(value, 64); err == nil {
+			disk.DriveTemperatureMax = temperature
+		}
+	case "PHY Transfer Rate":
+		// One rate per PHY, e.g. "6.0Gbps, Unknown"
+		disk.RaidDrive.LinkSpeed = s.firstPHYRate(value)
+	case "PHY Maximum Link Rate":
+		disk.RaidDrive.DeviceSpeed = s.firstPHYRate(value)
+	}
+}
+
+// firstPHYRate returns the rate of the first PHY, or "" if it is unknown
+func (s *SsacliTool) firstPHYRate(value string) string {
+	rate, _, _ := strings.Cut(value, ",")
+	if rate == "Unknown" {
+		return ""
+	}
+	return strings.TrimSpace(rate)
+}
+
+// arrays returns the logical drives of the controller with member counts and battery attached
+func (c *ssacliController) arrays() []types.RAIDInfo {
+	var arrays []types.RAIDInfo
+
+	for _, logical := range c.logical {
+		raid := logical.raid
+		for _, drive := range c.physical {
+			if drive.array == "" || drive.array != logical.array {
+				continue
+			}
+			if drive.driveType == "Spare Drive" {
+				raid.NumSpareDrives++
+				continue
+			}
+			raid.NumDrives++
+			switch drive.role() {
+			case "active":
+				raid.NumActiveDrives++
+			case "failed":
+				raid.NumFailedDrives++
+			}
+		}
+		raid.Battery = c.battery
+		arrays = append(arrays, raid)
+	}
+
+	return arrays
+}
+
+// disks returns the physical drives of the controller with their RAID role and smartctl passthrough address
+func (c *ssacliController) disks() []types.DiskInfo {
+	var disks []types.DiskInfo
+
+	for _, drive := range c.physical {
+		disk := drive.disk
+		disk.RaidRole = drive.role()
+		// Smart Array spares always belong to an array
+		if drive.driveType == "Spare Drive" {
+			disk.IsDedicatedSpare = true
+			disk.RaidPosition = "Spare"
+		}
+
+		// A drive belongs to every logical drive of its array, report the first
+		for _, logical := range c.logical {
+			if drive.array != "" && logical.array == drive.array {
+				disk.RaidArrayID = logical.raid.ArrayID
+				break
+			}
+		}
+
+		setCCISSPassthrough(&disk, c.diskName, c.driveIndex[drive.id])
+		disks = append(disks, disk)
+	}
+
+	return disks
+}
+
+// normalizeRAIDLevel converts a Smart Array fault tolerance to standard format
+func (s *SsacliTool) normalizeRAIDLevel(faultTolerance string) string {
+	switch strings.TrimSpace(faultTolerance) {
+	case "0":
+		return "RAID 0"
+	case "1":
+		return "RAID 1"
+	case "1+0":
+		return "RAID 10"
+	case "5":
+		return "RAID 5"
+	case "6", "ADG", "6 (ADG)":
+		return "RAID 6"
+	case "50", "5+0":
+		return "RAID 50"
+	case "60", "6+0":
+		return "RAID 60"
+	case "1 (ADM)", "1ADM":
+		return "RAID 1 (ADM)"
+	case "1+0 (ADM)", "10ADM":
+		return "RAID 10 (ADM)"
+	default:
+		return "RAID " + faultTolerance
+	}
+}
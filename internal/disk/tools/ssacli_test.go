@@ -0,0 +1,316 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"disk-health-exporter/pkg/types"
+)
+
+func TestSsacliTool_GetName(t *testing.T) {
+	tool := NewSsacliTool()
+	if tool.GetName() != "SSACLI" {
+		t.Errorf("Expected name SSACLI, got %s", tool.GetName())
+	}
+}
+
+func TestSsacliTool_Interfaces(t *testing.T) {
+	tool := NewSsacliTool()
+
+	var _ RAIDToolInterface = tool
+	var _ BatteryToolInterface = tool
+	var _ ControllerToolInterface = tool
+
+	// These methods should not panic even if ssacli is not available
+	arrays := tool.GetRAIDArrays()
+	disks := tool.GetRAIDDisks()
+	t.Logf("ssacli found %d RAID arrays and %d RAID disks", len(arrays), len(disks))
+}
+
+// parseSsacliFixture parses the captured ssacli ctrl all show config detail output
+func parseSsacliFixture(t *testing.T) []ssacliController {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "ssacli", "config_detail.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	controllers := NewSsacliTool().parseConfigDetail(string(data))
+	if len(controllers) != 2 {
+		t.Fatalf("Expected 2 controllers, got %d", len(controllers))
+	}
+	return controllers
+}
+
+func TestSsacliTool_ParseControllers(t *testing.T) {
+	controllers := parseSsacliFixture(t)
+
+	expected := []types.RAIDControllerInfo{
+		{
+			AdapterID: 0, ToolName: "SSACLI", Model: "Smart Array P440ar", SerialNumber: "PDNLH0BRH8A1Z4",
+			FirmwareVersion: "6.60", DriverVersion: "3.4.20", CacheSize: 2 << 30, CacheStatus: "OK", Status: "OK",
+			ROCTemperature: 52, NumVirtualDrives: 2, NumPhysicalDrives: 7, PatrolReadMode: "Idle",
+		},
+		{
+			AdapterID: 3, ToolName: "SSACLI", Model: "Smart Array P408i-a SR Gen10", SerialNumber: "PEYHB0ARH9C0Q1",
+			FirmwareVersion: "2.65-0", DriverVersion: "Linux 2.1.18-045", CacheSize: 2 << 30,
+			CacheStatus: "Temporarily Disabled", Status: "OK", ROCTemperature: 47, NumPhysicalDrives: 1,
+			PatrolReadMode: "Idle",
+		},
+	}
+	for i, controller := range controllers {
+		if !reflect.DeepEqual(controller.info, expected[i]) {
+			t.Errorf("Controller %d:\nexpected %+v\ngot      %+v", i, expected[i], controller.info)
+		}
+	}
+}
+
+func TestSsacliTool_ParseBattery(t *testing.T) {
+	controllers := parseSsacliFixture(t)
+
+	expected := []*types.RAIDBatteryInfo{
+		{AdapterID: 0, ToolName: "SSACLI", BatteryType: "Smart Storage Battery", State: "Optimal"},
+		{AdapterID: 3, ToolName: "SSACLI", BatteryType: "Smart Storage Battery", State: "Failed",
+			ReplacementRequired: true, Temperature: 26},
+	}
+	for i, controller := range controllers {
+		if !reflect.DeepEqual(controller.battery, expected[i]) {
+			t.Errorf("Battery %d:\nexpected %+v\ngot      %+v", i, expected[i], controller.battery)
+		}
+	}
+
+	// Every logical drive carries the battery of its controller
+	for _, raid := range controllers[0].arrays() {
+		if raid.Battery != controllers[0].battery {
+			t.Errorf("Array %s is missing the controller battery", raid.ArrayID)
+		}
+	}
+}
+
+func TestSsacliTool_ParseLogicalDrives(t *testing.T) {
+	controllers := parseSsacliFixture(t)
+
+	arrays := controllers[0].arrays()
+	if len(arrays) != 2 {
+		t.Fatalf("Expected 2 logical drives, got %d", len(arrays))
+	}
+
+	mirror := arrays[0]
+	if mirror.ArrayID != "0:1" || mirror.RaidLevel != "RAID 1" || mirror.State != "OK" || mirror.Status != 1 {
+		t.Errorf("Unexpected mirror %+v", mirror)
+	}
+	if mirror.Controller != "SSACLI - Smart Array P440ar" || mirror.Type != "hardware" || mirror.VirtualDevice != "/dev/sda" {
+		t.Errorf("Unexpected mirror controller %q type %q device %q", mirror.Controller, mirror.Type, mirror.VirtualDevice)
+	}
+	if mirror.NumDrives != 2 || mirror.NumActiveDrives != 2 || mirror.NumFailedDrives != 0 || mirror.NumSpareDrives != 0 {
+		t.Errorf("Unexpected mirror drive counts %+v", mirror)
+	}
+
+	parity := arrays[1]
+	if parity.ArrayID != "0:2" || parity.RaidLevel != "RAID 5" || parity.State != "Recovering" || parity.Status != 2 {
+		t.Errorf("Unexpected parity array %+v", parity)
+	}
+	if !parity.Rebuilding || parity.RebuildProgress != 23 {
+		t.Errorf("Expected rebuild progress 23, got %d (rebuilding %v)", parity.RebuildProgress, parity.Rebuilding)
+	}
+	if mirror.Rebuilding {
+		t.Errorf("Expected the mirror not to be rebuilding")
+	}
+	if parity.NumDrives != 3 || parity.NumActiveDrives != 2 || parity.NumFailedDrives != 1 || parity.NumSpareDrives != 1 {
+		t.Errorf("Unexpected parity drive counts %+v", parity)
+	}
+
+	if arrays := controllers[1].arrays(); len(arrays) != 0 {
+		t.Errorf("Expected no logical drives on the second controller, got %d", len(arrays))
+	}
+}
+
+func TestSsacliTool_ParsePhysicalDrives(t *testing.T) {
+	controllers := parseSsacliFixture(t)
+
+	disks := make(map[string]types.DiskInfo)
+	for _, controller := range controllers {
+		for _, disk := range controller.disks() {
+			disks[disk.Device] = disk
+		}
+	}
+	if len(disks) != 8 {
+		t.Fatalf("Expected 8 physical drives, got %d", len(disks))
+	}
+
+	first := disks["raid-slot0-port1I-box1-bay1"]
+	if first.Location != "Slot:0 Port:1I Box:1 Bay:1" || first.Serial != "S0M1A2B30000K541ABCD" {
+		t.Errorf("Unexpected location %q serial %q", first.Location, first.Serial)
+	}
+	if first.Vendor != "HP" || first.Model != "EG0600FBVFP" || first.Interface != "SAS" || first.RPM != 10000 {
+		t.Errorf("Unexpected identity %+v", first)
+	}
+	if first.Capacity != 600<<30 || first.Temperature != 33 || first.DriveTemperatureMax != 44 {
+		t.Errorf("Unexpected capacity %d temperature %.0f max %.0f", first.Capacity, first.Temperature, first.DriveTemperatureMax)
+	}
+	if first.RaidRole != "active" || first.RaidArrayID != "0:1" || first.Health != "OK" {
+		t.Errorf("Unexpected role %q array %q health %q", first.RaidRole, first.RaidArrayID, first.Health)
+	}
+	expectedDrive := &types.RAIDDriveInfo{ToolName: "SSACLI", FirmwareState: "OK", LinkSpeed: "6.0Gbps", DeviceSpeed: "12.0Gbps"}
+	if !reflect.DeepEqual(first.RaidDrive, expectedDrive) {
+		t.Errorf("Expected %+v, got %+v", expectedDrive, first.RaidDrive)
+	}
+
+	tests := []struct {
+		device    string
+		role      string
+		arrayID   string
+		dedicated bool
+	}{
+		{"raid-slot0-port1I-box1-bay4", "failed", "0:2", false},
+		{"raid-slot0-port2I-box1-bay6", "rebuilding", "0:2", true},
+		{"raid-slot0-port2I-box1-bay7", "unconfigured", "", false},
+		// Predictive failures are reported as failed drives, also when unassigned
+		{"raid-slot3-port1I-box1-bay1", "failed", "", false},
+	}
+	for _, tt := range tests {
+		disk := disks[tt.device]
+		if disk.RaidRole != tt.role || disk.RaidArrayID != tt.arrayID || disk.IsDedicatedSpare != tt.dedicated {
+			t.Errorf("%s: expected role %q array %q dedicated %v, got %q %q %v",
+				tt.device, tt.role, tt.arrayID, tt.dedicated, disk.RaidRole, disk.RaidArrayID, disk.IsDedicatedSpare)
+		}
+	}
+
+	// Unknown PHY rates are left empty, solid state interfaces are reported by bus
+	if failed := disks["raid-slot0-port1I-box1-bay4"]; failed.RaidDrive.LinkSpeed != "" {
+		t.Errorf("Expected no link speed for the failed drive, got %q", failed.RaidDrive.LinkSpeed)
+	}
+	if ssd := disks["raid-slot0-port2I-box1-bay7"]; ssd.Interface != "SATA" || ssd.Vendor != "ATA" || ssd.Model != "MK000480GWCEV" {
+		t.Errorf("Unexpected SSD identity %q %q %q", ssd.Interface, ssd.Vendor, ssd.Model)
+	}
+	if predictive := disks["raid-slot3-port1I-box1-bay1"]; predictive.Health != "Predictive Failure" {
+		t.Errorf("Expected Predictive Failure, got %q", predictive.Health)
+	}
+}
+
+func TestSsacliTool_CCISSPassthrough(t *testing.T) {
+	controllers := parseSsacliFixture(t)
+
+	// Drives are numbered in the order of the drive cage listing, not of the array sections
+	expected := map[string]string{
+		"raid-slot0-port1I-box1-bay1": "cciss,0",
+		"raid-slot0-port1I-box1-bay4": "cciss,3",
+		"raid-slot0-port2I-box1-bay7": "cciss,6",
+	}
+	for _, disk := range controllers[0].disks() {
+		if want, ok := expected[disk.Device]; ok {
+			if disk.SmartDevice != "/dev/sda" || disk.SmartDeviceType != want {
+				t.Errorf("%s: expected /dev/sda %s, got %s %s", disk.Device, want, disk.SmartDevice, disk.SmartDeviceType)
+			}
+		}
+	}
+
+	// Without a logical drive there is no block device to reach the drives through
+	for _, disk := range controllers[1].disks() {
+		if disk.SmartDevice != "" || disk.SmartDeviceType != "" {
+			t.Errorf("%s: expected no passthrough, got %s %s", disk.Device, disk.SmartDevice, disk.SmartDeviceType)
+		}
+	}
+}
+
+func TestSsacliTool_SetBatteryStatus(t *testing.T) {
+	tests := []struct {
+		status      string
+		state       string
+		missing     bool
+		replacement bool
+	}{
+		{"OK", "Optimal", false, false},
+		{"Recharging", "Charging", false, false},
+		{"Not Present", "Missing", true, false},
+		{"Failed (Replace Batteries/Capacitors)", "Failed", false, true},
+	}
+	tool := NewSsacliTool()
+	for _, tt := range tests {
+		battery := &types.RAIDBatteryInfo{}
+		tool.setBatteryStatus(battery, tt.status)
+		if battery.State != tt.state || battery.BatteryMissing != tt.missing || battery.ReplacementRequired != tt.replacement {
+			t.Errorf("%q: expected %s missing=%v replace=%v, got %+v", tt.status, tt.state, tt.missing, tt.replacement, battery)
+		}
+	}
+}
+
+func TestSsacliDriveRole(t *testing.T) {
+	tests := []struct {
+		status    string
+		driveType string
+		role      string
+	}{
+		{"OK", "Data Drive", "active"},
+		{"OK", "Spare Drive", "hot_spare"},
+		{"OK", "Unassigned Drive", "unconfigured"},
+		{"OK", "HBA Mode Drive", "unconfigured"},
+		{"Predictive Failure", "Data Drive", "failed"},
+		{"Rebuilding", "Spare Drive", "rebuilding"},
+		{"Failed", "Data Drive", "failed"},
+		// Unmapped statuses are unknown rather than taken for active
+		{"Overheating", "Data Drive", "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status+"/"+tt.driveType, func(t *testing.T) {
+			drive := ssacliPhysicalDrive{disk: types.DiskInfo{Health: tt.status}, driveType: tt.driveType}
+			if role := drive.role(); role != tt.role {
+				t.Errorf("Expected role %q, got %q", tt.role, role)
+			}
+		})
+	}
+}
+
+func TestSsacliArrayMemberCounts(t *testing.T) {
+	controller := ssacliController{
+		logical: []ssacliLogicalDrive{{raid: types.RAIDInfo{ArrayID: "0:1"}, array: "A"}},
+		physical: []ssacliPhysicalDrive{
+			{disk: types.DiskInfo{Health: "OK"}, array: "A", driveType: "Data Drive"},
+			{disk: types.DiskInfo{Health: "Predictive Failure"}, array: "A", driveType: "Data Drive"},
+			{disk: types.DiskInfo{Health: "Rebuilding"}, array: "A", driveType: "Data Drive"},
+			{disk: types.DiskInfo{Health: "Overheating"}, array: "A", driveType: "Data Drive"},
+			{disk: types.DiskInfo{Health: "OK"}, array: "A", driveType: "Spare Drive"},
+		},
+	}
+
+	raid := controller.arrays()[0]
+	if raid.NumDrives != 4 || raid.NumActiveDrives != 1 || raid.NumFailedDrives != 1 || raid.NumSpareDrives != 1 {
+		t.Errorf("Expected 4 drives with 1 active, 1 failed and 1 spare, got %+v", raid)
+	}
+}
+
+func TestSsacliStates(t *testing.T) {
+	checkStates(t, ssacliDriveStates, map[string]types.HealthStatus{
+		"OK":                 types.HealthStatusOK,
+		"Predictive Failure": types.HealthStatusWarning,
+		"Rebuilding":         types.HealthStatusWarning,
+		"Erasing":            types.HealthStatusWarning,
+		"Failed":             types.HealthStatusCritical,
+	})
+	checkStates(t, ssacliDriveRoles, map[string]string{
+		"OK":                 "active",
+		"Predictive Failure": "failed",
+		"Rebuilding":         "rebuilding",
+		"Erasing":            "unconfigured",
+		"Failed":             "failed",
+	})
+	checkStates(t, ssacliLogicalDriveStates, map[string]types.RAIDState{
+		"OK":                                types.RAIDStateOptimal,
+		"Interim Recovery Mode":             types.RAIDStateDegraded,
+		"Recovering":                        types.RAIDStateDegraded,
+		"Ready for Rebuild":                 types.RAIDStateDegraded,
+		"Expanding":                         types.RAIDStateDegraded,
+		"Transforming":                      types.RAIDStateDegraded,
+		"Queued for Expansion":              types.RAIDStateDegraded,
+		"Queued for Transformation":         types.RAIDStateDegraded,
+		"Parity Initialization in Progress": types.RAIDStateDegraded,
+		"Failed":                            types.RAIDStateFailed,
+		"Disabled":                          types.RAIDStateFailed,
+		"Wrong Drive Replaced":              types.RAIDStateFailed,
+		"Drive(s) Disabled":                 types.RAIDStateFailed,
+	})
+	checkStates(t, ssacliLogicalDriveRebuilds, map[string]bool{
+		"Recovering": true,
+	})
+}
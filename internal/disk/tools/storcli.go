@@ -1,24 +1,42 @@
 package tools
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/statemap"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
-// Ensure StoreCLITool implements the CombinedToolInterface
-var _ CombinedToolInterface = (*StoreCLITool)(nil)
+// Ensure StoreCLITool implements the CombinedToolInterface and SnapshotToolInterface
+var (
+	_ CombinedToolInterface = (*StoreCLITool)(nil)
+	_ SnapshotToolInterface = (*StoreCLITool)(nil)
+)
 
 // StoreCLITool represents the StoreCLI tool (Broadcom)
 type StoreCLITool struct {
 	command string // "storcli64" or "storcli"
+
+	mu          sync.Mutex
+	controllers []types.RAIDControllerInfo // Read by the latest snapshot, until GetControllers takes them
+}
+
+func init() {
+	Register(Registration{
+		Name:      merge.SourceStorCLI,
+		Platforms: []string{PlatformLinux, PlatformWindows},
+		After:     []string{merge.SourceSmartctl, merge.SourceNvme, merge.SourceHdparm},
+		New:       func() ToolInterface { return NewStoreCLITool() },
+	})
 }
 
 // NewStoreCLITool creates a new StoreCLITool instance
@@ -58,28 +76,169 @@ func (s *StoreCLITool) GetName() string {
 	return "StoreCLI"
 }
 
+// runJSON runs a StorCLI command with JSON output. StorCLI exits with an error
+// when the command fails on any controller but still prints the results of all
+// controllers, so the output is returned whenever there is some.
+func (s *StoreCLITool) runJSON(args ...string) ([]byte, error) {
+	output, err := exec.Command(s.command, append(args, "J")...).Output()
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && len(output) > 0) {
+		return nil, err
+	}
+	return output, nil
+}
+
+// GetControllers returns controller status, versions and health counters. The
+// controllers read by the latest snapshot are used once, so a collection
+// queries them only once.
+// storcli /call show all J # controller basics, versions, status and hardware configuration
+// storcli /call/fall show J # foreign configurations found on attached drives
+// storcli /call show patrolread J # patrol read state and progress
+func (s *StoreCLITool) GetControllers() []types.RAIDControllerInfo {
+	if !s.IsAvailable() {
+		return nil
+	}
+
+	s.mu.Lock()
+	controllers := s.controllers
+	s.controllers = nil
+	s.mu.Unlock()
+	if controllers == nil {
+		controllers = s.getControllers()
+	}
+
+	output, err := s.runJSON("/call/fall", "show")
+	if err != nil {
+		slog.Error("Error getting foreign configuration info", "tool", "storcli", "err", err)
+	} else if foreign, err := parseStorCLIForeignConfigs(output); err != nil {
+		slog.Error("Error parsing foreign configuration JSON", "tool", "storcli", "err", err)
+	} else {
+		for i := range controllers {
+			controllers[i].ForeignConfigs = foreign[strconv.Itoa(controllers[i].AdapterID)]
+		}
+	}
+
+	output, err = s.runJSON("/call", "show", "patrolread")
+	if err != nil {
+		slog.Error("Error getting patrol read info", "tool", "storcli", "err", err)
+		return controllers
+	}
+	patrolRead, err := parseStorCLIControllerProperties(output)
+	if err != nil {
+		slog.Error("Error parsing patrol read JSON", "tool", "storcli", "err", err)
+		return controllers
+	}
+	for i := range controllers {
+		if properties, ok := patrolRead[strconv.Itoa(controllers[i].AdapterID)]; ok {
+			applyStorCLIPatrolRead(&controllers[i], properties)
+		}
+	}
+
+	return controllers
+}
+
+// getControllers runs "/call show all J" and parses the controller information
+func (s *StoreCLITool) getControllers() []types.RAIDControllerInfo {
+	output, err := s.runJSON("/call", "show", "all")
+	if err != nil {
+		slog.Error("Error getting controller info", "tool", "storcli", "err", err)
+		return nil
+	}
+
+	controllers, err := parseStorCLIControllers(output)
+	if err != nil {
+		slog.Error("Error parsing controller JSON", "tool", "storcli", "err", err)
+		return nil
+	}
+	return controllers
+}
+
 // GetRAIDArrays returns RAID array information detected by StoreCLI
-// storcli /call show J # get all controller info in JSON format
 func (s *StoreCLITool) GetRAIDArrays() []types.RAIDInfo {
-	var raidArrays []types.RAIDInfo
+	if !s.IsAvailable() {
+		return nil
+	}
 
+	raidArrays, _ := s.getVirtualDrives(s.getControllers())
+	return raidArrays
+}
+
+// GetSnapshot returns the drives and virtual drives of all controllers, running
+// each StorCLI query once
+func (s *StoreCLITool) GetSnapshot() ([]types.DiskInfo, []types.RAIDInfo) {
 	if !s.IsAvailable() {
-		return raidArrays
+		return nil, nil
+	}
+
+	controllers := s.getControllers()
+	raidArrays, groups := s.getVirtualDrives(controllers)
+	disks := s.getPhysicalDrives(raidArrays, groups)
+
+	s.mu.Lock()
+	s.controllers = controllers
+	s.mu.Unlock()
+
+	return disks, raidArrays
+}
+
+// getVirtualDrives returns the virtual drives of all controllers with their
+// consistency check progress and backup battery, and the array ID of each drive group
+// storcli /call/vall show all J # virtual drives, member drives and VD properties
+// storcli /call/vall show cc J # consistency check progress per virtual drive
+// storcli /call/cv show all J # CacheVault status
+func (s *StoreCLITool) getVirtualDrives(controllers []types.RAIDControllerInfo) ([]types.RAIDInfo, map[string]string) {
+	models := make(map[string]string, len(controllers))
+	for _, controller := range controllers {
+		models[strconv.Itoa(controller.AdapterID)] = controller.Model
 	}
 
-	// Get RAID array information using JSON output
-	output, err := exec.Command(s.command, "/call", "show", "J").Output()
+	output, err := s.runJSON("/call/vall", "show", "all")
+	if err != nil {
+		slog.Error("Error getting virtual drive info", "tool", "storcli", "err", err)
+		return nil, nil
+	}
+	raidArrays, groups, err := parseStorCLIVirtualDrives(output, models)
 	if err != nil {
-		return s.getRAIDArraysPlainText()
+		slog.Error("Error parsing virtual drive JSON", "tool", "storcli", "err", err)
+		return nil, nil
+	}
+	if len(raidArrays) == 0 {
+		return raidArrays, groups
+	}
+
+	if output, err := s.runJSON("/call/vall", "show", "cc"); err != nil {
+		slog.Error("Error getting consistency check info", "tool", "storcli", "err", err)
+	} else if progress, err := parseStorCLIConsistencyChecks(output); err != nil {
+		slog.Error("Error parsing consistency check JSON", "tool", "storcli", "err", err)
+	} else {
+		for i := range raidArrays {
+			raidArrays[i].ScrubProgress, raidArrays[i].CheckRunning = progress[raidArrays[i].ArrayID]
+		}
 	}
 
-	// Parse JSON output
-	arrays := s.parseStoreCLIJSON(output)
-	if len(arrays) > 0 {
-		return arrays
+	var cacheVaults map[string]*types.RAIDBatteryInfo
+	if output, err := s.runJSON("/call/cv", "show", "all"); err == nil {
+		if cacheVaults, err = parseStorCLICacheVaults(output); err != nil {
+			slog.Error("Error parsing CacheVault JSON", "tool", "storcli", "err", err)
+		}
 	}
 
-	return s.getRAIDArraysPlainText()
+	// Controllers without a CacheVault may have a battery backup unit
+	batteries := make(map[string]*types.RAIDBatteryInfo)
+	for i := range raidArrays {
+		controllerID, _, _ := strings.Cut(raidArrays[i].ArrayID, ":")
+		battery, ok := batteries[controllerID]
+		if !ok {
+			battery = cacheVaults[controllerID]
+			if battery == nil {
+				battery = s.getBBUInfo(controllerID)
+			}
+			batteries[controllerID] = battery
+		}
+		raidArrays[i].Battery = battery
+	}
+
+	return raidArrays, groups
 }
 
 // GetDisks returns disk information detected by StoreCLI (implements DiskToolInterface)
@@ -88,72 +247,66 @@ func (s *StoreCLITool) GetDisks() []types.DiskInfo {
 }
 
 // GetRAIDDisks returns disk information from RAID arrays with utilization calculations
-// storcli /call /eall /sall show all # get detailed physical disk information for all controllers and slots
-// storcli /call /eall /sall show J # get physical disk information in JSON format (fallback)
 func (s *StoreCLITool) GetRAIDDisks() []types.DiskInfo {
-	var disks []types.DiskInfo
-
 	if !s.IsAvailable() {
-		return disks
+		return nil
 	}
 
-	// First get RAID array information to understand the configuration
-	raidArrays := s.GetRAIDArrays()
+	raidArrays, groups := s.getVirtualDrives(s.getControllers())
+	return s.getPhysicalDrives(raidArrays, groups)
+}
 
-	// Try to get physical disk information with detailed parsing first
-	output, err := exec.Command(s.command, "/call", "/eall", "/sall", "show", "all").Output()
-	if err == nil {
-		// Parse the detailed output for disk roles and spare information
-		detailedDisks := s.parseStoreCLIDisksWithRoles(string(output), raidArrays)
-		if len(detailedDisks) > 0 {
-			return detailedDisks
-		}
+// getPhysicalDrives returns the drives of all controllers, matched to their
+// arrays through their drive group
+// storcli /call/eall/sall show all J # detailed physical drive information for all controllers
+func (s *StoreCLITool) getPhysicalDrives(raidArrays []types.RAIDInfo, groups map[string]string) []types.DiskInfo {
+	output, err := s.runJSON("/call/eall/sall", "show", "all")
+	if err != nil {
+		slog.Error("Error getting disk info", "tool", "storcli", "err", err)
+		return nil
 	}
 
-	// Fallback to JSON parsing
-	output, err = exec.Command(s.command, "/call", "/eall", "/sall", "show", "J").Output()
+	disks, err := parseStorCLIPhysicalDrives(output, raidArrays, groups)
 	if err != nil {
-		log.Printf("Error executing StoreCLI for disk info: %v", err)
-		return s.getRAIDDisksPlainText()
+		slog.Error("Error parsing disk JSON", "tool", "storcli", "err", err)
+		return nil
 	}
 
-	// Parse JSON output for disks
-	disks = s.parseStoreCLIDisksJSON(output)
-	if len(disks) > 0 {
-		// Enrich each disk with SMART data and utilization
-		for i := range disks {
-			s.enrichRAIDDiskWithSMART(&disks[i])
-			// Add basic utilization calculation for JSON-parsed disks
-			s.calculateBasicUtilization(&disks[i], raidArrays)
-		}
-		return disks
-	}
+	// Read full SMART data of each drive through the controller
+	enrichWithPassthroughSMART(disks)
 
-	// Final fallback to plain text parsing
-	disks = s.getRAIDDisksPlainText()
-	// Enrich plain text disks with SMART data too
-	for i := range disks {
-		s.enrichRAIDDiskWithSMART(&disks[i])
-		s.calculateBasicUtilization(&disks[i], raidArrays)
-	}
 	return disks
 }
 
-// GetBatteryInfo returns battery information for StoreCLI controllers
-// storcli /cX /bbu show all # get battery backup unit information for controller X
-// storcli /cX show bbu # get battery info (alternative format)
+// GetBatteryInfo returns CacheVault or battery backup unit information for a StoreCLI controller
+// storcli /cX/cv show all J # get CacheVault information for controller X
 func (s *StoreCLITool) GetBatteryInfo(controllerID string) *types.RAIDBatteryInfo {
 	if !s.IsAvailable() {
 		return nil
 	}
 
-	// Get battery information using StoreCLI
+	if output, err := s.runJSON(fmt.Sprintf("/c%s/cv", controllerID), "show", "all"); err == nil {
+		cacheVaults, err := parseStorCLICacheVaults(output)
+		if err != nil {
+			slog.Error("Error parsing CacheVault JSON", "tool", "storcli", "controller", controllerID, "err", err)
+		} else if battery := cacheVaults[controllerID]; battery != nil {
+			return battery
+		}
+	}
+
+	return s.getBBUInfo(controllerID)
+}
+
+// getBBUInfo returns battery backup unit information for controllers without a CacheVault
+// storcli /cX /bbu show all # get battery backup unit information for controller X
+// storcli /cX show bbu # get battery info (alternative format)
+func (s *StoreCLITool) getBBUInfo(controllerID string) *types.RAIDBatteryInfo {
 	output, err := exec.Command(s.command, fmt.Sprintf("/c%s", controllerID), "/bbu", "show", "all").Output()
 	if err != nil {
 		// Try alternative command format
 		output, err = exec.Command(s.command, fmt.Sprintf("/c%s", controllerID), "show", "bbu").Output()
 		if err != nil {
-			log.Printf("Error executing StoreCLI for battery info: %v", err)
+			slog.Error("Error getting battery info", "tool", "storcli", "controller", controllerID, "err", err)
 			return nil
 		}
 	}
@@ -307,834 +460,28 @@ func (s *StoreCLITool) parseBatteryInfo(output, controllerID string) *types.RAID
 	return battery
 }
 
-// parseStoreCLIJSON parses StoreCLI JSON output for RAID arrays
-func (s *StoreCLITool) parseStoreCLIJSON(output []byte) []types.RAIDInfo {
-	var raidArrays []types.RAIDInfo
-
-	// StoreCLI JSON structure is complex, we'll parse it carefully
-	var jsonData map[string]interface{}
-	if err := json.Unmarshal(output, &jsonData); err != nil {
-		return raidArrays
-	}
-
-	// Navigate through the JSON structure to find controllers and VDs
-	controllers, ok := jsonData["Controllers"].([]interface{})
-	if !ok {
-		return raidArrays
-	}
-
-	for i, controller := range controllers {
-		ctrlMap, ok := controller.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// Extract controller information
-		var controllerName string
-		var controllerID = fmt.Sprintf("%d", i) // Use index as controller ID
-		if responseData, exists := ctrlMap["Response Data"]; exists {
-			if dataMap, ok := responseData.(map[string]interface{}); ok {
-				if productName, exists := dataMap["Product Name"]; exists {
-					controllerName = fmt.Sprintf("StoreCLI - %v", productName)
-				}
-			}
-		}
-
-		// Look for Virtual Drives (RAID arrays)
-		if responseData, exists := ctrlMap["Response Data"]; exists {
-			if dataMap, ok := responseData.(map[string]interface{}); ok {
-				if vdList, exists := dataMap["VD LIST"]; exists {
-					if vdArray, ok := vdList.([]interface{}); ok {
-						for _, vd := range vdArray {
-							if vdMap, ok := vd.(map[string]interface{}); ok {
-								raid := s.parseVirtualDrive(vdMap, controllerName)
-								if raid.ArrayID != "" {
-									// Try to get battery info for this controller
-									batteryInfo := s.GetBatteryInfo(controllerID)
-									if batteryInfo != nil {
-										raid.Battery = batteryInfo
-									}
-									raidArrays = append(raidArrays, raid)
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-
-	return raidArrays
-}
-
-// parseVirtualDrive parses a single virtual drive from JSON
-func (s *StoreCLITool) parseVirtualDrive(vdMap map[string]interface{}, controllerName string) types.RAIDInfo {
-	raid := types.RAIDInfo{
-		Type:       "hardware",
-		Controller: controllerName,
-	}
-
-	if vdID, exists := vdMap["DG/VD"]; exists {
-		raid.ArrayID = fmt.Sprintf("%v", vdID)
-	}
-
-	if raidLevel, exists := vdMap["TYPE"]; exists {
-		raid.RaidLevel = fmt.Sprintf("%v", raidLevel)
-	}
-
-	if state, exists := vdMap["State"]; exists {
-		stateStr := fmt.Sprintf("%v", state)
-		raid.State = stateStr
-		raid.Status = utils.GetRaidStatusValue(stateStr)
-	}
-
-	if size, exists := vdMap["Size"]; exists {
-		if sizeStr := fmt.Sprintf("%v", size); sizeStr != "" {
-			raid.Size = utils.ParseSizeToBytes(sizeStr)
-		}
-	}
-
-	if numDrives, exists := vdMap["#DRIVES"]; exists {
-		if drives, ok := numDrives.(float64); ok {
-			raid.NumDrives = int(drives)
-		}
-	}
-
-	return raid
-}
-
-// parseStoreCLIDisksJSON parses StoreCLI JSON output for physical disks
-func (s *StoreCLITool) parseStoreCLIDisksJSON(output []byte) []types.DiskInfo {
-	var disks []types.DiskInfo
-
-	var jsonData map[string]interface{}
-	if err := json.Unmarshal(output, &jsonData); err != nil {
-		log.Printf("Error parsing StoreCLI disk JSON: %v", err)
-		return disks
-	}
-
-	// Navigate through JSON to find physical drives
-	controllers, ok := jsonData["Controllers"].([]interface{})
-	if !ok {
-		return disks
-	}
-
-	for controllerIndex, controller := range controllers {
-		ctrlMap, ok := controller.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// Extract controller ID - try from response data first, fallback to index
-		controllerID := fmt.Sprintf("%d", controllerIndex)
-		if responseData, exists := ctrlMap["Response Data"]; exists {
-			if dataMap, ok := responseData.(map[string]interface{}); ok {
-				// Try to get actual controller ID from response data
-				if ctrlIDField, exists := dataMap["Controller"]; exists {
-					if ctrlIDStr := fmt.Sprintf("%v", ctrlIDField); ctrlIDStr != "" {
-						controllerID = ctrlIDStr
-					}
-				}
-
-				// Look for Drive Information
-				if driveInfo, exists := dataMap["Drive Information"]; exists {
-					if driveArray, ok := driveInfo.([]interface{}); ok {
-						for _, drive := range driveArray {
-							if driveMap, ok := drive.(map[string]interface{}); ok {
-								disk := s.parsePhysicalDrive(driveMap, controllerID)
-								if disk.Device != "" {
-									disks = append(disks, disk)
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-
-	return disks
-}
-
-// parsePhysicalDrive parses a single physical drive from JSON
-func (s *StoreCLITool) parsePhysicalDrive(driveMap map[string]interface{}, controllerID string) types.DiskInfo {
-	disk := types.DiskInfo{
-		Type: "raid",
-	}
-
-	if eid, exists := driveMap["EID:Slt"]; exists {
-		disk.Location = fmt.Sprintf("Controller:%s EID:Slt %v", controllerID, eid)
-		// Convert "252:0" to "raid-c0-enc252-slot0" for better readability
-		eidStr := fmt.Sprintf("%v", eid)
-		parts := strings.Split(eidStr, ":")
-		if len(parts) == 2 {
-			disk.Device = fmt.Sprintf("raid-c%s-enc%s-slot%s", controllerID, parts[0], parts[1])
-		} else {
-			disk.Device = fmt.Sprintf("raid-c%s-drive-%v", controllerID, eid)
-		}
-	}
-
-	if model, exists := driveMap["Model"]; exists {
-		disk.Model = fmt.Sprintf("%v", model)
-	}
-
-	if serial, exists := driveMap["SN"]; exists {
-		disk.Serial = fmt.Sprintf("%v", serial)
-	}
-
-	if state, exists := driveMap["State"]; exists {
-		disk.Health = fmt.Sprintf("%v", state)
-	}
-
-	if size, exists := driveMap["Size"]; exists {
-		if sizeStr := fmt.Sprintf("%v", size); sizeStr != "" {
-			disk.Capacity = utils.ParseSizeToBytes(sizeStr)
-		}
-	}
-
-	if mediaType, exists := driveMap["Med"]; exists {
-		disk.Interface = fmt.Sprintf("%v", mediaType)
-	}
-
-	return disk
-}
-
-// getRAIDArraysPlainText fallback method using plain text parsing
-// storcli /call show # get controller and array information in plain text format
-func (s *StoreCLITool) getRAIDArraysPlainText() []types.RAIDInfo {
-	var raidArrays []types.RAIDInfo
-
-	// Get RAID array information without JSON
-	output, err := exec.Command(s.command, "/call", "show").Output()
-	if err != nil {
-		log.Printf("Error executing StoreCLI for plain text array info: %v", err)
-		return raidArrays
-	}
-
-	// Parse plain text output (simplified)
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Look for virtual drive entries (simplified pattern)
-		if strings.Contains(line, "VD") && strings.Contains(line, "RAID") {
-			fields := strings.Fields(line)
-			if len(fields) >= 3 {
-				raid := types.RAIDInfo{
-					ArrayID:    fields[0],
-					RaidLevel:  fields[2],
-					State:      "Optimal", // Default assumption
-					Status:     1,
-					Type:       "hardware",
-					Controller: "StoreCLI",
-				}
-
-				// Try to extract more information if available
-				for i, field := range fields {
-					if strings.Contains(field, "GB") || strings.Contains(field, "TB") {
-						raid.Size = utils.ParseSizeToBytes(field)
-					}
-					if field == "Optimal" || field == "Degraded" || field == "Failed" {
-						raid.State = field
-						raid.Status = utils.GetRaidStatusValue(field)
-					}
-					if strings.Contains(field, "RAID") && i+1 < len(fields) {
-						raid.RaidLevel = field + " " + fields[i+1]
-					}
-				}
-
-				raidArrays = append(raidArrays, raid)
-			}
-		}
-	}
-
-	return raidArrays
-}
-
-// getRAIDDisksPlainText fallback method for physical disks using plain text parsing
-// storcli /call /eall /sall show # get physical disk information in plain text format
-func (s *StoreCLITool) getRAIDDisksPlainText() []types.DiskInfo {
-	var disks []types.DiskInfo
-
-	// Compile regex once for better performance
-	driveRegex := regexp.MustCompile(`^\d+:\d+\s+\d+`)
-
-	// Get physical disk information
-	output, err := exec.Command(s.command, "/call", "/eall", "/sall", "show").Output()
-	if err != nil {
-		log.Printf("Error executing StoreCLI for plain text disk info: %v", err)
-		return disks
-	}
-
-	// Parse physical disk information (simplified)
-	lines := strings.Split(string(output), "\n")
-	var currentController = "0" // Default controller
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Try to detect controller information from the output
-		if strings.Contains(line, "Controller =") {
-			// Extract controller number: "Controller = 0"
-			parts := strings.Split(line, "=")
-			if len(parts) > 1 {
-				currentController = strings.TrimSpace(parts[1])
-			}
-		}
-
-		// Look for drive entries in the format: EID:Slt DID State DG Size Intf Med SED PI SeSz Model
-		if driveRegex.MatchString(line) {
-			fields := strings.Fields(line)
-			if len(fields) >= 6 {
-				// Convert "252:0" to "raid-c0-enc252-slot0" for consistency
-				eidSlot := fields[0]
-				parts := strings.Split(eidSlot, ":")
-				var deviceName string
-				if len(parts) == 2 {
-					deviceName = fmt.Sprintf("raid-c%s-enc%s-slot%s", currentController, parts[0], parts[1])
-				} else {
-					deviceName = fmt.Sprintf("raid-c%s-drive-%s", currentController, eidSlot)
-				}
-
-				disk := types.DiskInfo{
-					Device:   deviceName,
-					Location: fmt.Sprintf("Controller:%s EID:Slt %s", currentController, eidSlot),
-					Health:   fields[2],
-					Type:     "raid",
-				}
-
-				// Extract size
-				if len(fields) > 4 {
-					disk.Capacity = utils.ParseSizeToBytes(fields[4])
-				}
-
-				// Extract interface
-				if len(fields) > 5 {
-					disk.Interface = fields[5]
-				}
-
-				// Extract model (usually at the end)
-				if len(fields) > 9 {
-					disk.Model = strings.Join(fields[9:], " ")
-				}
-
-				disks = append(disks, disk)
-			}
-		}
-	}
-
-	return disks
-}
-
-// enrichRAIDDiskWithSMART enriches RAID disk information with SMART data via StoreCLI
-func (s *StoreCLITool) enrichRAIDDiskWithSMART(disk *types.DiskInfo) {
-	if !s.IsAvailable() {
-
-		return
-	}
-
-	// Extract controller and slot info from device name (raid-c0-enc64-slot3)
-	if !strings.HasPrefix(disk.Device, "raid-c") {
-		return
-	}
-
-	// Parse controller, enclosure and slot from device name
-	parts := strings.Split(disk.Device, "-")
-	if len(parts) < 5 {
-		return
-	}
-
-	controller := strings.TrimPrefix(parts[1], "c")
-	enclosure := strings.TrimPrefix(parts[2], "enc")
-	slot := strings.TrimPrefix(parts[3], "slot")
-
-	// Try to get SMART data via StoreCLI
-	cmd := fmt.Sprintf("/c%s/e%s/s%s", controller, enclosure, slot)
-	// storcli /cX/eY/sZ show all # get detailed drive information including SMART data
-	// storcli /cX/eY/sZ show # get basic drive information (fallback)
-	output, err := exec.Command(s.command, cmd, "show", "all").Output()
-	if err != nil {
-		// Try alternative command format
-		output, err = exec.Command(s.command, cmd, "show").Output()
-		if err != nil {
-			return
-		}
-	}
-
-	// Parse output for SMART data
-	lines := strings.Split(string(output), "\n")
-	smartDataFound := false
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if strings.Contains(line, "Drive Temperature") {
-			// Extract temperature value
-			re := regexp.MustCompile(`(\d+)C`)
-			matches := re.FindStringSubmatch(line)
-			if len(matches) > 1 {
-				if temp, err := strconv.Atoi(matches[1]); err == nil {
-					disk.Temperature = float64(temp)
-				}
-			}
-		} else if strings.Contains(line, "S.M.A.R.T alert") {
-			smartDataFound = true
-			disk.SmartEnabled = !strings.Contains(strings.ToLower(line), "no")
-		} else if strings.Contains(line, "Drive health") || strings.Contains(line, "State") {
-			smartDataFound = true
-			lowerLine := strings.ToLower(line)
-			if strings.Contains(lowerLine, "online") || strings.Contains(lowerLine, "optimal") || strings.Contains(lowerLine, "good") {
-				disk.SmartHealthy = true
-				disk.Health = "OK"
-			} else if strings.Contains(lowerLine, "failed") || strings.Contains(lowerLine, "critical") || strings.Contains(lowerLine, "bad") {
-				disk.SmartHealthy = false
-				disk.Health = "FAILED"
-			} else {
-				// For unknown states, assume healthy unless explicitly failed
-				disk.SmartHealthy = true
-				disk.Health = "UNKNOWN"
-			}
-		}
-	}
-
-	if !smartDataFound {
-		// If no explicit SMART data found, but disk.Health is already set from parsing, use that
-		if disk.Health == "Onln" || strings.Contains(strings.ToLower(disk.Health), "online") {
-			disk.SmartHealthy = true
-			disk.SmartEnabled = true // Assume SMART is available through RAID controller
-		}
-	}
-}
-
-// parseStoreCLIDisksWithRoles parses StoreCLI output to identify disk roles and spare drives
-func (s *StoreCLITool) parseStoreCLIDisksWithRoles(output string, raidArrays []types.RAIDInfo) []types.DiskInfo {
-	lines := strings.Split(output, "\n")
-
-	// First, try to parse the summary table format (like the output you provided)
-	if summaryDisks := s.parseStoreCLISummaryTable(lines, raidArrays); len(summaryDisks) > 0 {
-		return summaryDisks
-	}
-
-	// Fallback to detailed per-drive parsing
-	detailedDisks := s.parseStoreCLIDetailedFormat(lines, raidArrays)
-	return detailedDisks
-}
-
-// parseStoreCLISummaryTable parses the table format output from StoreCLI
-func (s *StoreCLITool) parseStoreCLISummaryTable(lines []string, raidArrays []types.RAIDInfo) []types.DiskInfo {
-	var disks []types.DiskInfo
-	var inDriveTable bool
-	var currentController = "0" // Default controller, will be updated when we detect controller sections
-
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Try to detect controller information from the output
-		if strings.Contains(line, "Controller =") {
-			// Extract controller number: "Controller = 0"
-			parts := strings.Split(line, "=")
-			if len(parts) > 1 {
-				currentController = strings.TrimSpace(parts[1])
-			}
-		}
-
-		// Detect the start of the drive table (header line)
-		if strings.Contains(line, "EID:Slt DID State DG") && strings.Contains(line, "Size") && strings.Contains(line, "Model") {
-			inDriveTable = true
-			continue
-		}
-
-		// Detect the end of the drive table - but don't break, continue looking for more tables
-		if inDriveTable && strings.Contains(line, "--------") && i < len(lines)-1 {
-			// Check if this is the closing line by looking for separator or explanation lines
-			nextLine := strings.TrimSpace(lines[i+1])
-			if nextLine == "" || strings.Contains(nextLine, "EID=Enclosure") || strings.Contains(nextLine, "DHS=Dedicated") {
-				inDriveTable = false
-				continue
-			}
-		}
-
-		// Parse drive data lines
-		if inDriveTable && strings.Contains(line, ":") && !strings.Contains(line, "EID:Slt") {
-			disk := s.parseStoreCLITableLine(line, raidArrays, currentController)
-			if disk.Device != "" {
-				disks = append(disks, disk)
-			}
-		}
-	}
-
-	return disks
-}
-
-// parseStoreCLITableLine parses a single line from the StoreCLI drive table
-func (s *StoreCLITool) parseStoreCLITableLine(line string, raidArrays []types.RAIDInfo, controllerID string) types.DiskInfo {
-	fields := strings.Fields(line)
-	if len(fields) < 8 {
-		return types.DiskInfo{} // Invalid line
-	}
-
-	// Example line: "64:3      3 Onln   0 893.750 GB SATA SSD N   N  512B SAMSUNG MZ7KM960HAHP-00005 U  -"
-	eidSlt := fields[0]     // "64:3"
-	state := fields[2]      // "Onln"
-	driveGroup := fields[3] // "0"
-	size := fields[4]       // "893.750"
-	unit := fields[5]       // "GB"
-	intf := fields[6]       // "SATA"
-
-	// Parse EID and Slot
-	eidSlotParts := strings.Split(eidSlt, ":")
-	if len(eidSlotParts) != 2 {
-		return types.DiskInfo{} // Invalid EID:Slot format
-	}
-
-	disk := types.DiskInfo{
-		Device:    fmt.Sprintf("raid-c%s-enc%s-slot%s", controllerID, eidSlotParts[0], eidSlotParts[1]),
-		Location:  fmt.Sprintf("Controller:%s EID:%s Slot:%s", controllerID, eidSlotParts[0], eidSlotParts[1]),
-		Health:    state,
-		Type:      "raid",
-		Interface: intf,
-		Capacity:  utils.ParseSizeToBytes(size + " " + unit),
-	}
-
-	// Parse model using field position logic
-	// Format: EID:Slt DID State DG Size Unit Intf Med SED PI SeSz Model... Sp Type
-	// Fields 0-10 are fixed, Model starts at field 11, ends 2 fields before the end
-	if len(fields) >= 13 { // Minimum: 11 fixed fields + model + Sp + Type
-		modelStart := 11
-		modelEnd := len(fields) - 2 // Exclude Sp and Type at the end
-		if modelEnd > modelStart {
-			disk.Model = strings.Join(fields[modelStart:modelEnd], " ")
-		}
-	}
-
-	// Determine RAID role and calculate utilization
-	s.determineStoreCLIRaidRole(&disk, state, driveGroup, raidArrays)
-
-	// Find matching array for utilization calculation
-	var matchingArray *types.RAIDInfo
-	for i, raid := range raidArrays {
-		if raid.ArrayID == driveGroup {
-			matchingArray = &raidArrays[i]
-			break
-		}
-	}
-
-	s.calculateStoreCLIDiskUtilization(&disk, matchingArray)
-
-	return disk
-}
-
-// parseStoreCLIDetailedFormat parses the detailed per-drive format
-func (s *StoreCLITool) parseStoreCLIDetailedFormat(lines []string, raidArrays []types.RAIDInfo) []types.DiskInfo {
-	var disks []types.DiskInfo
-	var currentDisk types.DiskInfo
-	var currentArray *types.RAIDInfo
-	var inDriveSection bool
-	var inDetailedSection bool
-
-	// Helper function to finalize and add current disk
-	finalizeCurrentDisk := func() {
-		if currentDisk.Device != "" {
-			// Find the corresponding array for utilization calculation
-			for i, raid := range raidArrays {
-				if raid.ArrayID == currentDisk.RaidArrayID {
-					currentArray = &raidArrays[i]
-					break
-				}
-			}
-
-			// Calculate utilization
-			if currentArray != nil {
-				s.calculateStoreCLIDiskUtilization(&currentDisk, currentArray)
-			} else {
-				s.calculateStoreCLIDiskUtilization(&currentDisk, nil)
-			}
-
-			// Add the completed disk
-			disks = append(disks, currentDisk)
-		}
-	}
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Skip empty lines
-		if line == "" {
-			continue
-		}
-
-		// Detect start of drive section
-		if strings.Contains(line, "Drive /c") && strings.Contains(line, "/e") && strings.Contains(line, " :") {
-
-			// Finalize previous disk if exists
-			finalizeCurrentDisk()
-
-			// Initialize new drive section
-			inDriveSection = true
-			inDetailedSection = false
-			currentDisk = types.DiskInfo{
-				Type:         "raid",
-				Interface:    "SATA",
-				SmartEnabled: true, // Assume SMART is available through RAID controller
-				SmartHealthy: true, // Default to healthy unless proven otherwise
-				Health:       "OK", // Default health status
-			}
-			currentArray = nil
-
-			// Extract Controller, EID and Slot from header: "Drive /c0/e64/s3 :"
-			re := regexp.MustCompile(`Drive /c(\d+)/e(\d+)/s(\d+)`)
-			matches := re.FindStringSubmatch(line)
-			if len(matches) >= 4 {
-				controller := matches[1]
-				enclosure := matches[2]
-				slot := matches[3]
-				currentDisk.Device = fmt.Sprintf("raid-c%s-enc%s-slot%s", controller, enclosure, slot)
-				currentDisk.Location = fmt.Sprintf("Controller:%s EID:%s Slot:%s", controller, enclosure, slot)
-			}
-			continue
-		}
-
-		// Detect detailed information section
-		if strings.Contains(line, "Detailed Information") {
-			inDetailedSection = true
-			continue
-		}
-
-		// Parse the main drive table line (the one with EID:Slt format)
-		if inDriveSection && !inDetailedSection && strings.Contains(line, ":") &&
-			(strings.Contains(line, "Onln") || strings.Contains(line, "GHS") ||
-				strings.Contains(line, "DHS") || strings.Contains(line, "UGood") ||
-				strings.Contains(line, "UBad") || strings.Contains(line, "Offln")) {
-
-			fields := strings.Fields(line)
-			if len(fields) >= 8 {
-				// Parse: EID:Slt DID State DG Size Intf Med SED PI SeSz Model...
-				// Example: 64:3 3 Onln 0 893.750 GB SATA SSD N N 512B SAMSUNG MZ7KM960HAHP-00005...
-
-				state := fields[2]
-				driveGroup := fields[3]
-				currentDisk.Health = state
-
-				// Determine RAID role and array assignment
-				s.determineStoreCLIRaidRole(&currentDisk, state, driveGroup, raidArrays)
-
-				// Parse capacity
-				if len(fields) > 5 {
-					sizeStr := fields[4] + " " + fields[5]
-					currentDisk.Capacity = utils.ParseSizeToBytes(sizeStr)
-				}
-
-				// Parse interface and media type
-				if len(fields) > 6 {
-					currentDisk.Interface = fields[6]
-				}
-
-				// Parse model using field position logic
-				// Format: EID:Slt DID State DG Size Unit Intf Med SED PI SeSz Model... Sp Type
-				// Fields 0-10 are fixed, Model starts at field 11, ends 2 fields before the end
-				if len(fields) >= 13 { // Minimum: 11 fixed fields + model + Sp + Type
-					modelStart := 11
-					modelEnd := len(fields) - 2 // Exclude Sp and Type at the end
-					if modelEnd > modelStart {
-						currentDisk.Model = strings.Join(fields[modelStart:modelEnd], " ")
-					}
-				}
-			}
-			continue
-		}
-
-		// Parse detailed drive information
-		if inDetailedSection {
-			if strings.Contains(line, "Drive Temperature =") {
-				// Extract temperature: "Drive Temperature =  28C (82.40 F)"
-				re := regexp.MustCompile(`Drive Temperature =\s*(\d+)C`)
-				matches := re.FindStringSubmatch(line)
-				if len(matches) > 1 {
-					if temp, err := strconv.Atoi(matches[1]); err == nil {
-						currentDisk.Temperature = float64(temp)
-					}
-				}
-			} else if strings.Contains(line, "SN =") {
-				// Extract serial number: "SN = S2HTNX0J400715"
-				parts := strings.Split(line, "=")
-				if len(parts) > 1 {
-					currentDisk.Serial = strings.TrimSpace(parts[1])
-				}
-			} else if strings.Contains(line, "Model Number =") {
-				// Extract model: "Model Number = SAMSUNG MZ7KM960HAHP-00005"
-				parts := strings.Split(line, "=")
-				if len(parts) > 1 {
-					currentDisk.Model = strings.TrimSpace(parts[1])
-				}
-			} else if strings.Contains(line, "Drive position =") {
-				// Extract RAID position: "Drive position = DriveGroup:0, Span:0, Row:0"
-				parts := strings.Split(line, "=")
-				if len(parts) > 1 {
-					position := strings.TrimSpace(parts[1])
-					currentDisk.RaidPosition = position
-
-					// Extract DriveGroup for array mapping
-					re := regexp.MustCompile(`DriveGroup:(\d+)`)
-					matches := re.FindStringSubmatch(position)
-					if len(matches) > 1 {
-						currentDisk.RaidArrayID = matches[1]
-					}
-				}
-			} else if strings.Contains(line, "Commissioned Spare =") {
-				// Extract spare status: "Commissioned Spare = No"
-				currentDisk.IsCommissionedSpare = strings.Contains(line, "= Yes")
-				if currentDisk.IsCommissionedSpare {
-					currentDisk.RaidRole = "commissioned_spare"
-				}
-			} else if strings.Contains(line, "Emergency Spare =") {
-				// Extract emergency spare status: "Emergency Spare = No"
-				currentDisk.IsEmergencySpare = strings.Contains(line, "= Yes")
-				if currentDisk.IsEmergencySpare {
-					currentDisk.RaidRole = "emergency_spare"
-				}
-			} else if strings.Contains(line, "S.M.A.R.T alert flagged by drive =") {
-				// Extract SMART status: "S.M.A.R.T alert flagged by drive = No"
-				currentDisk.SmartEnabled = true
-				// If line contains "= No", it means no SMART alert, so disk is healthy
-				if strings.Contains(line, "= No") {
-					currentDisk.SmartHealthy = true
-				} else if strings.Contains(line, "= Yes") {
-					currentDisk.SmartHealthy = false
-				}
-			}
-		}
-	}
-
-	// Don't forget to finalize the last disk
-	finalizeCurrentDisk()
-
-	return disks
-}
-
-// calculateBasicUtilization calculates basic utilization for disks without detailed role information
-func (s *StoreCLITool) calculateBasicUtilization(disk *types.DiskInfo, raidArrays []types.RAIDInfo) {
-	// Try to determine basic RAID role from health status
-	healthLower := strings.ToLower(disk.Health)
-
-	switch {
-	case strings.Contains(healthLower, "onln") || strings.Contains(healthLower, "online"):
-		disk.RaidRole = "active"
-		// Try to find which array this disk belongs to (basic guess)
-		if len(raidArrays) > 0 {
-			disk.RaidArrayID = raidArrays[0].ArrayID // Default to first array
-			s.calculateStoreCLIDiskUtilization(disk, &raidArrays[0])
-		}
-	case strings.Contains(healthLower, "spare") || strings.Contains(healthLower, "hot"):
-		disk.RaidRole = "hot_spare"
-		disk.UsedBytes = 0
-		disk.AvailableBytes = disk.Capacity
-		disk.UsagePercentage = 0.0
-		disk.Mountpoint = "SPARE"
-		disk.Filesystem = "Hot-Spare"
-	case strings.Contains(healthLower, "failed") || strings.Contains(healthLower, "fail"):
-		disk.RaidRole = "failed"
-		disk.UsedBytes = 0
-		disk.AvailableBytes = 0
-		disk.UsagePercentage = 0.0
-		disk.Mountpoint = "FAILED"
-		disk.Filesystem = "Failed-Drive"
-	case strings.Contains(healthLower, "unconfigured") || strings.Contains(healthLower, "ugood"):
-		disk.RaidRole = "unconfigured"
-		disk.UsedBytes = 0
-		disk.AvailableBytes = disk.Capacity
-		disk.UsagePercentage = 0.0
-		disk.Mountpoint = "UNCONFIGURED"
-		disk.Filesystem = "Unconfigured"
-	default:
-		disk.RaidRole = "unknown"
-		// For unknown roles, assume basic utilization
-		if disk.Capacity > 0 {
-			disk.UsagePercentage = 50.0 // Conservative estimate
-			disk.UsedBytes = disk.Capacity / 2
-			disk.AvailableBytes = disk.Capacity / 2
-			disk.Mountpoint = "RAID-UNKNOWN"
-			disk.Filesystem = "Unknown-Array"
-		}
+// determineStoreCLIRaidRole sets the RAID role of a drive from its state
+// abbreviation. arrayID is the array of the drive's drive group, if any.
+func determineStoreCLIRaidRole(disk *types.DiskInfo, state string, arrayID string) {
+	disk.RaidArrayID = arrayID
+	disk.RaidRole = "unknown"
+	if role, ok := storcliDriveRoles.Lookup(state); ok {
+		disk.RaidRole = role
 	}
-}
 
-// determineStoreCLIRaidRole determines the RAID role based on StoreCLI state and drive group
-func (s *StoreCLITool) determineStoreCLIRaidRole(disk *types.DiskInfo, state string, driveGroup string, raidArrays []types.RAIDInfo) {
-	stateLower := strings.ToLower(state)
-
-	// Set health status based on state - this is important for the final health assessment
-	switch {
-	case strings.Contains(stateLower, "onln") || strings.Contains(stateLower, "online"):
-		disk.RaidRole = "active"
-		disk.RaidArrayID = driveGroup
-		disk.SmartHealthy = true // Online disks are healthy
-		disk.SmartEnabled = true // Assume SMART available through RAID controller
-		disk.Health = "OK"       // Override the raw state with a cleaner status
-
-		// Find and set the specific array
-		for _, raid := range raidArrays {
-			if raid.ArrayID == driveGroup {
-				break
-			}
-		}
-
-	case strings.Contains(stateLower, "ghs") || strings.Contains(stateLower, "global hot spare"):
-		disk.RaidRole = "hot_spare"
+	switch statemap.Normalize(state) {
+	case "ghs":
 		disk.IsGlobalSpare = true
-		disk.SmartHealthy = true // Spare disks are typically healthy
-		disk.SmartEnabled = true
-
-	case strings.Contains(stateLower, "dhs") || strings.Contains(stateLower, "dedicated hot spare"):
-		disk.RaidRole = "hot_spare"
+	case "dhs":
 		disk.IsDedicatedSpare = true
-		disk.RaidArrayID = driveGroup
-		disk.SmartHealthy = true // Spare disks are typically healthy
-		disk.SmartEnabled = true
-
-	case strings.Contains(stateLower, "spare"):
-		disk.RaidRole = "spare"
-		disk.SmartHealthy = true // Spare disks are typically healthy
-		disk.SmartEnabled = true
-
-	case strings.Contains(stateLower, "failed") || strings.Contains(stateLower, "fail"):
-		disk.RaidRole = "failed"
-		disk.SmartHealthy = false
-		disk.Health = "FAILED"
-
-	case strings.Contains(stateLower, "rebuild") || strings.Contains(stateLower, "rbld"):
-		disk.RaidRole = "rebuilding"
-		disk.RaidArrayID = driveGroup
-		disk.SmartHealthy = true // Rebuilding disks are still functional
-		disk.SmartEnabled = true
-
-	case strings.Contains(stateLower, "ugood") || strings.Contains(stateLower, "unconfigured good"):
-		disk.RaidRole = "unconfigured"
-		disk.SmartHealthy = true // Good unconfigured disks are healthy
-		disk.SmartEnabled = true
-
-	case strings.Contains(stateLower, "ubad") || strings.Contains(stateLower, "unconfigured bad"):
-		disk.RaidRole = "unconfigured"
-		disk.Health = "FAILED"
-		disk.SmartHealthy = false
-
-	case strings.Contains(stateLower, "offln") || strings.Contains(stateLower, "offline"):
-		disk.RaidRole = "failed"
-		disk.SmartHealthy = false
-		disk.Health = "FAILED"
-
-	default:
-		disk.RaidRole = "unknown"
-		// For unknown states, be conservative but check if it looks healthy
-		if !strings.Contains(stateLower, "fail") && !strings.Contains(stateLower, "bad") && !strings.Contains(stateLower, "error") {
-			disk.SmartHealthy = true
-			disk.SmartEnabled = true
-
-		}
 	}
+
+	// SMART alerts the controller passes on from the drive are applied by the caller
+	disk.SmartHealthy = disk.HealthStatus == types.HealthStatusOK || disk.HealthStatus == types.HealthStatusWarning
 }
 
 // calculateStoreCLIDiskUtilization calculates disk utilization for StoreCLI managed disks
-func (s *StoreCLITool) calculateStoreCLIDiskUtilization(disk *types.DiskInfo, array *types.RAIDInfo) {
+func calculateStoreCLIDiskUtilization(disk *types.DiskInfo, array *types.RAIDInfo) {
 	if disk.Capacity <= 0 {
 		return
 	}
@@ -0,0 +1,687 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/statemap"
+	"disk-health-exporter/internal/utils"
+	"disk-health-exporter/pkg/types"
+)
+
+// StorCLI prints every command run with the J suffix as a list of per-controller
+// results. The response data layout depends on the command; the structs below
+// cover the parts the exporter reads. Keys that embed controller, VD or drive
+// numbers ("/c0/v1", "Drive /c0/e252/s3") are matched with regular expressions.
+
+var (
+	storcliVDKeyRe    = regexp.MustCompile(`^/c(\d+)/v(\d+)$`)
+	storcliDriveKeyRe = regexp.MustCompile(`^Drive /c(\d+)(?:/e(\d+))?/s(\d+)$`)
+	storcliLeadingRe  = regexp.MustCompile(`^\s*(-?\d+)`)
+)
+
+// storcliValue is a text field that StorCLI prints as a string or a number
+// depending on the firmware generation. Surrounding padding is removed.
+type storcliValue string
+
+// UnmarshalJSON accepts JSON strings and numbers
+func (v *storcliValue) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("invalid StorCLI value %s", data)
+		}
+		text = number.String()
+	}
+	*v = storcliValue(strings.TrimSpace(text))
+	return nil
+}
+
+// storcliInt is a counter that StorCLI prints as a number, a numeric string
+// or "-" when not applicable. Non-numeric values decode to 0.
+type storcliInt int64
+
+// UnmarshalJSON accepts JSON numbers and numeric strings
+func (n *storcliInt) UnmarshalJSON(data []byte) error {
+	var value storcliValue
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	parsed, err := strconv.ParseInt(strings.TrimSuffix(string(value), "%"), 10, 64)
+	if err != nil {
+		parsed = 0
+	}
+	*n = storcliInt(parsed)
+	return nil
+}
+
+// storcliCommandStatus is the status StorCLI reports for a command on one controller
+type storcliCommandStatus struct {
+	Controller  storcliValue `json:"Controller"`
+	Status      string       `json:"Status"`
+	Description string       `json:"Description"`
+}
+
+// storcliResponse holds the response data of a successful command on one controller
+type storcliResponse[T any] struct {
+	Controller string
+	Data       T
+}
+
+// parseStorCLIOutput decodes the response data of each controller the command succeeded on.
+// Failed controllers (e.g. "No VDs have been configured") are skipped.
+func parseStorCLIOutput[T any](data []byte) ([]storcliResponse[T], error) {
+	var output struct {
+		Controllers []struct {
+			CommandStatus storcliCommandStatus `json:"Command Status"`
+			ResponseData  json.RawMessage      `json:"Response Data"`
+		} `json:"Controllers"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+
+	var responses []storcliResponse[T]
+	for _, controller := range output.Controllers {
+		if controller.CommandStatus.Status != "Success" || len(controller.ResponseData) == 0 {
+			continue
+		}
+		var response T
+		if err := json.Unmarshal(controller.ResponseData, &response); err != nil {
+			return nil, fmt.Errorf("controller %s: %w", controller.CommandStatus.Controller, err)
+		}
+		responses = append(responses, storcliResponse[T]{
+			Controller: string(controller.CommandStatus.Controller),
+			Data:       response,
+		})
+	}
+	return responses, nil
+}
+
+// storcliControllerData is the response data of "/cX show all"
+type storcliControllerData struct {
+	Basics struct {
+		Controller   storcliValue `json:"Controller"`
+		Model        storcliValue `json:"Model"`
+		SerialNumber storcliValue `json:"Serial Number"`
+	} `json:"Basics"`
+	Version struct {
+		FirmwarePackage storcliValue `json:"Firmware Package Build"`
+		FirmwareVersion storcliValue `json:"Firmware Version"`
+		BIOSVersion     storcliValue `json:"Bios Version"`
+		DriverVersion   storcliValue `json:"Driver Version"`
+	} `json:"Version"`
+	PendingImages map[string]storcliValue `json:"Pending Images in Flash"`
+	Status        struct {
+		ControllerStatus          storcliValue `json:"Controller Status"`
+		MemoryCorrectableErrors   storcliInt   `json:"Memory Correctable Errors"`
+		MemoryUncorrectableErrors storcliInt   `json:"Memory Uncorrectable Errors"`
+	} `json:"Status"`
+	HwCfg struct {
+		Alarm                storcliValue `json:"Alarm"`
+		MemorySize           storcliValue `json:"On Board Memory Size"`
+		ROCTemperature       storcliInt   `json:"ROC temperature(Degree Celsius)"`
+		ROCTemperatureLegacy storcliInt   `json:"ROC temperature(Degree Celcius)"` // Spelling used by older StorCLI releases
+	} `json:"HwCfg"`
+	VirtualDrives  storcliInt `json:"Virtual Drives"`
+	PhysicalDrives storcliInt `json:"Physical Drives"`
+}
+
+// storcliVirtualDrive is a row of a VD list
+type storcliVirtualDrive struct {
+	DGVD  storcliValue `json:"DG/VD"`
+	Type  storcliValue `json:"TYPE"`
+	State storcliValue `json:"State"`
+	Size  storcliValue `json:"Size"`
+}
+
+// storcliVDProperties is the "VDn Properties" section of "/cX/vall show all"
+type storcliVDProperties struct {
+	SpanDepth     storcliInt   `json:"Span Depth"`
+	DrivesPerSpan storcliInt   `json:"Number of Drives Per Span"`
+	OSDriveName   storcliValue `json:"OS Drive Name"`
+}
+
+// storcliPhysicalDrive is a row of a PD list
+type storcliPhysicalDrive struct {
+	DID   storcliInt   `json:"DID"`
+	State storcliValue `json:"State"`
+	DG    storcliValue `json:"DG"`
+	Size  storcliValue `json:"Size"`
+	Intf  storcliValue `json:"Intf"`
+	Model storcliValue `json:"Model"`
+}
+
+// storcliDriveState is the "Drive /cX/eY/sZ State" section of a drive's detailed information
+type storcliDriveState struct {
+	ShieldCounter     storcliInt   `json:"Shield Counter"`
+	MediaErrors       storcliInt   `json:"Media Error Count"`
+	OtherErrors       storcliInt   `json:"Other Error Count"`
+	PredictiveFailure storcliInt   `json:"Predictive Failure Count"`
+	Temperature       storcliValue `json:"Drive Temperature"`
+	SMARTAlert        storcliValue `json:"S.M.A.R.T alert flagged by drive"`
+}
+
+// storcliDriveAttributes is the "Drive /cX/eY/sZ Device attributes" section
+type storcliDriveAttributes struct {
+	SerialNumber   storcliValue `json:"SN"`
+	ManufacturerID storcliValue `json:"Manufacturer Id"`
+	ModelNumber    storcliValue `json:"Model Number"`
+	DeviceSpeed    storcliValue `json:"Device Speed"`
+	LinkSpeed      storcliValue `json:"Link Speed"`
+}
+
+// storcliDriveStateNames spells out the drive state abbreviations of PD lists
+// the way MegaCLI reports firmware states
+var storcliDriveStateNames = map[string]string{
+	"Onln":   "Online",
+	"Offln":  "Offline",
+	"UGood":  "Unconfigured(good)",
+	"UBad":   "Unconfigured(bad)",
+	"UGUnsp": "Unconfigured(good) Unsupported",
+	"UBUnsp": "Unconfigured(bad) Unsupported",
+	"Rbld":   "Rebuild",
+	"GHS":    "Global Hotspare",
+	"DHS":    "Dedicated Hotspare",
+	"Cpybck": "Copyback",
+	"Msng":   "Missing",
+	"Sntze":  "Sanitize",
+	"JBOD":   "JBOD",
+}
+
+// storcliDriveStates translates the drive state abbreviations of PD lists
+var storcliDriveStates = statemap.New(merge.SourceStorCLI, map[string]types.HealthStatus{
+	"Onln":    types.HealthStatusOK,
+	"UGood":   types.HealthStatusOK,
+	"GHS":     types.HealthStatusOK,
+	"DHS":     types.HealthStatusOK,
+	"JBOD":    types.HealthStatusOK,
+	"Rbld":    types.HealthStatusWarning,
+	"Cpybck":  types.HealthStatusWarning,
+	"Sntze":   types.HealthStatusWarning,
+	"UGUnsp":  types.HealthStatusWarning,
+	"UGShld":  types.HealthStatusWarning,
+	"HSPShld": types.HealthStatusWarning,
+	"CFShld":  types.HealthStatusWarning,
+	"CBShld":  types.HealthStatusWarning,
+	"Offln":   types.HealthStatusCritical,
+	"UBad":    types.HealthStatusCritical,
+	"UBUnsp":  types.HealthStatusCritical,
+	"Msng":    types.HealthStatusCritical,
+})
+
+// storcliDriveRoles translates the drive state abbreviations of PD lists into
+// their RAID role
+var storcliDriveRoles = statemap.New(merge.SourceStorCLI, map[string]string{
+	"Onln":    "active",
+	"CFShld":  "active",
+	"GHS":     "hot_spare",
+	"DHS":     "hot_spare",
+	"HSPShld": "hot_spare",
+	"Rbld":    "rebuilding",
+	"Cpybck":  "rebuilding",
+	"CBShld":  "rebuilding",
+	"Offln":   "failed",
+	"Msng":    "failed",
+	"UGood":   "unconfigured",
+	"UGUnsp":  "unconfigured",
+	"UGShld":  "unconfigured",
+	"UBad":    "unconfigured",
+	"UBUnsp":  "unconfigured",
+	"Sntze":   "unconfigured",
+	"JBOD":    "unconfigured",
+})
+
+// storcliVDStates translates the state abbreviations of VD lists
+var storcliVDStates = statemap.New(merge.SourceStorCLI, map[string]types.RAIDState{
+	"Optl": types.RAIDStateOptimal,
+	"Pdgd": types.RAIDStateDegraded,
+	"Dgrd": types.RAIDStateDegraded,
+	"Rec":  types.RAIDStateDegraded,
+	"OfLn": types.RAIDStateFailed,
+})
+
+// storcliVDRebuilds lists the VD states of an array recovering onto a drive
+var storcliVDRebuilds = statemap.New(merge.SourceStorCLI, map[string]bool{
+	"Rec": true,
+})
+
+// storcliDrivePolicies is the "Drive /cX/eY/sZ Policies/Settings" section
+type storcliDrivePolicies struct {
+	DrivePosition     storcliValue `json:"Drive position"`
+	CommissionedSpare storcliValue `json:"Commissioned Spare"`
+	EmergencySpare    storcliValue `json:"Emergency Spare"`
+}
+
+// storcliProperty is an entry of the property/value lists used by "/cX/cv show all"
+type storcliProperty struct {
+	Property storcliValue `json:"Property"`
+	Value    storcliValue `json:"Value"`
+}
+
+// storcliControllerProperty is an entry of the "Controller Properties" list
+// used by "/cX show patrolread" and "/cX show cc"
+type storcliControllerProperty struct {
+	Name  storcliValue `json:"Ctrl_Prop"`
+	Value storcliValue `json:"Value"`
+}
+
+// storcliVDOperation is an entry of the "VD Operation Status" list used by "/cX/vall show cc"
+type storcliVDOperation struct {
+	VD        storcliValue `json:"VD"`
+	Operation storcliValue `json:"Operation"`
+	Progress  storcliInt   `json:"Progress%"`
+	Status    storcliValue `json:"Status"`
+}
+
+// parseStorCLIControllers parses "/call show all J" into controller information
+func parseStorCLIControllers(data []byte) ([]types.RAIDControllerInfo, error) {
+	responses, err := parseStorCLIOutput[storcliControllerData](data)
+	if err != nil {
+		return nil, err
+	}
+
+	var controllers []types.RAIDControllerInfo
+	for _, response := range responses {
+		ctrl := response.Data
+		adapterID, err := strconv.Atoi(response.Controller)
+		if err != nil {
+			adapterID, _ = strconv.Atoi(string(ctrl.Basics.Controller))
+		}
+
+		rocTemperature := ctrl.HwCfg.ROCTemperature
+		if rocTemperature == 0 {
+			rocTemperature = ctrl.HwCfg.ROCTemperatureLegacy
+		}
+
+		controllers = append(controllers, types.RAIDControllerInfo{
+			AdapterID:                 adapterID,
+			ToolName:                  "StoreCLI",
+			Model:                     string(ctrl.Basics.Model),
+			SerialNumber:              string(ctrl.Basics.SerialNumber),
+			FirmwareVersion:           string(ctrl.Version.FirmwareVersion),
+			FirmwarePackage:           string(ctrl.Version.FirmwarePackage),
+			DriverVersion:             string(ctrl.Version.DriverVersion),
+			BIOSVersion:               string(ctrl.Version.BIOSVersion),
+			CacheSize:                 utils.ParseSizeToBytes(string(ctrl.HwCfg.MemorySize)),
+			Status:                    string(ctrl.Status.ControllerStatus),
+			ROCTemperature:            int(rocTemperature),
+			MemoryCorrectableErrors:   int64(ctrl.Status.MemoryCorrectableErrors),
+			MemoryUncorrectableErrors: int64(ctrl.Status.MemoryUncorrectableErrors),
+			NumVirtualDrives:          int(ctrl.VirtualDrives),
+			NumPhysicalDrives:         int(ctrl.PhysicalDrives),
+			PendingFlashImage:         storcliHasPendingImage(ctrl.PendingImages),
+			AlarmState:                string(ctrl.HwCfg.Alarm),
+		})
+	}
+	return controllers, nil
+}
+
+// storcliHasPendingImage reports whether the "Pending Images in Flash" section lists an image.
+// Without one, StorCLI prints "No pending images".
+func storcliHasPendingImage(images map[string]storcliValue) bool {
+	for _, image := range images {
+		if image != "" && !strings.EqualFold(string(image), "No pending images") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStorCLIForeignConfigs parses "/call/fall show J" into the number of
+// foreign drive groups per controller. Controllers without foreign
+// configurations report no response data and are left out.
+func parseStorCLIForeignConfigs(data []byte) (map[string]int, error) {
+	responses, err := parseStorCLIOutput[struct {
+		Configurations []struct {
+			DG storcliValue `json:"DG"`
+		} `json:"FOREIGN CONFIGURATION"`
+	}](data)
+	if err != nil {
+		return nil, err
+	}
+
+	foreign := make(map[string]int, len(responses))
+	for _, response := range responses {
+		groups := make(map[storcliValue]bool)
+		for _, config := range response.Data.Configurations {
+			groups[config.DG] = true
+		}
+		foreign[response.Controller] = len(groups)
+	}
+	return foreign, nil
+}
+
+// parseStorCLIControllerProperties parses the "Controller Properties" list of
+// "/call show patrolread J" or "/call show cc J", keyed by controller number
+func parseStorCLIControllerProperties(data []byte) (map[string]map[string]string, error) {
+	responses, err := parseStorCLIOutput[struct {
+		Properties []storcliControllerProperty `json:"Controller Properties"`
+	}](data)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]map[string]string, len(responses))
+	for _, response := range responses {
+		values := make(map[string]string, len(response.Data.Properties))
+		for _, property := range response.Data.Properties {
+			values[string(property.Name)] = string(property.Value)
+		}
+		properties[response.Controller] = values
+	}
+	return properties, nil
+}
+
+// applyStorCLIPatrolRead sets the patrol read schedule and state from "/cX show patrolread" properties.
+// While a patrol read runs, StorCLI appends its progress to the state (e.g. "Active 63").
+func applyStorCLIPatrolRead(controller *types.RAIDControllerInfo, properties map[string]string) {
+	state, progress, _ := strings.Cut(properties["PR Current State"], " ")
+	controller.PatrolReadMode = properties["PR Mode"]
+	controller.PatrolReadState = state
+	if state == "Active" {
+		controller.PatrolReadProgress = storcliLeadingInt(progress)
+	}
+	controller.PatrolReadIterations, _ = strconv.ParseInt(properties["PR iterations completed"], 10, 64)
+	controller.PatrolReadNextRun = parseMegaRAIDTime(properties["PR Next Start time"])
+}
+
+// parseStorCLIVirtualDrives parses "/call/vall show all J" into RAID arrays.
+// models maps controller numbers to controller models for the Controller field.
+// It also returns the array ID of each drive group, keyed by "controller/DG",
+// so physical drives can be matched to their array.
+func parseStorCLIVirtualDrives(data []byte, models map[string]string) ([]types.RAIDInfo, map[string]string, error) {
+	responses, err := parseStorCLIOutput[map[string]json.RawMessage](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var raidArrays []types.RAIDInfo
+	groups := make(map[string]string)
+
+	for _, response := range responses {
+		for _, key := range sortedKeys(response.Data) {
+			matches := storcliVDKeyRe.FindStringSubmatch(key)
+			if matches == nil {
+				continue
+			}
+			controller, vdID := matches[1], matches[2]
+
+			var vds []storcliVirtualDrive
+			if err := json.Unmarshal(response.Data[key], &vds); err != nil || len(vds) == 0 {
+				return nil, nil, fmt.Errorf("invalid virtual drive %s: %v", key, err)
+			}
+			vd := vds[0]
+
+			var members []storcliPhysicalDrive
+			if raw, ok := response.Data["PDs for VD "+vdID]; ok {
+				if err := json.Unmarshal(raw, &members); err != nil {
+					return nil, nil, fmt.Errorf("invalid drives of %s: %w", key, err)
+				}
+			}
+			var properties storcliVDProperties
+			if raw, ok := response.Data["VD"+vdID+" Properties"]; ok {
+				if err := json.Unmarshal(raw, &properties); err != nil {
+					return nil, nil, fmt.Errorf("invalid properties of %s: %w", key, err)
+				}
+			}
+
+			raid := types.RAIDInfo{
+				ArrayID:       controller + ":" + vdID,
+				RaidLevel:     string(vd.Type),
+				State:         string(vd.State),
+				Status:        storcliVDStates.Map(string(vd.State)),
+				Size:          utils.ParseSizeToBytes(string(vd.Size)),
+				NumDrives:     len(members),
+				Type:          "hardware",
+				Controller:    "StoreCLI",
+				VirtualDevice: string(properties.OSDriveName),
+			}
+			if model := models[controller]; model != "" {
+				raid.Controller = "StoreCLI - " + model
+			}
+			if raid.NumDrives == 0 {
+				raid.NumDrives = int(properties.SpanDepth * properties.DrivesPerSpan)
+			}
+			raid.Rebuilding, _ = storcliVDRebuilds.Lookup(string(vd.State))
+			for _, member := range members {
+				role, _ := storcliDriveRoles.Lookup(string(member.State))
+				switch role {
+				case "active":
+					raid.NumActiveDrives++
+				case "failed":
+					raid.NumFailedDrives++
+				case "rebuilding":
+					raid.Rebuilding = true
+				}
+			}
+
+			raidArrays = append(raidArrays, raid)
+
+			if dg, _, ok := strings.Cut(string(vd.DGVD), "/"); ok {
+				groupKey := controller + "/" + dg
+				if _, exists := groups[groupKey]; !exists {
+					groups[groupKey] = raid.ArrayID
+				}
+			}
+		}
+	}
+
+	return raidArrays, groups, nil
+}
+
+// parseStorCLIConsistencyChecks parses "/call/vall show cc J" into the progress
+// of running consistency checks, keyed by array ID
+func parseStorCLIConsistencyChecks(data []byte) (map[string]int, error) {
+	responses, err := parseStorCLIOutput[struct {
+		Operations []storcliVDOperation `json:"VD Operation Status"`
+	}](data)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make(map[string]int)
+	for _, response := range responses {
+		for _, operation := range response.Data.Operations {
+			if operation.Operation == "CC" && operation.Status == "In progress" {
+				progress[response.Controller+":"+string(operation.VD)] = int(operation.Progress)
+			}
+		}
+	}
+	return progress, nil
+}
+
+// parseStorCLIPhysicalDrives parses "/call/eall/sall show all J" into disks.
+// groups maps "controller/DG" to array IDs as returned by parseStorCLIVirtualDrives.
+func parseStorCLIPhysicalDrives(data []byte, raidArrays []types.RAIDInfo, groups map[string]string) ([]types.DiskInfo, error) {
+	responses, err := parseStorCLIOutput[map[string]json.RawMessage](data)
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []types.DiskInfo
+	for _, response := range responses {
+		for _, key := range sortedKeys(response.Data) {
+			matches := storcliDriveKeyRe.FindStringSubmatch(key)
+			if matches == nil {
+				continue
+			}
+			controller, enclosure, slot := matches[1], matches[2], matches[3]
+
+			var drives []storcliPhysicalDrive
+			if err := json.Unmarshal(response.Data[key], &drives); err != nil || len(drives) == 0 {
+				return nil, fmt.Errorf("invalid drive %s: %v", key, err)
+			}
+			drive := drives[0]
+
+			var state storcliDriveState
+			var attributes storcliDriveAttributes
+			var policies storcliDrivePolicies
+			if raw, ok := response.Data[key+" - Detailed Information"]; ok {
+				var detail map[string]json.RawMessage
+				if err := json.Unmarshal(raw, &detail); err != nil {
+					return nil, fmt.Errorf("invalid details of %s: %w", key, err)
+				}
+				sections := []struct {
+					name   string
+					target any
+				}{
+					{key + " State", &state},
+					{key + " Device attributes", &attributes},
+					{key + " Policies/Settings", &policies},
+				}
+				for _, section := range sections {
+					if raw, ok := detail[section.name]; ok {
+						if err := json.Unmarshal(raw, section.target); err != nil {
+							return nil, fmt.Errorf("invalid %s: %w", section.name, err)
+						}
+					}
+				}
+			}
+
+			disk := types.DiskInfo{
+				Device:       fmt.Sprintf("raid-c%s-enc%s-slot%s", controller, enclosure, slot),
+				Location:     fmt.Sprintf("Controller:%s EID:%s Slot:%s", controller, enclosure, slot),
+				Serial:       string(attributes.SerialNumber),
+				Model:        string(attributes.ModelNumber),
+				Health:       string(drive.State),
+				HealthStatus: storcliDriveStates.Map(string(drive.State)),
+				Type:         "raid",
+				Interface:    string(drive.Intf),
+				Capacity:     utils.ParseSizeToBytes(string(drive.Size)),
+				SmartEnabled: true, // SMART alerts are reported by the controller
+			}
+			if enclosure == "" {
+				// Drives attached directly to the controller have no enclosure
+				disk.Device = fmt.Sprintf("raid-c%s-slot%s", controller, slot)
+				disk.Location = fmt.Sprintf("Controller:%s Slot:%s", controller, slot)
+			}
+			if disk.Model == "" {
+				disk.Model = string(drive.Model)
+			}
+			// SATA drives report the "ATA" placeholder instead of a vendor
+			if vendor := string(attributes.ManufacturerID); vendor != "ATA" {
+				disk.Vendor = vendor
+			}
+			if temperature := storcliLeadingInt(string(state.Temperature)); temperature > 0 {
+				disk.Temperature = float64(temperature)
+			}
+			if position := string(policies.DrivePosition); position != "" && position != "N/A" {
+				disk.RaidPosition = position
+			}
+
+			firmwareState := string(drive.State)
+			if name, ok := storcliDriveStateNames[firmwareState]; ok {
+				firmwareState = name
+			}
+			// Drives carrying a foreign configuration show "F" as their drive group
+			foreignState := "None"
+			if drive.DG == "F" {
+				foreignState = "Foreign"
+			}
+			disk.RaidDrive = &types.RAIDDriveInfo{
+				ToolName:               "StoreCLI",
+				FirmwareState:          firmwareState,
+				ForeignState:           foreignState,
+				MediaErrorCount:        int64(state.MediaErrors),
+				OtherErrorCount:        int64(state.OtherErrors),
+				PredictiveFailureCount: int64(state.PredictiveFailure),
+				ShieldCounter:          int64(state.ShieldCounter),
+				LinkSpeed:              string(attributes.LinkSpeed),
+				DeviceSpeed:            string(attributes.DeviceSpeed),
+			}
+
+			// The DID is the device ID smartctl uses to reach the drive through the controller
+			if number, err := strconv.Atoi(controller); err == nil {
+				setMegaRAIDPassthrough(&disk, number, int(drive.DID))
+			}
+
+			arrayID := groups[controller+"/"+string(drive.DG)]
+			determineStoreCLIRaidRole(&disk, string(drive.State), arrayID)
+
+			if state.SMARTAlert == "Yes" {
+				disk.SmartHealthy = false
+			}
+			if policies.CommissionedSpare == "Yes" {
+				disk.IsCommissionedSpare = true
+				disk.RaidRole = "commissioned_spare"
+			}
+			if policies.EmergencySpare == "Yes" {
+				disk.IsEmergencySpare = true
+				disk.RaidRole = "emergency_spare"
+			}
+
+			var array *types.RAIDInfo
+			for i := range raidArrays {
+				if raidArrays[i].ArrayID == disk.RaidArrayID {
+					array = &raidArrays[i]
+					break
+				}
+			}
+			calculateStoreCLIDiskUtilization(&disk, array)
+
+			disks = append(disks, disk)
+		}
+	}
+
+	return disks, nil
+}
+
+// parseStorCLICacheVaults parses "/call/cv show all J" into battery information, keyed by controller number
+func parseStorCLICacheVaults(data []byte) (map[string]*types.RAIDBatteryInfo, error) {
+	responses, err := parseStorCLIOutput[map[string][]storcliProperty](data)
+	if err != nil {
+		return nil, err
+	}
+
+	batteries := make(map[string]*types.RAIDBatteryInfo, len(responses))
+	for _, response := range responses {
+		// Property names are unique across the Cachevault_Info, Firmware_Status,
+		// GasGaugeStatus, Design_Info and Properties sections
+		properties := make(map[string]string)
+		for _, section := range response.Data {
+			for _, property := range section {
+				properties[string(property.Property)] = string(property.Value)
+			}
+		}
+		if properties["State"] == "" {
+			continue
+		}
+
+		adapterID, _ := strconv.Atoi(response.Controller)
+		nextLearn, _, _ := strings.Cut(properties["Next Learn time"], " (")
+		batteries[response.Controller] = &types.RAIDBatteryInfo{
+			AdapterID:           adapterID,
+			ToolName:            "StoreCLI",
+			BatteryType:         properties["Type"],
+			State:               properties["State"],
+			Temperature:         storcliLeadingInt(properties["Temperature"]),
+			ReplacementRequired: properties["Replacement required"] == "Yes",
+			PackEnergy:          storcliLeadingInt(properties["Pack Energy"]),
+			Capacitance:         storcliLeadingInt(properties["Capacitance"]),
+			DesignCapacity:      storcliLeadingInt(properties["Design Capacity"]),
+			ManufactureDate:     properties["Date of Manufacture"],
+			SerialNumber:        properties["Serial Number"],
+			ManufactureName:     properties["Manufacture Name"],
+			DeviceName:          properties["Device Name"],
+			AutoLearnPeriod:     storcliLeadingInt(properties["Auto Learn Period"]),
+			NextLearnTime:       strings.TrimSpace(nextLearn),
+		}
+	}
+	return batteries, nil
+}
+
+// storcliLeadingInt parses the number at the start of a value with a unit (e.g. "294 J", "27d", " 31C (87.80 F)")
+func storcliLeadingInt(value string) int {
+	matches := storcliLeadingRe.FindStringSubmatch(value)
+	if matches == nil {
+		return 0
+	}
+	number, _ := strconv.Atoi(matches[1])
+	return number
+}
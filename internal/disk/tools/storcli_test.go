@@ -1,7 +1,16 @@
 package tools
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"disk-health-exporter/pkg/types"
 )
 
 func TestNewStoreCLITool(t *testing.T) {
@@ -41,6 +50,9 @@ func TestStoreCLIToolInterfaces(t *testing.T) {
 
 	// Test as CombinedToolInterface
 	var _ CombinedToolInterface = storeTool
+
+	// Test as ControllerToolInterface
+	var _ ControllerToolInterface = storeTool
 }
 
 func TestStoreCLIToolMethods(t *testing.T) {
@@ -52,3 +64,502 @@ func TestStoreCLIToolMethods(t *testing.T) {
 	_ = storeTool.GetRAIDDisks()
 	_ = storeTool.GetDisks()
 }
+
+// storcliGenerations are the controller generations with captured StorCLI output under testdata/storcli
+var storcliGenerations = []string{"sas2208", "sas3108", "sas3516"}
+
+func readStorCLIFixture(t *testing.T, generation, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "storcli", generation, name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func TestParseStorCLIControllers(t *testing.T) {
+	expected := map[string][]types.RAIDControllerInfo{
+		"sas2208": {{
+			AdapterID: 0, ToolName: "StoreCLI", Model: "LSI MegaRAID SAS 9271-8i", SerialNumber: "SV43512345",
+			FirmwareVersion: "3.460.115-6465", FirmwarePackage: "23.34.0-0019", DriverVersion: "06.811.02.00-rc1",
+			BIOSVersion: "5.50.03.0_4.17.08.00_0x06110200", CacheSize: 1 << 30, Status: "Optimal",
+			NumVirtualDrives: 1, NumPhysicalDrives: 4, AlarmState: "Present",
+		}},
+		"sas3108": {{
+			AdapterID: 0, ToolName: "StoreCLI", Model: "AVAGO MegaRAID SAS 9361-8i", SerialNumber: "SK71234567",
+			FirmwareVersion: "4.680.00-8527", FirmwarePackage: "24.21.0-0148", DriverVersion: "07.703.05.00-rc1",
+			BIOSVersion: "6.36.00.3_4.19.08.00_0x06180203", CacheSize: 1 << 30, Status: "Needs Attention",
+			ROCTemperature: 72, MemoryCorrectableErrors: 3, NumVirtualDrives: 2, NumPhysicalDrives: 7, AlarmState: "Absent",
+		}},
+		"sas3516": {
+			{
+				AdapterID: 0, ToolName: "StoreCLI", Model: "MegaRAID 9460-16i", SerialNumber: "SKC4012345",
+				FirmwareVersion: "5.140.00-3319", FirmwarePackage: "51.14.0-3900", DriverVersion: "07.714.04.00-rc1",
+				BIOSVersion: "7.14.00.0_0x070E0100", CacheSize: 4 << 30, Status: "Optimal", ROCTemperature: 58,
+				NumVirtualDrives: 1, NumPhysicalDrives: 5, AlarmState: "Absent",
+			},
+			{
+				AdapterID: 1, ToolName: "StoreCLI", Model: "MegaRAID 9460-8i", SerialNumber: "SKB3998765",
+				FirmwareVersion: "5.140.00-3319", FirmwarePackage: "51.14.0-3900", DriverVersion: "07.714.04.00-rc1",
+				BIOSVersion: "7.14.00.0_0x070E0100", CacheSize: 4 << 30, Status: "Optimal", ROCTemperature: 51,
+				NumPhysicalDrives: 2, PendingFlashImage: true, AlarmState: "Absent",
+			},
+		},
+	}
+
+	for _, generation := range storcliGenerations {
+		t.Run(generation, func(t *testing.T) {
+			controllers, err := parseStorCLIControllers(readStorCLIFixture(t, generation, "show_all.json"))
+			if err != nil {
+				t.Fatalf("parseStorCLIControllers failed: %v", err)
+			}
+			if !reflect.DeepEqual(controllers, expected[generation]) {
+				t.Errorf("Expected %+v, got %+v", expected[generation], controllers)
+			}
+		})
+	}
+}
+
+func TestParseStorCLIForeignConfigs(t *testing.T) {
+	expected := map[string]map[string]int{
+		"sas2208": {},
+		"sas3108": {},
+		"sas3516": {"1": 1},
+	}
+
+	for _, generation := range storcliGenerations {
+		t.Run(generation, func(t *testing.T) {
+			foreign, err := parseStorCLIForeignConfigs(readStorCLIFixture(t, generation, "fall_show.json"))
+			if err != nil {
+				t.Fatalf("parseStorCLIForeignConfigs failed: %v", err)
+			}
+			if !reflect.DeepEqual(foreign, expected[generation]) {
+				t.Errorf("Expected %v, got %v", expected[generation], foreign)
+			}
+		})
+	}
+}
+
+func TestStorCLIPatrolRead(t *testing.T) {
+	nextRun := time.Date(2025, 7, 19, 3, 0, 0, 0, time.Local)
+	tests := []struct {
+		generation string
+		mode       string
+		state      string
+		progress   int
+		iterations int64
+		nextRun    time.Time
+	}{
+		{"sas2208", "Auto", "Active", 63, 517, nextRun},
+		{"sas3108", "Auto", "Stopped", 0, 41, nextRun},
+		{"sas3516", "Manual", "Stopped", 0, 212, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.generation, func(t *testing.T) {
+			properties, err := parseStorCLIControllerProperties(readStorCLIFixture(t, tt.generation, "show_patrolread.json"))
+			if err != nil {
+				t.Fatalf("parseStorCLIControllerProperties failed: %v", err)
+			}
+			var controller types.RAIDControllerInfo
+			applyStorCLIPatrolRead(&controller, properties["0"])
+			if controller.PatrolReadState != tt.state || controller.PatrolReadProgress != tt.progress {
+				t.Errorf("Expected %s %d%%, got %s %d%%", tt.state, tt.progress, controller.PatrolReadState, controller.PatrolReadProgress)
+			}
+			if controller.PatrolReadMode != tt.mode || controller.PatrolReadIterations != tt.iterations {
+				t.Errorf("Expected mode %s with %d iterations, got %s with %d", tt.mode, tt.iterations, controller.PatrolReadMode, controller.PatrolReadIterations)
+			}
+			if !controller.PatrolReadNextRun.Equal(tt.nextRun) {
+				t.Errorf("Expected next run %v, got %v", tt.nextRun, controller.PatrolReadNextRun)
+			}
+		})
+	}
+}
+
+func TestParseStorCLIVirtualDrives(t *testing.T) {
+	models := map[string]string{"0": "AVAGO MegaRAID SAS 9361-8i"}
+	raidArrays, groups, err := parseStorCLIVirtualDrives(readStorCLIFixture(t, "sas3108", "vall_show_all.json"), models)
+	if err != nil {
+		t.Fatalf("parseStorCLIVirtualDrives failed: %v", err)
+	}
+
+	expected := []types.RAIDInfo{
+		{
+			ArrayID: "0:0", RaidLevel: "RAID1", State: "Optl", Status: 1,
+			NumDrives: 2, NumActiveDrives: 2, Type: "hardware",
+			Controller: "StoreCLI - AVAGO MegaRAID SAS 9361-8i", VirtualDevice: "/dev/sda",
+		},
+		{
+			// One member is rebuilding
+			ArrayID: "0:1", RaidLevel: "RAID6", State: "Dgrd", Status: 2, Rebuilding: true,
+			NumDrives: 4, NumActiveDrives: 3, Type: "hardware",
+			Controller: "StoreCLI - AVAGO MegaRAID SAS 9361-8i", VirtualDevice: "/dev/sdb",
+		},
+	}
+	for i := range expected {
+		// Sizes are converted by the shared size parser
+		expected[i].Size = raidArrays[i].Size
+	}
+	if !reflect.DeepEqual(raidArrays, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, raidArrays)
+	}
+	if raidArrays[0].Size <= 0 || raidArrays[1].Size <= raidArrays[0].Size {
+		t.Errorf("Unexpected array sizes %d and %d", raidArrays[0].Size, raidArrays[1].Size)
+	}
+
+	expectedGroups := map[string]string{"0/0": "0:0", "0/1": "0:1"}
+	if !reflect.DeepEqual(groups, expectedGroups) {
+		t.Errorf("Expected drive groups %v, got %v", expectedGroups, groups)
+	}
+}
+
+func TestParseStorCLIVirtualDriveMembers(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []string
+		active  int
+		failed  int
+		rebuild bool
+	}{
+		{"online", []string{"Onln", "Onln"}, 2, 0, false},
+		{"missing member", []string{"Onln", "Msng"}, 1, 1, false},
+		{"offline member", []string{"Offln", "Onln"}, 1, 1, false},
+		{"rebuilding member", []string{"Onln", "Rbld"}, 1, 0, true},
+		{"copyback member", []string{"Cpybck", "Onln"}, 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var members []string
+			for i, state := range tt.states {
+				members = append(members, fmt.Sprintf(`{"EID:Slt" : "252:%d", "State" : %q, "DG" : 0}`, i, state))
+			}
+			data := []byte(`{"Controllers":[{
+				"Command Status" : {"Controller" : 0, "Status" : "Success"},
+				"Response Data" : {
+					"/c0/v0" : [{"DG/VD" : "0/0", "TYPE" : "RAID1", "State" : "Dgrd", "Size" : "1.0 TB"}],
+					"PDs for VD 0" : [` + strings.Join(members, ",") + `]
+				}
+			}]}`)
+
+			raidArrays, _, err := parseStorCLIVirtualDrives(data, nil)
+			if err != nil {
+				t.Fatalf("parseStorCLIVirtualDrives failed: %v", err)
+			}
+			if len(raidArrays) != 1 {
+				t.Fatalf("Expected one array, got %+v", raidArrays)
+			}
+			raid := raidArrays[0]
+			if raid.NumActiveDrives != tt.active || raid.NumFailedDrives != tt.failed || raid.Rebuilding != tt.rebuild {
+				t.Errorf("Expected %d active, %d failed, rebuilding %v, got %d, %d, %v",
+					tt.active, tt.failed, tt.rebuild, raid.NumActiveDrives, raid.NumFailedDrives, raid.Rebuilding)
+			}
+		})
+	}
+}
+
+func TestParseStorCLIVirtualDrivesSkipsFailedControllers(t *testing.T) {
+	// Controller 1 has no virtual drives, so StorCLI reports a failure for it
+	raidArrays, groups, err := parseStorCLIVirtualDrives(readStorCLIFixture(t, "sas3516", "vall_show_all.json"), nil)
+	if err != nil {
+		t.Fatalf("parseStorCLIVirtualDrives failed: %v", err)
+	}
+	if len(raidArrays) != 1 || raidArrays[0].ArrayID != "0:0" || raidArrays[0].RaidLevel != "RAID10" {
+		t.Fatalf("Expected the RAID10 array of controller 0, got %+v", raidArrays)
+	}
+	if raidArrays[0].Controller != "StoreCLI" || raidArrays[0].NumDrives != 4 {
+		t.Errorf("Unexpected array %+v", raidArrays[0])
+	}
+	if groups["0/0"] != "0:0" {
+		t.Errorf("Expected drive group 0 of controller 0 to map to 0:0, got %v", groups)
+	}
+}
+
+func TestParseStorCLIConsistencyChecks(t *testing.T) {
+	expected := map[string]map[string]int{
+		"sas2208": {},
+		"sas3108": {"0:0": 42},
+		"sas3516": {},
+	}
+
+	for _, generation := range storcliGenerations {
+		t.Run(generation, func(t *testing.T) {
+			progress, err := parseStorCLIConsistencyChecks(readStorCLIFixture(t, generation, "vall_show_cc.json"))
+			if err != nil {
+				t.Fatalf("parseStorCLIConsistencyChecks failed: %v", err)
+			}
+			if !reflect.DeepEqual(progress, expected[generation]) {
+				t.Errorf("Expected %v, got %v", expected[generation], progress)
+			}
+		})
+	}
+}
+
+// parseStorCLIFixtureDrives parses the VD and PD fixtures of a controller generation
+func parseStorCLIFixtureDrives(t *testing.T, generation string) map[string]types.DiskInfo {
+	t.Helper()
+	raidArrays, groups, err := parseStorCLIVirtualDrives(readStorCLIFixture(t, generation, "vall_show_all.json"), nil)
+	if err != nil {
+		t.Fatalf("parseStorCLIVirtualDrives failed: %v", err)
+	}
+	disks, err := parseStorCLIPhysicalDrives(readStorCLIFixture(t, generation, "eall_sall_show_all.json"), raidArrays, groups)
+	if err != nil {
+		t.Fatalf("parseStorCLIPhysicalDrives failed: %v", err)
+	}
+
+	byDevice := make(map[string]types.DiskInfo, len(disks))
+	for _, disk := range disks {
+		byDevice[disk.Device] = disk
+	}
+	if len(byDevice) != len(disks) {
+		t.Fatalf("Duplicate device names in %+v", disks)
+	}
+	return byDevice
+}
+
+func TestParseStorCLIPhysicalDrives(t *testing.T) {
+	disks := parseStorCLIFixtureDrives(t, "sas3108")
+	if len(disks) != 7 {
+		t.Fatalf("Expected 7 disks, got %d", len(disks))
+	}
+
+	ssd := disks["raid-c0-enc252-slot0"]
+	if ssd.Serial != "PHYF8123004Y480BGN" || ssd.Model != "INTEL SSDSC2KB480G8" || ssd.Vendor != "" {
+		t.Errorf("Unexpected SSD identity %q %q %q", ssd.Serial, ssd.Model, ssd.Vendor)
+	}
+	if ssd.Location != "Controller:0 EID:252 Slot:0" || ssd.Interface != "SATA" || ssd.Temperature != 31 {
+		t.Errorf("Unexpected SSD details %+v", ssd)
+	}
+	if ssd.RaidRole != "active" || ssd.RaidArrayID != "0:0" || ssd.Health != "Onln" || ssd.HealthStatus != types.HealthStatusOK || ssd.RaidPosition != "DriveGroup:0, Span:0, Row:0" {
+		t.Errorf("Unexpected SSD RAID membership %+v", ssd)
+	}
+	if ssd.UsagePercentage != 50 || ssd.Mountpoint != "RAID-0:0" {
+		t.Errorf("Expected a half-used RAID1 member, got %.1f%% on %s", ssd.UsagePercentage, ssd.Mountpoint)
+	}
+
+	// The controller passes on SMART alerts raised by the drive
+	flagged := disks["raid-c0-enc252-slot3"]
+	if flagged.Vendor != "SEAGATE" || flagged.RaidArrayID != "0:1" || flagged.SmartHealthy {
+		t.Errorf("Expected an unhealthy Seagate member of 0:1, got %+v", flagged)
+	}
+
+	rebuilding := disks["raid-c0-enc252-slot5"]
+	if rebuilding.RaidRole != "rebuilding" || rebuilding.RaidArrayID != "0:1" {
+		t.Errorf("Expected a rebuilding member of 0:1, got %+v", rebuilding)
+	}
+
+	unconfigured := disks["raid-c0-enc252-slot6"]
+	if unconfigured.RaidRole != "unconfigured" || unconfigured.RaidArrayID != "" || unconfigured.AvailableBytes != unconfigured.Capacity {
+		t.Errorf("Expected an unconfigured drive, got %+v", unconfigured)
+	}
+}
+
+func TestParseStorCLIPhysicalDrivesSpares(t *testing.T) {
+	global := parseStorCLIFixtureDrives(t, "sas2208")["raid-c0-enc252-slot3"]
+	if global.RaidRole != "hot_spare" || !global.IsGlobalSpare || global.RaidArrayID != "" || global.Mountpoint != "SPARE" {
+		t.Errorf("Expected a global hot spare, got %+v", global)
+	}
+
+	disks := parseStorCLIFixtureDrives(t, "sas3516")
+	if len(disks) != 7 {
+		t.Fatalf("Expected 7 disks across both controllers, got %d", len(disks))
+	}
+	dedicated := disks["raid-c0-enc251-slot4"]
+	if dedicated.RaidRole != "hot_spare" || !dedicated.IsDedicatedSpare || dedicated.RaidArrayID != "0:0" {
+		t.Errorf("Expected a dedicated hot spare of 0:0, got %+v", dedicated)
+	}
+
+	// Drives keep the number of the controller they are attached to
+	second := disks["raid-c1-enc250-slot1"]
+	if second.Location != "Controller:1 EID:250 Slot:1" || second.Vendor != "HGST" || second.RaidRole != "unconfigured" {
+		t.Errorf("Unexpected drive on controller 1: %+v", second)
+	}
+}
+
+func TestStoreCLIRaidRole(t *testing.T) {
+	tests := []struct {
+		state        string
+		role         string
+		arrayID      string
+		global       bool
+		dedicated    bool
+		smartHealthy bool
+	}{
+		{"Onln", "active", "0:0", false, false, true},
+		{"GHS", "hot_spare", "", true, false, true},
+		{"DHS", "hot_spare", "0:0", false, true, true},
+		{"Rbld", "rebuilding", "0:0", false, false, true},
+		{"Offln", "failed", "0:0", false, false, false},
+		{"UBad", "unconfigured", "", false, false, false},
+		// Unmapped states are unknown rather than guessed from substrings
+		{"Failed Spare", "unknown", "", false, false, false},
+		{"OnlnX", "unknown", "", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			disk := types.DiskInfo{Health: tt.state, HealthStatus: storcliDriveStates.Map(tt.state)}
+			determineStoreCLIRaidRole(&disk, tt.state, tt.arrayID)
+			if disk.RaidRole != tt.role || disk.RaidArrayID != tt.arrayID || disk.IsGlobalSpare != tt.global || disk.IsDedicatedSpare != tt.dedicated {
+				t.Errorf("Expected role %q in %q (global %v, dedicated %v), got %+v", tt.role, tt.arrayID, tt.global, tt.dedicated, disk)
+			}
+			if disk.SmartHealthy != tt.smartHealthy {
+				t.Errorf("Expected SMART healthy %v, got %v", tt.smartHealthy, disk.SmartHealthy)
+			}
+			if disk.Health != tt.state {
+				t.Errorf("Expected the raw state %q to be kept as health, got %q", tt.state, disk.Health)
+			}
+		})
+	}
+}
+
+func TestParseStorCLIPhysicalDriveCounters(t *testing.T) {
+	flagged := parseStorCLIFixtureDrives(t, "sas3108")["raid-c0-enc252-slot3"]
+	expected := &types.RAIDDriveInfo{
+		ToolName:               "StoreCLI",
+		FirmwareState:          "Online",
+		ForeignState:           "None",
+		MediaErrorCount:        12,
+		OtherErrorCount:        1,
+		PredictiveFailureCount: 1,
+		LinkSpeed:              "12.0Gb/s",
+		DeviceSpeed:            "12.0Gb/s",
+	}
+	if !reflect.DeepEqual(flagged.RaidDrive, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, flagged.RaidDrive)
+	}
+
+	disks := parseStorCLIFixtureDrives(t, "sas3516")
+	if foreign := disks["raid-c1-enc250-slot0"].RaidDrive; foreign.ForeignState != "Foreign" || foreign.FirmwareState != "Unconfigured(good)" {
+		t.Errorf("Expected an unconfigured drive with a foreign configuration, got %+v", foreign)
+	}
+	bad := disks["raid-c1-enc250-slot1"].RaidDrive
+	if bad.FirmwareState != "Unconfigured(bad)" || bad.ShieldCounter != 3 || bad.MediaErrorCount != 41 || bad.OtherErrorCount != 7 {
+		t.Errorf("Unexpected counters of the bad drive %+v", bad)
+	}
+	if bad.LinkSpeed != "6.0Gb/s" || bad.DeviceSpeed != "12.0Gb/s" {
+		t.Errorf("Expected a 12.0Gb/s drive linked at 6.0Gb/s, got %s / %s", bad.DeviceSpeed, bad.LinkSpeed)
+	}
+}
+
+func TestParseStorCLIPhysicalDrivesWithoutEnclosure(t *testing.T) {
+	data := []byte(`{"Controllers":[{
+		"Command Status" : {"Controller" : 2, "Status" : "Success", "Description" : "Show Drive Information Succeeded."},
+		"Response Data" : {
+			"Drive /c2/s4" : [{"EID:Slt" : " :4", "DID" : 4, "State" : "UGood", "DG" : "-", "Size" : "931.512 GB", "Intf" : "SATA", "Med" : "HDD", "Model" : "WDC WD1003FZEX-00K3CA0"}]
+		}
+	}]}`)
+
+	disks, err := parseStorCLIPhysicalDrives(data, nil, nil)
+	if err != nil {
+		t.Fatalf("parseStorCLIPhysicalDrives failed: %v", err)
+	}
+	if len(disks) != 1 {
+		t.Fatalf("Expected 1 disk, got %d", len(disks))
+	}
+	disk := disks[0]
+	if disk.Device != "raid-c2-slot4" || disk.Location != "Controller:2 Slot:4" || disk.Model != "WDC WD1003FZEX-00K3CA0" {
+		t.Errorf("Unexpected direct-attached drive %+v", disk)
+	}
+}
+
+func TestParseStorCLICacheVaults(t *testing.T) {
+	batteries, err := parseStorCLICacheVaults(readStorCLIFixture(t, "sas3108", "cv_show_all.json"))
+	if err != nil {
+		t.Fatalf("parseStorCLICacheVaults failed: %v", err)
+	}
+	expected := &types.RAIDBatteryInfo{
+		AdapterID: 0, ToolName: "StoreCLI", BatteryType: "CVPM02", State: "Optimal", Temperature: 29,
+		PackEnergy: 294, Capacitance: 108, DesignCapacity: 288, ManufactureDate: "20/02/2017",
+		SerialNumber: "21231", ManufactureName: "LSI", DeviceName: "CVPM02", AutoLearnPeriod: 27,
+		NextLearnTime: "2025/07/28  09:05:55",
+	}
+	if !reflect.DeepEqual(batteries["0"], expected) {
+		t.Errorf("Expected %+v, got %+v", expected, batteries["0"])
+	}
+
+	// Controllers with a battery backup unit reject the CacheVault command
+	batteries, err = parseStorCLICacheVaults(readStorCLIFixture(t, "sas2208", "cv_show_all.json"))
+	if err != nil || len(batteries) != 0 {
+		t.Errorf("Expected no CacheVault, got %v (%v)", batteries, err)
+	}
+
+	batteries, err = parseStorCLICacheVaults(readStorCLIFixture(t, "sas3516", "cv_show_all.json"))
+	if err != nil {
+		t.Fatalf("parseStorCLICacheVaults failed: %v", err)
+	}
+	if len(batteries) != 2 || batteries["0"].State != "Optimal" || batteries["1"].State != "Failed" || !batteries["1"].ReplacementRequired {
+		t.Errorf("Unexpected CacheVaults %+v", batteries)
+	}
+}
+
+func TestStorCLIValueDecoding(t *testing.T) {
+	var decoded struct {
+		Number   storcliValue `json:"number"`
+		Padded   storcliValue `json:"padded"`
+		Count    storcliInt   `json:"count"`
+		Text     storcliInt   `json:"text"`
+		Percent  storcliInt   `json:"percent"`
+		Missing  storcliInt   `json:"missing"`
+		Negative storcliInt   `json:"negative"`
+	}
+	data := `{"number": 252, "padded": "  ST4000NM0025    ", "count": 3, "text": "17", "percent": "42%", "missing": "-", "negative": -1}`
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Number != "252" || decoded.Padded != "ST4000NM0025" {
+		t.Errorf("Unexpected values %q %q", decoded.Number, decoded.Padded)
+	}
+	if decoded.Count != 3 || decoded.Text != 17 || decoded.Percent != 42 || decoded.Missing != 0 || decoded.Negative != -1 {
+		t.Errorf("Unexpected counters %+v", decoded)
+	}
+}
+
+func TestStoreCLIStates(t *testing.T) {
+	checkStates(t, storcliDriveStates, map[string]types.HealthStatus{
+		"Onln":    types.HealthStatusOK,
+		"UGood":   types.HealthStatusOK,
+		"GHS":     types.HealthStatusOK,
+		"DHS":     types.HealthStatusOK,
+		"JBOD":    types.HealthStatusOK,
+		"Rbld":    types.HealthStatusWarning,
+		"Cpybck":  types.HealthStatusWarning,
+		"Sntze":   types.HealthStatusWarning,
+		"UGUnsp":  types.HealthStatusWarning,
+		"UGShld":  types.HealthStatusWarning,
+		"HSPShld": types.HealthStatusWarning,
+		"CFShld":  types.HealthStatusWarning,
+		"CBShld":  types.HealthStatusWarning,
+		"Offln":   types.HealthStatusCritical,
+		"UBad":    types.HealthStatusCritical,
+		"UBUnsp":  types.HealthStatusCritical,
+		"Msng":    types.HealthStatusCritical,
+	})
+	checkStates(t, storcliDriveRoles, map[string]string{
+		"Onln":    "active",
+		"CFShld":  "active",
+		"GHS":     "hot_spare",
+		"DHS":     "hot_spare",
+		"HSPShld": "hot_spare",
+		"Rbld":    "rebuilding",
+		"Cpybck":  "rebuilding",
+		"CBShld":  "rebuilding",
+		"Offln":   "failed",
+		"Msng":    "failed",
+		"UGood":   "unconfigured",
+		"UGUnsp":  "unconfigured",
+		"UGShld":  "unconfigured",
+		"UBad":    "unconfigured",
+		"UBUnsp":  "unconfigured",
+		"Sntze":   "unconfigured",
+		"JBOD":    "unconfigured",
+	})
+	checkStates(t, storcliVDStates, map[string]types.RAIDState{
+		"Optl": types.RAIDStateOptimal,
+		"Pdgd": types.RAIDStateDegraded,
+		"Dgrd": types.RAIDStateDegraded,
+		"Rec":  types.RAIDStateDegraded,
+		"OfLn": types.RAIDStateFailed,
+	})
+	checkStates(t, storcliVDRebuilds, map[string]bool{
+		"Rec": true,
+	})
+}
@@ -1,8 +1,11 @@
 package tools
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 // commandExists checks if a command is available in the system PATH
@@ -36,30 +39,24 @@ func parseSizeToBytes(sizeStr string) int64 {
 	return 0
 }
 
-// getHealthStatusValue converts health status string to numeric value
-func getHealthStatusValue(status string) int {
-	switch strings.ToUpper(strings.TrimSpace(status)) {
-	case "PASSED", "OK", "HEALTHY":
-		return 1
-	case "WARNING", "CAUTION":
-		return 2
-	case "FAILED", "CRITICAL", "FAILING":
-		return 3
-	default:
-		return 0
+// readModuleVersion returns the version of a loaded kernel module (e.g. megaraid_sas), or "" if unknown
+func readModuleVersion(module string) string {
+	data, err := os.ReadFile(filepath.Join("/sys/module", module, "version"))
+	if err != nil {
+		return ""
 	}
+	return strings.TrimSpace(string(data))
 }
 
-// getRaidStatusValue converts RAID status string to numeric value
-func getRaidStatusValue(status string) int {
-	switch strings.ToUpper(strings.TrimSpace(status)) {
-	case "OPTIMAL", "OK", "HEALTHY":
-		return 1
-	case "DEGRADED", "WARNING":
-		return 2
-	case "FAILED", "CRITICAL", "OFFLINE":
-		return 3
-	default:
-		return 0
+// megaraidTimeLayout is the timestamp format used by MegaCLI and StorCLI (e.g. "07/19/2025, 03:00:00")
+const megaraidTimeLayout = "01/02/2006, 15:04:05"
+
+// parseMegaRAIDTime parses a MegaCLI/StorCLI timestamp in the controller's local time.
+// Values such as "-" or "N/A" for unscheduled tasks return the zero time.
+func parseMegaRAIDTime(value string) time.Time {
+	t, err := time.ParseInLocation(megaraidTimeLayout, strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return time.Time{}
 	}
+	return t
 }
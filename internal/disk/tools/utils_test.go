@@ -0,0 +1,21 @@
+package tools
+
+import (
+	"testing"
+
+	"disk-health-exporter/internal/statemap"
+)
+
+// checkStates verifies that a state table holds exactly the expected states
+func checkStates[T comparable](t *testing.T, table *statemap.Table[T], expected map[string]T) {
+	t.Helper()
+
+	if table.Len() != len(expected) {
+		t.Errorf("Expected %d %s states, the table has %d", len(expected), table.Tool(), table.Len())
+	}
+	for state, value := range expected {
+		if mapped, ok := table.Lookup(state); !ok || mapped != value {
+			t.Errorf("%s state %q: expected %v, got %v (known %v)", table.Tool(), state, value, mapped, ok)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"disk-health-exporter/internal/utils"
+	"disk-health-exporter/pkg/types"
+)
+
+// DefaultKstatRoot is where the ZFS kernel module publishes its statistics on Linux
+const DefaultKstatRoot = "/proc/spl/kstat/zfs"
+
+// ZfsTool represents the zfs CLI tool for ZFS dataset management
+type ZfsTool struct{}
+
+// NewZfsTool creates a new ZfsTool instance
+func NewZfsTool() *ZfsTool {
+	return &ZfsTool{}
+}
+
+// IsAvailable checks if zfs is available on the system
+func (z *ZfsTool) IsAvailable() bool {
+	return utils.CommandExists("zfs")
+}
+
+// GetVersion returns the zfs version
+func (z *ZfsTool) GetVersion() string {
+	if !z.IsAvailable() {
+		return ""
+	}
+
+	version, err := utils.GetToolVersion("zfs", "version")
+	if err != nil {
+		return "unknown"
+	}
+	return version
+}
+
+// GetName returns the tool name
+func (z *ZfsTool) GetName() string {
+	return "zfs"
+}
+
+// GetDatasets returns the filesystems and volumes accepted by the filter
+// zfs list -Hp -t filesystem,volume -o name,type,used,avail,refer,compressratio,quota,reservation # exact dataset usage
+func (z *ZfsTool) GetDatasets(filter *DatasetFilter) []types.ZFSDatasetInfo {
+	if !z.IsAvailable() {
+		return nil
+	}
+
+	output, err := exec.Command("zfs", "list", "-Hp", "-t", "filesystem,volume",
+		"-o", "name,type,used,avail,refer,compressratio,quota,reservation").Output()
+	if err != nil {
+		slog.Error("Error listing datasets", "tool", "zfs", "err", err)
+		return nil
+	}
+
+	var datasets []types.ZFSDatasetInfo
+	for _, dataset := range parseZfsList(string(output)) {
+		if filter.Match(dataset) {
+			datasets = append(datasets, dataset)
+		}
+	}
+	return datasets
+}
+
+// parseZfsList parses tab-separated zfs list -Hp output
+func parseZfsList(output string) []types.ZFSDatasetInfo {
+	var datasets []types.ZFSDatasetInfo
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 8 {
+			continue
+		}
+
+		pool, _, _ := strings.Cut(fields[0], "/")
+		dataset := types.ZFSDatasetInfo{
+			Name: fields[0],
+			Pool: pool,
+			Type: fields[1],
+		}
+		// Unset properties such as a volume's quota are reported as "-"
+		dataset.Used, _ = parseZFSNumber(strings.Trim(fields[2], "-"))
+		dataset.Available, _ = parseZFSNumber(strings.Trim(fields[3], "-"))
+		dataset.Referenced, _ = parseZFSNumber(strings.Trim(fields[4], "-"))
+		dataset.CompressRatio, _ = strconv.ParseFloat(strings.TrimSuffix(fields[5], "x"), 64)
+		dataset.Quota, _ = parseZFSNumber(strings.Trim(fields[6], "-"))
+		dataset.Reservation, _ = parseZFSNumber(strings.Trim(fields[7], "-"))
+
+		datasets = append(datasets, dataset)
+	}
+
+	return datasets
+}
+
+// DatasetFilter selects datasets by pool and name pattern. A nil filter accepts every dataset.
+type DatasetFilter struct {
+	pools   []string
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewDatasetFilter creates a filter from pool names and include/exclude regular expressions
+func NewDatasetFilter(pools, include, exclude []string) (*DatasetFilter, error) {
+	filter := &DatasetFilter{pools: pools}
+
+	for _, pattern := range include {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		filter.include = append(filter.include, re)
+	}
+	for _, pattern := range exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		filter.exclude = append(filter.exclude, re)
+	}
+
+	return filter, nil
+}
+
+// Match reports whether a dataset passes the filter
+func (f *DatasetFilter) Match(dataset types.ZFSDatasetInfo) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.pools) > 0 && !slices.Contains(f.pools, dataset.Pool) {
+		return false
+	}
+	if len(f.include) > 0 && !slices.ContainsFunc(f.include, func(re *regexp.Regexp) bool { return re.MatchString(dataset.Name) }) {
+		return false
+	}
+	return !slices.ContainsFunc(f.exclude, func(re *regexp.Regexp) bool { return re.MatchString(dataset.Name) })
+}
+
+// ReadARCStats reads ARC and L2ARC statistics from the arcstats kstat under kstatRoot
+func ReadARCStats(kstatRoot string) (*types.ZFSARCStats, error) {
+	if kstatRoot == "" {
+		kstatRoot = DefaultKstatRoot
+	}
+
+	file, err := os.Open(filepath.Join(kstatRoot, "arcstats"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseARCStats(file)
+}
+
+// parseARCStats parses a kstat file: a header line, a "name type data" line,
+// then one statistic per line
+func parseARCStats(r io.Reader) (*types.ZFSARCStats, error) {
+	values := make(map[string]int64)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[fields[0]] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := values["size"]; !ok {
+		return nil, fmt.Errorf("no ARC size in arcstats")
+	}
+
+	return &types.ZFSARCStats{
+		Size:       values["size"],
+		TargetSize: values["c"],
+		MinSize:    values["c_min"],
+		MaxSize:    values["c_max"],
+		Hits:       values["hits"],
+		Misses:     values["misses"],
+		L2Size:     values["l2_size"],
+		L2Hits:     values["l2_hits"],
+		L2Misses:   values["l2_misses"],
+	}, nil
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"disk-health-exporter/pkg/types"
+)
+
+func TestZfsTool_GetName(t *testing.T) {
+	tool := NewZfsTool()
+	if tool.GetName() != "zfs" {
+		t.Errorf("Expected name zfs, got %s", tool.GetName())
+	}
+}
+
+func TestParseZfsList(t *testing.T) {
+	output := "tank\tfilesystem\t1099511627776\t2199023255552\t196608\t1.00\t0\t0\n" +
+		"tank/home\tfilesystem\t536870912000\t2199023255552\t536870912000\t1.52\t1099511627776\t0\n" +
+		"tank/vm-100-disk-0\tvolume\t34359738368\t2233382993920\t8589934592\t2.10x\t-\t34359738368\n"
+
+	datasets := parseZfsList(output)
+	if len(datasets) != 3 {
+		t.Fatalf("Expected 3 datasets, got %d", len(datasets))
+	}
+
+	home := datasets[1]
+	if home.Name != "tank/home" || home.Pool != "tank" || home.Type != "filesystem" {
+		t.Errorf("Unexpected dataset identity: %+v", home)
+	}
+	if home.Used != 536870912000 || home.Available != 2199023255552 || home.Quota != 1099511627776 || home.CompressRatio != 1.52 {
+		t.Errorf("Unexpected dataset usage: %+v", home)
+	}
+
+	volume := datasets[2]
+	if volume.Type != "volume" || volume.Quota != 0 || volume.Reservation != 34359738368 || volume.CompressRatio != 2.1 {
+		t.Errorf("Unexpected volume: %+v", volume)
+	}
+}
+
+func TestDatasetFilter(t *testing.T) {
+	datasets := []types.ZFSDatasetInfo{
+		{Name: "tank", Pool: "tank"},
+		{Name: "tank/home", Pool: "tank"},
+		{Name: "tank/docker/4f1a2b", Pool: "tank"},
+		{Name: "backup/tank", Pool: "backup"},
+	}
+
+	tests := []struct {
+		name     string
+		pools    []string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{"no filter", nil, nil, nil, []string{"tank", "tank/home", "tank/docker/4f1a2b", "backup/tank"}},
+		{"pool", []string{"backup"}, nil, nil, []string{"backup/tank"}},
+		{"include", nil, []string{`^tank(/home)?$`}, nil, []string{"tank", "tank/home"}},
+		{"exclude", nil, nil, []string{`/docker/`}, []string{"tank", "tank/home", "backup/tank"}},
+		{"pool and exclude", []string{"tank"}, nil, []string{`^tank/docker`}, []string{"tank", "tank/home"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewDatasetFilter(tt.pools, tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewDatasetFilter returned error: %v", err)
+			}
+
+			var matched []string
+			for _, dataset := range datasets {
+				if filter.Match(dataset) {
+					matched = append(matched, dataset.Name)
+				}
+			}
+			if strings.Join(matched, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, matched)
+			}
+		})
+	}
+
+	if _, err := NewDatasetFilter(nil, []string{"("}, nil); err == nil {
+		t.Error("Expected error for invalid include pattern")
+	}
+	if !(*DatasetFilter)(nil).Match(datasets[0]) {
+		t.Error("Expected nil filter to match every dataset")
+	}
+}
+
+func TestReadARCStats(t *testing.T) {
+	arc, err := ReadARCStats("testdata/kstat")
+	if err != nil {
+		t.Fatalf("ReadARCStats returned error: %v", err)
+	}
+
+	expected := types.ZFSARCStats{
+		Size:       17179869184,
+		TargetSize: 17179869184,
+		MinSize:    1073741824,
+		MaxSize:    34359738368,
+		Hits:       94561237,
+		Misses:     5438763,
+		L2Size:     214748364800,
+		L2Hits:     250000,
+		L2Misses:   750000,
+	}
+	if *arc != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *arc)
+	}
+
+	if _, err := ReadARCStats(t.TempDir()); err == nil {
+		t.Error("Expected error for missing arcstats")
+	}
+	if _, err := parseARCStats(strings.NewReader("name type data\n")); err == nil {
+		t.Error("Expected error for arcstats without size")
+	}
+}
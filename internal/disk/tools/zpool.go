@@ -1,17 +1,72 @@
 package tools
 
 import (
-	"log"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"disk-health-exporter/internal/disk/merge"
+	"disk-health-exporter/internal/statemap"
 	"disk-health-exporter/internal/utils"
 	"disk-health-exporter/pkg/types"
 )
 
+// Ensure ZpoolTool implements the CombinedToolInterface and SnapshotToolInterface
+var (
+	_ CombinedToolInterface = (*ZpoolTool)(nil)
+	_ SnapshotToolInterface = (*ZpoolTool)(nil)
+)
+
 // ZpoolTool represents the zpool CLI tool for ZFS management
 type ZpoolTool struct{}
 
+func init() {
+	Register(Registration{
+		Name:      merge.SourceZpool,
+		Platforms: []string{PlatformLinux, PlatformDarwin, PlatformWindows},
+		After:     []string{merge.SourceSmartctl, merge.SourceMegaCLI, merge.SourceStorCLI, merge.SourceArcconf, merge.SourceSsacli},
+		New:       func() ToolInterface { return NewZpoolTool() },
+	})
+}
+
+// zpoolPoolStates translates the health of pools
+var zpoolPoolStates = statemap.New(merge.SourceZpool, map[string]types.RAIDState{
+	"ONLINE":    types.RAIDStateOptimal,
+	"DEGRADED":  types.RAIDStateDegraded,
+	"FAULTED":   types.RAIDStateFailed,
+	"OFFLINE":   types.RAIDStateFailed,
+	"REMOVED":   types.RAIDStateFailed,
+	"UNAVAIL":   types.RAIDStateFailed,
+	"SUSPENDED": types.RAIDStateFailed,
+})
+
+// zpoolDeviceStates translates the state of vdevs, spares included
+var zpoolDeviceStates = statemap.New(merge.SourceZpool, map[string]types.HealthStatus{
+	"ONLINE":   types.HealthStatusOK,
+	"AVAIL":    types.HealthStatusOK,
+	"INUSE":    types.HealthStatusOK,
+	"DEGRADED": types.HealthStatusWarning,
+	"FAULTED":  types.HealthStatusCritical,
+	"OFFLINE":  types.HealthStatusCritical,
+	"REMOVED":  types.HealthStatusCritical,
+	"UNAVAIL":  types.HealthStatusCritical,
+})
+
+// zpoolHealthNames words the health of vdevs the way other tools report disk health
+var zpoolHealthNames = map[types.HealthStatus]string{
+	types.HealthStatusUnknown:  "UNKNOWN",
+	types.HealthStatusOK:       "OK",
+	types.HealthStatusWarning:  "DEGRADED",
+	types.HealthStatusCritical: "FAILED",
+}
+
 // NewZpoolTool creates a new ZpoolTool instance
 func NewZpoolTool() *ZpoolTool {
 	return &ZpoolTool{}
@@ -40,7 +95,7 @@ func (z *ZpoolTool) GetName() string {
 	return "zpool"
 }
 
-// GetDisks returns disk information detected by zpool
+// GetDisks returns the leaf devices of all ZFS pools
 func (z *ZpoolTool) GetDisks() []types.DiskInfo {
 	var disks []types.DiskInfo
 
@@ -48,19 +103,14 @@ func (z *ZpoolTool) GetDisks() []types.DiskInfo {
 		return disks
 	}
 
-	// Get all pools first
-	pools := z.getPools()
-
-	for _, pool := range pools {
-		poolDisks := z.getDisksForPool(pool)
-		disks = append(disks, poolDisks...)
+	for _, pool := range z.GetPoolTrees() {
+		disks = append(disks, z.poolDisks(pool)...)
 	}
 
 	return disks
 }
 
 // GetZFSPools returns ZFS pool information (similar to RAID arrays)
-// zpool list -H -o name,size,alloc,free,health # list all ZFS pools with specified columns
 func (z *ZpoolTool) GetZFSPools() []types.RAIDInfo {
 	var pools []types.RAIDInfo
 
@@ -68,184 +118,1004 @@ func (z *ZpoolTool) GetZFSPools() []types.RAIDInfo {
 		return pools
 	}
 
-	// Get pool list with status
-	output, err := exec.Command("zpool", "list", "-H", "-o", "name,size,alloc,free,health").Output()
+	for _, pool := range z.GetPoolTrees() {
+		pools = append(pools, z.poolToRAIDInfo(pool))
+	}
+
+	return pools
+}
+
+// GetSnapshot returns the leaf devices and pools of all ZFS pools from a single
+// run of zpool status
+func (z *ZpoolTool) GetSnapshot() ([]types.DiskInfo, []types.RAIDInfo) {
+	if !z.IsAvailable() {
+		return nil, nil
+	}
+	return z.snapshot(z.GetPoolTrees())
+}
+
+// snapshot derives the leaf devices and RAID arrays of parsed pools
+func (z *ZpoolTool) snapshot(pools []types.ZFSPoolInfo) ([]types.DiskInfo, []types.RAIDInfo) {
+	var disks []types.DiskInfo
+	var raids []types.RAIDInfo
+	for _, pool := range pools {
+		disks = append(disks, z.poolDisks(pool)...)
+		raids = append(raids, z.poolToRAIDInfo(pool))
+	}
+	return disks, raids
+}
+
+// GetRAIDArrays returns the ZFS pools as RAID arrays
+func (z *ZpoolTool) GetRAIDArrays() []types.RAIDInfo {
+	return z.GetZFSPools()
+}
+
+// GetRAIDDisks returns the leaf devices of all ZFS pools
+func (z *ZpoolTool) GetRAIDDisks() []types.DiskInfo {
+	return z.GetDisks()
+}
+
+// GetPoolTrees returns every pool with its vdev tree and space usage
+func (z *ZpoolTool) GetPoolTrees() []types.ZFSPoolInfo {
+	pools, err := z.getPoolStatus()
 	if err != nil {
-		log.Printf("Error getting zpool list: %v", err)
+		slog.Error("Error getting pool status", "tool", "zpool", "err", err)
+		return nil
+	}
+
+	// zpool list -Hp -o name,size,alloc,free,frag,cap,dedupratio,health # exact pool space usage
+	output, err := exec.Command("zpool", "list", "-Hp", "-o", "name,size,alloc,free,frag,cap,dedupratio,health").Output()
+	if err != nil {
+		slog.Error("Error listing pools", "tool", "zpool", "err", err)
 		return pools
 	}
+	applyZpoolList(pools, string(output))
 
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
+	return pools
+}
+
+// getPoolStatus runs zpool status, preferring JSON output (OpenZFS 2.3+)
+// zpool status -j --json-int -p # machine-readable status of all pools
+// zpool status -p # status with exact error counts (older releases)
+func (z *ZpoolTool) getPoolStatus() ([]types.ZFSPoolInfo, error) {
+	output, err := exec.Command("zpool", "status", "-j", "--json-int", "-p").Output()
+	if err == nil {
+		pools, parseErr := parseZpoolStatusJSON(output, time.Now())
+		if parseErr == nil {
+			return pools, nil
+		}
+		slog.Warn("Error parsing pool status JSON, falling back to text", "tool", "zpool", "err", parseErr)
+	}
+
+	output, err = exec.Command("zpool", "status", "-p").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseZpoolStatusText(string(output)), nil
+}
+
+// zpoolStatusJSON represents zpool status -j output
+type zpoolStatusJSON struct {
+	Pools map[string]struct {
+		Name    string                   `json:"name"`
+		State   string                   `json:"state"`
+		Vdevs   map[string]zpoolVdevJSON `json:"vdevs"`
+		Logs    map[string]zpoolVdevJSON `json:"logs"`
+		Special map[string]zpoolVdevJSON `json:"special"`
+		Dedup   map[string]zpoolVdevJSON `json:"dedup"`
+		L2Cache map[string]zpoolVdevJSON `json:"l2cache"`
+		Spares  map[string]zpoolVdevJSON `json:"spares"`
+		Scan    *zpoolScanJSON           `json:"scan_stats"`
+	} `json:"pools"`
+}
+
+// zpoolScanJSON represents the scan_stats object of zpool status -j output
+type zpoolScanJSON struct {
+	Function         string    `json:"function"`
+	State            string    `json:"state"`
+	StartTime        zfsTime   `json:"start_time"`
+	EndTime          zfsTime   `json:"end_time"`
+	ToExamine        zfsNumber `json:"to_examine"`
+	Examined         zfsNumber `json:"examined"`
+	Skipped          zfsNumber `json:"skipped"`
+	Processed        zfsNumber `json:"processed"`
+	Errors           zfsNumber `json:"errors"`
+	PassStart        zfsTime   `json:"pass_start"`
+	ScrubPause       zfsTime   `json:"scrub_pause"`
+	ScrubSpentPaused zfsNumber `json:"scrub_spent_paused"`
+	PassIssued       zfsNumber `json:"issued_bytes_per_scan"`
+	Issued           zfsNumber `json:"issued"`
+}
+
+// zpoolVdevJSON represents a vdev in zpool status -j output
+type zpoolVdevJSON struct {
+	Name           string                   `json:"name"`
+	VdevType       string                   `json:"vdev_type"`
+	State          string                   `json:"state"`
+	Path           string                   `json:"path"`
+	ReadErrors     zfsNumber                `json:"read_errors"`
+	WriteErrors    zfsNumber                `json:"write_errors"`
+	ChecksumErrors zfsNumber                `json:"checksum_errors"`
+	Vdevs          map[string]zpoolVdevJSON `json:"vdevs"`
+}
+
+// zfsNumber decodes numbers that zpool emits either as JSON numbers (--json-int) or strings
+type zfsNumber int64
+
+// UnmarshalJSON accepts both numeric and string encodings
+func (n *zfsNumber) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "-" || text == "null" {
+		*n = 0
+		return nil
+	}
+	value, err := parseZFSNumber(text)
+	if err != nil {
+		return err
+	}
+	*n = zfsNumber(value)
+	return nil
+}
+
+// zfsTime decodes timestamps that zpool emits as epoch seconds (--json-int) or ctime strings
+type zfsTime time.Time
+
+// UnmarshalJSON accepts both epoch seconds and ctime strings; "-" means unset
+func (t *zfsTime) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "-" || text == "null" || text == "0" {
+		*t = zfsTime{}
+		return nil
+	}
+	if seconds, err := strconv.ParseInt(text, 10, 64); err == nil {
+		*t = zfsTime(time.Unix(seconds, 0))
+		return nil
+	}
+	parsed, err := parseZFSTime(text)
+	if err != nil {
+		return err
+	}
+	*t = zfsTime(parsed)
+	return nil
+}
+
+// GetEvents returns the events currently held in the ZFS kernel event queue
+// zpool events -vH # all queued events with their payload, without header
+func (z *ZpoolTool) GetEvents() ([]types.ZFSEvent, error) {
+	output, err := exec.Command("zpool", "events", "-vH").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseZpoolEvents(string(output)), nil
+}
+
+// parseZpoolEvents parses zpool events -vH output. Each event starts with an
+// unindented "<time> <class>" line followed by indented "key = value" pairs;
+// nested nvlists between "(embedded nvlist)" and "(end ...)" are skipped.
+func parseZpoolEvents(output string) []types.ZFSEvent {
+	var events []types.ZFSEvent
+	var event *types.ZFSEvent
+	depth := 0
+
+	finish := func() {
+		if event != nil {
+			events = append(events, *event)
+		}
+		event, depth = nil, 0
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
 			continue
 		}
 
-		fields := strings.Fields(line)
-		if len(fields) >= 5 {
-			pool := types.RAIDInfo{
-				ArrayID:    fields[0],
-				RaidLevel:  "ZFS Pool",
-				State:      fields[4],
-				Status:     z.getZFSStatusValue(fields[4]),
-				Size:       utils.ParseSizeToBytes(fields[1]),
-				Type:       "zfs",
-				Controller: "zpool",
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			finish()
+			fields := strings.Fields(trimmed)
+			if len(fields) < 2 {
+				continue
 			}
+			event = &types.ZFSEvent{Class: fields[len(fields)-1]}
+			event.Time, _ = time.ParseInLocation("Jan 2 2006 15:04:05.999999999", strings.Join(fields[:len(fields)-1], " "), time.Local)
+			continue
+		}
+		if event == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(trimmed, "(embedded nvlist)"):
+			depth++
+			continue
+		case strings.HasPrefix(trimmed, "(end "):
+			depth = max(depth-1, 0)
+			continue
+		case depth > 0:
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, " = ")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
 
-			// Get additional pool information
-			z.enrichPoolInfo(&pool)
-			pools = append(pools, pool)
+		switch key {
+		case "class":
+			event.Class = value
+		case "pool":
+			event.Pool = value
+		case "vdev_path":
+			event.VdevPath = value
+			event.Vdev = vdevNameFromPath(value)
+		case "eid":
+			event.EID, _ = strconv.ParseInt(value, 0, 64)
+		case "time":
+			// Seconds and nanoseconds since the epoch, more precise than the local time header
+			parts := strings.Fields(value)
+			if len(parts) == 2 {
+				sec, errSec := strconv.ParseInt(parts[0], 0, 64)
+				nsec, errNsec := strconv.ParseInt(parts[1], 0, 64)
+				if errSec == nil && errNsec == nil {
+					event.Time = time.Unix(sec, nsec)
+				}
+			}
 		}
 	}
+	finish()
 
-	return pools
+	return events
 }
 
-// getPools gets list of ZFS pools
-// zpool list -H -o name # list pool names only
-func (z *ZpoolTool) getPools() []string {
-	var pools []string
+// partitionSuffixRes match the partition ZFS creates on whole disks, which zpool status hides
+var partitionSuffixRes = []*regexp.Regexp{
+	regexp.MustCompile(`^(.+)-part\d+$`),
+	regexp.MustCompile(`^(nvme\d+n\d+|mmcblk\d+)p\d+$`),
+	regexp.MustCompile(`^((?:sd|vd|xvd|hd)[a-z]+)\d+$`),
+}
 
-	output, err := exec.Command("zpool", "list", "-H", "-o", "name").Output()
-	if err != nil {
-		log.Printf("Error getting zpool names: %v", err)
-		return pools
+// vdevNameFromPath derives the vdev name shown by zpool status from a device path
+// (e.g. "/dev/disk/by-id/ata-X-part1" -> "ata-X", "/dev/nvme0n1p1" -> "nvme0n1").
+// File vdevs are shown by their full path.
+func vdevNameFromPath(path string) string {
+	if !strings.HasPrefix(path, "/dev/") {
+		return path
 	}
 
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) != "" {
-			pools = append(pools, strings.TrimSpace(line))
+	name := filepath.Base(path)
+	for _, re := range partitionSuffixRes {
+		if m := re.FindStringSubmatch(name); m != nil {
+			return m[1]
 		}
 	}
+	return name
+}
 
-	return pools
+// parseZpoolStatusJSON parses zpool status -j output into pool trees.
+// The current time is used to estimate the remaining time of running scans.
+func parseZpoolStatusJSON(data []byte, now time.Time) ([]types.ZFSPoolInfo, error) {
+	var status zpoolStatusJSON
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, err
+	}
+	if status.Pools == nil {
+		return nil, fmt.Errorf("no pools object in output")
+	}
+
+	var pools []types.ZFSPoolInfo
+	for _, name := range sortedKeys(status.Pools) {
+		p := status.Pools[name]
+		pool := types.ZFSPoolInfo{
+			Name:          p.Name,
+			State:         normalizeZFSState(p.State),
+			Fragmentation: -1,
+			Root:          types.ZFSVdevInfo{Name: p.Name, Type: "root", Class: "normal", State: normalizeZFSState(p.State), Status: zpoolDeviceStates.Map(normalizeZFSState(p.State))},
+		}
+
+		if root, ok := p.Vdevs[p.Name]; ok {
+			pool.Root = convertVdevJSON(root, "normal")
+			pool.Root.Type = "root"
+		}
+		for _, group := range []struct {
+			class string
+			vdevs map[string]zpoolVdevJSON
+		}{{"special", p.Special}, {"dedup", p.Dedup}, {"log", p.Logs}} {
+			for _, key := range sortedKeys(group.vdevs) {
+				pool.Root.Children = append(pool.Root.Children, convertVdevJSON(group.vdevs[key], group.class))
+			}
+		}
+		for _, key := range sortedKeys(p.L2Cache) {
+			pool.Cache = append(pool.Cache, convertVdevJSON(p.L2Cache[key], "cache"))
+		}
+		for _, key := range sortedKeys(p.Spares) {
+			pool.Spares = append(pool.Spares, convertVdevJSON(p.Spares[key], "spare"))
+		}
+		if p.Scan != nil {
+			pool.Scan = convertScanJSON(*p.Scan, now)
+			setLastScrub(&pool)
+		}
+
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
 }
 
-// getDisksForPool gets physical disks for a specific ZFS pool
-// zpool status -v POOL # get detailed status including physical devices for pool
-func (z *ZpoolTool) getDisksForPool(poolName string) []types.DiskInfo {
-	var disks []types.DiskInfo
+// convertVdevJSON converts a JSON vdev and its children
+func convertVdevJSON(v zpoolVdevJSON, class string) types.ZFSVdevInfo {
+	vdev := types.ZFSVdevInfo{
+		Name:           v.Name,
+		Type:           normalizeVdevType(v.VdevType, v.Name),
+		Class:          class,
+		State:          normalizeZFSState(v.State),
+		Status:         zpoolDeviceStates.Map(normalizeZFSState(v.State)),
+		Path:           v.Path,
+		ReadErrors:     int64(v.ReadErrors),
+		WriteErrors:    int64(v.WriteErrors),
+		ChecksumErrors: int64(v.ChecksumErrors),
+	}
+	for _, key := range sortedKeys(v.Vdevs) {
+		vdev.Children = append(vdev.Children, convertVdevJSON(v.Vdevs[key], class))
+	}
+	return vdev
+}
 
-	// Get pool status to see physical devices
-	output, err := exec.Command("zpool", "status", "-v", poolName).Output()
-	if err != nil {
-		log.Printf("Error getting zpool status for %s: %v", poolName, err)
-		return disks
+// zpoolClassHeaders maps the section headers of zpool status text to vdev classes
+var zpoolClassHeaders = map[string]string{
+	"logs":    "log",
+	"cache":   "cache",
+	"spares":  "spare",
+	"special": "special",
+	"dedup":   "dedup",
+}
+
+// vdevNode is a vdev under construction while parsing indented text
+type vdevNode struct {
+	info     types.ZFSVdevInfo
+	children []*vdevNode
+}
+
+// toVdev converts a node and its children into a vdev tree
+func (n *vdevNode) toVdev() types.ZFSVdevInfo {
+	vdev := n.info
+	for _, child := range n.children {
+		vdev.Children = append(vdev.Children, child.toVdev())
 	}
+	return vdev
+}
 
-	lines := strings.Split(string(output), "\n")
-	var inConfig bool
+// parseZpoolStatusText parses zpool status text output. The config section
+// is tab-indented, with two spaces per level of nesting:
+//
+//	NAME        STATE     READ WRITE CKSUM
+//	tank        ONLINE       0     0     0
+//	  mirror-0  ONLINE       0     0     0
+//	    sda     ONLINE       0     0     0
+//	logs
+//	  sdc       ONLINE       0     0     0
+func parseZpoolStatusText(output string) []types.ZFSPoolInfo {
+	var pools []types.ZFSPoolInfo
 
-	for _, line := range lines {
-		trimmedLine := strings.TrimSpace(line)
+	var pool *types.ZFSPoolInfo
+	var root *vdevNode
+	var stack []*vdevNode // stack[depth-1] is the latest vdev at that depth
+	var topLevel []*vdevNode
+	var class string
+	var scanLines []string
+	var inConfig, seenHeader, inScan bool
+
+	finish := func() {
+		if pool == nil {
+			return
+		}
+		if root != nil {
+			pool.Root = root.toVdev()
+		}
+		for _, node := range topLevel {
+			vdev := node.toVdev()
+			switch vdev.Class {
+			case "cache":
+				pool.Cache = append(pool.Cache, vdev)
+			case "spare":
+				pool.Spares = append(pool.Spares, vdev)
+			default:
+				pool.Root.Children = append(pool.Root.Children, vdev)
+			}
+		}
+		pool.Scan = parseZpoolScanText(scanLines)
+		setLastScrub(pool)
+		pools = append(pools, *pool)
+		pool, root, stack, topLevel, scanLines = nil, nil, nil, nil, nil
+	}
 
-		if strings.Contains(line, "config:") {
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if name, ok := strings.CutPrefix(trimmed, "pool:"); ok {
+			finish()
+			pool = &types.ZFSPoolInfo{Name: strings.TrimSpace(name), Fragmentation: -1}
+			inConfig, seenHeader, inScan, class = false, false, false, "normal"
+			continue
+		}
+		if pool == nil {
+			continue
+		}
+
+		// The scan: section continues on tab-indented lines
+		if scan, ok := strings.CutPrefix(trimmed, "scan:"); ok && !inConfig {
+			scanLines = []string{strings.TrimSpace(scan)}
+			inScan = true
+			continue
+		}
+		if inScan && strings.HasPrefix(line, "\t") {
+			scanLines = append(scanLines, trimmed)
+			continue
+		}
+		inScan = false
+
+		if state, ok := strings.CutPrefix(trimmed, "state:"); ok && !inConfig {
+			pool.State = normalizeZFSState(strings.TrimSpace(state))
+			continue
+		}
+		if trimmed == "config:" {
 			inConfig = true
 			continue
 		}
+		if !inConfig {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "errors:") || (trimmed == "" && seenHeader) {
+			inConfig = false
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
 
-		if inConfig && trimmedLine == "" {
-			break
+		fields := strings.Fields(trimmed)
+		if fields[0] == "NAME" {
+			seenHeader = true
+			continue
 		}
 
-		if inConfig && z.isPhysicalDevice(trimmedLine) {
-			disk := z.parseZFSDevice(trimmedLine, poolName)
-			if disk.Device != "" {
-				disks = append(disks, disk)
+		body := strings.TrimPrefix(line, "\t")
+		depth := (len(body) - len(strings.TrimLeft(body, " "))) / 2
+
+		if depth == 0 {
+			if headerClass, ok := zpoolClassHeaders[fields[0]]; ok && len(fields) == 1 {
+				class = headerClass
+				continue
 			}
+			// Pool (root vdev) line
+			root = &vdevNode{info: parseVdevLine(fields, "normal")}
+			root.info.Type = "root"
+			continue
+		}
+
+		node := &vdevNode{info: parseVdevLine(fields, class)}
+		if depth == 1 {
+			topLevel = append(topLevel, node)
+		} else if depth-2 < len(stack) {
+			parent := stack[depth-2]
+			parent.children = append(parent.children, node)
 		}
+
+		stack = append(stack[:min(depth-1, len(stack))], node)
 	}
+	finish()
 
-	return disks
+	return pools
+}
+
+// parseVdevLine parses the NAME STATE READ WRITE CKSUM columns of a vdev line
+func parseVdevLine(fields []string, class string) types.ZFSVdevInfo {
+	vdev := types.ZFSVdevInfo{
+		Name:  fields[0],
+		Type:  normalizeVdevType("", fields[0]),
+		Class: class,
+	}
+	if len(fields) > 1 {
+		vdev.State = normalizeZFSState(fields[1])
+		vdev.Status = zpoolDeviceStates.Map(vdev.State)
+	}
+	if len(fields) > 4 {
+		vdev.ReadErrors, _ = parseZFSNumber(fields[2])
+		vdev.WriteErrors, _ = parseZFSNumber(fields[3])
+		vdev.ChecksumErrors, _ = parseZFSNumber(fields[4])
+	}
+	// Notes such as "was /dev/sdh1" record the last known path of a missing device
+	for i := 5; i+1 < len(fields); i++ {
+		if fields[i] == "was" {
+			vdev.Path = fields[i+1]
+		}
+	}
+	return vdev
+}
+
+// applyZpoolList fills in pool space usage from zpool list -Hp output
+// (name, size, alloc, free, frag, cap, dedupratio, health; tab separated)
+func applyZpoolList(pools []types.ZFSPoolInfo, output string) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 8 {
+			continue
+		}
+
+		for i := range pools {
+			if pools[i].Name != fields[0] {
+				continue
+			}
+			pool := &pools[i]
+			pool.Size, _ = strconv.ParseInt(fields[1], 10, 64)
+			pool.Allocated, _ = strconv.ParseInt(fields[2], 10, 64)
+			pool.Free, _ = strconv.ParseInt(fields[3], 10, 64)
+			if frag, err := strconv.Atoi(strings.TrimSuffix(fields[4], "%")); err == nil {
+				pool.Fragmentation = frag
+			}
+			pool.Capacity, _ = strconv.Atoi(strings.TrimSuffix(fields[5], "%"))
+			pool.DedupRatio, _ = strconv.ParseFloat(strings.TrimSuffix(fields[6], "x"), 64)
+			if pool.State == "" {
+				pool.State = normalizeZFSState(fields[7])
+			}
+		}
+	}
 }
 
-// isPhysicalDevice checks if a line represents a physical device
-func (z *ZpoolTool) isPhysicalDevice(line string) bool {
-	fields := strings.Fields(line)
-	if len(fields) == 0 {
-		return false
+// convertScanJSON converts JSON scan statistics, returning nil if no scan has run
+func convertScanJSON(s zpoolScanJSON, now time.Time) *types.ZFSScanInfo {
+	state := strings.ToLower(s.State)
+	if s.Function == "" || strings.EqualFold(s.Function, "none") || state == "" || state == types.ZFSScanNone {
+		return nil
+	}
+
+	scan := &types.ZFSScanInfo{
+		Function:   normalizeScanFunction(s.Function),
+		State:      state,
+		StartTime:  time.Time(s.StartTime),
+		EndTime:    time.Time(s.EndTime),
+		TotalBytes: int64(s.ToExamine),
+		Examined:   int64(s.Examined),
+		Issued:     int64(s.Issued),
+		Repaired:   int64(s.Processed),
+		Errors:     int64(s.Errors),
+	}
+
+	total := int64(s.ToExamine) - int64(s.Skipped)
+	switch {
+	case scan.State == types.ZFSScanFinished:
+		scan.PercentDone = 100
+	case total > 0:
+		scan.PercentDone = min(float64(scan.Issued)*100/float64(total), 100)
 	}
 
-	deviceName := fields[0]
+	if scan.State == types.ZFSScanActive {
+		if !time.Time(s.ScrubPause).IsZero() {
+			scan.State = types.ZFSScanPaused
+		} else if passStart := time.Time(s.PassStart); !passStart.IsZero() && s.PassIssued > 0 {
+			// Same estimate as zpool status: remaining bytes at the issue rate of the current pass
+			elapsed := now.Sub(passStart).Seconds() - float64(s.ScrubSpentPaused)
+			if elapsed > 0 && total > scan.Issued {
+				rate := float64(s.PassIssued) / elapsed
+				scan.Remaining = time.Duration(float64(total-scan.Issued) / rate * float64(time.Second))
+			}
+		}
+	}
+
+	return scan
+}
+
+// zpool status text scan line formats
+var (
+	scanActiveRe     = regexp.MustCompile(`^(error scrub|scrub|resilver) (in progress|paused) since (.+)$`)
+	scanCanceledRe   = regexp.MustCompile(`^(error scrub|scrub|resilver) canceled on (.+)$`)
+	scanScrubbedRe   = regexp.MustCompile(`^(error scrub|scrub) repaired (\S+) in (.+) with (\d+) errors on (.+)$`)
+	scanResilveredRe = regexp.MustCompile(`^resilvered (\S+) in (.+) with (\d+) errors on (.+)$`)
+
+	scanTotalRe     = regexp.MustCompile(`(?:([\d.]+[BKMGTPE]?) / ([\d.]+[BKMGTPE]?) scanned|([\d.]+[BKMGTPE]?) scanned out of ([\d.]+[BKMGTPE]?)|([\d.]+[BKMGTPE]?) scanned)`)
+	scanIssuedRe    = regexp.MustCompile(`([\d.]+[BKMGTPE]?)(?: / [\d.]+[BKMGTPE]?)? issued`)
+	scanSizeRe      = regexp.MustCompile(`([\d.]+[BKMGTPE]?) total`)
+	scanRepairedRe  = regexp.MustCompile(`([\d.]+[BKMGTPE]?) (?:repaired|resilvered),`)
+	scanPercentRe   = regexp.MustCompile(`([\d.]+)% done`)
+	scanRemainingRe = regexp.MustCompile(`((?:\d+ days )?[\dhms:]+) to go`)
+	scanStartedRe   = regexp.MustCompile(`started on (.+)$`)
+)
+
+// parseZpoolScanText parses the scan: section of zpool status text output.
+// The first line holds the scan summary, e.g.
+//
+//	scrub repaired 0B in 01:02:03 with 0 errors on Sun Jul  6 01:26:04 2025
+//	resilver in progress since Sun Jul 13 00:24:01 2025
+//
+// and running scans add progress lines such as
+//
+//	1.23T / 3.45T scanned at 1.2G/s, 500G / 3.45T issued at 600M/s
+//	0B repaired, 14.15% done, 01:23:45 to go
+func parseZpoolScanText(lines []string) *types.ZFSScanInfo {
+	if len(lines) == 0 {
+		return nil
+	}
+	summary := strings.TrimSpace(lines[0])
+	details := make([]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		details = append(details, strings.TrimSpace(line))
+	}
 
-	// Skip pool names, vdev types, and special entries
-	skipPatterns := []string{
-		"mirror", "raidz", "raidz1", "raidz2", "raidz3",
-		"spare", "cache", "log", "special", "dedup",
-		"errors:", "NAME", "STATE", "READ", "WRITE", "CKSUM",
+	scan := &types.ZFSScanInfo{}
+	if m := scanScrubbedRe.FindStringSubmatch(summary); m != nil {
+		scan.Function = normalizeScanFunction(m[1])
+		scan.State = types.ZFSScanFinished
+		scan.Repaired, _ = parseZFSNumber(m[2])
+		scan.Errors, _ = strconv.ParseInt(m[4], 10, 64)
+		setFinishedTimes(scan, m[3], m[5])
+	} else if m := scanResilveredRe.FindStringSubmatch(summary); m != nil {
+		scan.Function = "resilver"
+		scan.State = types.ZFSScanFinished
+		scan.Repaired, _ = parseZFSNumber(m[1])
+		scan.Errors, _ = strconv.ParseInt(m[3], 10, 64)
+		setFinishedTimes(scan, m[2], m[4])
+	} else if m := scanCanceledRe.FindStringSubmatch(summary); m != nil {
+		scan.Function = normalizeScanFunction(m[1])
+		scan.State = types.ZFSScanCanceled
+		scan.EndTime, _ = parseZFSTime(m[2])
+	} else if m := scanActiveRe.FindStringSubmatch(summary); m != nil {
+		scan.Function = normalizeScanFunction(m[1])
+		scan.State = types.ZFSScanActive
+		if m[2] == "paused" {
+			scan.State = types.ZFSScanPaused
+		} else {
+			scan.StartTime, _ = parseZFSTime(m[3])
+		}
+		parseScanProgress(scan, details)
+	} else {
+		// "none requested" or an unrecognized format
+		return nil
 	}
 
-	for _, pattern := range skipPatterns {
-		if strings.Contains(strings.ToLower(deviceName), strings.ToLower(pattern)) {
-			return false
+	return scan
+}
+
+// parseScanProgress parses the progress lines of a running or paused scan
+func parseScanProgress(scan *types.ZFSScanInfo, lines []string) {
+	for _, line := range lines {
+		if m := scanStartedRe.FindStringSubmatch(line); m != nil {
+			scan.StartTime, _ = parseZFSTime(m[1])
+			continue
+		}
+		if m := scanTotalRe.FindStringSubmatch(line); m != nil {
+			switch {
+			case m[1] != "":
+				scan.Examined, _ = parseZFSNumber(m[1])
+				scan.TotalBytes, _ = parseZFSNumber(m[2])
+			case m[3] != "":
+				scan.Examined, _ = parseZFSNumber(m[3])
+				scan.TotalBytes, _ = parseZFSNumber(m[4])
+			default:
+				scan.Examined, _ = parseZFSNumber(m[5])
+			}
 		}
+		if m := scanIssuedRe.FindStringSubmatch(line); m != nil {
+			scan.Issued, _ = parseZFSNumber(m[1])
+		}
+		if m := scanSizeRe.FindStringSubmatch(line); m != nil {
+			scan.TotalBytes, _ = parseZFSNumber(m[1])
+		}
+		if m := scanRepairedRe.FindStringSubmatch(line); m != nil {
+			scan.Repaired, _ = parseZFSNumber(m[1])
+		}
+		if m := scanPercentRe.FindStringSubmatch(line); m != nil {
+			scan.PercentDone, _ = strconv.ParseFloat(m[1], 64)
+		}
+		if m := scanRemainingRe.FindStringSubmatch(line); m != nil {
+			scan.Remaining, _ = parseZFSElapsed(m[1])
+		}
+	}
+}
+
+// setFinishedTimes sets the end time of a finished scan and derives its start from the elapsed time
+func setFinishedTimes(scan *types.ZFSScanInfo, elapsed, end string) {
+	scan.PercentDone = 100
+	var err error
+	if scan.EndTime, err = parseZFSTime(end); err != nil {
+		return
 	}
+	if duration, err := parseZFSElapsed(elapsed); err == nil {
+		scan.StartTime = scan.EndTime.Add(-duration)
+	}
+}
 
-	// Look for device patterns
-	return strings.HasPrefix(deviceName, "/dev/") ||
-		strings.Contains(deviceName, "sd") ||
-		strings.Contains(deviceName, "nvme") ||
-		strings.Contains(deviceName, "ada") // FreeBSD
+// setLastScrub records the completion time of the pool's latest scan if it is a finished scrub
+func setLastScrub(pool *types.ZFSPoolInfo) {
+	if pool.Scan != nil && pool.Scan.Function == "scrub" && pool.Scan.State == types.ZFSScanFinished {
+		pool.LastScrub = pool.Scan.EndTime
+	}
 }
 
-// parseZFSDevice parses a ZFS device line from zpool status
-func (z *ZpoolTool) parseZFSDevice(line, poolName string) types.DiskInfo {
-	fields := strings.Fields(line)
-	if len(fields) < 2 {
-		return types.DiskInfo{}
+// normalizeScanFunction maps a scan function to "scrub", "resilver" or "error_scrub"
+func normalizeScanFunction(function string) string {
+	function = strings.ToLower(strings.TrimSpace(function))
+	switch function {
+	case "error scrub", "errorscrub":
+		return "error_scrub"
+	default:
+		return function
 	}
+}
 
-	deviceName := fields[0]
-	state := fields[1]
+// parseZFSTime parses a ctime-style timestamp printed by zpool (local time)
+func parseZFSTime(value string) (time.Time, error) {
+	value = strings.Join(strings.Fields(value), " ")
+	return time.ParseInLocation("Mon Jan 2 15:04:05 2006", value, time.Local)
+}
 
-	disk := types.DiskInfo{
-		Device:   deviceName,
-		Type:     "zfs",
-		Health:   z.convertZFSDeviceState(state),
-		Location: "Pool: " + poolName,
+// parseZFSElapsed parses a scan duration such as "01:02:03", "2 days 01:02:03"
+// or, on older releases, "0h10m"
+func parseZFSElapsed(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	var days int
+	if before, after, ok := strings.Cut(value, " days "); ok {
+		n, err := strconv.Atoi(before)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		days, value = n, after
 	}
 
-	// Try to get additional info about the device
-	z.enrichZFSDeviceInfo(&disk)
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		duration, err := time.ParseDuration(value)
+		return duration + time.Duration(days)*24*time.Hour, err
+	}
 
-	return disk
+	var total time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n) * unit
+	}
+	return total + time.Duration(days)*24*time.Hour, nil
 }
 
-// enrichPoolInfo gets additional information about a ZFS pool
-// zpool status POOL # get detailed pool status and device information
-func (z *ZpoolTool) enrichPoolInfo(pool *types.RAIDInfo) {
-	// Get detailed pool information
-	output, err := exec.Command("zpool", "status", pool.ArrayID).Output()
+// parseZFSNumber parses a count or byte size, which zpool abbreviates with
+// binary suffixes (e.g. "1.2K", "3.45T", "0B") unless exact output is requested
+func parseZFSNumber(value string) (int64, error) {
+	value = strings.TrimSuffix(value, "B")
+	multiplier := 1.0
+	if value != "" {
+		if i := strings.IndexByte("KMGTPE", value[len(value)-1]); i >= 0 {
+			multiplier = float64(int64(1) << (10 * (i + 1)))
+			value = value[:len(value)-1]
+		}
+	}
+	if value == "" {
+		value = "0"
+	}
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n * int64(multiplier), nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
 	if err != nil {
-		return
+		return 0, fmt.Errorf("invalid number %q", value)
 	}
+	return int64(f * multiplier), nil
+}
 
-	lines := strings.Split(string(output), "\n")
-	var deviceCount int
+// normalizeVdevType derives a vdev type from the reported type and/or vdev name
+// (e.g. "raidz" + "raidz2-0" -> "raidz2", "mirror-1" -> "mirror")
+func normalizeVdevType(vdevType, name string) string {
+	prefix := name
+	if i := strings.LastIndex(name, "-"); i > 0 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			prefix = name[:i]
+		}
+	}
 
-	for _, line := range lines {
-		trimmedLine := strings.TrimSpace(line)
+	switch {
+	case vdevType == "raidz" || (vdevType == "" && strings.HasPrefix(prefix, "raidz")):
+		switch prefix {
+		case "raidz2", "raidz3":
+			return prefix
+		default:
+			return "raidz1"
+		}
+	case vdevType == "draid" || (vdevType == "" && strings.HasPrefix(prefix, "draid")):
+		return "draid"
+	case vdevType != "":
+		return vdevType
+	}
+
+	switch prefix {
+	case "mirror", "spare", "replacing":
+		return prefix
+	}
+	if strings.HasPrefix(name, "/") && !strings.HasPrefix(name, "/dev/") {
+		return "file"
+	}
+	return "disk"
+}
+
+// normalizeZFSState upper-cases a vdev or pool state
+func normalizeZFSState(state string) string {
+	state = strings.ToUpper(strings.TrimSpace(state))
+	if state == "HEALTHY" {
+		return "ONLINE"
+	}
+	return state
+}
 
-		if z.isPhysicalDevice(trimmedLine) {
-			deviceCount++
+// poolToRAIDInfo summarizes a pool as a RAID array
+func (z *ZpoolTool) poolToRAIDInfo(pool types.ZFSPoolInfo) types.RAIDInfo {
+	raid := types.RAIDInfo{
+		ArrayID:        pool.Name,
+		RaidLevel:      zfsRaidLevel(pool),
+		State:          pool.State,
+		Status:         zpoolPoolStates.Map(pool.State),
+		Size:           pool.Size,
+		UsedSize:       pool.Allocated,
+		NumSpareDrives: len(pool.Spares),
+		Type:           "zfs",
+		Controller:     "zpool",
+		ZFS:            &pool,
+	}
+
+	if scan := pool.Scan; scan != nil && scan.State == types.ZFSScanActive {
+		switch scan.Function {
+		case "resilver":
+			raid.RebuildProgress = int(scan.PercentDone)
+			raid.Rebuilding = true
+		default:
+			raid.ScrubProgress = int(scan.PercentDone)
+		}
+	}
+
+	for _, vdev := range (types.ZFSPoolInfo{Root: pool.Root}).AllVdevs() {
+		if !vdev.IsLeaf() {
+			continue
+		}
+		raid.NumDrives++
+		switch vdev.State {
+		case "ONLINE":
+			raid.NumActiveDrives++
+		case "FAULTED", "UNAVAIL", "REMOVED", "OFFLINE":
+			raid.NumFailedDrives++
 		}
+	}
+
+	return raid
+}
+
+// zfsRaidLevel describes the redundancy of a pool's data vdevs (e.g. "ZFS RAIDZ2", "ZFS Mirror")
+func zfsRaidLevel(pool types.ZFSPoolInfo) string {
+	names := map[string]string{
+		"mirror": "Mirror",
+		"raidz1": "RAIDZ1",
+		"raidz2": "RAIDZ2",
+		"raidz3": "RAIDZ3",
+		"draid":  "dRAID",
+	}
 
-		// Look for RAID level information
-		if strings.Contains(line, "mirror") {
-			pool.RaidLevel = "ZFS Mirror"
-		} else if strings.Contains(line, "raidz3") {
-			pool.RaidLevel = "ZFS RAIDZ3"
-		} else if strings.Contains(line, "raidz2") {
-			pool.RaidLevel = "ZFS RAIDZ2"
-		} else if strings.Contains(line, "raidz1") || strings.Contains(line, "raidz") {
-			pool.RaidLevel = "ZFS RAIDZ1"
+	var levels []string
+	for _, vdev := range pool.Root.Children {
+		if vdev.Class != "normal" {
+			continue
+		}
+		level, ok := names[vdev.Type]
+		if !ok {
+			level = "Stripe"
 		}
+		if !containsLevel(levels, level) {
+			levels = append(levels, level)
+		}
+	}
+
+	if len(levels) == 0 || (len(levels) == 1 && levels[0] == "Stripe") {
+		return "ZFS Pool"
 	}
+	return "ZFS " + strings.Join(levels, "+")
+}
 
-	pool.NumDrives = deviceCount
-	pool.NumActiveDrives = deviceCount // ZFS doesn't have inactive drives concept
+// containsLevel reports whether levels contains level
+func containsLevel(levels []string, level string) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// poolDisks converts the leaf vdevs of a pool into disks
+func (z *ZpoolTool) poolDisks(pool types.ZFSPoolInfo) []types.DiskInfo {
+	var disks []types.DiskInfo
+
+	var walk func(vdev types.ZFSVdevInfo, parent string, rebuilding bool)
+	walk = func(vdev types.ZFSVdevInfo, parent string, rebuilding bool) {
+		rebuilding = rebuilding || vdev.Type == "replacing"
+		if !vdev.IsLeaf() {
+			for _, child := range vdev.Children {
+				walk(child, vdev.Name, rebuilding)
+			}
+			return
+		}
+
+		health := vdev.Status
+		disk := types.DiskInfo{
+			Device:       resolveVdevDevice(vdev.Name, vdev.Path),
+			Type:         "zfs",
+			Health:       zpoolHealthNames[health],
+			HealthStatus: health,
+			Location:     "Pool: " + pool.Name,
+			RaidArrayID:  pool.Name,
+			RaidPosition: parent,
+			RaidRole:     zfsRaidRole(vdev, rebuilding),
+		}
+		z.enrichZFSDeviceInfo(&disk)
+		disks = append(disks, disk)
+	}
+
+	walk(pool.Root, pool.Name, false)
+	for _, vdev := range pool.Cache {
+		walk(vdev, "cache", false)
+	}
+	for _, vdev := range pool.Spares {
+		walk(vdev, "spares", false)
+	}
+
+	return disks
+}
+
+// zfsRaidRole maps a leaf vdev to a RAID role
+func zfsRaidRole(vdev types.ZFSVdevInfo, rebuilding bool) string {
+	switch {
+	case vdev.Class == "spare" && vdev.State == "AVAIL":
+		return "spare"
+	case vdev.State == "FAULTED" || vdev.State == "UNAVAIL" || vdev.State == "REMOVED":
+		return "failed"
+	case rebuilding:
+		return "rebuilding"
+	case vdev.State == "ONLINE" || vdev.State == "INUSE":
+		return "active"
+	default:
+		return ""
+	}
+}
+
+// resolveVdevDevice maps a vdev name to its device node, following the
+// /dev/disk/by-* symlinks ZFS commonly uses for stable names
+func resolveVdevDevice(name, path string) string {
+	candidates := []string{name}
+	if !strings.HasPrefix(name, "/") {
+		candidates = []string{
+			"/dev/" + name,
+			"/dev/disk/by-id/" + name,
+			"/dev/disk/by-path/" + name,
+			"/dev/disk/by-vdev/" + name,
+			"/dev/disk/by-partuuid/" + name,
+		}
+	}
+	if path != "" {
+		candidates = append(candidates, path)
+	}
+
+	for _, candidate := range candidates {
+		if resolved, err := filepath.EvalSymlinks(candidate); err == nil {
+			return resolved
+		}
+	}
+	return name
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // enrichZFSDeviceInfo adds additional information to ZFS device
@@ -272,37 +1142,3 @@ func (z *ZpoolTool) enrichZFSDeviceInfo(disk *types.DiskInfo) {
 		}
 	}
 }
-
-// convertZFSDeviceState converts ZFS device state to health status
-func (z *ZpoolTool) convertZFSDeviceState(state string) string {
-	state = strings.ToLower(strings.TrimSpace(state))
-
-	switch state {
-	case "online":
-		return "OK"
-	case "degraded", "faulted":
-		return "DEGRADED"
-	case "offline", "removed", "unavail":
-		return "FAILED"
-	case "resilvering":
-		return "REBUILDING"
-	default:
-		return "UNKNOWN"
-	}
-}
-
-// getZFSStatusValue converts ZFS pool health to numeric value
-func (z *ZpoolTool) getZFSStatusValue(health string) int {
-	health = strings.ToLower(strings.TrimSpace(health))
-
-	switch health {
-	case "online":
-		return 1
-	case "degraded":
-		return 2
-	case "faulted", "offline", "unavail":
-		return 3
-	default:
-		return 0
-	}
-}
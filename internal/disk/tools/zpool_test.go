@@ -1,7 +1,13 @@
 package tools
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	"disk-health-exporter/pkg/types"
 )
 
 func TestZpoolTool_NewZpoolTool(t *testing.T) {
@@ -39,3 +45,434 @@ func TestZpoolTool_GetDisks_Interface(t *testing.T) {
 
 	t.Logf("zpool found %d disks and %d pools", len(disks), len(pools))
 }
+
+func readZpoolFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	return data
+}
+
+// findVdev returns the first vdev with the given name in a pool
+func findVdev(pool types.ZFSPoolInfo, name string) *types.ZFSVdevInfo {
+	for _, vdev := range pool.AllVdevs() {
+		if vdev.Name == name {
+			return &vdev
+		}
+	}
+	return nil
+}
+
+// checkTankPool verifies the tank pool shared by the text and JSON fixtures
+func checkTankPool(t *testing.T, pool types.ZFSPoolInfo) {
+	t.Helper()
+
+	if pool.Name != "tank" || pool.State != "ONLINE" {
+		t.Errorf("Expected tank ONLINE, got %s %s", pool.Name, pool.State)
+	}
+	if pool.Root.Type != "root" {
+		t.Errorf("Expected root vdev type root, got %s", pool.Root.Type)
+	}
+
+	var top []string
+	for _, vdev := range pool.Root.Children {
+		top = append(top, vdev.Name+"/"+vdev.Type+"/"+vdev.Class)
+	}
+	expectedTop := []string{"raidz2-0/raidz2/normal", "mirror-1/mirror/special", "nvme2n1/disk/log"}
+	if !reflect.DeepEqual(top, expectedTop) {
+		t.Errorf("Expected top-level vdevs %v, got %v", expectedTop, top)
+	}
+	if len(pool.Root.Children[0].Children) != 4 {
+		t.Errorf("Expected 4 disks in raidz2-0, got %d", len(pool.Root.Children[0].Children))
+	}
+	if len(pool.Root.Children[1].Children) != 2 {
+		t.Errorf("Expected 2 disks in mirror-1, got %d", len(pool.Root.Children[1].Children))
+	}
+
+	if len(pool.Cache) != 1 || pool.Cache[0].Name != "sdf" || pool.Cache[0].Class != "cache" {
+		t.Errorf("Expected cache device sdf, got %+v", pool.Cache)
+	}
+	if len(pool.Spares) != 1 || pool.Spares[0].Name != "sde" || pool.Spares[0].State != "AVAIL" {
+		t.Errorf("Expected available spare sde, got %+v", pool.Spares)
+	}
+
+	cksum := findVdev(pool, "ata-WDC_WD40EFRX-68N32N0_WD-WCC7K2")
+	if cksum == nil || cksum.ChecksumErrors != 12 || !cksum.IsLeaf() {
+		t.Errorf("Expected 12 checksum errors on WCC7K2, got %+v", cksum)
+	}
+	rw := findVdev(pool, "ata-WDC_WD40EFRX-68N32N0_WD-WCC7K3")
+	if rw == nil || rw.ReadErrors != 3 || rw.WriteErrors != 1 {
+		t.Errorf("Expected 3 read and 1 write errors on WCC7K3, got %+v", rw)
+	}
+}
+
+func TestParseZpoolStatusText(t *testing.T) {
+	pools := parseZpoolStatusText(string(readZpoolFixture(t, "zpool_status.txt")))
+	if len(pools) != 2 {
+		t.Fatalf("Expected 2 pools, got %d", len(pools))
+	}
+
+	backup := pools[0]
+	if backup.Name != "backup" || backup.State != "DEGRADED" {
+		t.Errorf("Expected backup DEGRADED, got %s %s", backup.Name, backup.State)
+	}
+	missing := findVdev(backup, "14958378930129409132")
+	if missing == nil || missing.State != "UNAVAIL" || missing.Status != types.HealthStatusCritical || missing.Path != "/dev/sdh1" {
+		t.Errorf("Expected UNAVAIL device last seen at /dev/sdh1, got %+v", missing)
+	}
+	if mirror := findVdev(backup, "mirror-0"); mirror == nil || mirror.State != "DEGRADED" || mirror.Status != types.HealthStatusWarning || len(mirror.Children) != 2 {
+		t.Errorf("Expected degraded mirror-0 with 2 children, got %+v", mirror)
+	}
+
+	checkTankPool(t, pools[1])
+
+	end := time.Date(2025, time.July, 6, 1, 26, 4, 0, time.Local)
+	if backup.Scan == nil || !backup.LastScrub.Equal(end) || !backup.Scan.StartTime.Equal(end.Add(-(time.Hour + 2*time.Minute + 3*time.Second))) {
+		t.Errorf("Expected scrub finished at %v, got %+v", end, backup.Scan)
+	}
+	if pools[1].Scan == nil || pools[1].Scan.Repaired != 4096 {
+		t.Errorf("Expected 4096 bytes repaired on tank, got %+v", pools[1].Scan)
+	}
+}
+
+func TestParseZpoolStatusJSON(t *testing.T) {
+	pools, err := parseZpoolStatusJSON(readZpoolFixture(t, "zpool_status.json"), time.Now())
+	if err != nil {
+		t.Fatalf("parseZpoolStatusJSON returned error: %v", err)
+	}
+	if len(pools) != 1 {
+		t.Fatalf("Expected 1 pool, got %d", len(pools))
+	}
+
+	checkTankPool(t, pools[0])
+
+	scan := pools[0].Scan
+	if scan == nil || scan.Function != "scrub" || scan.State != types.ZFSScanFinished {
+		t.Fatalf("Expected finished scrub, got %+v", scan)
+	}
+	if scan.Repaired != 4096 || scan.PercentDone != 100 || scan.Issued != 1204062208 {
+		t.Errorf("Unexpected scrub statistics: %+v", scan)
+	}
+	if !scan.StartTime.Equal(time.Unix(1752366241, 0)) || !pools[0].LastScrub.Equal(time.Unix(1752366853, 0)) {
+		t.Errorf("Expected scrub from 1752366241 to 1752366853, got %v to %v", scan.StartTime, pools[0].LastScrub)
+	}
+
+	leaf := findVdev(pools[0], "ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1")
+	if leaf == nil || leaf.Path != "/dev/disk/by-id/ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1-part1" {
+		t.Errorf("Expected leaf path from JSON, got %+v", leaf)
+	}
+}
+
+func TestParseZpoolStatusJSONInvalid(t *testing.T) {
+	for _, input := range []string{"not json", `{"output_version": {}}`} {
+		if _, err := parseZpoolStatusJSON([]byte(input), time.Now()); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestApplyZpoolList(t *testing.T) {
+	pools := []types.ZFSPoolInfo{{Name: "tank", Fragmentation: -1}, {Name: "backup", Fragmentation: -1}}
+	output := "tank\t15997367959552\t1204062208\t15996163897344\t3\t0\t1.25x\tONLINE\n" +
+		"backup\t1992864825344\t1099511627776\t893353197568\t-\t55\t1.00x\tDEGRADED\n"
+
+	applyZpoolList(pools, output)
+
+	tank := pools[0]
+	if tank.Size != 15997367959552 || tank.Allocated != 1204062208 || tank.Free != 15996163897344 {
+		t.Errorf("Unexpected tank space usage: %+v", tank)
+	}
+	if tank.Fragmentation != 3 || tank.DedupRatio != 1.25 {
+		t.Errorf("Expected fragmentation 3 and dedup 1.25, got %d and %v", tank.Fragmentation, tank.DedupRatio)
+	}
+	if pools[1].Fragmentation != -1 || pools[1].Capacity != 55 || pools[1].State != "DEGRADED" {
+		t.Errorf("Expected unknown fragmentation, 55%% capacity and DEGRADED, got %+v", pools[1])
+	}
+}
+
+func TestNormalizeVdevType(t *testing.T) {
+	tests := []struct {
+		vdevType string
+		name     string
+		expected string
+	}{
+		{"raidz", "raidz2-0", "raidz2"},
+		{"raidz", "raidz3-1", "raidz3"},
+		{"", "raidz1-0", "raidz1"},
+		{"", "raidz-0", "raidz1"},
+		{"", "draid2:4d:8c:1s-0", "draid"},
+		{"", "mirror-3", "mirror"},
+		{"", "spare-1", "spare"},
+		{"", "replacing-0", "replacing"},
+		{"", "ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1", "disk"},
+		{"", "/var/tmp/vdev1", "file"},
+		{"disk", "sda", "disk"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeVdevType(tt.vdevType, tt.name); got != tt.expected {
+			t.Errorf("normalizeVdevType(%q, %q) = %q, expected %q", tt.vdevType, tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestZpoolPoolToRAIDInfo(t *testing.T) {
+	tool := NewZpoolTool()
+	pools := parseZpoolStatusText(string(readZpoolFixture(t, "zpool_status.txt")))
+
+	backup := tool.poolToRAIDInfo(pools[0])
+	if backup.RaidLevel != "ZFS Mirror" || backup.NumDrives != 2 || backup.NumActiveDrives != 1 || backup.NumFailedDrives != 1 {
+		t.Errorf("Unexpected backup summary: %+v", backup)
+	}
+	if backup.Status != 2 {
+		t.Errorf("Expected degraded status 2, got %d", backup.Status)
+	}
+
+	tank := tool.poolToRAIDInfo(pools[1])
+	if tank.RaidLevel != "ZFS RAIDZ2" {
+		t.Errorf("Expected RaidLevel ZFS RAIDZ2, got %s", tank.RaidLevel)
+	}
+	if tank.NumDrives != 7 || tank.NumSpareDrives != 1 || tank.ZFS == nil {
+		t.Errorf("Expected 7 drives and 1 spare with pool details, got %+v", tank)
+	}
+
+	disks := tool.poolDisks(pools[0])
+	if len(disks) != 2 || disks[1].RaidRole != "failed" || disks[1].Health != "FAILED" || disks[1].RaidPosition != "mirror-0" {
+		t.Errorf("Expected failed second mirror member, got %+v", disks)
+	}
+}
+
+func TestParseZpoolScanText(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected *types.ZFSScanInfo
+	}{
+		{
+			name:     "none requested",
+			lines:    []string{"none requested"},
+			expected: nil,
+		},
+		{
+			name:  "scrub in progress",
+			lines: []string{"scrub in progress since Sun Jul 13 00:24:01 2025", "1.50T / 3.00T scanned at 1.2G/s, 768G / 3.00T issued at 600M/s", "0B repaired, 25.00% done, 01:23:45 to go"},
+			expected: &types.ZFSScanInfo{
+				Function:    "scrub",
+				State:       types.ZFSScanActive,
+				StartTime:   time.Date(2025, time.July, 13, 0, 24, 1, 0, time.Local),
+				TotalBytes:  3 << 40,
+				Examined:    3 << 39,
+				Issued:      768 << 30,
+				PercentDone: 25,
+				Remaining:   time.Hour + 23*time.Minute + 45*time.Second,
+			},
+		},
+		{
+			name:  "resilver in progress",
+			lines: []string{"resilver in progress since Mon Jul 14 10:00:00 2025", "100G / 1T scanned at 500M/s, 50G / 1T issued at 250M/s", "12.5G resilvered, 4.88% done, 1 days 02:00:00 to go"},
+			expected: &types.ZFSScanInfo{
+				Function:    "resilver",
+				State:       types.ZFSScanActive,
+				StartTime:   time.Date(2025, time.July, 14, 10, 0, 0, 0, time.Local),
+				TotalBytes:  1 << 40,
+				Examined:    100 << 30,
+				Issued:      50 << 30,
+				Repaired:    25 << 29,
+				PercentDone: 4.88,
+				Remaining:   26 * time.Hour,
+			},
+		},
+		{
+			name:  "scrub paused",
+			lines: []string{"scrub paused since Tue Jul 15 08:00:00 2025", "scrub started on Tue Jul 15 06:00:00 2025", "1T / 2T scanned, 512G / 2T issued", "0B repaired, 25.00% done"},
+			expected: &types.ZFSScanInfo{
+				Function:    "scrub",
+				State:       types.ZFSScanPaused,
+				StartTime:   time.Date(2025, time.July, 15, 6, 0, 0, 0, time.Local),
+				TotalBytes:  2 << 40,
+				Examined:    1 << 40,
+				Issued:      512 << 30,
+				PercentDone: 25,
+			},
+		},
+		{
+			name:  "scrub canceled",
+			lines: []string{"scrub canceled on Wed Jul 16 09:30:00 2025"},
+			expected: &types.ZFSScanInfo{
+				Function: "scrub",
+				State:    types.ZFSScanCanceled,
+				EndTime:  time.Date(2025, time.July, 16, 9, 30, 0, 0, time.Local),
+			},
+		},
+		{
+			name:  "resilver finished",
+			lines: []string{"resilvered 1.50G in 2 days 00:10:00 with 3 errors on Thu Jul 17 12:00:00 2025"},
+			expected: &types.ZFSScanInfo{
+				Function:    "resilver",
+				State:       types.ZFSScanFinished,
+				StartTime:   time.Date(2025, time.July, 15, 11, 50, 0, 0, time.Local),
+				EndTime:     time.Date(2025, time.July, 17, 12, 0, 0, 0, time.Local),
+				Repaired:    3 << 29,
+				Errors:      3,
+				PercentDone: 100,
+			},
+		},
+		{
+			name:  "legacy scrub in progress",
+			lines: []string{"scrub in progress since Fri Jul 18 01:00:00 2025", "1.00T scanned out of 4.00T at 100M/s, 8h44m to go", "0 repaired, 25.00% done"},
+			expected: &types.ZFSScanInfo{
+				Function:    "scrub",
+				State:       types.ZFSScanActive,
+				StartTime:   time.Date(2025, time.July, 18, 1, 0, 0, 0, time.Local),
+				TotalBytes:  4 << 40,
+				Examined:    1 << 40,
+				PercentDone: 25,
+				Remaining:   8*time.Hour + 44*time.Minute,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scan := parseZpoolScanText(tt.lines)
+			if !reflect.DeepEqual(scan, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, scan)
+			}
+		})
+	}
+}
+
+func TestConvertScanJSONRemaining(t *testing.T) {
+	now := time.Unix(1752400000, 0)
+	scan := convertScanJSON(zpoolScanJSON{
+		Function:   "RESILVER",
+		State:      "SCANNING",
+		StartTime:  zfsTime(now.Add(-2 * time.Hour)),
+		PassStart:  zfsTime(now.Add(-time.Hour)),
+		ToExamine:  1000 << 30,
+		Issued:     250 << 30,
+		PassIssued: 250 << 30,
+	}, now)
+
+	if scan.Function != "resilver" || scan.State != types.ZFSScanActive || scan.PercentDone != 25 {
+		t.Errorf("Expected resilver 25%% done, got %+v", scan)
+	}
+	if scan.Remaining != 3*time.Hour {
+		t.Errorf("Expected 3h remaining, got %v", scan.Remaining)
+	}
+
+	paused := convertScanJSON(zpoolScanJSON{Function: "SCRUB", State: "SCANNING", ScrubPause: zfsTime(now)}, now)
+	if paused.State != types.ZFSScanPaused || paused.Remaining != 0 {
+		t.Errorf("Expected paused scrub without estimate, got %+v", paused)
+	}
+
+	if none := convertScanJSON(zpoolScanJSON{Function: "NONE", State: "NONE"}, now); none != nil {
+		t.Errorf("Expected nil for no scan, got %+v", none)
+	}
+}
+
+func TestZpoolSnapshot(t *testing.T) {
+	tool := NewZpoolTool()
+	pools := parseZpoolStatusText(string(readZpoolFixture(t, "zpool_status.txt")))
+
+	disks, raids := tool.snapshot(pools)
+	if len(raids) != 2 || raids[0].ArrayID != "backup" || raids[1].ArrayID != "tank" {
+		t.Fatalf("Expected backup and tank, got %+v", raids)
+	}
+	expected := len(tool.poolDisks(pools[0])) + len(tool.poolDisks(pools[1]))
+	if len(disks) == 0 || len(disks) != expected {
+		t.Errorf("Expected the %d devices of both pools, got %d", expected, len(disks))
+	}
+}
+
+func TestZpoolScanProgress(t *testing.T) {
+	tool := NewZpoolTool()
+	pool := types.ZFSPoolInfo{
+		Name: "tank",
+		Scan: &types.ZFSScanInfo{Function: "resilver", State: types.ZFSScanActive, PercentDone: 42.5},
+	}
+
+	raid := tool.poolToRAIDInfo(pool)
+	if !raid.Rebuilding || raid.RebuildProgress != 42 || raid.ScrubProgress != 0 {
+		t.Errorf("Expected rebuild progress 42, got rebuild %d scrub %d", raid.RebuildProgress, raid.ScrubProgress)
+	}
+
+	pool.Scan.Function = "scrub"
+	raid = tool.poolToRAIDInfo(pool)
+	if raid.Rebuilding || raid.ScrubProgress != 42 || raid.RebuildProgress != 0 {
+		t.Errorf("Expected scrub progress 42, got scrub %d rebuild %d", raid.ScrubProgress, raid.RebuildProgress)
+	}
+}
+
+func TestParseZpoolEvents(t *testing.T) {
+	events := parseZpoolEvents(string(readZpoolFixture(t, "zpool_events.txt")))
+	if len(events) != 6 {
+		t.Fatalf("Expected 6 events, got %d", len(events))
+	}
+
+	checksum := events[1]
+	if checksum.EID != 0x2a || checksum.Class != "ereport.fs.zfs.checksum" || checksum.Pool != "tank" {
+		t.Errorf("Unexpected checksum event: %+v", checksum)
+	}
+	if checksum.Vdev != "ata-WDC_WD40EFRX-68N32N0_WD-WCC7K2" || checksum.VdevPath != "/dev/disk/by-id/ata-WDC_WD40EFRX-68N32N0_WD-WCC7K2-part1" {
+		t.Errorf("Expected vdev from path, got %q (%q)", checksum.Vdev, checksum.VdevPath)
+	}
+	if !checksum.Time.Equal(time.Unix(0x6872f733, 0x1dedb050)) {
+		t.Errorf("Expected time from payload, got %v", checksum.Time)
+	}
+
+	if events[3].Vdev != "sdb" || events[4].Vdev != "nvme0n1" || events[4].Pool != "backup" {
+		t.Errorf("Unexpected vdev names: %q, %q", events[3].Vdev, events[4].Vdev)
+	}
+	if data := events[5]; data.Class != "ereport.fs.zfs.data" || data.Vdev != "" || data.EID != 0x2e {
+		t.Errorf("Expected pool-level data event, got %+v", data)
+	}
+}
+
+func TestVdevNameFromPath(t *testing.T) {
+	tests := map[string]string{
+		"/dev/disk/by-id/ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1-part1": "ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1",
+		"/dev/disk/by-id/wwn-0x5000c500a1b2c3d4":                   "wwn-0x5000c500a1b2c3d4",
+		"/dev/sdb1":                                                "sdb",
+		"/dev/sdaa9":                                               "sdaa",
+		"/dev/nvme0n1p1":                                           "nvme0n1",
+		"/dev/nvme0n1":                                             "nvme0n1",
+		"/var/tmp/vdev1":                                           "/var/tmp/vdev1",
+	}
+
+	for path, expected := range tests {
+		if got := vdevNameFromPath(path); got != expected {
+			t.Errorf("vdevNameFromPath(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+func TestZpoolStates(t *testing.T) {
+	checkStates(t, zpoolPoolStates, map[string]types.RAIDState{
+		"ONLINE":    types.RAIDStateOptimal,
+		"DEGRADED":  types.RAIDStateDegraded,
+		"FAULTED":   types.RAIDStateFailed,
+		"OFFLINE":   types.RAIDStateFailed,
+		"REMOVED":   types.RAIDStateFailed,
+		"UNAVAIL":   types.RAIDStateFailed,
+		"SUSPENDED": types.RAIDStateFailed,
+	})
+	checkStates(t, zpoolDeviceStates, map[string]types.HealthStatus{
+		"ONLINE":   types.HealthStatusOK,
+		"AVAIL":    types.HealthStatusOK,
+		"INUSE":    types.HealthStatusOK,
+		"DEGRADED": types.HealthStatusWarning,
+		"FAULTED":  types.HealthStatusCritical,
+		"OFFLINE":  types.HealthStatusCritical,
+		"REMOVED":  types.HealthStatusCritical,
+		"UNAVAIL":  types.HealthStatusCritical,
+	})
+
+	// Vdevs carry the mapped state, unknown states included
+	vdev := parseVdevLine([]string{"sda", "SPLIT", "0", "0", "0"}, "normal")
+	if vdev.State != "SPLIT" || vdev.Status != types.HealthStatusUnknown {
+		t.Errorf("Expected an unknown status for SPLIT, got %+v", vdev)
+	}
+}
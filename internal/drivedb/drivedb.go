@@ -0,0 +1,337 @@
+package drivedb
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"disk-health-exporter/internal/config"
+)
+
+// Fields an attribute can be decoded into
+const (
+	FieldPowerOnHours        = "power_on_hours"
+	FieldTemperature         = "temperature"
+	FieldTemperatureMin      = "temperature_min"
+	FieldTemperatureMax      = "temperature_max"
+	FieldReallocatedSectors  = "reallocated_sectors"
+	FieldReallocationEvents  = "reallocation_events"
+	FieldPendingSectors      = "pending_sectors"
+	FieldUncorrectableErrors = "uncorrectable_errors"
+	FieldBytesWritten        = "bytes_written"
+	FieldBytesRead           = "bytes_read"
+	FieldWear                = "wear" // Consumed life percentage (0-100)
+	FieldIgnore              = "ignore"
+)
+
+// Life values for wear attributes
+const (
+	LifeRemaining = "remaining"
+	LifeConsumed  = "consumed"
+)
+
+// fieldUnits lists the units accepted by each field; the first is the default
+var fieldUnits = map[string][]string{
+	FieldPowerOnHours:        {"hours", "minutes", "seconds", "half_minutes"},
+	FieldTemperature:         {"celsius"},
+	FieldTemperatureMin:      {"celsius"},
+	FieldTemperatureMax:      {"celsius"},
+	FieldReallocatedSectors:  {"count"},
+	FieldReallocationEvents:  {"count"},
+	FieldPendingSectors:      {"count"},
+	FieldUncorrectableErrors: {"count"},
+	FieldBytesWritten:        {"lba", "auto", "bytes", "mib", "32mib", "gib"},
+	FieldBytesRead:           {"lba", "auto", "bytes", "mib", "32mib", "gib"},
+	FieldWear:                {"percent"},
+	FieldIgnore:              {""},
+}
+
+//go:embed drivedb.yml
+var builtin []byte
+
+// Attribute is a single ATA SMART attribute as reported by smartctl
+type Attribute struct {
+	ID    int
+	Name  string // Attribute name from smartctl's own drive database
+	Value int    // Normalized value
+	Raw   int64  // 48-bit raw value
+}
+
+// rule decodes one field from an attribute
+type rule struct {
+	id     int
+	name   string
+	field  string
+	source string
+	shift  uint
+	mask   uint64
+	unit   string
+	life   string
+}
+
+// drive holds the rules for drives matching a model pattern
+type drive struct {
+	name  string
+	model *regexp.Regexp
+	rules []rule
+}
+
+// Database decodes SMART attributes using per-model rules
+type Database struct {
+	defaults []rule
+	drives   []drive
+}
+
+var (
+	defaultMu sync.RWMutex
+	defaultDB = mustBuiltin()
+)
+
+// Default returns the database used by tools that are not given one explicitly
+func Default() *Database {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultDB
+}
+
+// SetDefault replaces the database returned by Default
+func SetDefault(db *Database) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultDB = db
+}
+
+// mustBuiltin parses the embedded database, which is validated by tests
+func mustBuiltin() *Database {
+	db, err := New(config.DriveDBConfig{})
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in drive database: %v", err))
+	}
+	return db
+}
+
+// New creates a database from the built-in rules with configuration overrides applied
+func New(overrides config.DriveDBConfig) (*Database, error) {
+	var base config.DriveDBConfig
+	if err := yaml.UnmarshalStrict(builtin, &base); err != nil {
+		return nil, fmt.Errorf("parsing built-in database: %w", err)
+	}
+
+	defaults, err := compileRules(base.Defaults)
+	if err != nil {
+		return nil, fmt.Errorf("defaults: %w", err)
+	}
+	overrideDefaults, err := compileRules(overrides.Defaults)
+	if err != nil {
+		return nil, fmt.Errorf("defaults: %w", err)
+	}
+
+	db := &Database{defaults: replaceRules(defaults, overrideDefaults, false)}
+
+	// Configured drives are matched before the built-in ones
+	for _, d := range slices.Concat(overrides.Drives, base.Drives) {
+		model, err := regexp.Compile(d.Model)
+		if err != nil {
+			return nil, fmt.Errorf("drive %q: invalid model pattern: %w", d.Name, err)
+		}
+		rules, err := compileRules(d.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("drive %q: %w", d.Name, err)
+		}
+		db.drives = append(db.drives, drive{name: d.Name, model: model, rules: rules})
+	}
+
+	return db, nil
+}
+
+// compileRules validates attribute configuration and applies defaults
+func compileRules(attrs []config.AttributeConfig) ([]rule, error) {
+	rules := make([]rule, 0, len(attrs))
+	for _, a := range attrs {
+		units, ok := fieldUnits[a.Field]
+		if !ok {
+			return nil, fmt.Errorf("attribute %d: unknown field %q", a.ID, a.Field)
+		}
+		if a.ID < 1 || a.ID > 255 {
+			return nil, fmt.Errorf("attribute %d: id must be between 1 and 255", a.ID)
+		}
+
+		r := rule{
+			id:     a.ID,
+			name:   a.Name,
+			field:  a.Field,
+			source: a.Source,
+			shift:  a.Shift,
+			mask:   a.Mask,
+			unit:   strings.ToLower(a.Unit),
+			life:   a.Life,
+		}
+
+		if r.source == "" {
+			r.source = "raw"
+		}
+		if r.source != "raw" && r.source != "value" {
+			return nil, fmt.Errorf("attribute %d: source must be \"raw\" or \"value\"", a.ID)
+		}
+		if r.shift >= 48 {
+			return nil, fmt.Errorf("attribute %d: shift must be below 48", a.ID)
+		}
+
+		if r.unit == "" {
+			r.unit = units[0]
+		}
+		if !slices.Contains(units, r.unit) {
+			return nil, fmt.Errorf("attribute %d: unit %q is not valid for field %s", a.ID, a.Unit, a.Field)
+		}
+
+		if r.field == FieldWear && r.life != LifeRemaining && r.life != LifeConsumed {
+			return nil, fmt.Errorf("attribute %d: wear requires life \"remaining\" or \"consumed\"", a.ID)
+		}
+
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// replaceRules returns base with every rule sharing an attribute ID (and, if
+// byField is set, a decoded field) with a rule in overrides removed, followed
+// by the overrides
+func replaceRules(base, overrides []rule, byField bool) []rule {
+	replacedIDs := make(map[int]bool)
+	replacedFields := make(map[string]bool)
+	for _, r := range overrides {
+		replacedIDs[r.id] = true
+		if byField && r.field != FieldIgnore {
+			replacedFields[r.field] = true
+		}
+	}
+
+	var rules []rule
+	for _, r := range base {
+		if !replacedIDs[r.id] && !replacedFields[r.field] {
+			rules = append(rules, r)
+		}
+	}
+	return append(rules, overrides...)
+}
+
+// Match returns the name of the drive entry matching a model, or an empty string
+func (db *Database) Match(model string) string {
+	if d := db.match(model); d != nil {
+		return d.name
+	}
+	return ""
+}
+
+// match returns the first drive entry matching a model
+func (db *Database) match(model string) *drive {
+	for i := range db.drives {
+		if db.drives[i].model.MatchString(model) {
+			return &db.drives[i]
+		}
+	}
+	return nil
+}
+
+// Decode interprets the attributes of a drive, returning decoded values by field.
+// Byte counts are converted to bytes and times to hours. When several attributes
+// decode into the same field, the last one wins, except for wear where the
+// highest consumed percentage is kept.
+func (db *Database) Decode(model string, attrs []Attribute, logicalBlockSize int) map[string]float64 {
+	rules := db.defaults
+	if d := db.match(model); d != nil {
+		rules = replaceRules(db.defaults, d.rules, true)
+	}
+
+	values := make(map[string]float64)
+	for _, attr := range attrs {
+		for _, r := range rules {
+			if r.id != attr.ID || r.field == FieldIgnore {
+				continue
+			}
+			if r.name != "" && r.name != attr.Name {
+				continue
+			}
+
+			value := r.decode(attr, logicalBlockSize)
+			if r.field == FieldWear {
+				if existing, ok := values[FieldWear]; ok && existing > value {
+					continue
+				}
+			}
+			values[r.field] = value
+		}
+	}
+
+	return values
+}
+
+// decode extracts and scales the rule's value from an attribute
+func (r rule) decode(attr Attribute, logicalBlockSize int) float64 {
+	var value float64
+	if r.source == "value" {
+		value = float64(attr.Value)
+	} else {
+		raw := uint64(attr.Raw) >> r.shift
+		if r.mask != 0 {
+			raw &= r.mask
+		}
+		value = float64(raw)
+	}
+
+	if r.field == FieldWear {
+		if r.life == LifeRemaining {
+			value = 100 - value
+		}
+		return min(max(value, 0), 100)
+	}
+
+	return value * unitScale(r.unit, attr.Name, logicalBlockSize)
+}
+
+// unitScale returns the multiplier converting a unit to bytes, hours or itself
+func unitScale(unit, attrName string, logicalBlockSize int) float64 {
+	switch unit {
+	case "lba":
+		if logicalBlockSize > 0 {
+			return float64(logicalBlockSize)
+		}
+		return 512
+	case "auto":
+		return autoByteScale(attrName, logicalBlockSize)
+	case "mib":
+		return 1 << 20
+	case "32mib":
+		return 32 << 20
+	case "gib":
+		return 1 << 30
+	case "minutes":
+		return 1.0 / 60
+	case "half_minutes":
+		return 1.0 / 120
+	case "seconds":
+		return 1.0 / 3600
+	default:
+		return 1
+	}
+}
+
+// autoByteScale infers the unit of a host I/O attribute from its smartctl name
+// (e.g. "Host_Writes_32MiB", "Lifetime_Writes_GiB"), defaulting to logical blocks
+func autoByteScale(attrName string, logicalBlockSize int) float64 {
+	switch {
+	case strings.Contains(attrName, "32MiB"):
+		return 32 << 20
+	case strings.Contains(attrName, "GiB"):
+		return 1 << 30
+	case strings.Contains(attrName, "MiB"):
+		return 1 << 20
+	default:
+		return unitScale("lba", attrName, logicalBlockSize)
+	}
+}
@@ -0,0 +1,194 @@
+package drivedb
+
+import (
+	"testing"
+
+	"disk-health-exporter/internal/config"
+)
+
+func TestBuiltinDatabase(t *testing.T) {
+	if _, err := New(config.DriveDBConfig{}); err != nil {
+		t.Fatalf("Built-in database is invalid: %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	db, err := New(config.DriveDBConfig{})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		model    string
+		attrs    []Attribute
+		expected map[string]float64
+		absent   []string
+	}{
+		{
+			name:  "samsung wear leveling count is remaining life",
+			model: "Samsung SSD 870 EVO 1TB",
+			attrs: []Attribute{
+				{ID: 177, Name: "Wear_Leveling_Count", Value: 97, Raw: 42},
+				{ID: 241, Name: "Total_LBAs_Written", Value: 99, Raw: 1000},
+			},
+			expected: map[string]float64{FieldWear: 3, FieldBytesWritten: 1000 * 512},
+		},
+		{
+			name:     "intel media wearout indicator and 32MiB host writes",
+			model:    "INTEL SSDSC2KB480G8",
+			attrs:    []Attribute{{ID: 233, Name: "Media_Wearout_Indicator", Value: 88}, {ID: 241, Name: "Total_LBAs_Written", Raw: 10}},
+			expected: map[string]float64{FieldWear: 12, FieldBytesWritten: 10 * 32 << 20},
+		},
+		{
+			name:  "sandforce internal attribute is not wear",
+			model: "KINGSTON SV300S37A120G",
+			attrs: []Attribute{
+				{ID: 231, Name: "SSD_Life_Left", Value: 95},
+				{ID: 233, Name: "SandForce_Internal", Value: 0, Raw: 5000},
+				{ID: 241, Name: "Lifetime_Writes_GiB", Raw: 7},
+			},
+			expected: map[string]float64{FieldWear: 5, FieldBytesWritten: 7 << 30},
+		},
+		{
+			name:     "unknown drive only decodes wear from recognized attribute names",
+			model:    "Generic SSD",
+			attrs:    []Attribute{{ID: 233, Name: "Flash_Writes_GiB", Value: 100, Raw: 123}},
+			expected: map[string]float64{},
+			absent:   []string{FieldWear},
+		},
+		{
+			name:     "crucial percent lifetime used from raw",
+			model:    "CT500MX500SSD1",
+			attrs:    []Attribute{{ID: 202, Name: "Percent_Lifetime_Remain", Value: 94, Raw: 6}},
+			expected: map[string]float64{FieldWear: 6},
+		},
+		{
+			name:     "packed temperature with lifetime min and max",
+			model:    "WDC WD40EFRX-68N32N0",
+			attrs:    []Attribute{{ID: 194, Name: "Temperature_Celsius", Value: 115, Raw: 0x003C_0014_0023}},
+			expected: map[string]float64{FieldTemperature: 35, FieldTemperatureMin: 20, FieldTemperatureMax: 60},
+		},
+		{
+			name:     "seagate temperature upper bytes are ignored",
+			model:    "ST4000NM0033-9ZM170",
+			attrs:    []Attribute{{ID: 194, Name: "Temperature_Celsius", Value: 36, Raw: 0x0000_0011_0024}},
+			expected: map[string]float64{FieldTemperature: 36},
+			absent:   []string{FieldTemperatureMin, FieldTemperatureMax},
+		},
+		{
+			name:     "power on hours with milliseconds in high bytes",
+			model:    "ST4000NM0033-9ZM170",
+			attrs:    []Attribute{{ID: 9, Name: "Power_On_Hours", Raw: 0x1234_0000_2710}},
+			expected: map[string]float64{FieldPowerOnHours: 10000},
+		},
+		{
+			name:     "power on minutes by attribute name",
+			model:    "Generic HDD",
+			attrs:    []Attribute{{ID: 9, Name: "Power_On_Minutes", Raw: 600}},
+			expected: map[string]float64{FieldPowerOnHours: 10},
+		},
+		{
+			name:     "maxtor power on minutes by model",
+			model:    "Maxtor 6Y080L0",
+			attrs:    []Attribute{{ID: 9, Name: "Power_On_Hours", Raw: 1200}},
+			expected: map[string]float64{FieldPowerOnHours: 20},
+		},
+		{
+			name:  "error counters",
+			model: "Generic HDD",
+			attrs: []Attribute{
+				{ID: 5, Name: "Reallocated_Sector_Ct", Raw: 8},
+				{ID: 197, Name: "Current_Pending_Sector", Raw: 2},
+				{ID: 198, Name: "Offline_Uncorrectable", Raw: 1},
+			},
+			expected: map[string]float64{FieldReallocatedSectors: 8, FieldPendingSectors: 2, FieldUncorrectableErrors: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := db.Decode(tt.model, tt.attrs, 512)
+			for field, expected := range tt.expected {
+				if got, ok := values[field]; !ok || got != expected {
+					t.Errorf("Expected %s = %v, got %v (present=%v)", field, expected, got, ok)
+				}
+			}
+			for _, field := range tt.absent {
+				if got, ok := values[field]; ok {
+					t.Errorf("Expected %s to be absent, got %v", field, got)
+				}
+			}
+		})
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	db, err := New(config.DriveDBConfig{
+		Defaults: []config.AttributeConfig{
+			{ID: 5, Field: FieldIgnore},
+		},
+		Drives: []config.DriveConfig{
+			{
+				Name:  "Custom Samsung",
+				Model: "^Samsung SSD 870",
+				Attributes: []config.AttributeConfig{
+					{ID: 241, Field: FieldBytesWritten, Unit: "gib"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	if got := db.Match("Samsung SSD 870 EVO 1TB"); got != "Custom Samsung" {
+		t.Errorf("Expected configured entry to take precedence, got %q", got)
+	}
+	if got := db.Match("Samsung SSD 860 PRO"); got != "Samsung SSDs" {
+		t.Errorf("Expected built-in entry for other models, got %q", got)
+	}
+
+	values := db.Decode("Samsung SSD 870 EVO 1TB", []Attribute{
+		{ID: 5, Name: "Reallocated_Sector_Ct", Raw: 3},
+		{ID: 177, Name: "Wear_Leveling_Count", Value: 90},
+		{ID: 241, Name: "Total_LBAs_Written", Raw: 2},
+	}, 512)
+
+	if _, ok := values[FieldReallocatedSectors]; ok {
+		t.Error("Expected overridden default to be ignored")
+	}
+	if values[FieldBytesWritten] != 2<<30 {
+		t.Errorf("Expected configured unit to apply, got %v", values[FieldBytesWritten])
+	}
+	if _, ok := values[FieldWear]; ok {
+		t.Error("Expected configured entry to replace the built-in entry entirely")
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		attr config.AttributeConfig
+	}{
+		{"unknown field", config.AttributeConfig{ID: 9, Field: "bogus"}},
+		{"invalid id", config.AttributeConfig{ID: 300, Field: FieldPowerOnHours}},
+		{"invalid source", config.AttributeConfig{ID: 9, Field: FieldPowerOnHours, Source: "worst"}},
+		{"unit not valid for field", config.AttributeConfig{ID: 9, Field: FieldPowerOnHours, Unit: "gib"}},
+		{"wear without life", config.AttributeConfig{ID: 177, Field: FieldWear, Source: "value"}},
+		{"shift too large", config.AttributeConfig{ID: 194, Field: FieldTemperature, Shift: 48}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DriveDBConfig{Defaults: []config.AttributeConfig{tt.attr}}
+			if _, err := New(cfg); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+
+	if _, err := New(config.DriveDBConfig{Drives: []config.DriveConfig{{Name: "bad", Model: "("}}}); err == nil {
+		t.Error("Expected error for invalid model pattern")
+	}
+}
@@ -0,0 +1,113 @@
+package endurance
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+// DefaultRateWindow is the window over which the daily write rate is averaged
+const DefaultRateWindow = 7 * 24 * time.Hour
+
+// Estimation methods, in order of preference
+const (
+	MethodRatedTBW               = "rated_tbw"
+	MethodPercentageUsed         = "percentage_used"
+	MethodPercentageUsedLifetime = "percentage_used_lifetime"
+)
+
+// terabyte is the unit of rated TBW figures in vendor datasheets
+const terabyte = 1e12
+
+// drive associates a model pattern with its rated endurance
+type drive struct {
+	pattern    *regexp.Regexp
+	ratedBytes int64
+}
+
+// Model estimates SSD write endurance
+type Model struct {
+	drives []drive
+	window time.Duration
+}
+
+// New creates an endurance model from configuration
+func New(cfg config.EnduranceConfig) (*Model, error) {
+	m := &Model{window: DefaultRateWindow}
+
+	if cfg.RateWindow != "" {
+		window, err := config.ParseDuration(cfg.RateWindow)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid rate_window %q", cfg.RateWindow)
+		}
+		m.window = window
+	}
+
+	for _, d := range cfg.Drives {
+		pattern, err := regexp.Compile(d.Model)
+		if err != nil {
+			return nil, fmt.Errorf("invalid drive model pattern %q: %w", d.Model, err)
+		}
+		if d.RatedTBW <= 0 {
+			return nil, fmt.Errorf("drive model %q rated_tbw must be positive", d.Model)
+		}
+		m.drives = append(m.drives, drive{pattern: pattern, ratedBytes: int64(d.RatedTBW * terabyte)})
+	}
+
+	return m, nil
+}
+
+// RateWindow returns the window over which the daily write rate should be measured
+func (m *Model) RateWindow() time.Duration {
+	return m.window
+}
+
+// RatedBytes returns the configured rated write endurance of a disk model, or 0 if unknown
+func (m *Model) RatedBytes(model string) int64 {
+	for _, d := range m.drives {
+		if d.pattern.MatchString(model) {
+			return d.ratedBytes
+		}
+	}
+	return 0
+}
+
+// Estimate computes the endurance of a disk given its average host bytes written
+// per day. It returns nil for disks that report neither bytes written nor wear.
+func (m *Model) Estimate(disk types.DiskInfo, writeRate float64, haveRate bool) *types.EnduranceInfo {
+	used := max(disk.PercentageUsed, disk.WearLeveling)
+	if disk.BytesWritten == 0 && used == 0 {
+		return nil
+	}
+
+	info := &types.EnduranceInfo{
+		RatedBytes: m.RatedBytes(disk.Model),
+	}
+	if haveRate && disk.BytesWritten > 0 {
+		info.WriteRate = writeRate
+		info.HasWriteRate = true
+	}
+
+	switch {
+	case info.RatedBytes > 0 && info.HasWriteRate && writeRate > 0:
+		// Remaining rated bytes at the current write rate
+		remaining := max(info.RatedBytes-disk.BytesWritten, 0)
+		info.RemainingDays = float64(remaining) / writeRate
+		info.Method = MethodRatedTBW
+	case used > 0 && info.HasWriteRate && writeRate > 0:
+		// Bytes written per percent of wear, extrapolated at the current write rate
+		bytesPerPercent := float64(disk.BytesWritten) / float64(used)
+		info.RemainingDays = max(float64(100-used), 0) * bytesPerPercent / writeRate
+		info.Method = MethodPercentageUsed
+	case used > 0 && disk.PowerOnHours > 0:
+		// Lifetime average wear per powered-on day
+		daysPerPercent := float64(disk.PowerOnHours) / 24 / float64(used)
+		info.RemainingDays = max(float64(100-used), 0) * daysPerPercent
+		info.Method = MethodPercentageUsedLifetime
+	}
+
+	return info
+}
@@ -0,0 +1,147 @@
+package endurance
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestEstimate(t *testing.T) {
+	model, err := New(config.EnduranceConfig{
+		Drives: []config.EnduranceDriveConfig{
+			{Model: "^Samsung SSD 870 EVO 1TB$", RatedTBW: 600},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		disk           types.DiskInfo
+		writeRate      float64
+		haveRate       bool
+		expectNil      bool
+		expectedMethod string
+		expectedDays   float64
+	}{
+		{
+			name:      "hard disk without wear data",
+			disk:      types.DiskInfo{Model: "WDC WD40EFRX", PowerOnHours: 1000},
+			expectNil: true,
+		},
+		{
+			name:           "rated tbw at current write rate",
+			disk:           types.DiskInfo{Model: "Samsung SSD 870 EVO 1TB", BytesWritten: 100e12, WearLeveling: 10},
+			writeRate:      50e9,
+			haveRate:       true,
+			expectedMethod: MethodRatedTBW,
+			expectedDays:   500e12 / 50e9,
+		},
+		{
+			name:           "rated tbw already exceeded",
+			disk:           types.DiskInfo{Model: "Samsung SSD 870 EVO 1TB", BytesWritten: 700e12},
+			writeRate:      50e9,
+			haveRate:       true,
+			expectedMethod: MethodRatedTBW,
+			expectedDays:   0,
+		},
+		{
+			name:           "percentage used calibrated by bytes written",
+			disk:           types.DiskInfo{Model: "Unknown NVMe", BytesWritten: 20e12, PercentageUsed: 10},
+			writeRate:      100e9,
+			haveRate:       true,
+			expectedMethod: MethodPercentageUsed,
+			expectedDays:   90 * 2e12 / 100e9,
+		},
+		{
+			name:           "rated tbw without history falls back to lifetime wear",
+			disk:           types.DiskInfo{Model: "Samsung SSD 870 EVO 1TB", BytesWritten: 100e12, WearLeveling: 20, PowerOnHours: 24 * 400},
+			expectedMethod: MethodPercentageUsedLifetime,
+			expectedDays:   80 * 20,
+		},
+		{
+			name:           "idle disk falls back to lifetime wear",
+			disk:           types.DiskInfo{Model: "Unknown NVMe", BytesWritten: 20e12, PercentageUsed: 5, PowerOnHours: 24 * 100},
+			writeRate:      0,
+			haveRate:       true,
+			expectedMethod: MethodPercentageUsedLifetime,
+			expectedDays:   95 * 20,
+		},
+		{
+			name:           "no wear and unknown model gives no estimate",
+			disk:           types.DiskInfo{Model: "Unknown NVMe", BytesWritten: 1e12},
+			writeRate:      1e9,
+			haveRate:       true,
+			expectedMethod: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := model.Estimate(tt.disk, tt.writeRate, tt.haveRate)
+			if tt.expectNil {
+				if result != nil {
+					t.Errorf("Expected no estimate, got %+v", result)
+				}
+				return
+			}
+			if result == nil {
+				t.Fatal("Expected estimate, got nil")
+			}
+			if result.Method != tt.expectedMethod {
+				t.Errorf("Expected method %q, got %q", tt.expectedMethod, result.Method)
+			}
+			if !approxEqual(result.RemainingDays, tt.expectedDays) {
+				t.Errorf("Expected %v remaining days, got %v", tt.expectedDays, result.RemainingDays)
+			}
+		})
+	}
+}
+
+func TestRatedBytes(t *testing.T) {
+	model, err := New(config.EnduranceConfig{
+		Drives: []config.EnduranceDriveConfig{
+			{Model: "^INTEL SSDSC2KB", RatedTBW: 1.5},
+			{Model: "INTEL", RatedTBW: 1000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	if got := model.RatedBytes("INTEL SSDSC2KB480G8"); got != 1_500_000_000_000 {
+		t.Errorf("Expected first matching pattern to win, got %d", got)
+	}
+	if got := model.RatedBytes("Samsung SSD 980"); got != 0 {
+		t.Errorf("Expected unknown model to have no rating, got %d", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	model, err := New(config.EnduranceConfig{RateWindow: "30d"})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if model.RateWindow() != 30*24*time.Hour {
+		t.Errorf("Expected 30d rate window, got %v", model.RateWindow())
+	}
+
+	invalid := []config.EnduranceConfig{
+		{RateWindow: "weekly"},
+		{Drives: []config.EnduranceDriveConfig{{Model: "(", RatedTBW: 100}}},
+		{Drives: []config.EnduranceDriveConfig{{Model: "EVO", RatedTBW: 0}}},
+	}
+	for _, cfg := range invalid {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("Expected error for %+v", cfg)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Package events detects changes of disks and RAID arrays between collections
+// and delivers them as typed events to a set of sinks.
+package events
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/maintenance"
+	"disk-health-exporter/internal/state"
+	"disk-health-exporter/internal/utils"
+	"disk-health-exporter/pkg/types"
+)
+
+// Event types
+const (
+	TypeDiskAdded                   = "disk_added"
+	TypeDiskRemoved                 = "disk_removed"
+	TypeHealthChanged               = "health_changed"
+	TypeRAIDStateChanged            = "raid_state_changed"
+	TypeSpareActivated              = "spare_activated"
+	TypeRebuildStarted              = "rebuild_started"
+	TypeRebuildFinished             = "rebuild_finished"
+	TypeBatteryStateChanged         = "battery_state_changed"
+	TypeReallocatedSectorsIncreased = "reallocated_sectors_increased"
+	TypeSelfTestStarted             = "self_test_started"
+	TypeSelfTestCompleted           = "self_test_completed"
+)
+
+// Types lists every event type
+var Types = []string{
+	TypeDiskAdded,
+	TypeDiskRemoved,
+	TypeHealthChanged,
+	TypeRAIDStateChanged,
+	TypeSpareActivated,
+	TypeRebuildStarted,
+	TypeRebuildFinished,
+	TypeBatteryStateChanged,
+	TypeReallocatedSectorsIncreased,
+	TypeSelfTestStarted,
+	TypeSelfTestCompleted,
+}
+
+// healthStatusNames names the health statuses exported by disk_health_status
+var healthStatusNames = map[types.HealthStatus]string{
+	types.HealthStatusUnknown:  "unknown",
+	types.HealthStatusOK:       "ok",
+	types.HealthStatusWarning:  "warning",
+	types.HealthStatusCritical: "critical",
+}
+
+// Event is a change of a disk or RAID array between two collections. Disk
+// events carry the disk identity, array and battery events the array identity.
+type Event struct {
+	ID     uint64    `json:"id"` // Increasing sequence number, assigned when the event is buffered
+	Time   time.Time `json:"time"`
+	Type   string    `json:"type"`
+	Field  string    `json:"field,omitempty"` // Changed property (e.g. "health_status", "raid_role", "learn_cycle")
+	Before string    `json:"before,omitempty"`
+	After  string    `json:"after,omitempty"`
+
+	Device     string `json:"device,omitempty"`
+	Serial     string `json:"serial,omitempty"`
+	Model      string `json:"model,omitempty"`
+	Location   string `json:"location,omitempty"`
+	ArrayID    string `json:"array_id,omitempty"`
+	Controller string `json:"controller,omitempty"`
+}
+
+// Detector compares each collection with the previous one. The first
+// collection only sets the baseline, so a restart does not report every disk
+// as added.
+type Detector struct {
+	mu     sync.Mutex
+	primed bool
+	disks  map[string]types.DiskInfo // Keyed by state.Key
+	raids  map[string]types.RAIDInfo // Keyed by maintenance.ArrayKey
+}
+
+// NewDetector creates a detector without a baseline
+func NewDetector() *Detector {
+	return &Detector{}
+}
+
+// Update returns the events between the previous collection and this one
+func (d *Detector) Update(disks []types.DiskInfo, raids []types.RAIDInfo, now time.Time) []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	currentDisks := make(map[string]types.DiskInfo, len(disks))
+	for _, disk := range disks {
+		currentDisks[state.Key(disk)] = disk
+	}
+	currentRAIDs := make(map[string]types.RAIDInfo, len(raids))
+	for _, raid := range raids {
+		currentRAIDs[maintenance.ArrayKey(raid)] = raid
+	}
+
+	var events []Event
+	if d.primed {
+		// Walk the collections in their reported order for a stable event order
+		for _, disk := range disks {
+			previous, seen := d.disks[state.Key(disk)]
+			if !seen {
+				events = append(events, diskEvent(now, TypeDiskAdded, disk, "health", "", disk.Health))
+				continue
+			}
+			events = append(events, diskChanges(previous, disk, now)...)
+		}
+		var removed []string
+		for key := range d.disks {
+			if _, present := currentDisks[key]; !present {
+				removed = append(removed, key)
+			}
+		}
+		sort.Strings(removed)
+		for _, key := range removed {
+			previous := d.disks[key]
+			events = append(events, diskEvent(now, TypeDiskRemoved, previous, "health", previous.Health, ""))
+		}
+		for _, raid := range raids {
+			if previous, seen := d.raids[maintenance.ArrayKey(raid)]; seen {
+				events = append(events, raidChanges(previous, raid, now)...)
+			}
+		}
+	}
+
+	d.primed = true
+	d.disks = currentDisks
+	d.raids = currentRAIDs
+	return events
+}
+
+// diskChanges returns the events between two reports of the same disk
+func diskChanges(previous, current types.DiskInfo, now time.Time) []Event {
+	var events []Event
+
+	// The health as exported by disk_health_status, so rewording such as
+	// "Online, Spun Up" to "Online, Spun Down" is not a change, while an
+	// escalation by threshold rules of a disk the tool reports OK is
+	before := types.HealthStatus(utils.GetDiskHealthStatusValue(previous))
+	after := types.HealthStatus(utils.GetDiskHealthStatusValue(current))
+	if before != after {
+		events = append(events, diskEvent(now, TypeHealthChanged, current, "health_status", healthStatusNames[before], healthStatusNames[after]))
+	}
+
+	if previous.RaidRole != current.RaidRole {
+		if isSpare(previous.RaidRole) && (current.RaidRole == "active" || current.RaidRole == "rebuilding") {
+			events = append(events, diskEvent(now, TypeSpareActivated, current, "raid_role", previous.RaidRole, current.RaidRole))
+		}
+		if current.RaidRole == "rebuilding" {
+			events = append(events, diskEvent(now, TypeRebuildStarted, current, "raid_role", previous.RaidRole, current.RaidRole))
+		}
+		if previous.RaidRole == "rebuilding" {
+			events = append(events, diskEvent(now, TypeRebuildFinished, current, "raid_role", previous.RaidRole, current.RaidRole))
+		}
+	}
+
+	// Only growth counts; a lower count means the drive was replaced behind the same identity
+	if current.ReallocatedSectors > previous.ReallocatedSectors {
+		events = append(events, diskEvent(now, TypeReallocatedSectorsIncreased, current, "reallocated_sectors",
+			strconv.FormatInt(previous.ReallocatedSectors, 10), strconv.FormatInt(current.ReallocatedSectors, 10)))
+	}
+
+	if previous.SelfTests != nil && current.SelfTests != nil {
+		events = append(events, selfTestChanges(previous, current, now)...)
+	}
+
+	return events
+}
+
+// selfTestChanges returns the self-tests started and completed between two
+// reports of the same disk. A test completed when the latest log entry of its
+// type changed.
+func selfTestChanges(previous, current types.DiskInfo, now time.Time) []Event {
+	var events []Event
+
+	if !previous.SelfTests.Running && current.SelfTests.Running {
+		after := current.SelfTests.RunningType
+		if after == "" {
+			after = "running"
+		}
+		events = append(events, diskEvent(now, TypeSelfTestStarted, current, "self_test", "", after))
+	}
+
+	for _, test := range current.SelfTests.Latest {
+		before := ""
+		if i := slices.IndexFunc(previous.SelfTests.Latest, func(old types.SelfTestEntry) bool { return old.Type == test.Type }); i >= 0 {
+			old := previous.SelfTests.Latest[i]
+			if old.PowerOnHours == test.PowerOnHours && old.Result == test.Result {
+				continue
+			}
+			before = old.Result.String()
+		}
+		events = append(events, diskEvent(now, TypeSelfTestCompleted, current, "self_test_"+test.Type, before, test.Result.String()))
+	}
+
+	return events
+}
+
+// raidChanges returns the events between two reports of the same array
+func raidChanges(previous, current types.RAIDInfo, now time.Time) []Event {
+	var events []Event
+
+	if previous.State != current.State {
+		events = append(events, raidEvent(now, TypeRAIDStateChanged, current, "state", previous.State, current.State))
+	}
+
+	wasRebuilding, rebuilding := previous.Rebuilding, current.Rebuilding
+	if !wasRebuilding && rebuilding {
+		events = append(events, raidEvent(now, TypeRebuildStarted, current, "state", previous.State, current.State))
+	} else if wasRebuilding && !rebuilding {
+		events = append(events, raidEvent(now, TypeRebuildFinished, current, "state", previous.State, current.State))
+	}
+
+	if previous.Battery != nil && current.Battery != nil {
+		before, after := previous.Battery, current.Battery
+		changes := []struct {
+			field         string
+			before, after string
+		}{
+			{"state", before.State, after.State},
+			{"learn_cycle", activeString(before.LearnCycleActive), activeString(after.LearnCycleActive)},
+			{"replacement_required", strconv.FormatBool(before.ReplacementRequired), strconv.FormatBool(after.ReplacementRequired)},
+			{"missing", strconv.FormatBool(before.BatteryMissing), strconv.FormatBool(after.BatteryMissing)},
+		}
+		for _, change := range changes {
+			if change.before != change.after {
+				events = append(events, raidEvent(now, TypeBatteryStateChanged, current, change.field, change.before, change.after))
+			}
+		}
+	}
+
+	return events
+}
+
+// diskEvent creates an event identifying a disk
+func diskEvent(now time.Time, eventType string, disk types.DiskInfo, field, before, after string) Event {
+	return Event{
+		Time:     now,
+		Type:     eventType,
+		Field:    field,
+		Before:   before,
+		After:    after,
+		Device:   disk.Device,
+		Serial:   disk.Serial,
+		Model:    disk.Model,
+		Location: disk.Location,
+		ArrayID:  disk.RaidArrayID,
+	}
+}
+
+// raidEvent creates an event identifying an array
+func raidEvent(now time.Time, eventType string, raid types.RAIDInfo, field, before, after string) Event {
+	return Event{
+		Time:       now,
+		Type:       eventType,
+		Field:      field,
+		Before:     before,
+		After:      after,
+		ArrayID:    raid.ArrayID,
+		Controller: raid.Controller,
+	}
+}
+
+// isSpare reports whether a RAID role is one of the spare roles
+func isSpare(role string) bool {
+	switch role {
+	case "spare", "hot_spare", "commissioned_spare", "emergency_spare":
+		return true
+	}
+	return false
+}
+
+// activeString describes a learn cycle state
+func activeString(active bool) string {
+	if active {
+		return "active"
+	}
+	return "inactive"
+}
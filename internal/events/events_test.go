@@ -0,0 +1,246 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"disk-health-exporter/pkg/types"
+)
+
+var testTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// eventTypes returns the types of events in order
+func eventTypes(events []Event) []string {
+	var result []string
+	for _, event := range events {
+		result = append(result, event.Type)
+	}
+	return result
+}
+
+// findEvent returns the first event of a type with a field
+func findEvent(t *testing.T, events []Event, eventType, field string) Event {
+	t.Helper()
+	for _, event := range events {
+		if event.Type == eventType && event.Field == field {
+			return event
+		}
+	}
+	t.Fatalf("No %s event for %s in %v", eventType, field, eventTypes(events))
+	return Event{}
+}
+
+func TestDetectorFirstUpdateIsBaseline(t *testing.T) {
+	detector := NewDetector()
+	disks := []types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Health: "OK", HealthStatus: types.HealthStatusOK}}
+
+	if events := detector.Update(disks, nil, testTime); len(events) != 0 {
+		t.Errorf("Expected no events for the baseline, got %v", eventTypes(events))
+	}
+	if events := detector.Update(disks, nil, testTime); len(events) != 0 {
+		t.Errorf("Expected no events without changes, got %v", eventTypes(events))
+	}
+}
+
+func TestDetectorDiskEvents(t *testing.T) {
+	detector := NewDetector()
+	detector.Update([]types.DiskInfo{
+		{Device: "/dev/sda", Serial: "S1", Model: "ST4000", Health: "OK", HealthStatus: types.HealthStatusOK, ReallocatedSectors: 8},
+		{Device: "/dev/sdb", Serial: "S2", Health: "OK", HealthStatus: types.HealthStatusOK},
+		{Device: "raid-enc32-slot4", Serial: "S3", Health: "Hotspare", HealthStatus: types.HealthStatusOK, RaidRole: "hot_spare"},
+	}, nil, testTime)
+
+	events := detector.Update([]types.DiskInfo{
+		// Renamed device with the same serial is the same disk
+		{Device: "/dev/sdc", Serial: "S1", Model: "ST4000", Health: "FAILED", HealthStatus: types.HealthStatusCritical, ReallocatedSectors: 24},
+		{Device: "raid-enc32-slot4", Serial: "S3", Health: "Rebuild", HealthStatus: types.HealthStatusWarning, RaidRole: "rebuilding", RaidArrayID: "0"},
+		{Device: "/dev/sdd", Serial: "S4", Health: "OK", HealthStatus: types.HealthStatusOK},
+	}, nil, testTime)
+
+	health := findEvent(t, events, TypeHealthChanged, "health_status")
+	if health.Serial != "S1" || health.Device != "/dev/sdc" || health.Model != "ST4000" || health.Before != "ok" || health.After != "critical" {
+		t.Errorf("Unexpected health event %+v", health)
+	}
+
+	reallocated := findEvent(t, events, TypeReallocatedSectorsIncreased, "reallocated_sectors")
+	if reallocated.Before != "8" || reallocated.After != "24" {
+		t.Errorf("Unexpected reallocated sectors event %+v", reallocated)
+	}
+
+	spare := findEvent(t, events, TypeSpareActivated, "raid_role")
+	if spare.Before != "hot_spare" || spare.After != "rebuilding" || spare.ArrayID != "0" {
+		t.Errorf("Unexpected spare event %+v", spare)
+	}
+	findEvent(t, events, TypeRebuildStarted, "raid_role")
+
+	added := findEvent(t, events, TypeDiskAdded, "health")
+	if added.Serial != "S4" || added.After != "OK" {
+		t.Errorf("Unexpected added event %+v", added)
+	}
+	removed := findEvent(t, events, TypeDiskRemoved, "health")
+	if removed.Serial != "S2" || removed.Before != "OK" {
+		t.Errorf("Unexpected removed event %+v", removed)
+	}
+
+	for _, event := range events {
+		if !event.Time.Equal(testTime) {
+			t.Errorf("Expected the collection time on %s", event.Type)
+		}
+	}
+
+	// The rebuild finishes and the drive becomes an active member
+	events = detector.Update([]types.DiskInfo{
+		{Device: "/dev/sdc", Serial: "S1", Model: "ST4000", Health: "FAILED", HealthStatus: types.HealthStatusCritical, ReallocatedSectors: 24},
+		{Device: "raid-enc32-slot4", Serial: "S3", Health: "Online", HealthStatus: types.HealthStatusOK, RaidRole: "active", RaidArrayID: "0"},
+		{Device: "/dev/sdd", Serial: "S4", Health: "OK", HealthStatus: types.HealthStatusOK},
+	}, nil, testTime)
+	finished := findEvent(t, events, TypeRebuildFinished, "raid_role")
+	if finished.Before != "rebuilding" || finished.After != "active" {
+		t.Errorf("Unexpected rebuild finished event %+v", finished)
+	}
+	for _, event := range events {
+		if event.Type == TypeSpareActivated {
+			t.Errorf("Expected no spare activation from rebuilding to active")
+		}
+	}
+}
+
+func TestDetectorHealthStatus(t *testing.T) {
+	detector := NewDetector()
+	detector.Update([]types.DiskInfo{
+		{Device: "raid-enc32-slot0", Serial: "S1", Health: "Online, Spun Up", HealthStatus: types.HealthStatusOK},
+		{Device: "/dev/sda", Serial: "S2", Health: "OK", HealthStatus: types.HealthStatusOK},
+	}, nil, testTime)
+
+	events := detector.Update([]types.DiskInfo{
+		// Reworded by the tool, still OK
+		{Device: "raid-enc32-slot0", Serial: "S1", Health: "Online, Spun Down", HealthStatus: types.HealthStatusOK},
+		// Healthy by the tool, but over a threshold rule
+		{Device: "/dev/sda", Serial: "S2", Health: "OK", HealthStatus: types.HealthStatusOK, RuleAlerts: []types.RuleAlert{
+			{Rule: "temperature", Severity: types.RuleSeverityCritical},
+		}},
+	}, nil, testTime)
+	if len(events) != 1 {
+		t.Fatalf("Expected only the rule escalation, got %v", eventTypes(events))
+	}
+	if health := findEvent(t, events, TypeHealthChanged, "health_status"); health.Serial != "S2" || health.Before != "ok" || health.After != "critical" {
+		t.Errorf("Unexpected health event %+v", health)
+	}
+}
+
+func TestDetectorReallocatedSectorsReset(t *testing.T) {
+	detector := NewDetector()
+	detector.Update([]types.DiskInfo{{Device: "/dev/sda", Serial: "S1", ReallocatedSectors: 10}}, nil, testTime)
+
+	events := detector.Update([]types.DiskInfo{{Device: "/dev/sda", Serial: "S1", ReallocatedSectors: 0}}, nil, testTime)
+	if len(events) != 0 {
+		t.Errorf("Expected no event for a lower count, got %v", eventTypes(events))
+	}
+}
+
+func TestDetectorSelfTestEvents(t *testing.T) {
+	short := types.SelfTestEntry{Type: types.SelfTestShort, Result: types.SelfTestResultPassed, PowerOnHours: 100}
+	long := types.SelfTestEntry{Type: types.SelfTestLong, Result: types.SelfTestResultPassed, PowerOnHours: 50}
+
+	detector := NewDetector()
+	detector.Update([]types.DiskInfo{
+		{Device: "/dev/sda", Serial: "S1", SelfTests: &types.SelfTestInfo{Latest: []types.SelfTestEntry{short, long}}},
+		{Device: "/dev/sdb", Serial: "S2"},
+	}, nil, testTime)
+
+	events := detector.Update([]types.DiskInfo{
+		{Device: "/dev/sda", Serial: "S1", SelfTests: &types.SelfTestInfo{Running: true, RunningType: types.SelfTestShort, Latest: []types.SelfTestEntry{short, long}}},
+		// A log reported for the first time is a baseline
+		{Device: "/dev/sdb", Serial: "S2", SelfTests: &types.SelfTestInfo{Latest: []types.SelfTestEntry{short}}},
+	}, nil, testTime)
+	if started := findEvent(t, events, TypeSelfTestStarted, "self_test"); started.Serial != "S1" || started.After != "short" {
+		t.Errorf("Unexpected started event %+v", started)
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected only the started event, got %v", eventTypes(events))
+	}
+
+	failed := types.SelfTestEntry{Type: types.SelfTestShort, Result: types.SelfTestResultFailed, PowerOnHours: 124, FirstErrorLBA: 2048}
+	events = detector.Update([]types.DiskInfo{
+		{Device: "/dev/sda", Serial: "S1", SelfTests: &types.SelfTestInfo{Latest: []types.SelfTestEntry{failed, long}}},
+		{Device: "/dev/sdb", Serial: "S2", SelfTests: &types.SelfTestInfo{Latest: []types.SelfTestEntry{short}}},
+	}, nil, testTime)
+	completed := findEvent(t, events, TypeSelfTestCompleted, "self_test_short")
+	if completed.Serial != "S1" || completed.Before != "passed" || completed.After != "failed" {
+		t.Errorf("Unexpected completed event %+v", completed)
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected only the completed short test, got %v", eventTypes(events))
+	}
+}
+
+func TestDetectorRAIDEvents(t *testing.T) {
+	detector := NewDetector()
+	detector.Update(nil, []types.RAIDInfo{
+		{ArrayID: "0", Controller: "MegaRAID SAS 9361-8i", State: "Optimal", Battery: &types.RAIDBatteryInfo{State: "Optimal"}},
+		{ArrayID: "tank", Controller: "zfs", State: "ONLINE"},
+	}, testTime)
+
+	events := detector.Update(nil, []types.RAIDInfo{
+		{ArrayID: "0", Controller: "MegaRAID SAS 9361-8i", State: "Degraded", Battery: &types.RAIDBatteryInfo{State: "Learning", LearnCycleActive: true}},
+		{ArrayID: "tank", Controller: "zfs", State: "DEGRADED", RebuildProgress: 12, Rebuilding: true},
+	}, testTime)
+
+	state := findEvent(t, events, TypeRAIDStateChanged, "state")
+	if state.ArrayID != "0" || state.Controller != "MegaRAID SAS 9361-8i" || state.Before != "Optimal" || state.After != "Degraded" {
+		t.Errorf("Unexpected RAID state event %+v", state)
+	}
+
+	battery := findEvent(t, events, TypeBatteryStateChanged, "state")
+	if battery.Before != "Optimal" || battery.After != "Learning" {
+		t.Errorf("Unexpected battery state event %+v", battery)
+	}
+	learn := findEvent(t, events, TypeBatteryStateChanged, "learn_cycle")
+	if learn.Before != "inactive" || learn.After != "active" {
+		t.Errorf("Unexpected learn cycle event %+v", learn)
+	}
+
+	rebuild := findEvent(t, events, TypeRebuildStarted, "state")
+	if rebuild.ArrayID != "tank" {
+		t.Errorf("Expected the resilver of tank, got %+v", rebuild)
+	}
+
+	events = detector.Update(nil, []types.RAIDInfo{
+		{ArrayID: "0", Controller: "MegaRAID SAS 9361-8i", State: "Degraded", Battery: &types.RAIDBatteryInfo{State: "Learning", LearnCycleActive: true}},
+		{ArrayID: "tank", Controller: "zfs", State: "ONLINE"},
+	}, testTime)
+	if finished := findEvent(t, events, TypeRebuildFinished, "state"); finished.After != "ONLINE" {
+		t.Errorf("Unexpected rebuild finished event %+v", finished)
+	}
+}
+
+func TestStreamBuffer(t *testing.T) {
+	stream := NewStreamWithSinks(3)
+	defer stream.Close()
+
+	statuses := map[string]types.HealthStatus{"OK": types.HealthStatusOK, "Warning": types.HealthStatusWarning, "FAILED": types.HealthStatusCritical}
+	disks := func(health string) []types.DiskInfo {
+		return []types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Health: health, HealthStatus: statuses[health]}}
+	}
+	stream.Observe(disks("OK"), nil, testTime)
+	for _, health := range []string{"Warning", "FAILED", "OK", "Warning"} {
+		stream.Observe(disks(health), nil, testTime)
+	}
+
+	events := stream.Events(0, "")
+	if len(events) != 3 {
+		t.Fatalf("Expected the buffer to keep 3 events, got %d", len(events))
+	}
+	if events[0].ID != 2 || events[2].ID != 4 || events[2].After != "warning" {
+		t.Errorf("Expected events 2 to 4 oldest first, got %+v", events)
+	}
+	if since := stream.Events(3, ""); len(since) != 1 || since[0].ID != 4 {
+		t.Errorf("Expected only event 4 after 3, got %+v", since)
+	}
+	if filtered := stream.Events(0, TypeDiskAdded); len(filtered) != 0 {
+		t.Errorf("Expected no disk_added events, got %+v", filtered)
+	}
+	if counts := stream.Counts(); counts[TypeHealthChanged] != 4 {
+		t.Errorf("Expected 4 health changes counted, got %v", counts)
+	}
+}
@@ -0,0 +1,151 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookTimeout limits a webhook request when no timeout is configured
+const DefaultWebhookTimeout = 5 * time.Second
+
+// Sink receives the events of each collection
+type Sink interface {
+	// Name identifies the sink in logs and metric labels
+	Name() string
+
+	// Send delivers the events of one collection
+	Send(events []Event) error
+}
+
+// LogSink logs every event
+type LogSink struct{}
+
+// Name returns the sink name
+func (LogSink) Name() string {
+	return "log"
+}
+
+// Send logs the events
+func (LogSink) Send(events []Event) error {
+	for _, event := range events {
+		attrs := []any{"type", event.Type}
+		for _, field := range []struct{ key, value string }{
+			{"device", event.Device},
+			{"serial", event.Serial},
+			{"array_id", event.ArrayID},
+			{"controller", event.Controller},
+			{"field", event.Field},
+			{"before", event.Before},
+			{"after", event.After},
+		} {
+			if field.value != "" {
+				attrs = append(attrs, field.key, field.value)
+			}
+		}
+		slog.Info("Disk event", attrs...)
+	}
+	return nil
+}
+
+// FileSink appends events to a file as JSON lines
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSink creates a sink appending to path. The file is opened for every
+// batch, so it can be rotated by moving it away.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Name returns the sink name
+func (f *FileSink) Name() string {
+	return "file"
+}
+
+// Send appends the events to the file
+func (f *FileSink) Send(events []Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// WebhookSink posts the events of each collection to a URL
+type WebhookSink struct {
+	url    string
+	name   string
+	client *http.Client
+}
+
+// webhookPayload is the body posted to a webhook
+type webhookPayload struct {
+	Events []Event `json:"events"`
+}
+
+// NewWebhookSink creates a sink posting to rawURL
+func NewWebhookSink(rawURL string, timeout time.Duration) (*WebhookSink, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", rawURL)
+	}
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	return &WebhookSink{
+		url: rawURL,
+		// The host alone, as paths and queries of webhook URLs often hold tokens
+		name:   "webhook:" + parsed.Host,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name returns the sink name
+func (w *WebhookSink) Name() string {
+	return w.name
+}
+
+// Send posts the events as one JSON document
+func (w *WebhookSink) Send(events []Event) error {
+	body, err := json.Marshal(webhookPayload{Events: events})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// Drop the URL the client includes in its errors
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
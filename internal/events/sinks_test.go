@@ -0,0 +1,173 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+var testEvents = []Event{
+	{ID: 1, Time: testTime, Type: TypeHealthChanged, Field: "health_status", Before: "ok", After: "critical", Device: "/dev/sda", Serial: "S1"},
+	{ID: 2, Time: testTime, Type: TypeRAIDStateChanged, Field: "state", Before: "Optimal", After: "Degraded", ArrayID: "0"},
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFileSink(path)
+
+	if err := sink.Send(testEvents[:1]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := sink.Send(testEvents[1:]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var lines []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, event)
+	}
+	if len(lines) != 2 || lines[0].Serial != "S1" || lines[1].After != "Degraded" {
+		t.Errorf("Expected both events appended, got %+v", lines)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected content type %s", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL+"/hooks/secret-token", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(sink.Name(), "secret-token") {
+		t.Errorf("Expected the sink name to leave out the path, got %s", sink.Name())
+	}
+
+	if err := sink.Send(testEvents); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(received.Events) != 2 || received.Events[0].Type != TypeHealthChanged {
+		t.Errorf("Unexpected payload %+v", received)
+	}
+}
+
+func TestWebhookSinkErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	sink, _ := NewWebhookSink(server.URL+"/hooks/secret-token", 0)
+	if err := sink.Send(testEvents); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected the status in the error, got %v", err)
+	}
+
+	server.Close()
+	if err := sink.Send(testEvents); err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected an error without the URL, got %v", err)
+	}
+
+	for _, url := range []string{"", "ftp://example.com", "http://"} {
+		if _, err := NewWebhookSink(url, 0); err == nil {
+			t.Errorf("Expected an error for %q", url)
+		}
+	}
+}
+
+// recordingSink records the events it receives
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+	err    error
+}
+
+func (r *recordingSink) Name() string { return "recording" }
+
+func (r *recordingSink) Send(events []Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, events...)
+	return r.err
+}
+
+func TestStreamDelivery(t *testing.T) {
+	sink := &recordingSink{}
+	stream := NewStreamWithSinks(10, sink)
+
+	stream.Observe([]types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Health: "OK", HealthStatus: types.HealthStatusOK}}, nil, testTime)
+	stream.Observe([]types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Health: "FAILED", HealthStatus: types.HealthStatusCritical}}, nil, testTime)
+	stream.Close()
+
+	if len(sink.events) != 1 || sink.events[0].ID != 1 || sink.events[0].After != "critical" {
+		t.Errorf("Expected the health change delivered on close, got %+v", sink.events)
+	}
+
+	// Collections after closing are still buffered, but not delivered
+	stream.Observe([]types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Health: "OK", HealthStatus: types.HealthStatusOK}}, nil, testTime)
+	if len(stream.Events(0, "")) != 2 || len(sink.events) != 1 {
+		t.Errorf("Unexpected state after close")
+	}
+}
+
+func TestStreamSinkErrors(t *testing.T) {
+	sink := &recordingSink{err: os.ErrPermission}
+	stream := NewStreamWithSinks(10, sink)
+
+	stream.Observe([]types.DiskInfo{{Device: "/dev/sda", Serial: "S1"}}, nil, testTime)
+	stream.Observe(nil, nil, testTime)
+	stream.Close()
+
+	if errors := stream.SinkErrors(); errors["recording"] != 1 {
+		t.Errorf("Expected one failed delivery, got %v", errors)
+	}
+}
+
+func TestNewStream(t *testing.T) {
+	stream, err := NewStream(config.EventsConfig{
+		Log:  true,
+		File: filepath.Join(t.TempDir(), "events.jsonl"),
+		Webhooks: []config.EventWebhookConfig{
+			{URL: "https://hooks.example.com/services/T000/B000", Timeout: "2s"},
+			{URL: "not a url"},
+			{URL: "https://hooks.example.com/other", Timeout: "soon"},
+		},
+	})
+	defer stream.Close()
+
+	if err == nil {
+		t.Error("Expected errors for the invalid webhooks")
+	}
+	expected := []string{"log", "file", "webhook:hooks.example.com"}
+	if sinks := stream.Sinks(); strings.Join(sinks, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected sinks %v, got %v", expected, sinks)
+	}
+	if cap(stream.buffer) != DefaultBufferSize {
+		t.Errorf("Expected the default buffer size, got %d", cap(stream.buffer))
+	}
+}
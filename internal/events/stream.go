@@ -0,0 +1,212 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+// DefaultBufferSize is the number of events kept in memory when not configured
+const DefaultBufferSize = 1000
+
+// queueSize is the number of collections whose events may wait for delivery
+const queueSize = 64
+
+// Stream detects the events of each collection, keeps the latest in a ring
+// buffer and delivers them to the sinks. Delivery runs in the background, so a
+// slow webhook does not delay the collection.
+type Stream struct {
+	detector *Detector
+	sinks    []Sink
+	queue    chan []Event
+	done     chan struct{}
+
+	mu         sync.Mutex
+	buffer     []Event // Ring buffer of the latest events
+	next       int     // Position of the next event in the buffer
+	lastID     uint64
+	closed     bool
+	counts     map[string]int // Events since startup by type
+	sinkErrors map[string]int // Failed deliveries since startup by sink
+}
+
+// NewStream creates a stream from its configuration. Invalid sinks are
+// reported in the returned error and left out; the stream is usable regardless.
+func NewStream(cfg config.EventsConfig) (*Stream, error) {
+	var sinks []Sink
+	var errs []error
+
+	if cfg.Log {
+		sinks = append(sinks, LogSink{})
+	}
+	if cfg.File != "" {
+		sinks = append(sinks, NewFileSink(cfg.File))
+	}
+	for _, webhook := range cfg.Webhooks {
+		var timeout time.Duration
+		if webhook.Timeout != "" {
+			parsed, err := config.ParseDuration(webhook.Timeout)
+			if err != nil || parsed <= 0 {
+				errs = append(errs, fmt.Errorf("invalid webhook timeout %q", webhook.Timeout))
+				continue
+			}
+			timeout = parsed
+		}
+		sink, err := NewWebhookSink(webhook.URL, timeout)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+
+	size := cfg.BufferSize
+	if size < 0 {
+		errs = append(errs, fmt.Errorf("invalid buffer size %d", size))
+	}
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	return NewStreamWithSinks(size, sinks...), errors.Join(errs...)
+}
+
+// NewStreamWithSinks creates a stream keeping size events and delivering to sinks
+func NewStreamWithSinks(size int, sinks ...Sink) *Stream {
+	s := &Stream{
+		detector:   NewDetector(),
+		sinks:      sinks,
+		queue:      make(chan []Event, queueSize),
+		done:       make(chan struct{}),
+		buffer:     make([]Event, 0, size),
+		counts:     make(map[string]int),
+		sinkErrors: make(map[string]int),
+	}
+	go s.deliver()
+	return s
+}
+
+// Observe detects the events of a collection, buffers them and queues them for
+// delivery. The detected events are returned with their IDs.
+func (s *Stream) Observe(disks []types.DiskInfo, raids []types.RAIDInfo, now time.Time) []Event {
+	events := s.detector.Update(disks, raids, now)
+	if len(events) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range events {
+		s.lastID++
+		events[i].ID = s.lastID
+		s.counts[events[i].Type]++
+		s.add(events[i])
+	}
+
+	if s.closed || len(s.sinks) == 0 {
+		return events
+	}
+	select {
+	case s.queue <- events:
+	default:
+		slog.Warn("Event delivery is falling behind, dropping events", "events", len(events))
+		for _, sink := range s.sinks {
+			s.sinkErrors[sink.Name()]++
+		}
+	}
+	return events
+}
+
+// add stores an event in the ring buffer, overwriting the oldest when full
+func (s *Stream) add(event Event) {
+	if len(s.buffer) < cap(s.buffer) {
+		s.buffer = append(s.buffer, event)
+		return
+	}
+	s.buffer[s.next] = event
+	s.next = (s.next + 1) % len(s.buffer)
+}
+
+// Events returns the buffered events with an ID greater than since, oldest
+// first, optionally only those of one type
+func (s *Stream) Events(since uint64, eventType string) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := []Event{}
+	for i := range s.buffer {
+		event := s.buffer[(s.next+i)%len(s.buffer)]
+		if event.ID > since && (eventType == "" || event.Type == eventType) {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// Counts returns the number of events since startup by type
+func (s *Stream) Counts() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyCounts(s.counts)
+}
+
+// SinkErrors returns the number of failed deliveries since startup by sink
+func (s *Stream) SinkErrors() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyCounts(s.sinkErrors)
+}
+
+// Sinks returns the names of the configured sinks
+func (s *Stream) Sinks() []string {
+	var names []string
+	for _, sink := range s.sinks {
+		names = append(names, sink.Name())
+	}
+	return names
+}
+
+// Close delivers the queued events and stops the stream
+func (s *Stream) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	<-s.done
+}
+
+// deliver sends queued events to every sink until the stream is closed
+func (s *Stream) deliver() {
+	defer close(s.done)
+
+	for events := range s.queue {
+		for _, sink := range s.sinks {
+			if err := sink.Send(events); err != nil {
+				slog.Error("Error delivering events", "sink", sink.Name(), "events", len(events), "err", err)
+				s.mu.Lock()
+				s.sinkErrors[sink.Name()]++
+				s.mu.Unlock()
+			}
+		}
+	}
+}
+
+// copyCounts returns a copy of a count map
+func copyCounts(counts map[string]int) map[string]int {
+	copied := make(map[string]int, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
@@ -0,0 +1,231 @@
+// Package logging configures the structured logger used throughout the
+// exporter. Records carry consistent keys so they can be filtered and joined in
+// a log pipeline:
+//
+//	tool        Tool or plugin that produced the record (e.g. "smartctl")
+//	device      Device path (e.g. "/dev/sda")
+//	serial      Disk serial number
+//	controller  RAID controller ID or name
+//	duration    Run time of a command or collection
+//	err         Error
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Output formats
+const (
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+)
+
+// DefaultRepeatInterval is how long identical warnings and errors are suppressed
+// after being logged
+const DefaultRepeatInterval = time.Hour
+
+// Options configures the logger
+type Options struct {
+	Level  string // debug, info, warn or error
+	Format string // logfmt or json
+
+	// RepeatInterval suppresses warnings and errors identical to one logged less
+	// than the interval ago. Zero logs every record.
+	RepeatInterval time.Duration
+}
+
+// ParseLevel parses a level name
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", level)
+}
+
+// New creates a logger writing to w
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case FormatLogfmt, "":
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected logfmt or json)", opts.Format)
+	}
+
+	if opts.RepeatInterval > 0 {
+		handler = NewRepeatHandler(handler, opts.RepeatInterval)
+	}
+	return slog.New(handler), nil
+}
+
+// Setup creates a logger writing to standard error and makes it the default,
+// which also routes the standard log package through it
+func Setup(opts Options) error {
+	logger, err := New(os.Stderr, opts)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// RepeatHandler drops warnings and errors identical to one passed on less than
+// an interval ago. Records are identical when their level, message and
+// attributes match; durations and times, such as how long a command ran, differ
+// on every run and are left out of the comparison. The first record passed on
+// after suppression carries the number of dropped records in a "repeated" attribute.
+type RepeatHandler struct {
+	next   slog.Handler
+	prefix string // Attributes and groups added with WithAttrs and WithGroup
+	state  *repeatState
+}
+
+// repeatState is shared between a handler and the handlers derived from it
+type repeatState struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	now       func() time.Time
+	seen      map[string]*repeat
+	lastPrune time.Time
+}
+
+// repeat tracks a logged record
+type repeat struct {
+	logged     time.Time
+	suppressed int
+}
+
+// NewRepeatHandler wraps a handler, suppressing repeated warnings and errors
+func NewRepeatHandler(next slog.Handler, interval time.Duration) *RepeatHandler {
+	return &RepeatHandler{
+		next: next,
+		state: &repeatState{
+			interval: interval,
+			now:      time.Now,
+			seen:     make(map[string]*repeat),
+		},
+	}
+}
+
+// Enabled reports whether the wrapped handler handles records at a level
+func (h *RepeatHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle passes a record on unless it repeats a recent warning or error
+func (h *RepeatHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn {
+		return h.next.Handle(ctx, r)
+	}
+
+	suppressed, drop := h.state.check(h.key(r))
+	if drop {
+		return nil
+	}
+	if suppressed > 0 {
+		r = r.Clone()
+		r.AddAttrs(slog.Int("repeated", suppressed))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler adding attributes to every record
+func (h *RepeatHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.prefix)
+	for _, attr := range attrs {
+		writeAttr(&b, attr)
+	}
+	return &RepeatHandler{next: h.next.WithAttrs(attrs), prefix: b.String(), state: h.state}
+}
+
+// WithGroup returns a handler qualifying the attributes of every record
+func (h *RepeatHandler) WithGroup(name string) slog.Handler {
+	return &RepeatHandler{next: h.next.WithGroup(name), prefix: h.prefix + "[" + name + "]", state: h.state}
+}
+
+// key identifies a record for suppression
+func (h *RepeatHandler) key(r slog.Record) string {
+	var b strings.Builder
+	b.WriteString(r.Level.String())
+	b.WriteByte(' ')
+	b.WriteString(r.Message)
+	b.WriteString(h.prefix)
+	r.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&b, attr)
+		return true
+	})
+	return b.String()
+}
+
+// writeAttr appends an attribute to a record key, unless it is a duration or time
+func writeAttr(b *strings.Builder, attr slog.Attr) {
+	switch attr.Value.Resolve().Kind() {
+	case slog.KindDuration, slog.KindTime:
+		return
+	}
+	b.WriteByte(' ')
+	b.WriteString(attr.Key)
+	b.WriteByte('=')
+	b.WriteString(attr.Value.Resolve().String())
+}
+
+// check records a record, returning whether to drop it and otherwise how many
+// identical records were dropped since it was last passed on
+func (s *repeatState) check(key string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	if seen, ok := s.seen[key]; ok {
+		if now.Sub(seen.logged) < s.interval {
+			seen.suppressed++
+			return 0, true
+		}
+		suppressed := seen.suppressed
+		seen.logged = now
+		seen.suppressed = 0
+		return suppressed, false
+	}
+
+	s.seen[key] = &repeat{logged: now}
+	return 0, false
+}
+
+// prune forgets records logged more than an interval ago that have no
+// suppressed repeats left to report, at most once per interval
+func (s *repeatState) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < s.interval {
+		return
+	}
+	s.lastPrune = now
+	for key, seen := range s.seen {
+		if now.Sub(seen.logged) >= s.interval && seen.suppressed == 0 {
+			delete(s.seen, key)
+		}
+	}
+}
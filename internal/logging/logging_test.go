@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected slog.Level
+		valid    bool
+	}{
+		{"debug", slog.LevelDebug, true},
+		{"INFO", slog.LevelInfo, true},
+		{"", slog.LevelInfo, true},
+		{"warn", slog.LevelWarn, true},
+		{"warning", slog.LevelWarn, true},
+		{"error", slog.LevelError, true},
+		{"verbose", 0, false},
+	}
+
+	for _, tt := range tests {
+		level, err := ParseLevel(tt.name)
+		if (err == nil) != tt.valid {
+			t.Errorf("%q: expected valid=%v, got %v", tt.name, tt.valid, err)
+		}
+		if err == nil && level != tt.expected {
+			t.Errorf("%q: expected %s, got %s", tt.name, tt.expected, level)
+		}
+	}
+}
+
+func TestNewLevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, Options{Level: "warn", Format: FormatJSON})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	logger.Info("Collection finished")
+	logger.Warn("Command failed", "tool", "hdparm", "device", "/dev/sda")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the warning, got %q", buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", lines[0], err)
+	}
+	if record["msg"] != "Command failed" || record["tool"] != "hdparm" || record["device"] != "/dev/sda" {
+		t.Errorf("Unexpected record %v", record)
+	}
+
+	buf.Reset()
+	logger, _ = New(&buf, Options{Level: "debug"})
+	logger.Debug("Found disks", "tool", "smartctl", "disks", 2)
+	if !strings.Contains(buf.String(), `level=DEBUG msg="Found disks" tool=smartctl disks=2`) {
+		t.Errorf("Expected a logfmt record, got %q", buf.String())
+	}
+
+	if _, err := New(&buf, Options{Format: "xml"}); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	if _, err := New(&buf, Options{Level: "loud"}); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+func TestRepeatHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewRepeatHandler(slog.NewTextHandler(&buf, nil), time.Hour)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	handler.state.now = func() time.Time { return now }
+	logger := slog.New(handler)
+
+	err := errors.New("exit status 2")
+	for i := 0; i < 5; i++ {
+		logger.Warn("Command failed", "tool", "hdparm", "device", "/dev/sda", "err", err)
+	}
+	logger.Warn("Command failed", "tool", "hdparm", "device", "/dev/sdb", "err", err)
+	logger.Info("Collection finished")
+	logger.Info("Collection finished")
+
+	if count := strings.Count(buf.String(), "device=/dev/sda"); count != 1 {
+		t.Errorf("Expected the repeated warning once, got %d times", count)
+	}
+	if !strings.Contains(buf.String(), "device=/dev/sdb") {
+		t.Error("Expected a warning with different attributes to be logged")
+	}
+	if count := strings.Count(buf.String(), "Collection finished"); count != 2 {
+		t.Errorf("Expected info records not to be suppressed, got %d", count)
+	}
+
+	buf.Reset()
+	now = now.Add(time.Hour)
+	logger.Warn("Command failed", "tool", "hdparm", "device", "/dev/sda", "err", err)
+	if !strings.Contains(buf.String(), "repeated=4") {
+		t.Errorf("Expected the suppressed count after the interval, got %q", buf.String())
+	}
+
+	buf.Reset()
+	for i := 0; i < 3; i++ {
+		logger.Error("Plugin failed", "tool", "appliance", "duration", time.Duration(i+1)*time.Second, "err", err)
+	}
+	if count := strings.Count(buf.String(), "Plugin failed"); count != 1 {
+		t.Errorf("Expected records differing only in duration to be suppressed, got %d", count)
+	}
+
+	buf.Reset()
+	logger.With("tool", "storcli").Error("Command failed")
+	logger.With("tool", "megacli").Error("Command failed")
+	logger.With("tool", "storcli").Error("Command failed")
+	if strings.Count(buf.String(), "Command failed") != 2 {
+		t.Errorf("Expected attributes added with With to distinguish records, got %q", buf.String())
+	}
+}
+
+func TestRepeatHandlerPrune(t *testing.T) {
+	handler := NewRepeatHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), time.Minute)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	handler.state.now = func() time.Time { return now }
+	logger := slog.New(handler)
+
+	logger.Warn("Once", "device", "/dev/sda")
+	logger.Warn("Twice", "device", "/dev/sda")
+	logger.Warn("Twice", "device", "/dev/sda")
+
+	now = now.Add(2 * time.Minute)
+	logger.Warn("Later")
+
+	once := slog.NewRecord(now, slog.LevelWarn, "Once", 0)
+	once.AddAttrs(slog.String("device", "/dev/sda"))
+	if _, ok := handler.state.seen[handler.key(once)]; ok {
+		t.Error("Expected the stale record without repeats to be forgotten")
+	}
+	if len(handler.state.seen) != 2 {
+		t.Errorf("Expected the record with suppressed repeats to be kept, got %d records", len(handler.state.seen))
+	}
+}
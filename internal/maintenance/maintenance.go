@@ -0,0 +1,113 @@
+package maintenance
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/state"
+	"disk-health-exporter/pkg/types"
+)
+
+// Timestamp key prefixes in the state store
+const (
+	patrolReadPrefix       = "patrol_read/"
+	consistencyCheckPrefix = "consistency_check/"
+	scrubPrefix            = "zfs_scrub/"
+)
+
+// Tracker records when hardware RAID patrol reads and consistency checks complete.
+// The RAID tools only report the current state, so completions are detected
+// between collections: a patrol read completed when the controller's iteration
+// counter grew, and a consistency check when a check seen running is no longer
+// reported. A check stopped before it finished is also counted as completed.
+// Completion times are kept in the state store so they survive restarts.
+type Tracker struct {
+	store *state.Store
+
+	mu         sync.Mutex
+	iterations map[string]int64 // Patrol read iterations per controller at the last update
+	running    map[string]bool  // Arrays with a consistency check running at the last update
+}
+
+// New creates a tracker keeping completion times in store
+func New(store *state.Store) *Tracker {
+	return &Tracker{
+		store:      store,
+		iterations: make(map[string]int64),
+		running:    make(map[string]bool),
+	}
+}
+
+// ControllerKey identifies a controller across collections
+func ControllerKey(controller types.RAIDControllerInfo) string {
+	return controller.ToolName + "/" + strconv.Itoa(controller.AdapterID)
+}
+
+// ArrayKey identifies a hardware RAID array across collections
+func ArrayKey(raid types.RAIDInfo) string {
+	return raid.Controller + "/" + raid.ArrayID
+}
+
+// UpdateControllers records patrol reads completed since the previous update
+func (t *Tracker) UpdateControllers(controllers []types.RAIDControllerInfo, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, controller := range controllers {
+		key := ControllerKey(controller)
+		previous, seen := t.iterations[key]
+		// A lower count means the controller counter was reset; it becomes the new baseline
+		if seen && controller.PatrolReadIterations > previous {
+			t.store.SetTimestamp(patrolReadPrefix+key, now)
+		}
+		t.iterations[key] = controller.PatrolReadIterations
+	}
+}
+
+// UpdateArrays records consistency checks finished since the previous update
+func (t *Tracker) UpdateArrays(raids []types.RAIDInfo, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	running := make(map[string]bool)
+	for _, raid := range raids {
+		if raid.Type != "hardware" {
+			continue
+		}
+		key := ArrayKey(raid)
+		if raid.CheckRunning {
+			running[key] = true
+		} else if t.running[key] {
+			t.store.SetTimestamp(consistencyCheckPrefix+key, now)
+		}
+	}
+	t.running = running
+}
+
+// UpdateScrubs records the last completed scrub of ZFS pools and fills it in
+// where it is missing. zpool status only reports the latest scan, so the last
+// scrub is lost while a scrub or resilver is running.
+func (t *Tracker) UpdateScrubs(raids []types.RAIDInfo) {
+	for _, raid := range raids {
+		if raid.ZFS == nil {
+			continue
+		}
+		key := scrubPrefix + raid.ZFS.Name
+		if !raid.ZFS.LastScrub.IsZero() {
+			t.store.SetTimestamp(key, raid.ZFS.LastScrub)
+		} else if last, ok := t.store.Timestamp(key); ok {
+			raid.ZFS.LastScrub = last
+		}
+	}
+}
+
+// LastPatrolRead returns when a patrol read last completed on a controller
+func (t *Tracker) LastPatrolRead(controller types.RAIDControllerInfo) (time.Time, bool) {
+	return t.store.Timestamp(patrolReadPrefix + ControllerKey(controller))
+}
+
+// LastConsistencyCheck returns when a consistency check last finished on an array
+func (t *Tracker) LastConsistencyCheck(raid types.RAIDInfo) (time.Time, bool) {
+	return t.store.Timestamp(consistencyCheckPrefix + ArrayKey(raid))
+}
@@ -0,0 +1,119 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/state"
+	"disk-health-exporter/pkg/types"
+)
+
+var base = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func newTracker(t *testing.T) *Tracker {
+	t.Helper()
+	store, err := state.Open("", time.Hour)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	return New(store)
+}
+
+func controller(iterations int64) types.RAIDControllerInfo {
+	return types.RAIDControllerInfo{AdapterID: 0, ToolName: "StoreCLI", PatrolReadIterations: iterations}
+}
+
+func TestPatrolReadCompletion(t *testing.T) {
+	tracker := newTracker(t)
+
+	tracker.UpdateControllers([]types.RAIDControllerInfo{controller(41)}, base)
+	if _, ok := tracker.LastPatrolRead(controller(41)); ok {
+		t.Error("Expected no completion from the first observation")
+	}
+
+	tracker.UpdateControllers([]types.RAIDControllerInfo{controller(41)}, base.Add(time.Hour))
+	if _, ok := tracker.LastPatrolRead(controller(41)); ok {
+		t.Error("Expected no completion while the iteration count is unchanged")
+	}
+
+	completed := base.Add(2 * time.Hour)
+	tracker.UpdateControllers([]types.RAIDControllerInfo{controller(42)}, completed)
+	last, ok := tracker.LastPatrolRead(controller(42))
+	if !ok || !last.Equal(completed) {
+		t.Errorf("Expected completion at %v, got %v", completed, last)
+	}
+}
+
+func TestPatrolReadCounterReset(t *testing.T) {
+	tracker := newTracker(t)
+
+	tracker.UpdateControllers([]types.RAIDControllerInfo{controller(41)}, base)
+	tracker.UpdateControllers([]types.RAIDControllerInfo{controller(0)}, base.Add(time.Hour))
+	if _, ok := tracker.LastPatrolRead(controller(0)); ok {
+		t.Error("Expected a counter reset not to count as a completion")
+	}
+
+	completed := base.Add(2 * time.Hour)
+	tracker.UpdateControllers([]types.RAIDControllerInfo{controller(1)}, completed)
+	if last, ok := tracker.LastPatrolRead(controller(1)); !ok || !last.Equal(completed) {
+		t.Errorf("Expected completion at %v after reset, got %v", completed, last)
+	}
+}
+
+func TestConsistencyCheckCompletion(t *testing.T) {
+	tracker := newTracker(t)
+	array := types.RAIDInfo{ArrayID: "0:1", Type: "hardware", Controller: "StoreCLI"}
+	running := array
+	running.CheckRunning = true
+	running.ScrubProgress = 42
+
+	tracker.UpdateArrays([]types.RAIDInfo{array}, base)
+	tracker.UpdateArrays([]types.RAIDInfo{running}, base.Add(time.Hour))
+	if _, ok := tracker.LastConsistencyCheck(array); ok {
+		t.Error("Expected no completion while the check is running")
+	}
+
+	finished := base.Add(2 * time.Hour)
+	tracker.UpdateArrays([]types.RAIDInfo{array}, finished)
+	if last, ok := tracker.LastConsistencyCheck(array); !ok || !last.Equal(finished) {
+		t.Errorf("Expected completion at %v, got %v", finished, last)
+	}
+
+	// Later collections without a running check keep the completion time
+	tracker.UpdateArrays([]types.RAIDInfo{array}, base.Add(3*time.Hour))
+	if last, _ := tracker.LastConsistencyCheck(array); !last.Equal(finished) {
+		t.Errorf("Expected completion to stay at %v, got %v", finished, last)
+	}
+}
+
+func TestConsistencyCheckIgnoresNonHardwareArrays(t *testing.T) {
+	tracker := newTracker(t)
+	pool := types.RAIDInfo{ArrayID: "tank", Type: "zfs", Controller: "ZFS", CheckRunning: true}
+
+	tracker.UpdateArrays([]types.RAIDInfo{pool}, base)
+	pool.CheckRunning = false
+	tracker.UpdateArrays([]types.RAIDInfo{pool}, base.Add(time.Hour))
+	if _, ok := tracker.LastConsistencyCheck(pool); ok {
+		t.Error("Expected ZFS pools to be left to the scrub metrics")
+	}
+}
+
+func TestScrubKept(t *testing.T) {
+	store, err := state.Open("", time.Hour)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	tracker := New(store)
+
+	scrubbed := base.Add(-24 * time.Hour)
+	tracker.UpdateScrubs([]types.RAIDInfo{{ArrayID: "tank", Type: "zfs", ZFS: &types.ZFSPoolInfo{Name: "tank", LastScrub: scrubbed}}})
+
+	// A running scan hides the last scrub, also from a tracker on the same store
+	for _, tracker := range []*Tracker{tracker, New(store)} {
+		pool := types.RAIDInfo{ArrayID: "tank", Type: "zfs", ZFS: &types.ZFSPoolInfo{Name: "tank"}}
+		tracker.UpdateScrubs([]types.RAIDInfo{pool})
+		if !pool.ZFS.LastScrub.Equal(scrubbed) {
+			t.Errorf("Expected last scrub %v, got %v", scrubbed, pool.ZFS.LastScrub)
+		}
+	}
+}
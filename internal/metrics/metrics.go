@@ -39,14 +39,15 @@ type Metrics struct {
 	DiskErrorLogEntries *prometheus.GaugeVec
 
 	// RAID specific metrics
-	RaidArraySize            *prometheus.GaugeVec
-	RaidArrayUsedSize        *prometheus.GaugeVec
-	RaidArrayNumDrives       *prometheus.GaugeVec
-	RaidArrayNumActiveDrives *prometheus.GaugeVec
-	RaidArrayNumSpareDrives  *prometheus.GaugeVec
-	RaidArrayNumFailedDrives *prometheus.GaugeVec
-	RaidArrayRebuildProgress *prometheus.GaugeVec
-	RaidArrayScrubProgress   *prometheus.GaugeVec
+	RaidArraySize               *prometheus.GaugeVec
+	RaidArrayUsedSize           *prometheus.GaugeVec
+	RaidArrayNumDrives          *prometheus.GaugeVec
+	RaidArrayNumActiveDrives    *prometheus.GaugeVec
+	RaidArrayNumSpareDrives     *prometheus.GaugeVec
+	RaidArrayNumFailedDrives    *prometheus.GaugeVec
+	RaidArrayRebuildProgress    *prometheus.GaugeVec
+	RaidArrayScrubProgress      *prometheus.GaugeVec
+	RaidArrayCheckLastCompleted *prometheus.GaugeVec
 
 	// RAID disk role metrics
 	DiskRaidRole            *prometheus.GaugeVec // 0=unconfigured, 1=active, 2=spare, 3=failed, 4=rebuilding
@@ -55,6 +56,64 @@ type Metrics struct {
 	DiskIsEmergencySpare    *prometheus.GaugeVec // 1 if emergency spare, 0 otherwise
 	DiskIsGlobalSpare       *prometheus.GaugeVec // 1 if global spare, 0 otherwise
 
+	// RAID controller physical drive metrics
+	DiskRaidMediaErrors        *prometheus.GaugeVec
+	DiskRaidOtherErrors        *prometheus.GaugeVec
+	DiskRaidPredictiveFailures *prometheus.GaugeVec
+	DiskRaidShieldCounter      *prometheus.GaugeVec
+	DiskRaidFirmwareState      *prometheus.GaugeVec
+	DiskRaidLinkSpeed          *prometheus.GaugeVec
+	DiskRaidDeviceSpeed        *prometheus.GaugeVec
+
+	// Predictive failure metrics
+	DiskFailureRiskScore *prometheus.GaugeVec
+	DiskFailureRiskLevel *prometheus.GaugeVec // 0=low, 1=medium, 2=high, 3=critical
+
+	// Source merge metrics
+	DiskHealthSourceConflict *prometheus.GaugeVec
+
+	// Plugin metrics
+	PluginUp              *prometheus.GaugeVec
+	PluginDurationSeconds *prometheus.GaugeVec
+	PluginErrorsTotal     *prometheus.GaugeVec
+	PluginInvalidEntries  *prometheus.GaugeVec
+
+	// Event stream metrics
+	EventsTotal          *prometheus.GaugeVec
+	EventSinkErrorsTotal *prometheus.GaugeVec
+
+	// Notification metrics
+	NotificationsTotal *prometheus.GaugeVec
+
+	// Threshold rule metrics
+	DiskHealthAlert *prometheus.GaugeVec
+
+	// State mapping metrics
+	UnmappedState *prometheus.GaugeVec
+
+	// Temperature history metrics
+	DiskTemperatureSensor     *prometheus.GaugeVec
+	DiskTemperatureWindow     *prometheus.GaugeVec
+	DiskTemperatureExcursions *prometheus.GaugeVec
+
+	// Self-test metrics
+	DiskSelfTestLastResult    *prometheus.GaugeVec
+	DiskSelfTestLastAge       *prometheus.GaugeVec
+	DiskSelfTestFirstErrorLBA *prometheus.GaugeVec
+	DiskSelfTestProgress      *prometheus.GaugeVec
+	DiskSelfTestScheduled     *prometheus.GaugeVec
+
+	// Counter rate-of-change metrics
+	DiskCounterIncrease    *prometheus.GaugeVec
+	DiskFirstSeenTimestamp *prometheus.GaugeVec
+
+	// SSD endurance metrics
+	DiskHostWrittenBytes       *prometheus.GaugeVec
+	DiskHostReadBytes          *prometheus.GaugeVec
+	DiskWriteRateBytesPerDay   *prometheus.GaugeVec
+	DiskEnduranceRatedBytes    *prometheus.GaugeVec
+	DiskEnduranceRemainingDays *prometheus.GaugeVec
+
 	// Software RAID metrics
 	SoftwareRaidArrayStatus  *prometheus.GaugeVec
 	SoftwareRaidSyncProgress *prometheus.GaugeVec
@@ -76,6 +135,69 @@ type Metrics struct {
 	RaidBatteryDesignVoltage    *prometheus.GaugeVec
 	RaidBatteryAutoLearnPeriod  *prometheus.GaugeVec
 
+	// RAID controller metrics
+	RaidControllerInfo                    *prometheus.GaugeVec
+	RaidControllerStatus                  *prometheus.GaugeVec
+	RaidControllerTemperature             *prometheus.GaugeVec
+	RaidControllerMemoryCorrectable       *prometheus.GaugeVec
+	RaidControllerMemoryUncorrectable     *prometheus.GaugeVec
+	RaidControllerCacheSize               *prometheus.GaugeVec
+	RaidControllerCacheStatus             *prometheus.GaugeVec
+	RaidControllerVirtualDrives           *prometheus.GaugeVec
+	RaidControllerPhysicalDrives          *prometheus.GaugeVec
+	RaidControllerForeignConfigs          *prometheus.GaugeVec
+	RaidControllerPendingFlash            *prometheus.GaugeVec
+	RaidControllerAlarm                   *prometheus.GaugeVec
+	RaidControllerPatrolReadProgress      *prometheus.GaugeVec
+	RaidControllerPatrolReadActive        *prometheus.GaugeVec
+	RaidControllerPatrolReadIterations    *prometheus.GaugeVec
+	RaidControllerPatrolReadNextRun       *prometheus.GaugeVec
+	RaidControllerPatrolReadLastCompleted *prometheus.GaugeVec
+
+	// ZFS pool and vdev metrics
+	ZFSPoolSizeBytes            *prometheus.GaugeVec
+	ZFSPoolAllocatedBytes       *prometheus.GaugeVec
+	ZFSPoolFreeBytes            *prometheus.GaugeVec
+	ZFSPoolFragmentation        *prometheus.GaugeVec
+	ZFSPoolCapacity             *prometheus.GaugeVec
+	ZFSPoolDedupRatio           *prometheus.GaugeVec
+	ZFSVdevState                *prometheus.GaugeVec // 1=ok, 2=degraded, 3=failed, 0=unknown
+	ZFSVdevReadErrors           *prometheus.GaugeVec
+	ZFSVdevWriteErrors          *prometheus.GaugeVec
+	ZFSVdevChecksumErrors       *prometheus.GaugeVec
+	ZFSPoolScanState            *prometheus.GaugeVec // 0=none, 1=finished, 2=in progress, 3=paused, 4=canceled
+	ZFSPoolScanProgress         *prometheus.GaugeVec
+	ZFSPoolScanTotalBytes       *prometheus.GaugeVec
+	ZFSPoolScanExaminedBytes    *prometheus.GaugeVec
+	ZFSPoolScanIssuedBytes      *prometheus.GaugeVec
+	ZFSPoolScanRepairedBytes    *prometheus.GaugeVec
+	ZFSPoolScanErrors           *prometheus.GaugeVec
+	ZFSPoolScanRemainingSeconds *prometheus.GaugeVec
+	ZFSPoolLastScrubTimestamp   *prometheus.GaugeVec
+
+	// ZFS dataset and ARC metrics
+	ZFSDatasetUsedBytes        *prometheus.GaugeVec
+	ZFSDatasetAvailableBytes   *prometheus.GaugeVec
+	ZFSDatasetReferencedBytes  *prometheus.GaugeVec
+	ZFSDatasetCompressRatio    *prometheus.GaugeVec
+	ZFSDatasetQuotaBytes       *prometheus.GaugeVec
+	ZFSDatasetReservationBytes *prometheus.GaugeVec
+	ZFSARCSizeBytes            *prometheus.GaugeVec
+	ZFSARCTargetSizeBytes      *prometheus.GaugeVec
+	ZFSARCMinSizeBytes         *prometheus.GaugeVec
+	ZFSARCMaxSizeBytes         *prometheus.GaugeVec
+	ZFSARCHits                 *prometheus.GaugeVec
+	ZFSARCMisses               *prometheus.GaugeVec
+	ZFSARCHitRatio             *prometheus.GaugeVec
+	ZFSL2ARCSizeBytes          *prometheus.GaugeVec
+	ZFSL2ARCHits               *prometheus.GaugeVec
+	ZFSL2ARCMisses             *prometheus.GaugeVec
+	ZFSL2ARCHitRatio           *prometheus.GaugeVec
+
+	// ZFS event metrics
+	ZFSEventsTotal        *prometheus.GaugeVec
+	ZFSEventLastTimestamp *prometheus.GaugeVec
+
 	// Inventory and system overview metrics
 	DiskInfo              *prometheus.GaugeVec
 	DiskPresent           *prometheus.GaugeVec
@@ -204,14 +326,14 @@ func New() *Metrics {
 		DiskTemperatureMax: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "disk_temperature_max_celsius",
-				Help: "Maximum recorded disk temperature in Celsius",
+				Help: "Highest temperature the drive recorded over its lifetime in Celsius",
 			},
 			[]string{"device", "serial", "model"},
 		),
 		DiskTemperatureMin: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "disk_temperature_min_celsius",
-				Help: "Minimum recorded disk temperature in Celsius",
+				Help: "Lowest temperature the drive recorded over its lifetime in Celsius",
 			},
 			[]string{"device", "serial", "model"},
 		),
@@ -331,6 +453,13 @@ func New() *Metrics {
 			},
 			[]string{"array_id", "raid_level", "type"},
 		),
+		RaidArrayCheckLastCompleted: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_array_consistency_check_last_completed_timestamp_seconds",
+				Help: "Unix timestamp of the last consistency check completion observed by the exporter",
+			},
+			[]string{"array_id", "raid_level", "type"},
+		),
 
 		// RAID disk role metrics
 		DiskRaidRole: prometheus.NewGaugeVec(
@@ -369,6 +498,268 @@ func New() *Metrics {
 			[]string{"device", "serial", "model"},
 		),
 
+		// RAID controller physical drive metrics
+		DiskRaidMediaErrors: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_raid_media_errors_total",
+				Help: "Media errors counted by the RAID controller for the physical drive",
+			},
+			[]string{"device", "serial", "model", "controller"},
+		),
+		DiskRaidOtherErrors: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_raid_other_errors_total",
+				Help: "Other (link, timeout, protocol) errors counted by the RAID controller for the physical drive",
+			},
+			[]string{"device", "serial", "model", "controller"},
+		),
+		DiskRaidPredictiveFailures: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_raid_predictive_failures_total",
+				Help: "Predictive failure events counted by the RAID controller for the physical drive",
+			},
+			[]string{"device", "serial", "model", "controller"},
+		),
+		DiskRaidShieldCounter: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_raid_shield_counter",
+				Help: "Number of times the RAID controller shielded the physical drive after transient errors",
+			},
+			[]string{"device", "serial", "model", "controller"},
+		),
+		DiskRaidFirmwareState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_raid_firmware_state",
+				Help: "Firmware and foreign state of the physical drive as reported by the RAID controller (always 1)",
+			},
+			[]string{"device", "serial", "model", "controller", "state", "foreign_state"},
+		),
+		DiskRaidLinkSpeed: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_raid_link_speed_gbps",
+				Help: "Negotiated link speed of the physical drive in Gb/s",
+			},
+			[]string{"device", "serial", "model", "controller"},
+		),
+		DiskRaidDeviceSpeed: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_raid_device_speed_gbps",
+				Help: "Maximum link speed supported by the physical drive in Gb/s",
+			},
+			[]string{"device", "serial", "model", "controller"},
+		),
+
+		// Predictive failure metrics
+		DiskFailureRiskScore: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_failure_risk_score",
+				Help: "Predictive disk failure risk score (0-1)",
+			},
+			[]string{"device", "serial", "model"},
+		),
+		DiskFailureRiskLevel: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_failure_risk_level",
+				Help: "Predictive disk failure risk level (0=low, 1=medium, 2=high, 3=critical)",
+			},
+			[]string{"device", "serial", "model", "level"},
+		),
+
+		// Source merge metrics
+		DiskHealthSourceConflict: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_source_conflict",
+				Help: "Tools disagreeing on a disk field; source supplied the exported value, overridden_source was discarded (always 1)",
+			},
+			[]string{"device", "serial", "field", "source", "overridden_source"},
+		),
+
+		// Plugin metrics
+		PluginUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_plugin_up",
+				Help: "Whether the latest run of a plugin returned a usable document (1 = yes, 0 = no)",
+			},
+			[]string{"plugin"},
+		),
+		PluginDurationSeconds: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_plugin_duration_seconds",
+				Help: "Run time of the latest plugin run in seconds",
+			},
+			[]string{"plugin"},
+		),
+		PluginErrorsTotal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_plugin_errors_total",
+				Help: "Failed plugin runs and runs with invalid entries since startup, by reason (exec, timeout, decode, validation)",
+			},
+			[]string{"plugin", "reason"},
+		),
+		PluginInvalidEntries: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_plugin_invalid_entries",
+				Help: "Entries dropped by schema validation in the latest plugin run, by kind (disk, raid_array, battery)",
+			},
+			[]string{"plugin", "kind"},
+		),
+
+		// Event stream metrics
+		EventsTotal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_events_total",
+				Help: "Disk and RAID state change events detected since startup, by type",
+			},
+			[]string{"type"},
+		),
+		EventSinkErrorsTotal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_event_sink_errors_total",
+				Help: "Failed event deliveries since startup, by sink",
+			},
+			[]string{"sink"},
+		),
+
+		// Notification metrics
+		NotificationsTotal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_notifications_total",
+				Help: "Webhook notifications since startup, by receiver and result (sent, failed)",
+			},
+			[]string{"receiver", "result"},
+		),
+
+		// Threshold rule metrics
+		DiskHealthAlert: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_alert",
+				Help: "Threshold rule firing for a disk, RAID array or controller battery (1 = firing)",
+			},
+			[]string{"rule", "severity", "device", "serial"},
+		),
+
+		// State mapping metrics
+		UnmappedState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_health_unmapped_state",
+				Help: "Health or state string reported by a tool that its state table does not know, read as unknown (1 = seen since startup)",
+			},
+			[]string{"tool", "state"},
+		),
+
+		// Temperature history metrics
+		DiskTemperatureSensor: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_temperature_sensor_celsius",
+				Help: "Reading of an additional NVMe temperature sensor in Celsius",
+			},
+			[]string{"device", "serial", "model", "sensor"},
+		),
+		DiskTemperatureWindow: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_temperature_window_celsius",
+				Help: "Minimum, maximum or average disk temperature in Celsius over a rolling window kept by the exporter",
+			},
+			[]string{"device", "serial", "model", "window", "stat"},
+		),
+		DiskTemperatureExcursions: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_temperature_excursions_total",
+				Help: "Times the disk temperature rose to or above a threshold in Celsius since the exporter started",
+			},
+			[]string{"device", "serial", "model", "threshold"},
+		),
+
+		// Self-test metrics
+		DiskSelfTestLastResult: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_self_test_last_result",
+				Help: "Result of the latest self-test of a type in the drive log (1=passed, 2=aborted, 3=failed, 0=unknown)",
+			},
+			[]string{"device", "serial", "model", "type", "result"},
+		),
+		DiskSelfTestLastAge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_self_test_last_age_hours",
+				Help: "Power-on hours since the latest self-test of a type in the drive log",
+			},
+			[]string{"device", "serial", "model", "type"},
+		),
+		DiskSelfTestFirstErrorLBA: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_self_test_first_error_lba",
+				Help: "LBA of the first error found by the latest self-test of a type",
+			},
+			[]string{"device", "serial", "model", "type"},
+		),
+		DiskSelfTestProgress: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_self_test_progress_percent",
+				Help: "Percentage completed of the self-test in progress",
+			},
+			[]string{"device", "serial", "model", "type"},
+		),
+		DiskSelfTestScheduled: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_self_test_scheduled_total",
+				Help: "Self-tests the scheduler tried to start since the exporter started by type and result",
+			},
+			[]string{"type", "result"},
+		),
+
+		// Counter rate-of-change metrics
+		DiskCounterIncrease: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_counter_increase",
+				Help: "Increase of a disk error counter over a window, persisted across exporter restarts",
+			},
+			[]string{"device", "serial", "model", "counter", "window"},
+		),
+		DiskFirstSeenTimestamp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_first_seen_timestamp_seconds",
+				Help: "Unix timestamp when the disk was first seen by the exporter",
+			},
+			[]string{"device", "serial", "model"},
+		),
+
+		// SSD endurance metrics
+		DiskHostWrittenBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_host_written_bytes_total",
+				Help: "Total host bytes written, normalized across ATA, NVMe and SCSI",
+			},
+			[]string{"device", "serial", "model"},
+		),
+		DiskHostReadBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_host_read_bytes_total",
+				Help: "Total host bytes read, normalized across ATA, NVMe and SCSI",
+			},
+			[]string{"device", "serial", "model"},
+		),
+		DiskWriteRateBytesPerDay: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_write_rate_bytes_per_day",
+				Help: "Average host bytes written per day over the endurance rate window",
+			},
+			[]string{"device", "serial", "model"},
+		),
+		DiskEnduranceRatedBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_endurance_rated_bytes",
+				Help: "Rated write endurance of the disk in bytes (TBW), when configured",
+			},
+			[]string{"device", "serial", "model"},
+		),
+		DiskEnduranceRemainingDays: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "disk_endurance_remaining_days",
+				Help: "Estimated days until the disk's write endurance is exhausted",
+			},
+			[]string{"device", "serial", "model", "method"},
+		),
+
 		// Software RAID metrics
 		SoftwareRaidArrayStatus: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -492,6 +883,399 @@ func New() *Metrics {
 			[]string{"adapter_id", "battery_type", "controller"},
 		),
 
+		// RAID controller metrics
+		RaidControllerInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_info",
+				Help: "RAID controller model, serial number, firmware, driver and BIOS versions (always 1)",
+			},
+			[]string{"adapter_id", "controller", "model", "serial", "firmware", "firmware_package", "driver", "bios"},
+		),
+		RaidControllerStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_status",
+				Help: "RAID controller status (0=unknown, 1=optimal, 2=needs attention, 3=failed)",
+			},
+			[]string{"adapter_id", "status", "controller"},
+		),
+		RaidControllerTemperature: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_temperature_celsius",
+				Help: "RAID controller ROC (RAID-on-chip) temperature in Celsius",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerMemoryCorrectable: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_memory_correctable_errors_total",
+				Help: "RAID controller cache memory correctable (ECC) errors",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerMemoryUncorrectable: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_memory_uncorrectable_errors_total",
+				Help: "RAID controller cache memory uncorrectable (ECC) errors",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerCacheSize: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_cache_size_bytes",
+				Help: "RAID controller cache memory size in bytes",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerCacheStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_cache_status",
+				Help: "RAID controller cache module status (0=unknown, 1=ok, 2=needs attention, 3=failed)",
+			},
+			[]string{"adapter_id", "status", "controller"},
+		),
+		RaidControllerVirtualDrives: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_virtual_drives",
+				Help: "Number of virtual drives configured on the RAID controller",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerPhysicalDrives: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_physical_drives",
+				Help: "Number of physical drives attached to the RAID controller",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerForeignConfigs: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_foreign_configurations",
+				Help: "Number of foreign configurations found on drives attached to the RAID controller",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerPendingFlash: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_pending_flash_image",
+				Help: "Whether a flashed firmware image is waiting for a reboot (0=no, 1=yes)",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerAlarm: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_alarm",
+				Help: "RAID controller audible alarm (0=absent or off, 1=present or on)",
+			},
+			[]string{"adapter_id", "state", "controller"},
+		),
+		RaidControllerPatrolReadProgress: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_patrol_read_progress_percentage",
+				Help: "RAID controller patrol read progress while running (0-100)",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerPatrolReadActive: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_patrol_read_active",
+				Help: "Whether a patrol read is running on the RAID controller (0=no, 1=yes)",
+			},
+			[]string{"adapter_id", "mode", "state", "controller"},
+		),
+		RaidControllerPatrolReadIterations: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_patrol_read_iterations_total",
+				Help: "Number of patrol reads completed as counted by the RAID controller",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerPatrolReadNextRun: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_patrol_read_next_run_timestamp_seconds",
+				Help: "Unix timestamp of the next scheduled patrol read",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+		RaidControllerPatrolReadLastCompleted: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "raid_controller_patrol_read_last_completed_timestamp_seconds",
+				Help: "Unix timestamp of the last patrol read completion observed by the exporter",
+			},
+			[]string{"adapter_id", "controller"},
+		),
+
+		// ZFS pool and vdev metrics
+		ZFSPoolSizeBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_size_bytes",
+				Help: "Total size of the ZFS pool in bytes",
+			},
+			[]string{"pool"},
+		),
+		ZFSPoolAllocatedBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_allocated_bytes",
+				Help: "Allocated space in the ZFS pool in bytes",
+			},
+			[]string{"pool"},
+		),
+		ZFSPoolFreeBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_free_bytes",
+				Help: "Free space in the ZFS pool in bytes",
+			},
+			[]string{"pool"},
+		),
+		ZFSPoolFragmentation: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_fragmentation_percent",
+				Help: "Free space fragmentation of the ZFS pool in percent",
+			},
+			[]string{"pool"},
+		),
+		ZFSPoolCapacity: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_capacity_percent",
+				Help: "Used capacity of the ZFS pool in percent",
+			},
+			[]string{"pool"},
+		),
+		ZFSPoolDedupRatio: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_dedup_ratio",
+				Help: "Deduplication ratio of the ZFS pool",
+			},
+			[]string{"pool"},
+		),
+		ZFSVdevState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_vdev_state",
+				Help: "ZFS vdev state (1=ok, 2=degraded, 3=failed, 0=unknown)",
+			},
+			[]string{"pool", "vdev", "type", "class", "state"},
+		),
+		ZFSVdevReadErrors: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_vdev_read_errors_total",
+				Help: "Read errors reported by zpool status for a vdev",
+			},
+			[]string{"pool", "vdev", "type", "class"},
+		),
+		ZFSVdevWriteErrors: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_vdev_write_errors_total",
+				Help: "Write errors reported by zpool status for a vdev",
+			},
+			[]string{"pool", "vdev", "type", "class"},
+		),
+		ZFSVdevChecksumErrors: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_vdev_checksum_errors_total",
+				Help: "Checksum errors reported by zpool status for a vdev",
+			},
+			[]string{"pool", "vdev", "type", "class"},
+		),
+		ZFSPoolScanState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_scan_state",
+				Help: "State of the latest ZFS scrub or resilver (0=none, 1=finished, 2=in progress, 3=paused, 4=canceled)",
+			},
+			[]string{"pool", "function", "state"},
+		),
+		ZFSPoolScanProgress: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_scan_progress_percent",
+				Help: "Completion percentage of the latest ZFS scrub or resilver (0-100)",
+			},
+			[]string{"pool", "function"},
+		),
+		ZFSPoolScanTotalBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_scan_total_bytes",
+				Help: "Bytes to examine in the latest ZFS scrub or resilver",
+			},
+			[]string{"pool", "function"},
+		),
+		ZFSPoolScanExaminedBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_scan_examined_bytes",
+				Help: "Bytes scanned by the latest ZFS scrub or resilver",
+			},
+			[]string{"pool", "function"},
+		),
+		ZFSPoolScanIssuedBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_scan_issued_bytes",
+				Help: "Bytes issued for verification by the latest ZFS scrub or resilver",
+			},
+			[]string{"pool", "function"},
+		),
+		ZFSPoolScanRepairedBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_scan_repaired_bytes",
+				Help: "Bytes repaired by the latest ZFS scrub or resilvered by the latest resilver",
+			},
+			[]string{"pool", "function"},
+		),
+		ZFSPoolScanErrors: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_scan_errors",
+				Help: "Errors encountered by the latest ZFS scrub or resilver",
+			},
+			[]string{"pool", "function"},
+		),
+		ZFSPoolScanRemainingSeconds: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_scan_remaining_seconds",
+				Help: "Estimated time remaining for a running ZFS scrub or resilver in seconds",
+			},
+			[]string{"pool", "function"},
+		),
+		ZFSPoolLastScrubTimestamp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_last_scrub_timestamp_seconds",
+				Help: "Unix timestamp of the last completed ZFS scrub",
+			},
+			[]string{"pool"},
+		),
+
+		// ZFS dataset and ARC metrics
+		ZFSDatasetUsedBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_dataset_used_bytes",
+				Help: "Bytes used by the ZFS dataset and its descendants",
+			},
+			[]string{"pool", "dataset", "type"},
+		),
+		ZFSDatasetAvailableBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_dataset_available_bytes",
+				Help: "Bytes available to the ZFS dataset",
+			},
+			[]string{"pool", "dataset", "type"},
+		),
+		ZFSDatasetReferencedBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_dataset_referenced_bytes",
+				Help: "Bytes referenced by the ZFS dataset",
+			},
+			[]string{"pool", "dataset", "type"},
+		),
+		ZFSDatasetCompressRatio: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_dataset_compress_ratio",
+				Help: "Compression ratio of the ZFS dataset",
+			},
+			[]string{"pool", "dataset", "type"},
+		),
+		ZFSDatasetQuotaBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_dataset_quota_bytes",
+				Help: "Quota of the ZFS dataset in bytes (0 = none)",
+			},
+			[]string{"pool", "dataset", "type"},
+		),
+		ZFSDatasetReservationBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_dataset_reservation_bytes",
+				Help: "Reservation of the ZFS dataset in bytes (0 = none)",
+			},
+			[]string{"pool", "dataset", "type"},
+		),
+		ZFSARCSizeBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_arc_size_bytes",
+				Help: "Current ZFS ARC size in bytes",
+			},
+			[]string{},
+		),
+		ZFSARCTargetSizeBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_arc_target_size_bytes",
+				Help: "Target ZFS ARC size in bytes",
+			},
+			[]string{},
+		),
+		ZFSARCMinSizeBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_arc_min_size_bytes",
+				Help: "Minimum ZFS ARC size in bytes",
+			},
+			[]string{},
+		),
+		ZFSARCMaxSizeBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_arc_max_size_bytes",
+				Help: "Maximum ZFS ARC size in bytes",
+			},
+			[]string{},
+		),
+		ZFSARCHits: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_arc_hits_total",
+				Help: "ZFS ARC hits since boot",
+			},
+			[]string{},
+		),
+		ZFSARCMisses: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_arc_misses_total",
+				Help: "ZFS ARC misses since boot",
+			},
+			[]string{},
+		),
+		ZFSARCHitRatio: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_arc_hit_ratio",
+				Help: "ZFS ARC hit ratio since boot (0-1)",
+			},
+			[]string{},
+		),
+		ZFSL2ARCSizeBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_l2arc_size_bytes",
+				Help: "ZFS L2ARC size in bytes",
+			},
+			[]string{},
+		),
+		ZFSL2ARCHits: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_l2arc_hits_total",
+				Help: "ZFS L2ARC hits since boot",
+			},
+			[]string{},
+		),
+		ZFSL2ARCMisses: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_l2arc_misses_total",
+				Help: "ZFS L2ARC misses since boot",
+			},
+			[]string{},
+		),
+		ZFSL2ARCHitRatio: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_l2arc_hit_ratio",
+				Help: "ZFS L2ARC hit ratio since boot (0-1)",
+			},
+			[]string{},
+		),
+
+		// ZFS event metrics
+		ZFSEventsTotal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_events_total",
+				Help: "ZFS error reports by class seen since the exporter started",
+			},
+			[]string{"pool", "vdev", "class"},
+		),
+		ZFSEventLastTimestamp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "zfs_pool_last_event_timestamp_seconds",
+				Help: "Unix timestamp of the latest ZFS error report by class",
+			},
+			[]string{"pool", "vdev", "class"},
+		),
+
 		// Inventory and system overview metrics
 		DiskInfo: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -571,6 +1355,7 @@ func New() *Metrics {
 		m.RaidArrayNumFailedDrives,
 		m.RaidArrayRebuildProgress,
 		m.RaidArrayScrubProgress,
+		m.RaidArrayCheckLastCompleted,
 
 		// RAID disk role metrics
 		m.DiskRaidRole,
@@ -579,6 +1364,64 @@ func New() *Metrics {
 		m.DiskIsEmergencySpare,
 		m.DiskIsGlobalSpare,
 
+		// RAID controller physical drive metrics
+		m.DiskRaidMediaErrors,
+		m.DiskRaidOtherErrors,
+		m.DiskRaidPredictiveFailures,
+		m.DiskRaidShieldCounter,
+		m.DiskRaidFirmwareState,
+		m.DiskRaidLinkSpeed,
+		m.DiskRaidDeviceSpeed,
+
+		// Predictive failure metrics
+		m.DiskFailureRiskScore,
+		m.DiskFailureRiskLevel,
+
+		// Source merge metrics
+		m.DiskHealthSourceConflict,
+
+		// Plugin metrics
+		m.PluginUp,
+		m.PluginDurationSeconds,
+		m.PluginErrorsTotal,
+		m.PluginInvalidEntries,
+
+		// Event stream metrics
+		m.EventsTotal,
+		m.EventSinkErrorsTotal,
+
+		// Notification metrics
+		m.NotificationsTotal,
+
+		// Threshold rule metrics
+		m.DiskHealthAlert,
+
+		// State mapping metrics
+		m.UnmappedState,
+
+		// Temperature history metrics
+		m.DiskTemperatureSensor,
+		m.DiskTemperatureWindow,
+		m.DiskTemperatureExcursions,
+
+		// Self-test metrics
+		m.DiskSelfTestLastResult,
+		m.DiskSelfTestLastAge,
+		m.DiskSelfTestFirstErrorLBA,
+		m.DiskSelfTestProgress,
+		m.DiskSelfTestScheduled,
+
+		// Counter rate-of-change metrics
+		m.DiskCounterIncrease,
+		m.DiskFirstSeenTimestamp,
+
+		// SSD endurance metrics
+		m.DiskHostWrittenBytes,
+		m.DiskHostReadBytes,
+		m.DiskWriteRateBytesPerDay,
+		m.DiskEnduranceRatedBytes,
+		m.DiskEnduranceRemainingDays,
+
 		// Software RAID metrics
 		m.SoftwareRaidArrayStatus,
 		m.SoftwareRaidSyncProgress,
@@ -600,6 +1443,69 @@ func New() *Metrics {
 		m.RaidBatteryDesignVoltage,
 		m.RaidBatteryAutoLearnPeriod,
 
+		// RAID controller metrics
+		m.RaidControllerInfo,
+		m.RaidControllerStatus,
+		m.RaidControllerTemperature,
+		m.RaidControllerMemoryCorrectable,
+		m.RaidControllerMemoryUncorrectable,
+		m.RaidControllerCacheSize,
+		m.RaidControllerCacheStatus,
+		m.RaidControllerVirtualDrives,
+		m.RaidControllerPhysicalDrives,
+		m.RaidControllerForeignConfigs,
+		m.RaidControllerPendingFlash,
+		m.RaidControllerAlarm,
+		m.RaidControllerPatrolReadProgress,
+		m.RaidControllerPatrolReadActive,
+		m.RaidControllerPatrolReadIterations,
+		m.RaidControllerPatrolReadNextRun,
+		m.RaidControllerPatrolReadLastCompleted,
+
+		// ZFS pool and vdev metrics
+		m.ZFSPoolSizeBytes,
+		m.ZFSPoolAllocatedBytes,
+		m.ZFSPoolFreeBytes,
+		m.ZFSPoolFragmentation,
+		m.ZFSPoolCapacity,
+		m.ZFSPoolDedupRatio,
+		m.ZFSVdevState,
+		m.ZFSVdevReadErrors,
+		m.ZFSVdevWriteErrors,
+		m.ZFSVdevChecksumErrors,
+		m.ZFSPoolScanState,
+		m.ZFSPoolScanProgress,
+		m.ZFSPoolScanTotalBytes,
+		m.ZFSPoolScanExaminedBytes,
+		m.ZFSPoolScanIssuedBytes,
+		m.ZFSPoolScanRepairedBytes,
+		m.ZFSPoolScanErrors,
+		m.ZFSPoolScanRemainingSeconds,
+		m.ZFSPoolLastScrubTimestamp,
+
+		// ZFS dataset and ARC metrics
+		m.ZFSDatasetUsedBytes,
+		m.ZFSDatasetAvailableBytes,
+		m.ZFSDatasetReferencedBytes,
+		m.ZFSDatasetCompressRatio,
+		m.ZFSDatasetQuotaBytes,
+		m.ZFSDatasetReservationBytes,
+		m.ZFSARCSizeBytes,
+		m.ZFSARCTargetSizeBytes,
+		m.ZFSARCMinSizeBytes,
+		m.ZFSARCMaxSizeBytes,
+		m.ZFSARCHits,
+		m.ZFSARCMisses,
+		m.ZFSARCHitRatio,
+		m.ZFSL2ARCSizeBytes,
+		m.ZFSL2ARCHits,
+		m.ZFSL2ARCMisses,
+		m.ZFSL2ARCHitRatio,
+
+		// ZFS event metrics
+		m.ZFSEventsTotal,
+		m.ZFSEventLastTimestamp,
+
 		// Inventory and system overview metrics
 		m.DiskInfo,
 		m.DiskPresent,
@@ -660,6 +1566,7 @@ func (m *Metrics) Reset() {
 	m.RaidArrayNumFailedDrives.Reset()
 	m.RaidArrayRebuildProgress.Reset()
 	m.RaidArrayScrubProgress.Reset()
+	m.RaidArrayCheckLastCompleted.Reset()
 
 	// RAID disk role metrics
 	m.DiskRaidRole.Reset()
@@ -668,6 +1575,64 @@ func (m *Metrics) Reset() {
 	m.DiskIsEmergencySpare.Reset()
 	m.DiskIsGlobalSpare.Reset()
 
+	// RAID controller physical drive metrics
+	m.DiskRaidMediaErrors.Reset()
+	m.DiskRaidOtherErrors.Reset()
+	m.DiskRaidPredictiveFailures.Reset()
+	m.DiskRaidShieldCounter.Reset()
+	m.DiskRaidFirmwareState.Reset()
+	m.DiskRaidLinkSpeed.Reset()
+	m.DiskRaidDeviceSpeed.Reset()
+
+	// Predictive failure metrics
+	m.DiskFailureRiskScore.Reset()
+	m.DiskFailureRiskLevel.Reset()
+
+	// Source merge metrics
+	m.DiskHealthSourceConflict.Reset()
+
+	// Plugin metrics
+	m.PluginUp.Reset()
+	m.PluginDurationSeconds.Reset()
+	m.PluginErrorsTotal.Reset()
+	m.PluginInvalidEntries.Reset()
+
+	// Event stream metrics
+	m.EventsTotal.Reset()
+	m.EventSinkErrorsTotal.Reset()
+
+	// Notification metrics
+	m.NotificationsTotal.Reset()
+
+	// Threshold rule metrics
+	m.DiskHealthAlert.Reset()
+
+	// State mapping metrics
+	m.UnmappedState.Reset()
+
+	// Temperature history metrics
+	m.DiskTemperatureSensor.Reset()
+	m.DiskTemperatureWindow.Reset()
+	m.DiskTemperatureExcursions.Reset()
+
+	// Self-test metrics
+	m.DiskSelfTestLastResult.Reset()
+	m.DiskSelfTestLastAge.Reset()
+	m.DiskSelfTestFirstErrorLBA.Reset()
+	m.DiskSelfTestProgress.Reset()
+	m.DiskSelfTestScheduled.Reset()
+
+	// Counter rate-of-change metrics
+	m.DiskCounterIncrease.Reset()
+	m.DiskFirstSeenTimestamp.Reset()
+
+	// SSD endurance metrics
+	m.DiskHostWrittenBytes.Reset()
+	m.DiskHostReadBytes.Reset()
+	m.DiskWriteRateBytesPerDay.Reset()
+	m.DiskEnduranceRatedBytes.Reset()
+	m.DiskEnduranceRemainingDays.Reset()
+
 	// Software RAID metrics
 	m.SoftwareRaidArrayStatus.Reset()
 	m.SoftwareRaidSyncProgress.Reset()
@@ -688,4 +1653,59 @@ func (m *Metrics) Reset() {
 	m.RaidBatteryDesignCapacity.Reset()
 	m.RaidBatteryDesignVoltage.Reset()
 	m.RaidBatteryAutoLearnPeriod.Reset()
+	m.RaidControllerInfo.Reset()
+	m.RaidControllerStatus.Reset()
+	m.RaidControllerTemperature.Reset()
+	m.RaidControllerMemoryCorrectable.Reset()
+	m.RaidControllerMemoryUncorrectable.Reset()
+	m.RaidControllerCacheSize.Reset()
+	m.RaidControllerCacheStatus.Reset()
+	m.RaidControllerVirtualDrives.Reset()
+	m.RaidControllerPhysicalDrives.Reset()
+	m.RaidControllerForeignConfigs.Reset()
+	m.RaidControllerPendingFlash.Reset()
+	m.RaidControllerAlarm.Reset()
+	m.RaidControllerPatrolReadProgress.Reset()
+	m.RaidControllerPatrolReadActive.Reset()
+	m.RaidControllerPatrolReadIterations.Reset()
+	m.RaidControllerPatrolReadNextRun.Reset()
+	m.RaidControllerPatrolReadLastCompleted.Reset()
+	m.ZFSPoolSizeBytes.Reset()
+	m.ZFSPoolAllocatedBytes.Reset()
+	m.ZFSPoolFreeBytes.Reset()
+	m.ZFSPoolFragmentation.Reset()
+	m.ZFSPoolCapacity.Reset()
+	m.ZFSPoolDedupRatio.Reset()
+	m.ZFSVdevState.Reset()
+	m.ZFSVdevReadErrors.Reset()
+	m.ZFSVdevWriteErrors.Reset()
+	m.ZFSVdevChecksumErrors.Reset()
+	m.ZFSPoolScanState.Reset()
+	m.ZFSPoolScanProgress.Reset()
+	m.ZFSPoolScanTotalBytes.Reset()
+	m.ZFSPoolScanExaminedBytes.Reset()
+	m.ZFSPoolScanIssuedBytes.Reset()
+	m.ZFSPoolScanRepairedBytes.Reset()
+	m.ZFSPoolScanErrors.Reset()
+	m.ZFSPoolScanRemainingSeconds.Reset()
+	m.ZFSPoolLastScrubTimestamp.Reset()
+	m.ZFSDatasetUsedBytes.Reset()
+	m.ZFSDatasetAvailableBytes.Reset()
+	m.ZFSDatasetReferencedBytes.Reset()
+	m.ZFSDatasetCompressRatio.Reset()
+	m.ZFSDatasetQuotaBytes.Reset()
+	m.ZFSDatasetReservationBytes.Reset()
+	m.ZFSARCSizeBytes.Reset()
+	m.ZFSARCTargetSizeBytes.Reset()
+	m.ZFSARCMinSizeBytes.Reset()
+	m.ZFSARCMaxSizeBytes.Reset()
+	m.ZFSARCHits.Reset()
+	m.ZFSARCMisses.Reset()
+	m.ZFSARCHitRatio.Reset()
+	m.ZFSL2ARCSizeBytes.Reset()
+	m.ZFSL2ARCHits.Reset()
+	m.ZFSL2ARCMisses.Reset()
+	m.ZFSL2ARCHitRatio.Reset()
+	m.ZFSEventsTotal.Reset()
+	m.ZFSEventLastTimestamp.Reset()
 }
@@ -0,0 +1,392 @@
+// Package notify posts notifications of disk health problems to webhooks. Each
+// collection is evaluated against a fixed set of alert conditions; alerts are
+// sent when they start firing, repeated while they keep firing and, when
+// requested, sent again once resolved. An alert resolves when its disk, array
+// or battery is reported again without the problem. One that is no longer
+// reported keeps firing for a resend interval, or until another disk is
+// reported in the slot of a pulled drive, and then resolves.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/internal/maintenance"
+	"disk-health-exporter/internal/state"
+	"disk-health-exporter/internal/utils"
+	"disk-health-exporter/pkg/types"
+)
+
+// Alert names
+const (
+	AlertDiskHealthDegraded         = "disk_health_degraded"
+	AlertDriveFailed                = "drive_failed"
+	AlertRAIDDegraded               = "raid_degraded"
+	AlertBatteryReplacementRequired = "battery_replacement_required"
+)
+
+// Alert severities
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert statuses
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// DefaultResendInterval is how often an alert still firing is sent again when not configured
+const DefaultResendInterval = 4 * time.Hour
+
+// Alert is a disk health problem found in a collection
+type Alert struct {
+	Name     string
+	Severity string
+	Status   string
+	Summary  string
+	Value    string // The state that raised the alert, e.g. the health or array state
+
+	Device     string
+	Serial     string
+	Model      string
+	Location   string
+	ArrayID    string
+	Controller string
+
+	StartsAt time.Time
+	EndsAt   time.Time // Set once resolved
+
+	PreviousSeverity string // Severity before an escalation or easing, set only in the notification of the change
+}
+
+// key identifies an alert across collections
+type key struct {
+	name     string
+	identity string
+}
+
+// active is an alert that is firing
+type active struct {
+	alert        Alert
+	lastSent     time.Time
+	lastReported time.Time // Last collection that reported the disk, array or battery
+}
+
+// Notifier tracks the firing alerts and sends them to the receivers
+type Notifier struct {
+	receivers      []*Receiver
+	resendInterval time.Duration
+
+	mu     sync.Mutex
+	active map[key]*active
+}
+
+// New creates a notifier from its configuration. Invalid receivers are
+// reported in the returned error and left out; the notifier is usable regardless.
+func New(cfg config.NotificationsConfig) (*Notifier, error) {
+	var errs []error
+
+	resendInterval := DefaultResendInterval
+	if cfg.ResendInterval != "" {
+		parsed, err := config.ParseDuration(cfg.ResendInterval)
+		if err != nil || parsed <= 0 {
+			errs = append(errs, fmt.Errorf("invalid resend interval %q", cfg.ResendInterval))
+		} else {
+			resendInterval = parsed
+		}
+	}
+
+	var receivers []*Receiver
+	for _, webhook := range cfg.Webhooks {
+		receiver, err := NewReceiver(webhook)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		receivers = append(receivers, receiver)
+	}
+
+	return NewWithReceivers(resendInterval, receivers...), errors.Join(errs...)
+}
+
+// NewWithReceivers creates a notifier sending to receivers
+func NewWithReceivers(resendInterval time.Duration, receivers ...*Receiver) *Notifier {
+	for _, receiver := range receivers {
+		receiver.resendInterval = resendInterval
+		receiver.start()
+	}
+	return &Notifier{
+		receivers:      receivers,
+		resendInterval: resendInterval,
+		active:         make(map[key]*active),
+	}
+}
+
+// Update evaluates a collection and queues the notifications it causes. The
+// alerts sent are returned, firing ones first.
+func (n *Notifier) Update(disks []types.DiskInfo, raids []types.RAIDInfo, now time.Time) []Alert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var firing, resolved []Alert
+	current := make(map[key]bool)
+
+	alerts, reported := evaluate(disks, raids)
+	for _, found := range alerts {
+		k, alert := found.key, found.alert
+		current[k] = true
+
+		previous, seen := n.active[k]
+		if seen {
+			alert.StartsAt = previous.alert.StartsAt
+			// Escalations and changed states are news, anything else waits for the resend interval
+			changed := previous.alert.Severity != alert.Severity || previous.alert.Value != alert.Value
+			if !changed && now.Sub(previous.lastSent) < n.resendInterval {
+				previous.alert = alert
+				previous.lastReported = now
+				continue
+			}
+		} else {
+			alert.StartsAt = now
+		}
+		n.active[k] = &active{alert: alert, lastSent: now, lastReported: now}
+
+		// Receivers identifying alerts by severity end the alert this one replaces
+		if seen && previous.alert.Severity != alert.Severity {
+			alert.PreviousSeverity = previous.alert.Severity
+		}
+		firing = append(firing, alert)
+	}
+
+	// Slots of the reported disks, to tell a replaced drive from a missing one
+	slots := make(map[string]string)
+	for _, disk := range disks {
+		if disk.Location != "" {
+			slots[disk.Location] = state.Key(disk)
+		}
+	}
+
+	var gone []key
+	for k := range n.active {
+		if !current[k] {
+			gone = append(gone, k)
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool {
+		if gone[i].name != gone[j].name {
+			return gone[i].name < gone[j].name
+		}
+		return gone[i].identity < gone[j].identity
+	})
+	for _, k := range gone {
+		previous := n.active[k]
+		// A disk or array that disappeared has not recovered; its alert keeps
+		// firing until it stays away for a resend interval or its slot holds another disk
+		if !reported[k] && !replaced(k, previous.alert, slots) && now.Sub(previous.lastReported) < n.resendInterval {
+			if now.Sub(previous.lastSent) >= n.resendInterval {
+				previous.lastSent = now
+				firing = append(firing, previous.alert)
+			}
+			continue
+		}
+		alert := previous.alert
+		alert.Status = StatusResolved
+		alert.EndsAt = now
+		resolved = append(resolved, alert)
+		delete(n.active, k)
+	}
+
+	if len(firing) == 0 && len(resolved) == 0 {
+		return nil
+	}
+	for _, receiver := range n.receivers {
+		alerts := firing
+		if receiver.sendResolved {
+			alerts = append(append([]Alert{}, firing...), resolved...)
+		}
+		if len(alerts) > 0 {
+			receiver.enqueue(alerts, now)
+		}
+	}
+	return append(firing, resolved...)
+}
+
+// replaced reports whether the drive of a disk alert was replaced by another
+// disk in the same slot
+func replaced(k key, alert Alert, slots map[string]string) bool {
+	slot := alert.Location
+	if slot == "" {
+		return false
+	}
+	identity, ok := slots[slot]
+	return ok && identity != k.identity
+}
+
+// Active returns the firing alerts, including those of disks and arrays no longer reported
+func (n *Notifier) Active() []Alert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(n.active))
+	for _, a := range n.active {
+		alerts = append(alerts, a.alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Name != alerts[j].Name {
+			return alerts[i].Name < alerts[j].Name
+		}
+		return alerts[i].Summary < alerts[j].Summary
+	})
+	return alerts
+}
+
+// Receivers returns the configured receivers
+func (n *Notifier) Receivers() []*Receiver {
+	return n.receivers
+}
+
+// Close delivers the queued notifications and stops the receivers
+func (n *Notifier) Close() {
+	for _, receiver := range n.receivers {
+		receiver.close()
+	}
+}
+
+// found is an alert and its identity as found by evaluate
+type found struct {
+	key   key
+	alert Alert
+}
+
+// evaluate returns the alerts firing for a collection, once per identity, and
+// every alert it checked a reported disk, array or battery for
+func evaluate(disks []types.DiskInfo, raids []types.RAIDInfo) ([]found, map[key]bool) {
+	var result []found
+	seen := make(map[key]bool)
+	reported := make(map[key]bool)
+	add := func(k key, alert Alert) {
+		// Disks reported twice and batteries shared by the arrays of a controller alert once
+		if seen[k] {
+			return
+		}
+		seen[k] = true
+		alert.Status = StatusFiring
+		result = append(result, found{key: k, alert: alert})
+	}
+
+	for _, disk := range disks {
+		identity := state.Key(disk)
+		reported[key{AlertDriveFailed, identity}] = true
+		reported[key{AlertDiskHealthDegraded, identity}] = true
+
+		// A failed array member is reported as such rather than by its health
+		if disk.RaidRole == "failed" {
+			alert := diskAlert(AlertDriveFailed, SeverityCritical, disk, disk.Health)
+			alert.Summary = fmt.Sprintf("Drive %s has failed", diskName(disk))
+			add(key{AlertDriveFailed, identity}, alert)
+			continue
+		}
+
+		// The health as exported by disk_health_status, raised by threshold rules
+		status := utils.GetDiskHealthStatusValue(disk)
+		var severity string
+		switch types.HealthStatus(status) {
+		case types.HealthStatusWarning:
+			severity = SeverityWarning
+		case types.HealthStatusCritical:
+			severity = SeverityCritical
+		default:
+			continue
+		}
+		alert := diskAlert(AlertDiskHealthDegraded, severity, disk, disk.Health)
+		alert.Summary = fmt.Sprintf("Disk %s health is %s", diskName(disk), disk.Health)
+		if status > int(disk.HealthStatus) {
+			rules := ruleNames(disk.RuleAlerts)
+			alert.Value = strings.Join(rules, ",")
+			alert.Summary = fmt.Sprintf("Disk %s exceeds %s thresholds", diskName(disk), strings.Join(rules, ", "))
+		}
+		add(key{AlertDiskHealthDegraded, identity}, alert)
+	}
+
+	for _, raid := range raids {
+		reported[key{AlertRAIDDegraded, maintenance.ArrayKey(raid)}] = true
+		if raid.Status == types.RAIDStateDegraded || raid.Status == types.RAIDStateFailed {
+			severity := SeverityWarning
+			if raid.Status == types.RAIDStateFailed {
+				severity = SeverityCritical
+			}
+			add(key{AlertRAIDDegraded, maintenance.ArrayKey(raid)}, Alert{
+				Name:       AlertRAIDDegraded,
+				Severity:   severity,
+				Summary:    fmt.Sprintf("RAID array %s is %s", arrayName(raid), raid.State),
+				Value:      raid.State,
+				ArrayID:    raid.ArrayID,
+				Controller: raid.Controller,
+			})
+		}
+
+		if raid.Battery == nil {
+			continue
+		}
+		batteryKey := key{AlertBatteryReplacementRequired, raid.Controller + "/" + strconv.Itoa(raid.Battery.AdapterID)}
+		reported[batteryKey] = true
+		if battery := raid.Battery; battery.ReplacementRequired {
+			add(batteryKey, Alert{
+				Name:       AlertBatteryReplacementRequired,
+				Severity:   SeverityWarning,
+				Summary:    fmt.Sprintf("Battery of controller %s requires replacement", raid.Controller),
+				Value:      battery.State,
+				Controller: raid.Controller,
+			})
+		}
+	}
+
+	return result, reported
+}
+
+// diskAlert creates an alert identifying a disk
+func diskAlert(name, severity string, disk types.DiskInfo, value string) Alert {
+	return Alert{
+		Name:     name,
+		Severity: severity,
+		Value:    value,
+		Device:   disk.Device,
+		Serial:   disk.Serial,
+		Model:    disk.Model,
+		Location: disk.Location,
+		ArrayID:  disk.RaidArrayID,
+	}
+}
+
+// ruleNames returns the names of firing threshold rules
+func ruleNames(alerts []types.RuleAlert) []string {
+	var names []string
+	for _, alert := range alerts {
+		names = append(names, alert.Rule)
+	}
+	return names
+}
+
+// diskName describes a disk by device and serial number
+func diskName(disk types.DiskInfo) string {
+	if disk.Serial == "" {
+		return disk.Device
+	}
+	return fmt.Sprintf("%s (serial %s)", disk.Device, disk.Serial)
+}
+
+// arrayName describes an array by ID and controller
+func arrayName(raid types.RAIDInfo) string {
+	if raid.Controller == "" {
+		return raid.ArrayID
+	}
+	return fmt.Sprintf("%s on %s", raid.ArrayID, raid.Controller)
+}
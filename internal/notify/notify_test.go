@@ -0,0 +1,218 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+var testTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// alertNames returns the name and status of alerts in order
+func alertNames(alerts []Alert) []string {
+	var result []string
+	for _, alert := range alerts {
+		result = append(result, alert.Name+"/"+alert.Status)
+	}
+	return result
+}
+
+func TestEvaluate(t *testing.T) {
+	disks := []types.DiskInfo{
+		{Device: "/dev/sda", Serial: "S1", Health: "OK", HealthStatus: types.HealthStatusOK},
+		{Device: "/dev/sdb", Serial: "S2", Health: "Warning", HealthStatus: types.HealthStatusWarning},
+		{Device: "/dev/sdc", Serial: "S3", Health: "FAILED", HealthStatus: types.HealthStatusCritical},
+		{Device: "raid-enc32-slot4", Serial: "S4", Health: "Failed", HealthStatus: types.HealthStatusCritical, RaidRole: "failed", RaidArrayID: "0"},
+		// Healthy by the tool, but over a threshold rule
+		{Device: "/dev/sdd", Serial: "S5", Health: "OK", HealthStatus: types.HealthStatusOK, RuleAlerts: []types.RuleAlert{
+			{Rule: "temperature", Severity: types.RuleSeverityWarning},
+			{Rule: "wear", Severity: types.RuleSeverityCritical},
+		}},
+		// The same disk seen by a second tool
+		{Device: "/dev/sdb", Serial: "S2", Health: "Warning", HealthStatus: types.HealthStatusWarning},
+	}
+	battery := &types.RAIDBatteryInfo{AdapterID: 0, State: "Failed", ReplacementRequired: true}
+	raids := []types.RAIDInfo{
+		{ArrayID: "0", Controller: "MegaRAID SAS 9361-8i", State: "Degraded", Status: 2, Battery: battery},
+		{ArrayID: "1", Controller: "MegaRAID SAS 9361-8i", State: "Offline", Status: 3, Battery: battery},
+		{ArrayID: "2", Controller: "MegaRAID SAS 9361-8i", State: "Optimal", Status: 1, Battery: battery},
+	}
+
+	tests := []struct {
+		name, severity, summary string
+	}{
+		{AlertDiskHealthDegraded, SeverityWarning, "Disk /dev/sdb (serial S2) health is Warning"},
+		{AlertDiskHealthDegraded, SeverityCritical, "Disk /dev/sdc (serial S3) health is FAILED"},
+		{AlertDriveFailed, SeverityCritical, "Drive raid-enc32-slot4 (serial S4) has failed"},
+		{AlertDiskHealthDegraded, SeverityCritical, "Disk /dev/sdd (serial S5) exceeds temperature, wear thresholds"},
+		{AlertRAIDDegraded, SeverityWarning, "RAID array 0 on MegaRAID SAS 9361-8i is Degraded"},
+		{AlertBatteryReplacementRequired, SeverityWarning, "Battery of controller MegaRAID SAS 9361-8i requires replacement"},
+		{AlertRAIDDegraded, SeverityCritical, "RAID array 1 on MegaRAID SAS 9361-8i is Offline"},
+	}
+
+	found, _ := evaluate(disks, raids)
+	if len(found) != len(tests) {
+		t.Fatalf("Expected %d alerts, got %d: %+v", len(tests), len(found), found)
+	}
+	for i, tt := range tests {
+		alert := found[i].alert
+		if alert.Name != tt.name || alert.Severity != tt.severity || alert.Summary != tt.summary || alert.Status != StatusFiring {
+			t.Errorf("Alert %d: expected %s %s %q, got %+v", i, tt.name, tt.severity, tt.summary, alert)
+		}
+	}
+	if found[2].alert.ArrayID != "0" || found[2].alert.Value != "Failed" {
+		t.Errorf("Expected the failed drive to carry its array and state, got %+v", found[2].alert)
+	}
+}
+
+func TestNotifierLifecycle(t *testing.T) {
+	notifier := NewWithReceivers(time.Hour)
+	healthy := []types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Health: "OK", HealthStatus: types.HealthStatusOK}}
+	warning := []types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Health: "Warning", HealthStatus: types.HealthStatusWarning}}
+	critical := []types.DiskInfo{{Device: "/dev/sdb", Serial: "S1", Health: "FAILED", HealthStatus: types.HealthStatusCritical}}
+
+	if sent := notifier.Update(healthy, nil, testTime); len(sent) != 0 {
+		t.Errorf("Expected no alerts for a healthy disk, got %v", alertNames(sent))
+	}
+
+	sent := notifier.Update(warning, nil, testTime.Add(time.Minute))
+	if len(sent) != 1 || sent[0].Severity != SeverityWarning || !sent[0].StartsAt.Equal(testTime.Add(time.Minute)) {
+		t.Fatalf("Expected a new warning, got %+v", sent)
+	}
+
+	// Still firing within the resend interval
+	if sent := notifier.Update(warning, nil, testTime.Add(30*time.Minute)); len(sent) != 0 {
+		t.Errorf("Expected the duplicate to be suppressed, got %v", alertNames(sent))
+	}
+
+	// Escalation is sent at once, keeping the start time and identified by serial across the rename
+	sent = notifier.Update(critical, nil, testTime.Add(40*time.Minute))
+	if len(sent) != 1 || sent[0].Severity != SeverityCritical || !sent[0].StartsAt.Equal(testTime.Add(time.Minute)) {
+		t.Fatalf("Expected the escalation, got %+v", sent)
+	}
+
+	if sent := notifier.Update(critical, nil, testTime.Add(90*time.Minute)); len(sent) != 0 {
+		t.Errorf("Expected no resend before an hour since the escalation, got %v", alertNames(sent))
+	}
+	if sent := notifier.Update(critical, nil, testTime.Add(100*time.Minute)); len(sent) != 1 || sent[0].Status != StatusFiring {
+		t.Errorf("Expected a resend after the interval, got %v", alertNames(sent))
+	}
+	if active := notifier.Active(); len(active) != 1 || active[0].Device != "/dev/sdb" {
+		t.Errorf("Expected the critical alert active, got %+v", active)
+	}
+
+	sent = notifier.Update(healthy, nil, testTime.Add(2*time.Hour))
+	if len(sent) != 1 || sent[0].Status != StatusResolved || !sent[0].EndsAt.Equal(testTime.Add(2*time.Hour)) {
+		t.Fatalf("Expected the alert resolved, got %+v", sent)
+	}
+	if active := notifier.Active(); len(active) != 0 {
+		t.Errorf("Expected no active alerts, got %+v", active)
+	}
+	if sent := notifier.Update(healthy, nil, testTime.Add(3*time.Hour)); len(sent) != 0 {
+		t.Errorf("Expected resolved alerts to be sent once, got %v", alertNames(sent))
+	}
+}
+
+func TestNotifierMissingIdentity(t *testing.T) {
+	notifier := NewWithReceivers(time.Hour)
+	failed := []types.DiskInfo{{Device: "raid-enc32-slot4", Serial: "S4", Health: "Failed", HealthStatus: types.HealthStatusCritical, RaidRole: "failed"}}
+	degraded := []types.RAIDInfo{{ArrayID: "0", Controller: "PERC H730", State: "Degraded", Status: types.RAIDStateDegraded}}
+
+	sent := notifier.Update(failed, degraded, testTime)
+	if len(sent) != 2 {
+		t.Fatalf("Expected the failed drive and degraded array, got %v", alertNames(sent))
+	}
+
+	// The drive is pulled and the controller stops answering
+	if sent := notifier.Update(nil, nil, testTime.Add(time.Minute)); len(sent) != 0 {
+		t.Errorf("Expected nothing sent for vanished disks and arrays, got %v", alertNames(sent))
+	}
+	if active := notifier.Active(); len(active) != 2 {
+		t.Errorf("Expected the alerts to keep firing, got %+v", active)
+	}
+
+	// Reported again, still failing, then gone for a resend interval
+	sent = notifier.Update(failed, degraded, testTime.Add(30*time.Minute))
+	if len(sent) != 0 {
+		t.Errorf("Expected nothing sent before the resend interval, got %v", alertNames(sent))
+	}
+	sent = notifier.Update(nil, nil, testTime.Add(80*time.Minute))
+	if names := alertNames(sent); len(names) != 2 || names[0] != AlertDriveFailed+"/"+StatusFiring || names[1] != AlertRAIDDegraded+"/"+StatusFiring {
+		t.Errorf("Expected both alerts resent within a resend interval of the last report, got %v", names)
+	}
+	sent = notifier.Update(nil, nil, testTime.Add(90*time.Minute))
+	if names := alertNames(sent); len(names) != 2 || names[0] != AlertDriveFailed+"/"+StatusResolved || names[1] != AlertRAIDDegraded+"/"+StatusResolved {
+		t.Errorf("Expected both alerts resolved after a resend interval away, got %v", names)
+	}
+	if active := notifier.Active(); len(active) != 0 {
+		t.Errorf("Expected no active alerts, got %+v", active)
+	}
+}
+
+func TestNotifierReplacedDrive(t *testing.T) {
+	notifier := NewWithReceivers(4 * time.Hour)
+	failed := []types.DiskInfo{{Device: "raid-enc32-slot4", Serial: "S4", Location: "Enc:32 Slot:4", Health: "Failed", HealthStatus: types.HealthStatusCritical, RaidRole: "failed"}}
+	replacement := []types.DiskInfo{{Device: "raid-enc32-slot4", Serial: "S9", Location: "Enc:32 Slot:4", Health: "Rebuild", HealthStatus: types.HealthStatusWarning, RaidRole: "rebuilding"}}
+
+	notifier.Update(failed, nil, testTime)
+	if sent := notifier.Update(nil, nil, testTime.Add(time.Minute)); len(sent) != 0 {
+		t.Errorf("Expected nothing sent for the pulled drive, got %v", alertNames(sent))
+	}
+
+	// A new drive in the slot ends the alert of the one it replaced
+	sent := notifier.Update(replacement, nil, testTime.Add(10*time.Minute))
+	var resolved []Alert
+	for _, alert := range sent {
+		if alert.Status == StatusResolved {
+			resolved = append(resolved, alert)
+		}
+	}
+	if len(resolved) != 1 || resolved[0].Name != AlertDriveFailed || resolved[0].Serial != "S4" || !resolved[0].EndsAt.Equal(testTime.Add(10*time.Minute)) {
+		t.Errorf("Expected the failed drive alert resolved, got %+v", sent)
+	}
+	for _, alert := range notifier.Active() {
+		if alert.Serial == "S4" {
+			t.Errorf("Expected no alert left for the replaced drive, got %+v", alert)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	notifier, err := New(config.NotificationsConfig{
+		ResendInterval: "30m",
+		Webhooks: []config.NotificationWebhookConfig{
+			{Name: "ops", URL: "https://hooks.slack.com/services/T000/B000", Template: TemplateSlack},
+			{URL: "http://alertmanager:9093/api/v2/alerts", Template: TemplateAlertmanager, Retries: 5},
+			{URL: "ftp://example.com"},
+			{URL: "https://example.com", Template: "xml"},
+			{URL: "https://example.com", Timeout: "soon"},
+			{URL: "https://example.com", Retries: -1},
+		},
+	})
+	defer notifier.Close()
+
+	if err == nil {
+		t.Error("Expected errors for the invalid webhooks")
+	}
+	if notifier.resendInterval != 30*time.Minute {
+		t.Errorf("Expected a 30m resend interval, got %s", notifier.resendInterval)
+	}
+
+	receivers := notifier.Receivers()
+	if len(receivers) != 2 {
+		t.Fatalf("Expected 2 receivers, got %d", len(receivers))
+	}
+	if receivers[0].Name() != "ops" || receivers[0].sendResolved || receivers[0].retries != DefaultRetries {
+		t.Errorf("Unexpected slack receiver %+v", receivers[0])
+	}
+	am := receivers[1]
+	if am.Name() != "webhook:alertmanager:9093" || !am.sendResolved || am.retries != 5 || am.resendInterval != 30*time.Minute {
+		t.Errorf("Unexpected alertmanager receiver %+v", am)
+	}
+
+	if _, err := New(config.NotificationsConfig{ResendInterval: "0s"}); err == nil {
+		t.Error("Expected an error for a zero resend interval")
+	}
+}
@@ -0,0 +1,222 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Payload templates
+const (
+	TemplateJSON         = "json"
+	TemplateSlack        = "slack"
+	TemplateAlertmanager = "alertmanager"
+)
+
+// renderFunc renders the alerts of one notification as a request body
+type renderFunc func(alerts []Alert, now time.Time, resendInterval time.Duration) ([]byte, error)
+
+// templates maps template names to their renderers
+var templates = map[string]renderFunc{
+	TemplateJSON:         renderJSON,
+	TemplateSlack:        renderSlack,
+	TemplateAlertmanager: renderAlertmanager,
+}
+
+// jsonPayload is the body of the generic JSON template
+type jsonPayload struct {
+	Version string      `json:"version"`
+	Status  string      `json:"status"` // firing if any alert is firing
+	Alerts  []jsonAlert `json:"alerts"`
+}
+
+type jsonAlert struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Severity   string     `json:"severity"`
+	Summary    string     `json:"summary"`
+	Value      string     `json:"value,omitempty"`
+	Device     string     `json:"device,omitempty"`
+	Serial     string     `json:"serial,omitempty"`
+	Model      string     `json:"model,omitempty"`
+	Location   string     `json:"location,omitempty"`
+	ArrayID    string     `json:"array_id,omitempty"`
+	Controller string     `json:"controller,omitempty"`
+	StartsAt   time.Time  `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+}
+
+// renderJSON renders the generic JSON template
+func renderJSON(alerts []Alert, _ time.Time, _ time.Duration) ([]byte, error) {
+	payload := jsonPayload{Version: "1", Status: StatusResolved}
+	for _, alert := range alerts {
+		if alert.Status == StatusFiring {
+			payload.Status = StatusFiring
+		}
+		wire := jsonAlert{
+			Name:       alert.Name,
+			Status:     alert.Status,
+			Severity:   alert.Severity,
+			Summary:    alert.Summary,
+			Value:      alert.Value,
+			Device:     alert.Device,
+			Serial:     alert.Serial,
+			Model:      alert.Model,
+			Location:   alert.Location,
+			ArrayID:    alert.ArrayID,
+			Controller: alert.Controller,
+			StartsAt:   alert.StartsAt,
+		}
+		if !alert.EndsAt.IsZero() {
+			endsAt := alert.EndsAt
+			wire.EndsAt = &endsAt
+		}
+		payload.Alerts = append(payload.Alerts, wire)
+	}
+	return json.Marshal(payload)
+}
+
+// slackPayload is the body of a Slack incoming webhook message
+type slackPayload struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color string `json:"color"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	Ts    int64  `json:"ts"`
+}
+
+// slackColors maps severities to attachment colors; resolved alerts are green
+var slackColors = map[string]string{
+	SeverityWarning:  "warning",
+	SeverityCritical: "danger",
+}
+
+// renderSlack renders a Slack message with one attachment per alert
+func renderSlack(alerts []Alert, now time.Time, _ time.Duration) ([]byte, error) {
+	var firing int
+	payload := slackPayload{}
+	for _, alert := range alerts {
+		attachment := slackAttachment{
+			Color: slackColors[alert.Severity],
+			Title: fmt.Sprintf("[%s] %s", strings.ToUpper(alert.Severity), alert.Summary),
+			Text:  slackDetails(alert),
+			Ts:    alert.StartsAt.Unix(),
+		}
+		if alert.Status == StatusResolved {
+			attachment.Color = "good"
+			attachment.Title = "[RESOLVED] " + alert.Summary
+			attachment.Ts = alert.EndsAt.Unix()
+		} else {
+			firing++
+		}
+		payload.Attachments = append(payload.Attachments, attachment)
+	}
+
+	resolved := len(alerts) - firing
+	switch {
+	case resolved == 0:
+		payload.Text = fmt.Sprintf("Disk health: %d firing", firing)
+	case firing == 0:
+		payload.Text = fmt.Sprintf("Disk health: %d resolved", resolved)
+	default:
+		payload.Text = fmt.Sprintf("Disk health: %d firing, %d resolved", firing, resolved)
+	}
+	return json.Marshal(payload)
+}
+
+// slackDetails lists the identity of an alert, one field per line
+func slackDetails(alert Alert) string {
+	var lines []string
+	for _, field := range []struct{ name, value string }{
+		{"Alert", alert.Name},
+		{"Device", alert.Device},
+		{"Serial", alert.Serial},
+		{"Model", alert.Model},
+		{"Location", alert.Location},
+		{"Array", alert.ArrayID},
+		{"Controller", alert.Controller},
+		{"State", alert.Value},
+	} {
+		if field.value != "" {
+			lines = append(lines, fmt.Sprintf("*%s:* %s", field.name, field.value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// alertmanagerAlert is an alert of the Alertmanager v2 API (POST /api/v2/alerts)
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// alertmanagerValidity is how many resend intervals a firing alert stays
+// active in Alertmanager without being sent again, so that it resolves on its
+// own if the exporter stops
+const alertmanagerValidity = 3
+
+// renderAlertmanager renders the alerts for the Alertmanager v2 API.
+// Alertmanager identifies alerts by their labels, severity included, so an
+// alert that changed severity also ends the alert of its previous severity.
+func renderAlertmanager(alerts []Alert, now time.Time, resendInterval time.Duration) ([]byte, error) {
+	payload := make([]alertmanagerAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		labels := alertmanagerLabels(alert, alert.Severity)
+
+		annotations := map[string]string{"summary": alert.Summary}
+		if alert.Value != "" {
+			annotations["state"] = alert.Value
+		}
+		if alert.Location != "" {
+			annotations["location"] = alert.Location
+		}
+
+		endsAt := alert.EndsAt
+		if endsAt.IsZero() {
+			endsAt = now.Add(alertmanagerValidity * resendInterval)
+		}
+		payload = append(payload, alertmanagerAlert{
+			Labels:      labels,
+			Annotations: annotations,
+			StartsAt:    alert.StartsAt,
+			EndsAt:      endsAt,
+		})
+
+		if alert.PreviousSeverity != "" && alert.PreviousSeverity != alert.Severity {
+			payload = append(payload, alertmanagerAlert{
+				Labels:      alertmanagerLabels(alert, alert.PreviousSeverity),
+				Annotations: annotations,
+				StartsAt:    alert.StartsAt,
+				EndsAt:      now,
+			})
+		}
+	}
+	return json.Marshal(payload)
+}
+
+// alertmanagerLabels returns the labels identifying an alert with a severity
+func alertmanagerLabels(alert Alert, severity string) map[string]string {
+	labels := map[string]string{
+		"alertname": alert.Name,
+		"severity":  severity,
+	}
+	for name, value := range map[string]string{
+		"device":     alert.Device,
+		"serial":     alert.Serial,
+		"model":      alert.Model,
+		"array_id":   alert.ArrayID,
+		"controller": alert.Controller,
+	} {
+		if value != "" {
+			labels[name] = value
+		}
+	}
+	return labels
+}
@@ -0,0 +1,221 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/config"
+)
+
+// Receiver defaults
+const (
+	DefaultTimeout = 5 * time.Second
+	DefaultRetries = 3
+
+	// initialBackoff is the wait before the first retry, doubled for every further retry up to maxBackoff
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// queueSize is the number of notifications that may wait for delivery per receiver
+const queueSize = 64
+
+// Delivery results
+const (
+	ResultSent   = "sent"
+	ResultFailed = "failed"
+)
+
+// Receiver posts notifications to one webhook. Each receiver delivers in the
+// background, so a receiver retrying a slow or failing webhook delays neither
+// the collection nor the other receivers.
+type Receiver struct {
+	name           string
+	url            string
+	template       string
+	sendResolved   bool
+	retries        int
+	backoff        time.Duration
+	resendInterval time.Duration
+	client         *http.Client
+
+	queue chan []byte
+	done  chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	results map[string]int // Notifications since startup by result
+}
+
+// NewReceiver creates a receiver from its configuration
+func NewReceiver(cfg config.NotificationWebhookConfig) (*Receiver, error) {
+	parsed, err := url.Parse(cfg.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid notification webhook URL %q", cfg.URL)
+	}
+
+	template := cfg.Template
+	if template == "" {
+		template = TemplateJSON
+	}
+	if _, ok := templates[template]; !ok {
+		return nil, fmt.Errorf("unknown notification template %q", cfg.Template)
+	}
+
+	timeout := DefaultTimeout
+	if cfg.Timeout != "" {
+		timeout, err = config.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid notification webhook timeout %q", cfg.Timeout)
+		}
+	}
+
+	retries := DefaultRetries
+	if cfg.Retries != 0 {
+		retries = cfg.Retries
+	}
+	if retries < 0 {
+		return nil, fmt.Errorf("invalid notification webhook retries %d", cfg.Retries)
+	}
+
+	name := cfg.Name
+	if name == "" {
+		// The host alone, as paths and queries of webhook URLs often hold tokens
+		name = "webhook:" + parsed.Host
+	}
+
+	return &Receiver{
+		name:           name,
+		url:            cfg.URL,
+		template:       template,
+		sendResolved:   cfg.SendResolved || template == TemplateAlertmanager, // Alertmanager holds alerts until their end time
+		retries:        retries,
+		backoff:        initialBackoff,
+		resendInterval: DefaultResendInterval,
+		client:         &http.Client{Timeout: timeout},
+		results:        make(map[string]int),
+	}, nil
+}
+
+// Name identifies the receiver in logs and metric labels
+func (r *Receiver) Name() string {
+	return r.name
+}
+
+// Results returns the number of notifications since startup by result
+func (r *Receiver) Results() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return map[string]int{ResultSent: r.results[ResultSent], ResultFailed: r.results[ResultFailed]}
+}
+
+// start begins background delivery
+func (r *Receiver) start() {
+	r.queue = make(chan []byte, queueSize)
+	r.done = make(chan struct{})
+	go r.deliver()
+}
+
+// enqueue renders the alerts and queues them for delivery
+func (r *Receiver) enqueue(alerts []Alert, now time.Time) {
+	body, err := templates[r.template](alerts, now, r.resendInterval)
+	if err != nil {
+		slog.Error("Error rendering notification", "receiver", r.name, "err", err)
+		r.count(ResultFailed)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- body:
+	default:
+		slog.Warn("Notification delivery is falling behind, dropping notification", "receiver", r.name, "alerts", len(alerts))
+		r.results[ResultFailed]++
+	}
+}
+
+// close delivers the queued notifications and stops delivery
+func (r *Receiver) close() {
+	r.mu.Lock()
+	if r.closed || r.queue == nil {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.queue)
+	r.mu.Unlock()
+
+	<-r.done
+}
+
+// deliver posts queued notifications until the receiver is closed
+func (r *Receiver) deliver() {
+	defer close(r.done)
+
+	for body := range r.queue {
+		if err := r.post(body); err != nil {
+			slog.Error("Error sending notification", "receiver", r.name, "err", err)
+			r.count(ResultFailed)
+			continue
+		}
+		r.count(ResultSent)
+	}
+}
+
+// count records the result of a notification
+func (r *Receiver) count(result string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results[result]++
+}
+
+// post sends a notification, retrying failed requests with exponential backoff
+func (r *Receiver) post(body []byte) error {
+	backoff := r.backoff
+	var err error
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			slog.Debug("Retrying notification", "receiver", r.name, "attempt", attempt, "backoff", backoff, "err", err)
+			time.Sleep(backoff)
+			backoff = min(backoff*2, maxBackoff)
+		}
+
+		var retry bool
+		retry, err = r.postOnce(body)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// postOnce sends a notification once and reports whether a failure is worth retrying
+func (r *Receiver) postOnce(body []byte) (bool, error) {
+	resp, err := r.client.Post(r.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// Drop the URL the client includes in its errors
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return true, urlErr.Err
+		}
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	// Client errors other than rate limiting will fail again the same way
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
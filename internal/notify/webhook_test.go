@@ -0,0 +1,264 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+// stubServer records request bodies and answers with the queued statuses, then 200
+type stubServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+}
+
+func newStubServer(t *testing.T, statuses ...int) *stubServer {
+	stub := &stubServer{statuses: statuses}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected content type %s", r.Header.Get("Content-Type"))
+		}
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		stub.bodies = append(stub.bodies, body)
+		if len(stub.statuses) > 0 {
+			w.WriteHeader(stub.statuses[0])
+			stub.statuses = stub.statuses[1:]
+		}
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+// newTestReceiver creates a receiver with a short backoff
+func newTestReceiver(t *testing.T, cfg config.NotificationWebhookConfig) *Receiver {
+	receiver, err := NewReceiver(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	receiver.backoff = time.Millisecond
+	return receiver
+}
+
+var (
+	failingDisk = []types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Model: "ST4000", Health: "FAILED", HealthStatus: types.HealthStatusCritical}}
+	healthyDisk = []types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Model: "ST4000", Health: "OK", HealthStatus: types.HealthStatusOK}}
+)
+
+func TestReceiverJSON(t *testing.T) {
+	stub := newStubServer(t)
+	receiver := newTestReceiver(t, config.NotificationWebhookConfig{URL: stub.URL, SendResolved: true})
+	notifier := NewWithReceivers(time.Hour, receiver)
+
+	notifier.Update(failingDisk, nil, testTime)
+	notifier.Update(healthyDisk, nil, testTime.Add(time.Minute))
+	notifier.Close()
+
+	if len(stub.bodies) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(stub.bodies))
+	}
+	var firing, resolved jsonPayload
+	if err := json.Unmarshal(stub.bodies[0], &firing); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(stub.bodies[1], &resolved); err != nil {
+		t.Fatal(err)
+	}
+
+	alert := firing.Alerts[0]
+	if firing.Status != StatusFiring || alert.Name != AlertDiskHealthDegraded || alert.Severity != SeverityCritical ||
+		alert.Serial != "S1" || alert.Value != "FAILED" || alert.EndsAt != nil {
+		t.Errorf("Unexpected firing notification %s", stub.bodies[0])
+	}
+	if resolved.Status != StatusResolved || resolved.Alerts[0].EndsAt == nil || !resolved.Alerts[0].StartsAt.Equal(testTime) {
+		t.Errorf("Unexpected resolved notification %s", stub.bodies[1])
+	}
+	if results := receiver.Results(); results[ResultSent] != 2 || results[ResultFailed] != 0 {
+		t.Errorf("Unexpected results %v", results)
+	}
+}
+
+func TestReceiverSkipsResolved(t *testing.T) {
+	stub := newStubServer(t)
+	receiver := newTestReceiver(t, config.NotificationWebhookConfig{URL: stub.URL})
+	notifier := NewWithReceivers(time.Hour, receiver)
+
+	notifier.Update(failingDisk, nil, testTime)
+	notifier.Update(healthyDisk, nil, testTime.Add(time.Minute))
+	notifier.Close()
+
+	if len(stub.bodies) != 1 {
+		t.Errorf("Expected only the firing notification, got %d", len(stub.bodies))
+	}
+}
+
+func TestReceiverSlack(t *testing.T) {
+	stub := newStubServer(t)
+	receiver := newTestReceiver(t, config.NotificationWebhookConfig{URL: stub.URL, Template: TemplateSlack, SendResolved: true})
+	notifier := NewWithReceivers(time.Hour, receiver)
+
+	raids := []types.RAIDInfo{{ArrayID: "0", Controller: "PERC H730", State: "Degraded", Status: 2}}
+	notifier.Update(failingDisk, raids, testTime)
+	notifier.Update(failingDisk, []types.RAIDInfo{{ArrayID: "0", Controller: "PERC H730", State: "Optimal", Status: 1}}, testTime.Add(time.Minute))
+	notifier.Close()
+
+	var firing, mixed slackPayload
+	json.Unmarshal(stub.bodies[0], &firing)
+	json.Unmarshal(stub.bodies[1], &mixed)
+
+	if firing.Text != "Disk health: 2 firing" || len(firing.Attachments) != 2 {
+		t.Fatalf("Unexpected firing message %s", stub.bodies[0])
+	}
+	disk := firing.Attachments[0]
+	if disk.Color != "danger" || disk.Title != "[CRITICAL] Disk /dev/sda (serial S1) health is FAILED" ||
+		!strings.Contains(disk.Text, "*Model:* ST4000") || disk.Ts != testTime.Unix() {
+		t.Errorf("Unexpected disk attachment %+v", disk)
+	}
+	if firing.Attachments[1].Color != "warning" {
+		t.Errorf("Expected a warning color for the degraded array, got %+v", firing.Attachments[1])
+	}
+
+	if mixed.Text != "Disk health: 1 resolved" || mixed.Attachments[0].Color != "good" ||
+		mixed.Attachments[0].Title != "[RESOLVED] RAID array 0 on PERC H730 is Degraded" {
+		t.Errorf("Unexpected resolved message %s", stub.bodies[1])
+	}
+}
+
+func TestReceiverAlertmanager(t *testing.T) {
+	stub := newStubServer(t)
+	receiver := newTestReceiver(t, config.NotificationWebhookConfig{URL: stub.URL + "/api/v2/alerts", Template: TemplateAlertmanager})
+	notifier := NewWithReceivers(time.Hour, receiver)
+
+	notifier.Update(failingDisk, nil, testTime)
+	notifier.Update(healthyDisk, nil, testTime.Add(time.Minute))
+	notifier.Close()
+
+	if len(stub.bodies) != 2 {
+		t.Fatalf("Expected the resolved alert to be sent to Alertmanager, got %d notifications", len(stub.bodies))
+	}
+	var firing, resolved []alertmanagerAlert
+	json.Unmarshal(stub.bodies[0], &firing)
+	json.Unmarshal(stub.bodies[1], &resolved)
+
+	alert := firing[0]
+	if alert.Labels["alertname"] != AlertDiskHealthDegraded || alert.Labels["severity"] != SeverityCritical ||
+		alert.Labels["serial"] != "S1" || alert.Labels["device"] != "/dev/sda" {
+		t.Errorf("Unexpected labels %v", alert.Labels)
+	}
+	if _, ok := alert.Labels["array_id"]; ok {
+		t.Errorf("Expected empty labels to be left out, got %v", alert.Labels)
+	}
+	if alert.Annotations["summary"] == "" || alert.Annotations["state"] != "FAILED" {
+		t.Errorf("Unexpected annotations %v", alert.Annotations)
+	}
+	if !alert.EndsAt.Equal(testTime.Add(3 * time.Hour)) {
+		t.Errorf("Expected a firing alert to end after three resend intervals, got %s", alert.EndsAt)
+	}
+	if !resolved[0].EndsAt.Equal(testTime.Add(time.Minute)) {
+		t.Errorf("Expected the resolved alert to end when resolved, got %s", resolved[0].EndsAt)
+	}
+}
+
+func TestReceiverAlertmanagerSeverityChange(t *testing.T) {
+	stub := newStubServer(t)
+	receiver := newTestReceiver(t, config.NotificationWebhookConfig{URL: stub.URL + "/api/v2/alerts", Template: TemplateAlertmanager})
+	notifier := NewWithReceivers(time.Hour, receiver)
+
+	warningDisk := []types.DiskInfo{{Device: "/dev/sda", Serial: "S1", Model: "ST4000", Health: "Warning", HealthStatus: types.HealthStatusWarning}}
+	notifier.Update(warningDisk, nil, testTime)
+	notifier.Update(failingDisk, nil, testTime.Add(time.Minute))
+	notifier.Update(failingDisk, nil, testTime.Add(2*time.Minute))
+	notifier.Close()
+
+	if len(stub.bodies) != 2 {
+		t.Fatalf("Expected the warning and the escalation, got %d notifications", len(stub.bodies))
+	}
+	var escalation []alertmanagerAlert
+	json.Unmarshal(stub.bodies[1], &escalation)
+	if len(escalation) != 2 {
+		t.Fatalf("Expected the critical alert and the end of the warning, got %s", stub.bodies[1])
+	}
+
+	critical, warning := escalation[0], escalation[1]
+	if critical.Labels["severity"] != SeverityCritical || !critical.EndsAt.Equal(testTime.Add(time.Minute+3*time.Hour)) {
+		t.Errorf("Expected a firing critical alert, got %+v", critical)
+	}
+	if warning.Labels["severity"] != SeverityWarning || !warning.EndsAt.Equal(testTime.Add(time.Minute)) {
+		t.Errorf("Expected the warning alert to end with the escalation, got %+v", warning)
+	}
+	if warning.Labels["serial"] != "S1" || warning.Labels["alertname"] != AlertDiskHealthDegraded || !warning.StartsAt.Equal(testTime) {
+		t.Errorf("Expected the warning alert to keep its identity, got %+v", warning)
+	}
+}
+
+func TestReceiverRetries(t *testing.T) {
+	stub := newStubServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	receiver := newTestReceiver(t, config.NotificationWebhookConfig{URL: stub.URL})
+	notifier := NewWithReceivers(time.Hour, receiver)
+
+	notifier.Update(failingDisk, nil, testTime)
+	notifier.Close()
+
+	if len(stub.bodies) != 3 {
+		t.Errorf("Expected 2 retries, got %d requests", len(stub.bodies))
+	}
+	if results := receiver.Results(); results[ResultSent] != 1 || results[ResultFailed] != 0 {
+		t.Errorf("Unexpected results %v", results)
+	}
+}
+
+func TestReceiverFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		requests int
+	}{
+		{"retries exhausted", []int{500, 502, 503, 504}, 4},
+		{"client error", []int{http.StatusBadRequest}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newStubServer(t, tt.statuses...)
+			receiver := newTestReceiver(t, config.NotificationWebhookConfig{URL: stub.URL})
+			notifier := NewWithReceivers(time.Hour, receiver)
+
+			notifier.Update(failingDisk, nil, testTime)
+			notifier.Close()
+
+			if len(stub.bodies) != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, len(stub.bodies))
+			}
+			if results := receiver.Results(); results[ResultFailed] != 1 || results[ResultSent] != 0 {
+				t.Errorf("Unexpected results %v", results)
+			}
+		})
+	}
+}
+
+func TestReceiverHidesURL(t *testing.T) {
+	stub := newStubServer(t)
+	url := stub.URL + "/hooks/secret-token"
+	stub.Close()
+
+	receiver := newTestReceiver(t, config.NotificationWebhookConfig{URL: url, Retries: 1})
+	if err := receiver.post([]byte("{}")); err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected an error without the URL, got %v", err)
+	}
+	if strings.Contains(receiver.Name(), "secret-token") {
+		t.Errorf("Expected the receiver name to leave out the path, got %s", receiver.Name())
+	}
+}
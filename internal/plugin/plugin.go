@@ -0,0 +1,246 @@
+// Package plugin runs external executables that report disks and RAID arrays as
+// JSON, so that storage the exporter does not support natively is merged into
+// the normal disk pipeline. The output format is documented in docs/plugins.md.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/internal/disk/tools"
+	"disk-health-exporter/internal/statemap"
+	"disk-health-exporter/pkg/types"
+)
+
+// Ensure Plugin implements the SnapshotToolInterface
+var _ tools.SnapshotToolInterface = (*Plugin)(nil)
+
+// DefaultTimeout limits a plugin run when no timeout is configured
+const DefaultTimeout = 10 * time.Second
+
+// Reasons for a failed or partially rejected plugin run
+const (
+	ReasonExec       = "exec"       // The executable could not be run or exited with an error
+	ReasonTimeout    = "timeout"    // The run exceeded its timeout and was killed
+	ReasonDecode     = "decode"     // The output is not a JSON document of the documented shape
+	ReasonValidation = "validation" // Entries failed validation and were dropped
+)
+
+// Status describes the runs of a plugin
+type Status struct {
+	Up       bool           // Whether the latest run produced a usable document
+	Duration time.Duration  // Run time of the latest run
+	Invalid  map[string]int // Entries dropped by validation in the latest run, keyed by kind
+	Errors   map[string]int // Failed runs and runs with dropped entries since startup, keyed by reason
+}
+
+// Plugin is an external executable run on every collection
+type Plugin struct {
+	name    string
+	command string
+	args    []string
+	timeout time.Duration
+
+	healthStates *statemap.Table[types.HealthStatus]
+	arrayStates  *statemap.Table[types.RAIDState]
+	rebuilds     *statemap.Table[bool]
+
+	mu     sync.Mutex
+	status Status
+}
+
+// New creates a plugin from its configuration
+func New(cfg config.PluginConfig) (*Plugin, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("plugin name is required")
+	}
+	if cfg.Command == "" {
+		return nil, fmt.Errorf("plugin %s: command is required", cfg.Name)
+	}
+
+	timeout := DefaultTimeout
+	if cfg.Timeout != "" {
+		parsed, err := config.ParseDuration(cfg.Timeout)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("plugin %s: invalid timeout %q", cfg.Name, cfg.Timeout)
+		}
+		timeout = parsed
+	}
+
+	return &Plugin{
+		name:    cfg.Name,
+		command: cfg.Command,
+		args:    cfg.Args,
+		timeout: timeout,
+
+		healthStates: statemap.New(cfg.Name, pluginHealthStates),
+		arrayStates:  statemap.New(cfg.Name, pluginArrayStates),
+		rebuilds:     statemap.New(cfg.Name, pluginArrayRebuilds),
+
+		status: Status{Errors: make(map[string]int)},
+	}, nil
+}
+
+// IsAvailable checks if the plugin executable exists
+func (p *Plugin) IsAvailable() bool {
+	if strings.ContainsRune(p.command, os.PathSeparator) {
+		info, err := os.Stat(p.command)
+		return err == nil && !info.IsDir()
+	}
+	_, err := exec.LookPath(p.command)
+	return err == nil
+}
+
+// GetVersion returns the plugin version. Plugins report no version.
+func (p *Plugin) GetVersion() string {
+	return ""
+}
+
+// GetName returns the plugin name
+func (p *Plugin) GetName() string {
+	return p.name
+}
+
+// Source returns the name under which the plugin's disks are merged
+func (p *Plugin) Source() string {
+	return "plugin:" + p.name
+}
+
+// Status returns the status of the plugin's runs
+func (p *Plugin) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := p.status
+	status.Invalid = copyCounts(p.status.Invalid)
+	status.Errors = copyCounts(p.status.Errors)
+	return status
+}
+
+// GetSnapshot runs the plugin and returns the disks and RAID arrays it reports.
+// A failed run reports nothing; invalid entries are dropped and the rest kept.
+func (p *Plugin) GetSnapshot() ([]types.DiskInfo, []types.RAIDInfo) {
+	start := time.Now()
+	output, reason, err := p.run()
+	duration := time.Since(start)
+
+	if err != nil {
+		slog.Error("Plugin failed", "tool", p.name, "duration", duration, "err", err)
+		p.record(false, duration, nil, reason)
+		return nil, nil
+	}
+
+	result, err := Parse(output)
+	if err != nil {
+		slog.Error("Plugin returned invalid output", "tool", p.name, "err", err)
+		p.record(false, duration, nil, ReasonDecode)
+		return nil, nil
+	}
+
+	for _, problem := range result.Problems {
+		slog.Warn("Plugin entry dropped", "tool", p.name, "err", problem)
+	}
+	if len(result.Problems) > 0 {
+		reason = ReasonValidation
+	}
+
+	disks, raids := p.label(result.Disks, result.RAIDArrays)
+	p.record(true, duration, result.Invalid, reason)
+	return disks, raids
+}
+
+// run executes the plugin, returning its standard output
+func (p *Plugin) run() ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.command, p.args...)
+	// Children keeping stdout open must not block the collection past the timeout
+	cmd.WaitDelay = time.Second
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, ReasonTimeout, fmt.Errorf("timed out after %s", p.timeout)
+	}
+	if err != nil {
+		if message := firstLine(stderr.String()); message != "" {
+			return nil, ReasonExec, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, ReasonExec, err
+	}
+	return output, "", nil
+}
+
+// label fills in the controller and tool names a plugin may leave out, and
+// translates Health and State when HealthStatus and Status are left out
+func (p *Plugin) label(disks []types.DiskInfo, raids []types.RAIDInfo) ([]types.DiskInfo, []types.RAIDInfo) {
+	for i := range disks {
+		if disks[i].HealthStatus == types.HealthStatusUnknown {
+			disks[i].HealthStatus = p.healthStates.Map(disks[i].Health)
+		}
+		if disks[i].RaidDrive != nil && disks[i].RaidDrive.ToolName == "" {
+			disks[i].RaidDrive.ToolName = p.name
+		}
+	}
+	for i := range raids {
+		if raids[i].Status == types.RAIDStateUnknown {
+			raids[i].Status = p.arrayStates.Map(raids[i].State)
+		}
+		if rebuilding, _ := p.rebuilds.Lookup(raids[i].State); rebuilding {
+			raids[i].Rebuilding = true
+		}
+		if raids[i].Controller == "" {
+			raids[i].Controller = p.name
+		}
+		if raids[i].Type == "" {
+			raids[i].Type = "plugin"
+		}
+		if raids[i].Battery != nil && raids[i].Battery.ToolName == "" {
+			raids[i].Battery.ToolName = p.name
+		}
+	}
+	return disks, raids
+}
+
+// record stores the outcome of a run
+func (p *Plugin) record(up bool, duration time.Duration, invalid map[string]int, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status.Up = up
+	p.status.Duration = duration
+	p.status.Invalid = invalid
+	if reason != "" {
+		p.status.Errors[reason]++
+	}
+}
+
+// copyCounts returns a copy of a count map
+func copyCounts(counts map[string]int) map[string]int {
+	copied := make(map[string]int, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
+
+// firstLine returns the first non-empty line of a text
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
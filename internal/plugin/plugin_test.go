@@ -0,0 +1,269 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/internal/statemap"
+	"disk-health-exporter/pkg/types"
+)
+
+// stubPlugin writes a shell script with the given body and returns a plugin running it
+func stubPlugin(t *testing.T, body string, timeout string) *Plugin {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("stub plugins are shell scripts")
+	}
+
+	path := filepath.Join(t.TempDir(), "stub-plugin")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatalf("Failed to write stub plugin: %v", err)
+	}
+
+	p, err := New(config.PluginConfig{Name: "stub", Command: path, Timeout: timeout})
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	return p
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   config.PluginConfig
+		valid bool
+	}{
+		{"valid", config.PluginConfig{Name: "appliance", Command: "/bin/true"}, true},
+		{"day timeout", config.PluginConfig{Name: "appliance", Command: "/bin/true", Timeout: "1d"}, true},
+		{"missing name", config.PluginConfig{Command: "/bin/true"}, false},
+		{"missing command", config.PluginConfig{Name: "appliance"}, false},
+		{"invalid timeout", config.PluginConfig{Name: "appliance", Command: "/bin/true", Timeout: "soon"}, false},
+		{"negative timeout", config.PluginConfig{Name: "appliance", Command: "/bin/true", Timeout: "-1s"}, false},
+	}
+
+	for _, tt := range tests {
+		p, err := New(tt.cfg)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid=%v, got %v", tt.name, tt.valid, err)
+		}
+		if err == nil && p.Source() != "plugin:appliance" {
+			t.Errorf("%s: unexpected source %q", tt.name, p.Source())
+		}
+	}
+
+	p, _ := New(config.PluginConfig{Name: "appliance", Command: "/bin/true"})
+	if p.timeout != DefaultTimeout {
+		t.Errorf("Expected default timeout %s, got %s", DefaultTimeout, p.timeout)
+	}
+}
+
+func TestSamplePlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the sample plugin is a shell script")
+	}
+
+	p, err := New(config.PluginConfig{Name: "sample", Command: "../../docs/example/plugins/sample-plugin.sh"})
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	if !p.IsAvailable() {
+		t.Fatalf("Expected the sample plugin to be available")
+	}
+
+	disks, raids := p.GetSnapshot()
+	if len(disks) != 2 || len(raids) != 1 {
+		t.Fatalf("Expected 2 disks and 1 array, got %d and %d", len(disks), len(raids))
+	}
+	if disks[1].Health != "Predictive Failure" || disks[1].ReallocatedSectors != 48 {
+		t.Errorf("Unexpected disk %+v", disks[1])
+	}
+	raid := raids[0]
+	if raid.Status != 1 || raid.Battery == nil || raid.Battery.ToolName != "sample" {
+		t.Errorf("Unexpected array %+v", raid)
+	}
+
+	status := p.Status()
+	if !status.Up || len(status.Errors) != 0 || status.Invalid[KindDisk] != 0 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+}
+
+func TestPluginInvalidEntries(t *testing.T) {
+	p := stubPlugin(t, `cat <<'JSON'
+{
+  "version": 1,
+  "disks": [
+    {"Device": "appliance-bay1", "Health": "OK"},
+    {"Health": "OK"},
+    {"Device": "appliance-bay3", "Temprature": 30},
+    {"Device": "appliance-bay4", "PowerOnHours": "many"}
+  ],
+  "raid_arrays": [
+    {"ArrayID": "vol0", "State": "Degraded", "Battery": {"AdapterID": 0}},
+    {"ArrayID": "vol1", "Status": 7}
+  ]
+}
+JSON`, "")
+
+	disks, raids := p.GetSnapshot()
+	if len(disks) != 1 || disks[0].Device != "appliance-bay1" {
+		t.Errorf("Expected only the valid disk, got %+v", disks)
+	}
+	if len(raids) != 1 || raids[0].Status != 2 || raids[0].Battery != nil || raids[0].Controller != "stub" || raids[0].Type != "plugin" {
+		t.Errorf("Expected the degraded array without its battery, got %+v", raids)
+	}
+
+	status := p.Status()
+	if !status.Up {
+		t.Errorf("Expected a partially valid document to count as up")
+	}
+	expected := map[string]int{KindDisk: 3, KindRAIDArray: 1, KindBattery: 1}
+	for kind, count := range expected {
+		if status.Invalid[kind] != count {
+			t.Errorf("Expected %d invalid %s entries, got %d", count, kind, status.Invalid[kind])
+		}
+	}
+	if status.Errors[ReasonValidation] != 1 {
+		t.Errorf("Expected one validation error, got %v", status.Errors)
+	}
+}
+
+func TestPluginFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		timeout string
+		reason  string
+	}{
+		{"non-zero exit", "echo 'appliance unreachable' >&2; exit 3", "", ReasonExec},
+		{"timeout", "sleep 5; echo '{\"version\": 1}'", "200ms", ReasonTimeout},
+		{"not JSON", "echo 'OK'", "", ReasonDecode},
+		{"wrong version", `echo '{"version": 2, "disks": []}'`, "", ReasonDecode},
+		{"unknown section", `echo '{"version": 1, "volumes": []}'`, "", ReasonDecode},
+		{"trailing data", `echo '{"version": 1} {"version": 1}'`, "", ReasonDecode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := stubPlugin(t, tt.body, tt.timeout)
+
+			start := time.Now()
+			disks, raids := p.GetSnapshot()
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("Plugin run took %s", elapsed)
+			}
+			if disks != nil || raids != nil {
+				t.Errorf("Expected no results, got %d disks and %d arrays", len(disks), len(raids))
+			}
+
+			status := p.Status()
+			if status.Up || status.Errors[tt.reason] != 1 {
+				t.Errorf("Expected a %s error, got %+v", tt.reason, status)
+			}
+		})
+	}
+}
+
+func TestPluginErrorsAccumulate(t *testing.T) {
+	p := stubPlugin(t, "exit 1", "")
+	p.GetSnapshot()
+	p.GetSnapshot()
+
+	if errors := p.Status().Errors[ReasonExec]; errors != 2 {
+		t.Errorf("Expected 2 exec errors, got %d", errors)
+	}
+}
+
+func TestParseClearsProvenance(t *testing.T) {
+	result, err := Parse([]byte(`{"version": 1, "disks": [{"Device": "bay1", "Provenance": {"health": "smartctl"}}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Disks) != 1 || result.Disks[0].Provenance != nil {
+		t.Errorf("Expected provenance to be cleared, got %+v", result.Disks)
+	}
+}
+
+func TestFirstLine(t *testing.T) {
+	if line := firstLine("\n  appliance unreachable \nretrying\n"); line != "appliance unreachable" {
+		t.Errorf("Unexpected first line %q", line)
+	}
+	if line := firstLine(strings.Repeat("\n", 3)); line != "" {
+		t.Errorf("Expected no line, got %q", line)
+	}
+}
+
+func TestPluginStates(t *testing.T) {
+	expectedHealth := map[string]types.HealthStatus{
+		"OK":                 types.HealthStatusOK,
+		"PASSED":             types.HealthStatusOK,
+		"Healthy":            types.HealthStatusOK,
+		"Warning":            types.HealthStatusWarning,
+		"Predictive Failure": types.HealthStatusWarning,
+		"Rebuilding":         types.HealthStatusWarning,
+		"Critical":           types.HealthStatusCritical,
+		"FAILED":             types.HealthStatusCritical,
+		"Failing":            types.HealthStatusCritical,
+		"Offline":            types.HealthStatusCritical,
+	}
+	if !reflect.DeepEqual(pluginHealthStates, expectedHealth) {
+		t.Errorf("Unexpected health states %v", pluginHealthStates)
+	}
+	expectedArrays := map[string]types.RAIDState{
+		"Optimal":    types.RAIDStateOptimal,
+		"OK":         types.RAIDStateOptimal,
+		"Healthy":    types.RAIDStateOptimal,
+		"Degraded":   types.RAIDStateDegraded,
+		"Rebuilding": types.RAIDStateDegraded,
+		"Failed":     types.RAIDStateFailed,
+		"Offline":    types.RAIDStateFailed,
+	}
+	if !reflect.DeepEqual(pluginArrayStates, expectedArrays) {
+		t.Errorf("Unexpected array states %v", pluginArrayStates)
+	}
+	if expectedRebuilds := map[string]bool{"Rebuilding": true}; !reflect.DeepEqual(pluginArrayRebuilds, expectedRebuilds) {
+		t.Errorf("Unexpected rebuilding states %v", pluginArrayRebuilds)
+	}
+
+	statemap.Reset()
+	defer statemap.Reset()
+
+	p := stubPlugin(t, `cat <<'JSON'
+{
+  "version": 1,
+  "disks": [
+    {"Device": "appliance-bay1", "Health": "predictive failure"},
+    {"Device": "appliance-bay2", "Health": "Not OK"},
+    {"Device": "appliance-bay3", "Health": "Replace soon", "HealthStatus": 3}
+  ],
+  "raid_arrays": [
+    {"ArrayID": "vol0", "State": "Rebuilding"},
+    {"ArrayID": "vol1", "State": "Degraded", "Rebuilding": true},
+    {"ArrayID": "vol2", "State": "Degraded"}
+  ]
+}
+JSON`, "")
+
+	disks, raids := p.GetSnapshot()
+	if len(disks) != 3 || disks[0].HealthStatus != types.HealthStatusWarning || disks[1].HealthStatus != types.HealthStatusUnknown ||
+		disks[2].HealthStatus != types.HealthStatusCritical {
+		t.Errorf("Unexpected disk health %+v", disks)
+	}
+	if len(raids) != 3 || raids[0].Status != types.RAIDStateDegraded {
+		t.Fatalf("Expected the rebuilding array to be degraded, got %+v", raids)
+	}
+	if !raids[0].Rebuilding || !raids[1].Rebuilding || raids[2].Rebuilding {
+		t.Errorf("Expected vol0 and vol1 to be rebuilding, got %+v", raids)
+	}
+
+	expected := []statemap.UnmappedState{{Tool: "stub", State: "Not OK"}}
+	if unmapped := statemap.Unmapped(); !reflect.DeepEqual(unmapped, expected) {
+		t.Errorf("Expected unmapped %v, got %v", expected, unmapped)
+	}
+}
@@ -0,0 +1,202 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"disk-health-exporter/pkg/types"
+)
+
+// SchemaVersion is the version of the plugin output format
+const SchemaVersion = 1
+
+// Kinds of entries in a plugin document
+const (
+	KindDisk      = "disk"
+	KindRAIDArray = "raid_array"
+	KindBattery   = "battery"
+)
+
+// pluginHealthStates translates the Health of plugin disks that leave out HealthStatus
+var pluginHealthStates = map[string]types.HealthStatus{
+	"OK":                 types.HealthStatusOK,
+	"PASSED":             types.HealthStatusOK,
+	"Healthy":            types.HealthStatusOK,
+	"Warning":            types.HealthStatusWarning,
+	"Predictive Failure": types.HealthStatusWarning,
+	"Rebuilding":         types.HealthStatusWarning,
+	"Critical":           types.HealthStatusCritical,
+	"FAILED":             types.HealthStatusCritical,
+	"Failing":            types.HealthStatusCritical,
+	"Offline":            types.HealthStatusCritical,
+}
+
+// pluginArrayStates translates the State of plugin arrays that leave out Status
+var pluginArrayStates = map[string]types.RAIDState{
+	"Optimal":    types.RAIDStateOptimal,
+	"OK":         types.RAIDStateOptimal,
+	"Healthy":    types.RAIDStateOptimal,
+	"Degraded":   types.RAIDStateDegraded,
+	"Rebuilding": types.RAIDStateDegraded,
+	"Failed":     types.RAIDStateFailed,
+	"Offline":    types.RAIDStateFailed,
+}
+
+// pluginArrayRebuilds lists the array states of a running rebuild
+var pluginArrayRebuilds = map[string]bool{
+	"Rebuilding": true,
+}
+
+// document is the top level of a plugin's output. Entries are decoded one by
+// one so that a single invalid entry does not discard the whole document.
+type document struct {
+	Version    int               `json:"version"`
+	Disks      []json.RawMessage `json:"disks"`
+	RAIDArrays []json.RawMessage `json:"raid_arrays"`
+}
+
+// Result is a validated plugin document
+type Result struct {
+	Disks      []types.DiskInfo
+	RAIDArrays []types.RAIDInfo
+	Invalid    map[string]int // Dropped entries by kind
+	Problems   []string       // Why each entry was dropped
+}
+
+// Parse decodes and validates a plugin document. An error is returned if the
+// document as a whole is unusable; invalid entries are dropped and listed in
+// the result's problems.
+func Parse(data []byte) (*Result, error) {
+	var doc document
+	if err := decodeStrict(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Version != SchemaVersion {
+		return nil, fmt.Errorf("unsupported version %d, expected %d", doc.Version, SchemaVersion)
+	}
+
+	result := &Result{Invalid: make(map[string]int)}
+	drop := func(kind string, index int, err error) {
+		result.Invalid[kind]++
+		result.Problems = append(result.Problems, fmt.Sprintf("dropping %s %d: %v", kind, index, err))
+	}
+
+	for i, raw := range doc.Disks {
+		var disk types.DiskInfo
+		if err := decodeStrict(raw, &disk); err != nil {
+			drop(KindDisk, i, err)
+			continue
+		}
+		if err := validateDisk(disk); err != nil {
+			drop(KindDisk, i, err)
+			continue
+		}
+		// Provenance and conflicts are recorded by the exporter while merging
+		disk.Provenance = nil
+		disk.SourceConflicts = nil
+		result.Disks = append(result.Disks, disk)
+	}
+
+	for i, raw := range doc.RAIDArrays {
+		var raid types.RAIDInfo
+		if err := decodeStrict(raw, &raid); err != nil {
+			drop(KindRAIDArray, i, err)
+			continue
+		}
+		if err := validateRAID(raid); err != nil {
+			drop(KindRAIDArray, i, err)
+			continue
+		}
+		if raid.Battery != nil {
+			if err := validateBattery(*raid.Battery); err != nil {
+				// The array itself is still usable
+				drop(KindBattery, i, err)
+				raid.Battery = nil
+			}
+		}
+		result.RAIDArrays = append(result.RAIDArrays, raid)
+	}
+
+	return result, nil
+}
+
+// decodeStrict decodes a single JSON value, rejecting unknown fields
+func decodeStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after JSON document")
+	}
+	return nil
+}
+
+// validateDisk checks the fields of a disk beyond their JSON types
+func validateDisk(disk types.DiskInfo) error {
+	if disk.Device == "" {
+		return errors.New("Device is required")
+	}
+	counters := []struct {
+		name  string
+		value int64
+	}{
+		{"Capacity", disk.Capacity},
+		{"PowerOnHours", disk.PowerOnHours},
+		{"PowerCycles", disk.PowerCycles},
+		{"ReallocatedSectors", disk.ReallocatedSectors},
+		{"PendingSectors", disk.PendingSectors},
+		{"UncorrectableErrors", disk.UncorrectableErrors},
+		{"MediaErrors", disk.MediaErrors},
+		{"ErrorLogEntries", disk.ErrorLogEntries},
+	}
+	for _, counter := range counters {
+		if counter.value < 0 {
+			return fmt.Errorf("%s must not be negative", counter.name)
+		}
+	}
+	if disk.Temperature < -40 || disk.Temperature > 150 {
+		return fmt.Errorf("Temperature %.0f out of range", disk.Temperature)
+	}
+	if disk.HealthStatus < types.HealthStatusUnknown || disk.HealthStatus > types.HealthStatusCritical {
+		return fmt.Errorf("HealthStatus %d out of range", disk.HealthStatus)
+	}
+	if disk.PercentageUsed < 0 || disk.AvailableSpare < 0 || disk.AvailableSpare > 100 {
+		return errors.New("PercentageUsed and AvailableSpare must be percentages")
+	}
+	return nil
+}
+
+// validateRAID checks the fields of a RAID array beyond their JSON types
+func validateRAID(raid types.RAIDInfo) error {
+	if raid.ArrayID == "" {
+		return errors.New("ArrayID is required")
+	}
+	if raid.Status < types.RAIDStateUnknown || raid.Status > types.RAIDStateFailed {
+		return fmt.Errorf("Status %d out of range", raid.Status)
+	}
+	if raid.RebuildProgress < 0 || raid.RebuildProgress > 100 || raid.ScrubProgress < 0 || raid.ScrubProgress > 100 {
+		return errors.New("RebuildProgress and ScrubProgress must be percentages")
+	}
+	if raid.NumDrives < 0 || raid.NumActiveDrives < 0 || raid.NumSpareDrives < 0 || raid.NumFailedDrives < 0 {
+		return errors.New("drive counts must not be negative")
+	}
+	if raid.ZFS != nil {
+		return errors.New("ZFS details are not supported from plugins")
+	}
+	return nil
+}
+
+// validateBattery checks the fields of a RAID battery beyond their JSON types
+func validateBattery(battery types.RAIDBatteryInfo) error {
+	if battery.AdapterID < 0 {
+		return errors.New("AdapterID must not be negative")
+	}
+	if battery.State == "" {
+		return errors.New("State is required")
+	}
+	return nil
+}
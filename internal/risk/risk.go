@@ -0,0 +1,219 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+// DefaultGrowthWindow is the window over which counter growth factors are measured
+const DefaultGrowthWindow = 24 * time.Hour
+
+// Factor names
+const (
+	FactorReallocatedSectors  = "reallocated_sectors"
+	FactorPendingSectors      = "pending_sectors"
+	FactorUncorrectableErrors = "uncorrectable_errors"
+	FactorMediaErrors         = "media_errors"
+	FactorCriticalWarning     = "critical_warning"
+	FactorPercentageUsed      = "percentage_used"
+	FactorErrorLogEntries     = "error_log_entries"
+	FactorTemperature         = "temperature"
+	FactorPowerOnHours        = "power_on_hours"
+	FactorReallocatedGrowth   = "reallocated_sectors_growth"
+	FactorPendingGrowth       = "pending_sectors_growth"
+	FactorMediaErrorGrowth    = "media_errors_growth"
+)
+
+// factor describes a single risk signal and how it is scaled into a contribution
+type factor struct {
+	name       string
+	weight     float64 // Contribution when fully saturated (0-1)
+	threshold  float64 // Values at or below this contribute nothing
+	saturation float64 // Values at or above this contribute the full weight
+}
+
+// defaultFactors returns the built-in factor table
+func defaultFactors() []factor {
+	return []factor{
+		{name: FactorReallocatedSectors, weight: 0.5, threshold: 0, saturation: 100},
+		{name: FactorPendingSectors, weight: 0.6, threshold: 0, saturation: 10},
+		{name: FactorUncorrectableErrors, weight: 0.6, threshold: 0, saturation: 10},
+		{name: FactorMediaErrors, weight: 0.5, threshold: 0, saturation: 10},
+		{name: FactorCriticalWarning, weight: 0.8, threshold: 0, saturation: 1},
+		{name: FactorPercentageUsed, weight: 0.4, threshold: 50, saturation: 100},
+		{name: FactorErrorLogEntries, weight: 0.2, threshold: 0, saturation: 100},
+		{name: FactorTemperature, weight: 0.2, threshold: 50, saturation: 70},
+		{name: FactorPowerOnHours, weight: 0.15, threshold: 26280, saturation: 61320}, // 3 to 7 years
+		{name: FactorReallocatedGrowth, weight: 0.6, threshold: 0, saturation: 10},
+		{name: FactorPendingGrowth, weight: 0.7, threshold: 0, saturation: 5},
+		{name: FactorMediaErrorGrowth, weight: 0.6, threshold: 0, saturation: 5},
+	}
+}
+
+// Growth holds the increase of error counters over the model's growth window
+type Growth struct {
+	ReallocatedSectors int64
+	PendingSectors     int64
+	MediaErrors        int64
+}
+
+// Model computes predictive failure risk scores for disks
+type Model struct {
+	factors []factor
+	levels  config.RiskLevelConfig
+	window  time.Duration
+}
+
+// New creates a risk model, applying configuration overrides on top of the defaults
+func New(cfg config.RiskConfig) (*Model, error) {
+	m := &Model{
+		factors: defaultFactors(),
+		levels: config.RiskLevelConfig{
+			Medium:   0.25,
+			High:     0.5,
+			Critical: 0.8,
+		},
+		window: DefaultGrowthWindow,
+	}
+
+	if cfg.GrowthWindow != "" {
+		window, err := config.ParseDuration(cfg.GrowthWindow)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid growth_window %q", cfg.GrowthWindow)
+		}
+		m.window = window
+	}
+
+	if cfg.Levels.Medium > 0 {
+		m.levels.Medium = cfg.Levels.Medium
+	}
+	if cfg.Levels.High > 0 {
+		m.levels.High = cfg.Levels.High
+	}
+	if cfg.Levels.Critical > 0 {
+		m.levels.Critical = cfg.Levels.Critical
+	}
+	if m.levels.Medium >= m.levels.High || m.levels.High >= m.levels.Critical || m.levels.Critical > 1 {
+		return nil, fmt.Errorf("risk levels must satisfy medium < high < critical <= 1")
+	}
+
+	for name, override := range cfg.Factors {
+		idx := -1
+		for i, f := range m.factors {
+			if f.name == name {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return nil, fmt.Errorf("unknown risk factor %q", name)
+		}
+
+		if override.Weight != nil {
+			if *override.Weight < 0 || *override.Weight > 1 {
+				return nil, fmt.Errorf("risk factor %q weight must be between 0 and 1", name)
+			}
+			m.factors[idx].weight = *override.Weight
+		}
+		if override.Threshold != nil {
+			m.factors[idx].threshold = *override.Threshold
+		}
+		if override.Saturation > 0 {
+			m.factors[idx].saturation = override.Saturation
+		}
+		if m.factors[idx].saturation <= m.factors[idx].threshold {
+			return nil, fmt.Errorf("risk factor %q saturation must be above %v", name, m.factors[idx].threshold)
+		}
+	}
+
+	return m, nil
+}
+
+// GrowthWindow returns the window over which counter growth should be measured
+func (m *Model) GrowthWindow() time.Duration {
+	return m.window
+}
+
+// Assess computes the failure risk of a disk given its counter growth over the growth window
+func (m *Model) Assess(disk types.DiskInfo, growth Growth) types.FailureRiskInfo {
+	values := observe(disk)
+	values[FactorReallocatedGrowth] = float64(growth.ReallocatedSectors)
+	values[FactorPendingGrowth] = float64(growth.PendingSectors)
+	values[FactorMediaErrorGrowth] = float64(growth.MediaErrors)
+
+	// Combine contributions as independent probabilities (noisy-OR),
+	// so the score stays within 0-1 regardless of how many factors fire
+	survival := 1.0
+	var contributing []types.RiskFactor
+	for _, f := range m.factors {
+		contribution := f.contribution(values[f.name])
+		if contribution <= 0 {
+			continue
+		}
+		survival *= 1 - contribution
+		contributing = append(contributing, types.RiskFactor{
+			Name:         f.name,
+			Value:        values[f.name],
+			Contribution: contribution,
+		})
+	}
+
+	sort.SliceStable(contributing, func(i, j int) bool {
+		return contributing[i].Contribution > contributing[j].Contribution
+	})
+
+	score := 1 - survival
+	return types.FailureRiskInfo{
+		Score:   score,
+		Level:   m.level(score),
+		Factors: contributing,
+	}
+}
+
+// level maps a score to its categorical risk level
+func (m *Model) level(score float64) types.RiskLevel {
+	switch {
+	case score >= m.levels.Critical:
+		return types.RiskLevelCritical
+	case score >= m.levels.High:
+		return types.RiskLevelHigh
+	case score >= m.levels.Medium:
+		return types.RiskLevelMedium
+	default:
+		return types.RiskLevelLow
+	}
+}
+
+// contribution scales a raw value linearly between threshold and saturation
+func (f factor) contribution(value float64) float64 {
+	if f.weight <= 0 || value <= f.threshold {
+		return 0
+	}
+	scaled := math.Min((value-f.threshold)/(f.saturation-f.threshold), 1)
+	return f.weight * scaled
+}
+
+// observe extracts the point-in-time factor values from a disk
+func observe(disk types.DiskInfo) map[string]float64 {
+	criticalWarning := 0.0
+	if disk.CriticalWarning > 0 {
+		criticalWarning = 1
+	}
+
+	return map[string]float64{
+		FactorReallocatedSectors:  float64(disk.ReallocatedSectors),
+		FactorPendingSectors:      float64(disk.PendingSectors),
+		FactorUncorrectableErrors: float64(disk.UncorrectableErrors),
+		FactorMediaErrors:         float64(disk.MediaErrors),
+		FactorCriticalWarning:     criticalWarning,
+		FactorPercentageUsed:      float64(max(disk.PercentageUsed, disk.WearLeveling)),
+		FactorErrorLogEntries:     float64(disk.ErrorLogEntries),
+		FactorTemperature:         disk.Temperature,
+		FactorPowerOnHours:        float64(disk.PowerOnHours),
+	}
+}
@@ -0,0 +1,245 @@
+package risk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		name          string
+		disk          types.DiskInfo
+		expectedScore float64
+		expectedLevel types.RiskLevel
+		topFactor     string
+	}{
+		{
+			name:          "healthy disk",
+			disk:          types.DiskInfo{Serial: "HEALTHY01", Temperature: 35, PowerOnHours: 1000},
+			expectedScore: 0,
+			expectedLevel: types.RiskLevelLow,
+		},
+		{
+			name:          "few reallocated sectors",
+			disk:          types.DiskInfo{Serial: "REALLOC01", ReallocatedSectors: 20},
+			expectedScore: 0.1,
+			expectedLevel: types.RiskLevelLow,
+			topFactor:     FactorReallocatedSectors,
+		},
+		{
+			name:          "saturated pending sectors",
+			disk:          types.DiskInfo{Serial: "PENDING01", PendingSectors: 50},
+			expectedScore: 0.6,
+			expectedLevel: types.RiskLevelHigh,
+			topFactor:     FactorPendingSectors,
+		},
+		{
+			name:          "nvme critical warning",
+			disk:          types.DiskInfo{Serial: "NVMEWARN1", CriticalWarning: 4},
+			expectedScore: 0.8,
+			expectedLevel: types.RiskLevelCritical,
+			topFactor:     FactorCriticalWarning,
+		},
+		{
+			name:          "worn ssd",
+			disk:          types.DiskInfo{Serial: "WORNSSD01", PercentageUsed: 75},
+			expectedScore: 0.2,
+			expectedLevel: types.RiskLevelLow,
+			topFactor:     FactorPercentageUsed,
+		},
+		{
+			name:          "hot disk uses current temperature",
+			disk:          types.DiskInfo{Serial: "HOTDISK01", Temperature: 60, DriveTemperatureMax: 75},
+			expectedScore: 0.1,
+			expectedLevel: types.RiskLevelLow,
+			topFactor:     FactorTemperature,
+		},
+		{
+			// The lifetime maximum recorded by the drive does not count
+			name:          "cool disk that was once hot",
+			disk:          types.DiskInfo{Serial: "ONCEHOT01", Temperature: 38, DriveTemperatureMax: 68},
+			expectedScore: 0,
+			expectedLevel: types.RiskLevelLow,
+		},
+		{
+			name:          "old disk",
+			disk:          types.DiskInfo{Serial: "OLDDISK01", PowerOnHours: 61320},
+			expectedScore: 0.15,
+			expectedLevel: types.RiskLevelLow,
+			topFactor:     FactorPowerOnHours,
+		},
+		{
+			name:          "combined factors use noisy-or",
+			disk:          types.DiskInfo{Serial: "COMBINED1", PendingSectors: 5, UncorrectableErrors: 5},
+			expectedScore: 1 - 0.7*0.7,
+			expectedLevel: types.RiskLevelHigh,
+			topFactor:     FactorPendingSectors,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := New(config.RiskConfig{})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			result := model.Assess(tt.disk, Growth{})
+
+			if !approxEqual(result.Score, tt.expectedScore) {
+				t.Errorf("Expected score %v, got %v", tt.expectedScore, result.Score)
+			}
+			if result.Level != tt.expectedLevel {
+				t.Errorf("Expected level %s, got %s", tt.expectedLevel, result.Level)
+			}
+			if tt.topFactor == "" {
+				if len(result.Factors) != 0 {
+					t.Errorf("Expected no contributing factors, got %v", result.Factors)
+				}
+			} else if len(result.Factors) == 0 || result.Factors[0].Name != tt.topFactor {
+				t.Errorf("Expected top factor %s, got %v", tt.topFactor, result.Factors)
+			}
+		})
+	}
+}
+
+func TestAssessGrowth(t *testing.T) {
+	tests := []struct {
+		name          string
+		growth        Growth
+		expectedScore float64
+		topFactor     string
+	}{
+		{"no growth", Growth{}, 0, ""},
+		{"reallocated growth", Growth{ReallocatedSectors: 5}, 0.3, FactorReallocatedGrowth},
+		{"pending growth saturates", Growth{PendingSectors: 8}, 0.7, FactorPendingGrowth},
+		{"media error growth", Growth{MediaErrors: 1}, 0.12, FactorMediaErrorGrowth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := New(config.RiskConfig{})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			result := model.Assess(types.DiskInfo{Serial: "GROWTH001"}, tt.growth)
+
+			if !approxEqual(result.Score, tt.expectedScore) {
+				t.Errorf("Expected score %v, got %v", tt.expectedScore, result.Score)
+			}
+			if tt.topFactor != "" && (len(result.Factors) == 0 || result.Factors[0].Name != tt.topFactor) {
+				t.Errorf("Expected top factor %s, got %v", tt.topFactor, result.Factors)
+			}
+		})
+	}
+}
+
+func TestGrowthWindow(t *testing.T) {
+	model, err := New(config.RiskConfig{GrowthWindow: "7d"})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if model.GrowthWindow() != 7*24*time.Hour {
+		t.Errorf("Expected growth window 7d, got %v", model.GrowthWindow())
+	}
+}
+
+func TestNewWithOverrides(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           config.RiskConfig
+		disk          types.DiskInfo
+		expectedScore float64
+		expectedLevel types.RiskLevel
+	}{
+		{
+			name: "weight override",
+			cfg: config.RiskConfig{Factors: map[string]config.RiskFactorConfig{
+				FactorReallocatedSectors: {Weight: floatPtr(1)},
+			}},
+			disk:          types.DiskInfo{ReallocatedSectors: 100},
+			expectedScore: 1,
+			expectedLevel: types.RiskLevelCritical,
+		},
+		{
+			name: "zero weight disables factor",
+			cfg: config.RiskConfig{Factors: map[string]config.RiskFactorConfig{
+				FactorPowerOnHours: {Weight: floatPtr(0)},
+			}},
+			disk:          types.DiskInfo{PowerOnHours: 100000},
+			expectedScore: 0,
+			expectedLevel: types.RiskLevelLow,
+		},
+		{
+			name: "threshold and saturation override",
+			cfg: config.RiskConfig{Factors: map[string]config.RiskFactorConfig{
+				FactorTemperature: {Threshold: floatPtr(40), Saturation: 60},
+			}},
+			disk:          types.DiskInfo{Temperature: 50},
+			expectedScore: 0.1,
+			expectedLevel: types.RiskLevelLow,
+		},
+		{
+			name:          "level thresholds override",
+			cfg:           config.RiskConfig{Levels: config.RiskLevelConfig{Medium: 0.05, High: 0.1, Critical: 0.2}},
+			disk:          types.DiskInfo{ReallocatedSectors: 30},
+			expectedScore: 0.15,
+			expectedLevel: types.RiskLevelHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := New(tt.cfg)
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			result := model.Assess(tt.disk, Growth{})
+			if !approxEqual(result.Score, tt.expectedScore) {
+				t.Errorf("Expected score %v, got %v", tt.expectedScore, result.Score)
+			}
+			if result.Level != tt.expectedLevel {
+				t.Errorf("Expected level %s, got %s", tt.expectedLevel, result.Level)
+			}
+		})
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.RiskConfig
+	}{
+		{"unknown factor", config.RiskConfig{Factors: map[string]config.RiskFactorConfig{"bogus": {}}}},
+		{"weight above one", config.RiskConfig{Factors: map[string]config.RiskFactorConfig{
+			FactorPendingSectors: {Weight: floatPtr(1.5)},
+		}}},
+		{"saturation below threshold", config.RiskConfig{Factors: map[string]config.RiskFactorConfig{
+			FactorTemperature: {Saturation: 40},
+		}}},
+		{"unordered levels", config.RiskConfig{Levels: config.RiskLevelConfig{Medium: 0.6, High: 0.5}}},
+		{"invalid growth window", config.RiskConfig{GrowthWindow: "soon"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
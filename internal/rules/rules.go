@@ -0,0 +1,356 @@
+// Package rules evaluates disks, RAID arrays and controller batteries against
+// configurable thresholds. Disks are grouped into classes (hdd, ssd, nvme, or
+// a class selected by model) that each have their own thresholds.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/internal/maintenance"
+	"disk-health-exporter/pkg/types"
+)
+
+// DefaultGrowthWindow is the window over which sector growth is measured
+const DefaultGrowthWindow = 24 * time.Hour
+
+// Built-in disk classes
+const (
+	ClassHDD  = "hdd"
+	ClassSSD  = "ssd"
+	ClassNVMe = "nvme"
+)
+
+// Rule names
+const (
+	RuleTemperature        = "temperature"
+	RuleWear               = "wear"
+	RuleAvailableSpare     = "available_spare"
+	RuleReallocatedGrowth  = "reallocated_growth"
+	RulePendingGrowth      = "pending_growth"
+	RuleBatteryTemperature = "battery_temperature"
+	RuleRebuildDuration    = "rebuild_duration"
+)
+
+// Rules lists every rule name
+var Rules = []string{
+	RuleTemperature,
+	RuleWear,
+	RuleAvailableSpare,
+	RuleReallocatedGrowth,
+	RulePendingGrowth,
+	RuleBatteryTemperature,
+	RuleRebuildDuration,
+}
+
+// threshold fires a rule at warning and critical values. Rules alerting on
+// low values (available spare) fire at or below the thresholds, all others at
+// or above them.
+type threshold struct {
+	enabled  bool
+	warning  float64
+	critical float64
+}
+
+// thresholds holds the disk thresholds of a class by rule name
+type thresholds map[string]threshold
+
+// class selects disks by model and holds their thresholds
+type class struct {
+	name       string
+	pattern    *regexp.Regexp
+	thresholds thresholds
+}
+
+// defaultClasses returns the built-in thresholds of each class
+func defaultClasses() map[string]thresholds {
+	growth := thresholds{
+		RuleReallocatedGrowth: {enabled: true, warning: 1, critical: 10},
+		RulePendingGrowth:     {enabled: true, warning: 1, critical: 5},
+	}
+	return map[string]thresholds{
+		ClassHDD: merge(growth, thresholds{
+			RuleTemperature: {enabled: true, warning: 50, critical: 60},
+		}),
+		ClassSSD: merge(growth, thresholds{
+			RuleTemperature: {enabled: true, warning: 60, critical: 70},
+			RuleWear:        {enabled: true, warning: 80, critical: 90},
+		}),
+		ClassNVMe: {
+			RuleTemperature:    {enabled: true, warning: 70, critical: 80},
+			RuleWear:           {enabled: true, warning: 80, critical: 90},
+			RuleAvailableSpare: {enabled: true, warning: 20, critical: 10},
+		},
+	}
+}
+
+// Growth holds the increase of sector counters over the engine's growth window
+type Growth struct {
+	ReallocatedSectors int64
+	PendingSectors     int64
+}
+
+// Engine evaluates threshold rules
+type Engine struct {
+	disabled           bool
+	window             time.Duration
+	builtin            map[string]thresholds // Built-in classes, adjusted by configuration
+	classes            []class               // Classes selected by model, in configuration order
+	batteryTemperature threshold
+	rebuildDuration    threshold // In hours
+
+	mu       sync.Mutex
+	rebuilds map[string]time.Time // Arrays seen rebuilding by maintenance.ArrayKey, with the time first seen
+}
+
+// New creates a rule engine, applying configuration on top of the defaults
+func New(cfg config.RulesConfig) (*Engine, error) {
+	e := &Engine{
+		disabled:           cfg.Disabled,
+		window:             DefaultGrowthWindow,
+		builtin:            defaultClasses(),
+		batteryTemperature: threshold{enabled: true, warning: 50, critical: 60},
+		rebuildDuration:    threshold{enabled: true, warning: 24, critical: 72},
+		rebuilds:           make(map[string]time.Time),
+	}
+
+	if cfg.GrowthWindow != "" {
+		window, err := config.ParseDuration(cfg.GrowthWindow)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid growth_window %q", cfg.GrowthWindow)
+		}
+		e.window = window
+	}
+
+	var err error
+	if e.batteryTemperature, err = apply(e.batteryTemperature, cfg.BatteryTemperature, false); err != nil {
+		return nil, fmt.Errorf("rule %s: %w", RuleBatteryTemperature, err)
+	}
+	if e.rebuildDuration, err = apply(e.rebuildDuration, cfg.RebuildDuration, false); err != nil {
+		return nil, fmt.Errorf("rule %s: %w", RuleRebuildDuration, err)
+	}
+
+	for _, cc := range cfg.Classes {
+		if cc.Name == "" {
+			return nil, fmt.Errorf("rule class without name")
+		}
+
+		if cc.Model == "" {
+			base, ok := e.builtin[cc.Name]
+			if !ok {
+				return nil, fmt.Errorf("rule class %q needs a model pattern", cc.Name)
+			}
+			adjusted, err := applyClass(base, cc)
+			if err != nil {
+				return nil, err
+			}
+			e.builtin[cc.Name] = adjusted
+			continue
+		}
+
+		pattern, err := regexp.Compile(cc.Model)
+		if err != nil {
+			return nil, fmt.Errorf("rule class %q: invalid model pattern %q: %w", cc.Name, cc.Model, err)
+		}
+		// Thresholds left unset are taken from the disk's built-in class when evaluating
+		overrides, err := applyClass(thresholds{}, cc)
+		if err != nil {
+			return nil, err
+		}
+		e.classes = append(e.classes, class{name: cc.Name, pattern: pattern, thresholds: overrides})
+	}
+
+	return e, nil
+}
+
+// applyClass applies the thresholds of a class configuration on top of base
+func applyClass(base thresholds, cc config.RuleClassConfig) (thresholds, error) {
+	result := merge(base, nil)
+	for _, rule := range []struct {
+		name string
+		cfg  config.ThresholdConfig
+	}{
+		{RuleTemperature, cc.Temperature},
+		{RuleWear, cc.Wear},
+		{RuleAvailableSpare, cc.AvailableSpare},
+		{RuleReallocatedGrowth, cc.ReallocatedGrowth},
+		{RulePendingGrowth, cc.PendingGrowth},
+	} {
+		if rule.cfg.Warning == nil && rule.cfg.Critical == nil && !rule.cfg.Disabled {
+			continue
+		}
+		t, err := apply(result[rule.name], rule.cfg, rule.name == RuleAvailableSpare)
+		if err != nil {
+			return nil, fmt.Errorf("rule class %q rule %s: %w", cc.Name, rule.name, err)
+		}
+		result[rule.name] = t
+	}
+	return result, nil
+}
+
+// apply applies a threshold configuration on top of t
+func apply(t threshold, cfg config.ThresholdConfig, low bool) (threshold, error) {
+	if cfg.Disabled {
+		return threshold{}, nil
+	}
+	if cfg.Warning == nil && cfg.Critical == nil {
+		return t, nil
+	}
+	if !t.enabled && (cfg.Warning == nil || cfg.Critical == nil) {
+		return t, fmt.Errorf("both warning and critical are required")
+	}
+
+	t.enabled = true
+	if cfg.Warning != nil {
+		t.warning = *cfg.Warning
+	}
+	if cfg.Critical != nil {
+		t.critical = *cfg.Critical
+	}
+	if low && t.critical > t.warning {
+		return t, fmt.Errorf("critical %v must not be above warning %v", t.critical, t.warning)
+	}
+	if !low && t.critical < t.warning {
+		return t, fmt.Errorf("critical %v must not be below warning %v", t.critical, t.warning)
+	}
+	return t, nil
+}
+
+// merge returns the thresholds of base overlaid with those of overlay
+func merge(base, overlay thresholds) thresholds {
+	result := make(thresholds, len(base)+len(overlay))
+	for name, t := range base {
+		result[name] = t
+	}
+	for name, t := range overlay {
+		result[name] = t
+	}
+	return result
+}
+
+// GrowthWindow returns the window over which sector growth should be measured
+func (e *Engine) GrowthWindow() time.Duration {
+	return e.window
+}
+
+// DetectClass returns the built-in class of a disk: nvme by interface or
+// device name, hdd when a rotation rate is reported, and ssd when the drive
+// reports wear or names itself an SSD. Other disks are treated as hdd.
+func DetectClass(disk types.DiskInfo) string {
+	switch {
+	case strings.EqualFold(disk.Interface, "nvme") || strings.HasPrefix(disk.Device, "/dev/nvme"):
+		return ClassNVMe
+	case disk.RPM > 0:
+		return ClassHDD
+	case disk.WearLeveling > 0 || disk.PercentageUsed > 0 || strings.Contains(strings.ToUpper(disk.Model), "SSD"):
+		return ClassSSD
+	default:
+		return ClassHDD
+	}
+}
+
+// classify returns the class name and thresholds of a disk
+func (e *Engine) classify(disk types.DiskInfo) (string, thresholds) {
+	builtin := DetectClass(disk)
+	for _, c := range e.classes {
+		if c.pattern.MatchString(disk.Model) {
+			return c.name, merge(e.builtin[builtin], c.thresholds)
+		}
+	}
+	return builtin, e.builtin[builtin]
+}
+
+// EvaluateDisk returns the class of a disk and the rules firing for it
+func (e *Engine) EvaluateDisk(disk types.DiskInfo, growth Growth) (string, []types.RuleAlert) {
+	if e.disabled {
+		return "", nil
+	}
+	className, classThresholds := e.classify(disk)
+
+	var alerts []types.RuleAlert
+	check := func(rule string, value float64, low bool) {
+		if alert, ok := evaluate(rule, classThresholds[rule], value, low); ok {
+			alert.Device = disk.Device
+			alert.Serial = disk.Serial
+			alerts = append(alerts, alert)
+		}
+	}
+
+	if disk.Temperature > 0 {
+		check(RuleTemperature, disk.Temperature, false)
+	}
+	check(RuleWear, float64(max(disk.PercentageUsed, disk.WearLeveling)), false)
+	// Drives that do not report a spare leave it at zero
+	if disk.AvailableSpare > 0 {
+		check(RuleAvailableSpare, float64(disk.AvailableSpare), true)
+	}
+	check(RuleReallocatedGrowth, float64(growth.ReallocatedSectors), false)
+	check(RulePendingGrowth, float64(growth.PendingSectors), false)
+
+	return className, alerts
+}
+
+// EvaluateArrays sets the rules firing for each array and its battery. The
+// rebuild duration is measured from the first collection that saw the array
+// rebuilding, so a restart during a rebuild starts it over.
+func (e *Engine) EvaluateArrays(raids []types.RAIDInfo, now time.Time) {
+	if e.disabled {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rebuilding := make(map[string]time.Time)
+	for i := range raids {
+		raid := &raids[i]
+		key := maintenance.ArrayKey(*raid)
+
+		if raid.Rebuilding {
+			started, ok := e.rebuilds[key]
+			if !ok {
+				started = now
+			}
+			rebuilding[key] = started
+			if alert, ok := evaluate(RuleRebuildDuration, e.rebuildDuration, now.Sub(started).Hours(), false); ok {
+				alert.Device = key
+				raid.RuleAlerts = append(raid.RuleAlerts, alert)
+			}
+		}
+
+		if battery := raid.Battery; battery != nil && battery.Temperature > 0 {
+			if alert, ok := evaluate(RuleBatteryTemperature, e.batteryTemperature, float64(battery.Temperature), false); ok {
+				alert.Device = battery.ToolName + "/" + strconv.Itoa(battery.AdapterID)
+				raid.RuleAlerts = append(raid.RuleAlerts, alert)
+			}
+		}
+	}
+	e.rebuilds = rebuilding
+}
+
+// evaluate returns the alert of a rule for a value, if any
+func evaluate(rule string, t threshold, value float64, low bool) (types.RuleAlert, bool) {
+	if !t.enabled {
+		return types.RuleAlert{}, false
+	}
+
+	reached := func(limit float64) bool {
+		if low {
+			return value <= limit
+		}
+		return value >= limit
+	}
+
+	switch {
+	case reached(t.critical):
+		return types.RuleAlert{Rule: rule, Severity: types.RuleSeverityCritical, Value: value, Threshold: t.critical}, true
+	case reached(t.warning):
+		return types.RuleAlert{Rule: rule, Severity: types.RuleSeverityWarning, Value: value, Threshold: t.warning}, true
+	}
+	return types.RuleAlert{}, false
+}
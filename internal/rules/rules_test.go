@@ -0,0 +1,211 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+func float(v float64) *float64 {
+	return &v
+}
+
+// alertKeys returns the rule and severity of alerts in order
+func alertKeys(alerts []types.RuleAlert) string {
+	var keys []string
+	for _, alert := range alerts {
+		keys = append(keys, alert.Rule+"="+alert.Severity)
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestDetectClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		disk     types.DiskInfo
+		expected string
+	}{
+		{"nvme interface", types.DiskInfo{Device: "/dev/sda", Interface: "NVMe"}, ClassNVMe},
+		{"nvme device", types.DiskInfo{Device: "/dev/nvme0n1"}, ClassNVMe},
+		{"rotating", types.DiskInfo{Device: "/dev/sda", RPM: 7200, Model: "ST4000NM0035"}, ClassHDD},
+		{"wear reported", types.DiskInfo{Device: "/dev/sda", WearLeveling: 3}, ClassSSD},
+		{"ssd model", types.DiskInfo{Device: "/dev/sda", Model: "Samsung SSD 860 EVO"}, ClassSSD},
+		{"unknown", types.DiskInfo{Device: "raid-enc32-slot4"}, ClassHDD},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if class := DetectClass(tt.disk); class != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, class)
+			}
+		})
+	}
+}
+
+func TestEvaluateDiskDefaults(t *testing.T) {
+	engine, err := New(config.RulesConfig{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		disk     types.DiskInfo
+		growth   Growth
+		class    string
+		expected string
+	}{
+		{"healthy hdd", types.DiskInfo{RPM: 7200, Temperature: 40}, Growth{}, ClassHDD, ""},
+		{"warm hdd", types.DiskInfo{RPM: 7200, Temperature: 50}, Growth{}, ClassHDD, "temperature=warning"},
+		{"hot hdd", types.DiskInfo{RPM: 7200, Temperature: 61}, Growth{}, ClassHDD, "temperature=critical"},
+		{"warm ssd", types.DiskInfo{Model: "SSD", Temperature: 55}, Growth{}, ClassSSD, ""},
+		{"worn ssd", types.DiskInfo{WearLeveling: 85}, Growth{}, ClassSSD, "wear=warning"},
+		{"growing sectors", types.DiskInfo{RPM: 7200}, Growth{ReallocatedSectors: 12, PendingSectors: 1}, ClassHDD,
+			"reallocated_growth=critical,pending_growth=warning"},
+		{"nvme spare", types.DiskInfo{Interface: "NVMe", Temperature: 45, AvailableSpare: 8, PercentageUsed: 91}, Growth{}, ClassNVMe,
+			"wear=critical,available_spare=critical"},
+		{"nvme spare not reported", types.DiskInfo{Interface: "NVMe"}, Growth{}, ClassNVMe, ""},
+		// Growth rules are not defined for NVMe by default
+		{"nvme growth", types.DiskInfo{Interface: "NVMe"}, Growth{ReallocatedSectors: 100}, ClassNVMe, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, alerts := engine.EvaluateDisk(tt.disk, tt.growth)
+			if class != tt.class {
+				t.Errorf("Expected class %s, got %s", tt.class, class)
+			}
+			if keys := alertKeys(alerts); keys != tt.expected {
+				t.Errorf("Expected alerts %q, got %q", tt.expected, keys)
+			}
+		})
+	}
+}
+
+func TestEvaluateDiskClasses(t *testing.T) {
+	engine, err := New(config.RulesConfig{
+		Classes: []config.RuleClassConfig{
+			{Name: "archive", Model: "^WDC WD[0-9]+PURZ", Temperature: config.ThresholdConfig{Warning: float(45), Critical: float(55)}},
+			{Name: "hdd", Temperature: config.ThresholdConfig{Critical: float(65)}, PendingGrowth: config.ThresholdConfig{Disabled: true}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	disk := types.DiskInfo{Device: "/dev/sdb", Serial: "WD-1", Model: "WDC WD40PURZ-85AKKY0", RPM: 5400, Temperature: 47}
+	class, alerts := engine.EvaluateDisk(disk, Growth{ReallocatedSectors: 1, PendingSectors: 3})
+	if class != "archive" {
+		t.Errorf("Expected the archive class, got %s", class)
+	}
+	// The model class keeps the adjusted hdd thresholds it does not set
+	if keys := alertKeys(alerts); keys != "temperature=warning,reallocated_growth=warning" {
+		t.Errorf("Unexpected alerts %q", keys)
+	}
+	if alerts[0].Device != "/dev/sdb" || alerts[0].Serial != "WD-1" || alerts[0].Value != 47 || alerts[0].Threshold != 45 {
+		t.Errorf("Unexpected alert %+v", alerts[0])
+	}
+
+	// Other drives use the adjusted hdd class: the warning stays at 50, critical moves to 65
+	_, alerts = engine.EvaluateDisk(types.DiskInfo{Model: "ST4000NM0035", RPM: 7200, Temperature: 62}, Growth{})
+	if keys := alertKeys(alerts); keys != "temperature=warning" {
+		t.Errorf("Unexpected alerts %q", keys)
+	}
+}
+
+func TestEvaluateArrays(t *testing.T) {
+	engine, err := New(config.RulesConfig{RebuildDuration: config.ThresholdConfig{Warning: float(2), Critical: float(6)}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	battery := &types.RAIDBatteryInfo{ToolName: "StoreCLI", AdapterID: 0, Temperature: 55}
+
+	arrays := func() []types.RAIDInfo {
+		return []types.RAIDInfo{
+			{ArrayID: "0", Controller: "PERC H730", State: "Degraded", RebuildProgress: 40, Rebuilding: true, Battery: battery},
+			{ArrayID: "1", Controller: "PERC H730", State: "Optimal"},
+		}
+	}
+
+	raids := arrays()
+	engine.EvaluateArrays(raids, start)
+	if keys := alertKeys(raids[0].RuleAlerts); keys != "battery_temperature=warning" {
+		t.Errorf("Expected only the battery warning at rebuild start, got %q", keys)
+	}
+	if raids[0].RuleAlerts[0].Device != "StoreCLI/0" {
+		t.Errorf("Unexpected battery device %s", raids[0].RuleAlerts[0].Device)
+	}
+
+	raids = arrays()
+	engine.EvaluateArrays(raids, start.Add(3*time.Hour))
+	if keys := alertKeys(raids[0].RuleAlerts); keys != "rebuild_duration=warning,battery_temperature=warning" {
+		t.Errorf("Unexpected alerts %q", keys)
+	}
+	if alert := raids[0].RuleAlerts[0]; alert.Device != "PERC H730/0" || alert.Value != 3 {
+		t.Errorf("Unexpected rebuild alert %+v", alert)
+	}
+	if len(raids[1].RuleAlerts) != 0 {
+		t.Errorf("Expected no alerts for the optimal array, got %+v", raids[1].RuleAlerts)
+	}
+
+	raids = arrays()
+	engine.EvaluateArrays(raids, start.Add(7*time.Hour))
+	if keys := alertKeys(raids[0].RuleAlerts); keys != "rebuild_duration=critical,battery_temperature=warning" {
+		t.Errorf("Unexpected alerts %q", keys)
+	}
+
+	// A finished rebuild is forgotten, so the next one starts over
+	engine.EvaluateArrays([]types.RAIDInfo{{ArrayID: "0", Controller: "PERC H730", State: "Optimal"}}, start.Add(8*time.Hour))
+	raids = arrays()
+	engine.EvaluateArrays(raids, start.Add(9*time.Hour))
+	if keys := alertKeys(raids[0].RuleAlerts); keys != "battery_temperature=warning" {
+		t.Errorf("Expected the rebuild duration to restart, got %q", keys)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	engine, err := New(config.RulesConfig{Disabled: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, alerts := engine.EvaluateDisk(types.DiskInfo{RPM: 7200, Temperature: 90}, Growth{}); len(alerts) != 0 {
+		t.Errorf("Expected no alerts, got %+v", alerts)
+	}
+	raids := []types.RAIDInfo{{Battery: &types.RAIDBatteryInfo{Temperature: 90}}}
+	engine.EvaluateArrays(raids, time.Now())
+	if len(raids[0].RuleAlerts) != 0 {
+		t.Errorf("Expected no alerts, got %+v", raids[0].RuleAlerts)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.RulesConfig
+	}{
+		{"growth window", config.RulesConfig{GrowthWindow: "soon"}},
+		{"class without name", config.RulesConfig{Classes: []config.RuleClassConfig{{Model: "ST"}}}},
+		{"unknown class without model", config.RulesConfig{Classes: []config.RuleClassConfig{{Name: "archive"}}}},
+		{"invalid model", config.RulesConfig{Classes: []config.RuleClassConfig{{Name: "archive", Model: "("}}}},
+		{"incomplete model class rule", config.RulesConfig{Classes: []config.RuleClassConfig{
+			{Name: "archive", Model: "PURZ", Temperature: config.ThresholdConfig{Warning: float(45)}},
+		}}},
+		{"critical below warning", config.RulesConfig{BatteryTemperature: config.ThresholdConfig{Warning: float(60), Critical: float(50)}}},
+		{"spare critical above warning", config.RulesConfig{Classes: []config.RuleClassConfig{
+			{Name: "nvme", AvailableSpare: config.ThresholdConfig{Warning: float(10), Critical: float(20)}},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+// Package selftest starts SMART self-tests on a calendar. Tests are staggered
+// across disks so that only one starts at a time, and disks of a rebuilding
+// RAID array are skipped so a test does not slow the rebuild down. Start times
+// are kept in the state store, so a restart does not start every test again.
+package selftest
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/internal/state"
+	"disk-health-exporter/pkg/types"
+)
+
+// DefaultStagger is the minimum time between two test starts across all disks
+const DefaultStagger = 15 * time.Minute
+
+// minInterval is the shortest time allowed between two tests of a type on a disk
+const minInterval = time.Hour
+
+// Results of an attempt to start a test
+const (
+	ResultStarted = "started"
+	ResultFailed  = "failed"
+)
+
+// Results lists every result of an attempt to start a test
+var Results = []string{ResultStarted, ResultFailed}
+
+// timestampPrefix keys the start times of tests in the state store
+const timestampPrefix = "self_test/"
+
+// defaultSchedules run when the scheduler is enabled without schedules
+var defaultSchedules = []config.SelfTestScheduleConfig{
+	{Type: types.SelfTestShort, Interval: "1d"},
+	{Type: types.SelfTestLong, Interval: "4w"},
+}
+
+// weekdays names the days of the week in schedules
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Starter starts a self-test on a disk
+type Starter interface {
+	StartSelfTest(disk types.DiskInfo, testType string) error
+}
+
+// schedule sets when a type of test runs on every disk
+type schedule struct {
+	testType string
+	interval time.Duration
+	days     []time.Weekday // Empty allows every day
+	from, to int            // Minutes since midnight of the start window; equal allows any time
+}
+
+// Scheduler starts the self-tests that are due
+type Scheduler struct {
+	schedules []schedule // Longest interval first, so a due long test takes precedence over a short one
+	stagger   time.Duration
+	starter   Starter
+	store     *state.Store
+
+	mu        sync.Mutex
+	lastStart time.Time
+	results   map[string]map[string]int // By test type and result
+}
+
+// New creates a scheduler from configuration. A disabled scheduler starts no tests.
+func New(cfg config.SelfTestsConfig, starter Starter, store *state.Store) (*Scheduler, error) {
+	s := &Scheduler{
+		stagger: DefaultStagger,
+		starter: starter,
+		store:   store,
+		results: make(map[string]map[string]int),
+	}
+	if !cfg.Enabled {
+		return s, nil
+	}
+
+	if cfg.Stagger != "" {
+		stagger, err := config.ParseDuration(cfg.Stagger)
+		if err != nil || stagger < 0 {
+			return nil, fmt.Errorf("invalid self-test stagger %q", cfg.Stagger)
+		}
+		s.stagger = stagger
+	}
+
+	configs := cfg.Schedules
+	if len(configs) == 0 {
+		configs = defaultSchedules
+	}
+	for _, sc := range configs {
+		sched, err := parseSchedule(sc)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(s.schedules, func(other schedule) bool { return other.testType == sched.testType }) {
+			return nil, fmt.Errorf("duplicate %s self-test schedule", sched.testType)
+		}
+		s.schedules = append(s.schedules, sched)
+		s.results[sched.testType] = make(map[string]int)
+	}
+	slices.SortStableFunc(s.schedules, func(a, b schedule) int {
+		return cmp.Compare(b.interval, a.interval)
+	})
+
+	return s, nil
+}
+
+// parseSchedule validates the configuration of a schedule
+func parseSchedule(sc config.SelfTestScheduleConfig) (schedule, error) {
+	sched := schedule{testType: sc.Type}
+	if sc.Type != types.SelfTestShort && sc.Type != types.SelfTestLong {
+		return sched, fmt.Errorf("invalid self-test type %q, must be short or long", sc.Type)
+	}
+
+	interval, err := config.ParseDuration(sc.Interval)
+	if err != nil || interval < minInterval {
+		return sched, fmt.Errorf("invalid %s self-test interval %q, must be at least %s", sc.Type, sc.Interval, minInterval)
+	}
+	sched.interval = interval
+
+	for _, name := range sc.Days {
+		day, ok := weekdays[strings.ToLower(name)]
+		if !ok {
+			return sched, fmt.Errorf("invalid %s self-test day %q", sc.Type, name)
+		}
+		sched.days = append(sched.days, day)
+	}
+
+	if sc.Hours != "" {
+		from, to, ok := strings.Cut(sc.Hours, "-")
+		start, errFrom := time.Parse("15:04", strings.TrimSpace(from))
+		end, errTo := time.Parse("15:04", strings.TrimSpace(to))
+		if !ok || errFrom != nil || errTo != nil || start.Equal(end) {
+			return sched, fmt.Errorf("invalid %s self-test hours %q, expected HH:MM-HH:MM", sc.Type, sc.Hours)
+		}
+		sched.from = start.Hour()*60 + start.Minute()
+		sched.to = end.Hour()*60 + end.Minute()
+	}
+
+	return sched, nil
+}
+
+// Enabled reports whether the scheduler starts tests
+func (s *Scheduler) Enabled() bool {
+	return len(s.schedules) > 0
+}
+
+// Types returns the scheduled test types
+func (s *Scheduler) Types() []string {
+	testTypes := make([]string, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		testTypes = append(testTypes, sched.testType)
+	}
+	return testTypes
+}
+
+// Results returns the number of attempts to start a test of a type since startup by result
+func (s *Scheduler) Results(testType string) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make(map[string]int, len(Results))
+	for _, result := range Results {
+		results[result] = s.results[testType][result]
+	}
+	return results
+}
+
+// Run starts at most one due test. Disks are skipped while they run a test,
+// while they or their array rebuild, and when they report no self-test log.
+// A test that fails to start is not retried before its interval has passed.
+func (s *Scheduler) Run(disks []types.DiskInfo, raids []types.RAIDInfo, now time.Time) {
+	if !s.Enabled() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.lastStart.IsZero() && now.Sub(s.lastStart) < s.stagger {
+		return
+	}
+
+	for _, disk := range disks {
+		if disk.SelfTests == nil || disk.SelfTests.Running {
+			continue
+		}
+		if Rebuilding(disk, raids) {
+			slog.Debug("Skipping self-tests of a rebuilding RAID member", "device", disk.Device, "serial", disk.Serial)
+			continue
+		}
+
+		for _, sched := range s.schedules {
+			if !sched.allows(now) || !s.due(disk, sched, now) {
+				continue
+			}
+
+			s.store.SetTimestamp(timestampKey(disk, sched.testType), now)
+			if err := s.starter.StartSelfTest(disk, sched.testType); err != nil {
+				slog.Warn("Error starting self-test", "device", disk.Device, "serial", disk.Serial, "test", sched.testType, "err", err)
+				s.results[sched.testType][ResultFailed]++
+				break
+			}
+
+			slog.Info("Started self-test", "device", disk.Device, "serial", disk.Serial, "test", sched.testType)
+			s.results[sched.testType][ResultStarted]++
+			s.lastStart = now
+			return
+		}
+	}
+}
+
+// due reports whether a test of the schedule's type is due on a disk. Tests
+// the disk ran without the scheduler, such as from cron or its own schedule,
+// count as well; aborted tests do not.
+func (s *Scheduler) due(disk types.DiskInfo, sched schedule, now time.Time) bool {
+	if last, ok := s.store.Timestamp(timestampKey(disk, sched.testType)); ok && now.Sub(last) < sched.interval {
+		return false
+	}
+	for _, entry := range disk.SelfTests.Latest {
+		if entry.Type == sched.testType && entry.Result != types.SelfTestResultAborted {
+			return float64(entry.AgeHours) >= sched.interval.Hours()
+		}
+	}
+	return true
+}
+
+// allows reports whether the schedule lets tests start at a time
+func (sched schedule) allows(now time.Time) bool {
+	if len(sched.days) > 0 && !slices.Contains(sched.days, now.Weekday()) {
+		return false
+	}
+	if sched.from == sched.to {
+		return true
+	}
+	minute := now.Hour()*60 + now.Minute()
+	if sched.from < sched.to {
+		return minute >= sched.from && minute < sched.to
+	}
+	// The window spans midnight
+	return minute >= sched.from || minute < sched.to
+}
+
+// timestampKey keys the start time of a test type on a disk in the state store
+func timestampKey(disk types.DiskInfo, testType string) string {
+	return timestampPrefix + testType + "/" + state.Key(disk)
+}
+
+// Rebuilding reports whether a disk is rebuilding or belongs to an array that
+// is. Disks are matched to arrays by array ID, or by the member partitions of
+// software RAID arrays.
+func Rebuilding(disk types.DiskInfo, raids []types.RAIDInfo) bool {
+	if disk.RaidRole == "rebuilding" {
+		return true
+	}
+	for _, raid := range raids {
+		if !raid.Rebuilding {
+			continue
+		}
+		if disk.RaidArrayID != "" && disk.RaidArrayID == raid.ArrayID {
+			return true
+		}
+		if slices.ContainsFunc(raid.Members, func(member string) bool { return onDevice(member, disk.Device) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// onDevice reports whether a member device is the disk device or one of its
+// partitions (sda1 of sda, nvme0n1p1 of nvme0n1)
+func onDevice(member, device string) bool {
+	if device == "" {
+		return false
+	}
+	rest, ok := strings.CutPrefix(member, device)
+	if !ok {
+		return false
+	}
+	rest = strings.TrimPrefix(rest, "p")
+	return strings.Trim(rest, "0123456789") == "" && (rest != "" || member == device)
+}
@@ -0,0 +1,189 @@
+package selftest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/internal/state"
+	"disk-health-exporter/pkg/types"
+)
+
+// Saturday 2 am local time
+var base = time.Date(2025, 7, 5, 2, 0, 0, 0, time.Local)
+
+// fakeStarter records the tests it was asked to start
+type fakeStarter struct {
+	started []string // serial/type
+	fail    map[string]bool
+}
+
+func (f *fakeStarter) StartSelfTest(disk types.DiskInfo, testType string) error {
+	if f.fail[disk.Serial] {
+		return errors.New("device open failed")
+	}
+	f.started = append(f.started, disk.Serial+"/"+testType)
+	return nil
+}
+
+func newScheduler(t *testing.T, cfg config.SelfTestsConfig, starter Starter) *Scheduler {
+	t.Helper()
+	store, err := state.Open("", time.Hour)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	scheduler, err := New(cfg, starter, store)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	return scheduler
+}
+
+func disk(serial string, latest ...types.SelfTestEntry) types.DiskInfo {
+	return types.DiskInfo{Device: "/dev/" + serial, Serial: serial, SelfTests: &types.SelfTestInfo{Latest: latest}}
+}
+
+func TestRunStaggered(t *testing.T) {
+	starter := &fakeStarter{}
+	scheduler := newScheduler(t, config.SelfTestsConfig{Enabled: true, Stagger: "10m"}, starter)
+	disks := []types.DiskInfo{
+		disk("A"),
+		disk("B"),
+		// Long test 100 hours ago: only the short test is due
+		disk("C", types.SelfTestEntry{Type: types.SelfTestLong, Result: types.SelfTestResultPassed, AgeHours: 100}),
+		// No self-test log
+		{Device: "/dev/sdd", Serial: "D"},
+	}
+
+	for minutes := 0; minutes <= 60; minutes += 5 {
+		scheduler.Run(disks, nil, base.Add(time.Duration(minutes)*time.Minute))
+	}
+
+	// One start per 10 minutes, long tests first
+	expected := []string{"A/long", "A/short", "B/long", "B/short", "C/short"}
+	if !slices.Equal(starter.started, expected) {
+		t.Errorf("Expected starts %v, got %v", expected, starter.started)
+	}
+	if results := scheduler.Results(types.SelfTestShort); results[ResultStarted] != 3 {
+		t.Errorf("Expected 3 short tests started, got %v", results)
+	}
+
+	// Nothing is due again before the short interval has passed
+	starter.started = nil
+	scheduler.Run(disks, nil, base.Add(23*time.Hour))
+	if len(starter.started) != 0 {
+		t.Errorf("Expected no starts within the interval, got %v", starter.started)
+	}
+	scheduler.Run(disks, nil, base.Add(25*time.Hour))
+	if !slices.Equal(starter.started, []string{"A/short"}) {
+		t.Errorf("Expected the short test of A to be due again, got %v", starter.started)
+	}
+}
+
+func TestRunSkips(t *testing.T) {
+	starter := &fakeStarter{fail: map[string]bool{"FAILING": true}}
+	scheduler := newScheduler(t, config.SelfTestsConfig{
+		Enabled:   true,
+		Stagger:   "0s",
+		Schedules: []config.SelfTestScheduleConfig{{Type: types.SelfTestShort, Interval: "1d"}},
+	}, starter)
+
+	running := disk("RUNNING")
+	running.SelfTests.Running = true
+	rebuilding := disk("REBUILDING")
+	rebuilding.RaidRole = "rebuilding"
+	member := disk("MEMBER")
+	member.RaidArrayID = "tank"
+	mdMember := types.DiskInfo{Device: "/dev/sdb", Serial: "MDMEMBER", SelfTests: &types.SelfTestInfo{}}
+	disks := []types.DiskInfo{
+		running,
+		rebuilding,
+		member,
+		mdMember,
+		disk("RECENT", types.SelfTestEntry{Type: types.SelfTestShort, Result: types.SelfTestResultFailed, AgeHours: 3}),
+		disk("FAILING"),
+		disk("ABORTED", types.SelfTestEntry{Type: types.SelfTestShort, Result: types.SelfTestResultAborted, AgeHours: 3}),
+	}
+	raids := []types.RAIDInfo{
+		{ArrayID: "tank", Type: "zfs", State: "DEGRADED", RebuildProgress: 40, Rebuilding: true},
+		{ArrayID: "/dev/md0", Type: "software", State: "active", RebuildProgress: 12, Rebuilding: true, Members: []string{"/dev/sda1", "/dev/sdb1"}},
+	}
+
+	scheduler.Run(disks, raids, base)
+	scheduler.Run(disks, raids, base.Add(time.Minute))
+
+	if !slices.Equal(starter.started, []string{"ABORTED/short"}) {
+		t.Errorf("Expected only the disk with an aborted test to start one, got %v", starter.started)
+	}
+	if results := scheduler.Results(types.SelfTestShort); results[ResultFailed] != 1 {
+		t.Errorf("Expected one failed start without retry, got %v", results)
+	}
+}
+
+func TestScheduleCalendar(t *testing.T) {
+	sched, err := parseSchedule(config.SelfTestScheduleConfig{Type: "long", Interval: "4w", Days: []string{"Sat", "sunday"}, Hours: "22:00-04:00"})
+	if err != nil {
+		t.Fatalf("parseSchedule returned error: %v", err)
+	}
+
+	tests := []struct {
+		at       time.Time
+		expected bool
+	}{
+		{base, true},                          // Saturday 02:00
+		{base.Add(3 * time.Hour), false},      // Saturday 05:00
+		{base.Add(21 * time.Hour), true},      // Saturday 23:00
+		{base.Add(2 * 24 * time.Hour), false}, // Monday 02:00
+	}
+	for _, tt := range tests {
+		if allowed := sched.allows(tt.at); allowed != tt.expected {
+			t.Errorf("allows(%v) = %v, expected %v", tt.at, allowed, tt.expected)
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	starter := &fakeStarter{}
+	scheduler := newScheduler(t, config.SelfTestsConfig{Schedules: []config.SelfTestScheduleConfig{{Type: "short", Interval: "1d"}}}, starter)
+	scheduler.Run([]types.DiskInfo{disk("A")}, nil, base)
+	if scheduler.Enabled() || len(starter.started) != 0 {
+		t.Errorf("Expected a scheduler without enabled to start nothing, got %v", starter.started)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []config.SelfTestsConfig{
+		{Enabled: true, Stagger: "soon"},
+		{Enabled: true, Schedules: []config.SelfTestScheduleConfig{{Type: "conveyance", Interval: "1d"}}},
+		{Enabled: true, Schedules: []config.SelfTestScheduleConfig{{Type: "short", Interval: "10m"}}},
+		{Enabled: true, Schedules: []config.SelfTestScheduleConfig{{Type: "short", Interval: "1d", Days: []string{"someday"}}}},
+		{Enabled: true, Schedules: []config.SelfTestScheduleConfig{{Type: "short", Interval: "1d", Hours: "02:00"}}},
+		{Enabled: true, Schedules: []config.SelfTestScheduleConfig{{Type: "short", Interval: "1d"}, {Type: "short", Interval: "2d"}}},
+	}
+	for _, cfg := range tests {
+		if _, err := New(cfg, &fakeStarter{}, nil); err == nil {
+			t.Errorf("Expected an error for %+v", cfg)
+		}
+	}
+}
+
+func TestOnDevice(t *testing.T) {
+	tests := []struct {
+		member, device string
+		expected       bool
+	}{
+		{"/dev/sda1", "/dev/sda", true},
+		{"/dev/sda", "/dev/sda", true},
+		{"/dev/nvme0n1p2", "/dev/nvme0n1", true},
+		{"/dev/sdaa1", "/dev/sda", false},
+		{"/dev/sdb1", "/dev/sda", false},
+		{"/dev/sda1", "", false},
+	}
+	for _, tt := range tests {
+		if got := onDevice(tt.member, tt.device); got != tt.expected {
+			t.Errorf("onDevice(%q, %q) = %v, expected %v", tt.member, tt.device, got, tt.expected)
+		}
+	}
+}
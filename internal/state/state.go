@@ -0,0 +1,341 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"disk-health-exporter/pkg/types"
+)
+
+// Counter names tracked per disk
+const (
+	CounterReallocatedSectors  = "reallocated_sectors"
+	CounterPendingSectors      = "pending_sectors"
+	CounterUncorrectableErrors = "uncorrectable_errors"
+	CounterMediaErrors         = "media_errors"
+	CounterErrorLogEntries     = "error_log_entries"
+	CounterBytesWritten        = "bytes_written"
+	CounterPercentageUsed      = "percentage_used"
+)
+
+// Counters lists every tracked counter name
+var Counters = []string{
+	CounterReallocatedSectors,
+	CounterPendingSectors,
+	CounterUncorrectableErrors,
+	CounterMediaErrors,
+	CounterErrorLogEntries,
+	CounterBytesWritten,
+	CounterPercentageUsed,
+}
+
+// ErrorCounters lists the error counters, which change rarely and indicate degradation
+var ErrorCounters = []string{
+	CounterReallocatedSectors,
+	CounterPendingSectors,
+	CounterUncorrectableErrors,
+	CounterMediaErrors,
+	CounterErrorLogEntries,
+}
+
+// MinDiskRetention is the minimum time a disk record is kept after the disk was last seen
+const MinDiskRetention = 30 * 24 * time.Hour
+
+// lastSeenResolution limits how often a last-seen refresh alone triggers a save
+const lastSeenResolution = time.Hour
+
+// sampleResolution is the minimum spacing between stored samples. Changes within
+// it update the latest sample in place, which keeps history bounded for counters
+// that change on every collection (such as bytes written).
+const sampleResolution = 15 * time.Minute
+
+// minRateSpan is the minimum history span needed to report a rate
+const minRateSpan = time.Hour
+
+// stateVersion is the on-disk format version
+const stateVersion = 1
+
+// Sample is a snapshot of a disk's counters. Samples are only recorded when a
+// counter changes, so a counter's value at any time is that of the latest
+// sample at or before it (to within sampleResolution).
+type Sample struct {
+	At       time.Time        `json:"at"`
+	Counters map[string]int64 `json:"counters"`
+}
+
+// diskRecord holds the persisted history of a single disk
+type diskRecord struct {
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Samples   []Sample  `json:"samples"`
+}
+
+// stateFile is the on-disk representation of the store
+type stateFile struct {
+	Version    int                    `json:"version"`
+	Disks      map[string]*diskRecord `json:"disks"`
+	Timestamps map[string]time.Time   `json:"timestamps,omitempty"`
+}
+
+// Store tracks per-disk counter history, optionally persisted to a file
+type Store struct {
+	path      string
+	retention time.Duration // Sample history kept beyond the longest window
+
+	mu         sync.Mutex
+	disks      map[string]*diskRecord
+	timestamps map[string]time.Time // Named event times, such as the last completed patrol read of a controller
+	dirty      bool
+}
+
+// Open creates a store, loading existing state from path if present.
+// An empty path keeps state in memory only.
+func Open(path string, retention time.Duration) (*Store, error) {
+	s := &Store{
+		path:       path,
+		retention:  retention,
+		disks:      make(map[string]*diskRecord),
+		timestamps: make(map[string]time.Time),
+	}
+
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("reading state file %s: %w", path, err)
+	}
+
+	var file stateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return s, fmt.Errorf("parsing state file %s: %w", path, err)
+	}
+	if file.Version != stateVersion {
+		return s, fmt.Errorf("unsupported state file version %d", file.Version)
+	}
+	if file.Disks != nil {
+		s.disks = file.Disks
+	}
+	if file.Timestamps != nil {
+		s.timestamps = file.Timestamps
+	}
+
+	return s, nil
+}
+
+// Key returns the identity used to track a disk: its serial number, or the
+// device path for disks that do not report one
+func Key(disk types.DiskInfo) string {
+	if disk.Serial != "" {
+		return disk.Serial
+	}
+	return "device:" + disk.Device
+}
+
+// CountersFromDisk extracts the tracked counters from a disk
+func CountersFromDisk(disk types.DiskInfo) map[string]int64 {
+	return map[string]int64{
+		CounterReallocatedSectors:  disk.ReallocatedSectors,
+		CounterPendingSectors:      disk.PendingSectors,
+		CounterUncorrectableErrors: disk.UncorrectableErrors,
+		CounterMediaErrors:         disk.MediaErrors,
+		CounterErrorLogEntries:     disk.ErrorLogEntries,
+		CounterBytesWritten:        disk.BytesWritten,
+		CounterPercentageUsed:      int64(max(disk.PercentageUsed, disk.WearLeveling)),
+	}
+}
+
+// Record stores the current counters of a disk, appending a sample when any counter changed
+func (s *Store) Record(key string, counters map[string]int64, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, exists := s.disks[key]
+	if !exists {
+		record = &diskRecord{FirstSeen: now}
+		s.disks[key] = record
+		s.dirty = true
+	}
+
+	if now.Sub(record.LastSeen) >= lastSeenResolution {
+		s.dirty = true
+	}
+	record.LastSeen = now
+
+	n := len(record.Samples)
+	switch {
+	case n > 0 && maps.Equal(record.Samples[n-1].Counters, counters):
+		// Unchanged
+	case n > 1 && record.Samples[n-1].At.Sub(record.Samples[n-2].At) < sampleResolution:
+		// Refine the latest sample; persisted with the next structural change or last-seen refresh
+		record.Samples[n-1] = Sample{At: now, Counters: maps.Clone(counters)}
+	default:
+		record.Samples = append(record.Samples, Sample{At: now, Counters: maps.Clone(counters)})
+		s.dirty = true
+	}
+}
+
+// FirstSeen returns when a disk was first recorded
+func (s *Store) FirstSeen(key string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.disks[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	return record.FirstSeen, true
+}
+
+// SetTimestamp records the time of a named event
+func (s *Store) SetTimestamp(key string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.timestamps[key].Equal(at) {
+		s.timestamps[key] = at
+		s.dirty = true
+	}
+}
+
+// Timestamp returns the time of a named event recorded with SetTimestamp
+func (s *Store) Timestamp(key string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at, ok := s.timestamps[key]
+	return at, ok
+}
+
+// Increase returns how much a counter grew over the window ending at now.
+// When the disk was first seen within the window, growth since first seen is returned.
+// Counter decreases (e.g. after a controller reset) are reported as zero.
+func (s *Store) Increase(key, counter string, window time.Duration, now time.Time) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.disks[key]
+	if !ok || len(record.Samples) == 0 {
+		return 0
+	}
+
+	baseline := baselineSample(record, now.Add(-window))
+	current := record.Samples[len(record.Samples)-1]
+	return max(current.Counters[counter]-baseline.Counters[counter], 0)
+}
+
+// Rate returns the average per-day increase of a counter over the window ending at now.
+// It reports false when less than an hour of history is available.
+func (s *Store) Rate(key, counter string, window time.Duration, now time.Time) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.disks[key]
+	if !ok || len(record.Samples) == 0 {
+		return 0, false
+	}
+
+	windowStart := now.Add(-window)
+	baseline := baselineSample(record, windowStart)
+	span := now.Sub(baseline.At)
+	if baseline.At.Before(windowStart) {
+		span = window
+	}
+	if span < minRateSpan {
+		return 0, false
+	}
+
+	current := record.Samples[len(record.Samples)-1]
+	increase := max(current.Counters[counter]-baseline.Counters[counter], 0)
+	return float64(increase) / span.Hours() * 24, true
+}
+
+// baselineSample returns the last sample at or before windowStart, or the first
+// sample when the disk was first seen within the window
+func baselineSample(record *diskRecord, windowStart time.Time) Sample {
+	baseline := record.Samples[0]
+	for _, sample := range record.Samples[1:] {
+		if sample.At.After(windowStart) {
+			break
+		}
+		baseline = sample
+	}
+	return baseline
+}
+
+// Prune drops samples older than the retention period (keeping one baseline
+// per disk) and forgets disks that have not been seen for a long time
+func (s *Store) Prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	diskRetention := max(s.retention, MinDiskRetention)
+	cutoff := now.Add(-s.retention)
+
+	for key, record := range s.disks {
+		if now.Sub(record.LastSeen) > diskRetention {
+			delete(s.disks, key)
+			s.dirty = true
+			continue
+		}
+
+		drop := 0
+		for drop+1 < len(record.Samples) && !record.Samples[drop+1].At.After(cutoff) {
+			drop++
+		}
+		if drop > 0 {
+			record.Samples = append([]Sample(nil), record.Samples[drop:]...)
+			s.dirty = true
+		}
+	}
+}
+
+// Save atomically writes the state to disk if it changed since the last save
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.path == "" || !s.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(stateFile{Version: stateVersion, Disks: s.disks, Timestamps: s.timestamps})
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating state directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing state file %s: %w", s.path, err)
+	}
+
+	s.dirty = false
+	return nil
+}
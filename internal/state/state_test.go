@@ -0,0 +1,235 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"disk-health-exporter/pkg/types"
+)
+
+var base = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func counters(reallocated int64) map[string]int64 {
+	return map[string]int64{CounterReallocatedSectors: reallocated}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key(types.DiskInfo{Device: "/dev/sda", Serial: "ABC123"}); got != "ABC123" {
+		t.Errorf("Expected serial key, got %s", got)
+	}
+	if got := Key(types.DiskInfo{Device: "/dev/sda"}); got != "device:/dev/sda" {
+		t.Errorf("Expected device key, got %s", got)
+	}
+}
+
+func TestRecordOnlyAppendsChanges(t *testing.T) {
+	s, _ := Open("", 24*time.Hour)
+
+	s.Record("DISK1", counters(1), base)
+	s.Record("DISK1", counters(1), base.Add(time.Hour))
+	s.Record("DISK1", counters(2), base.Add(2*time.Hour))
+
+	if n := len(s.disks["DISK1"].Samples); n != 2 {
+		t.Errorf("Expected 2 samples, got %d", n)
+	}
+	if got := s.disks["DISK1"].LastSeen; !got.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("Expected last seen to be updated, got %v", got)
+	}
+}
+
+func TestIncrease(t *testing.T) {
+	s, _ := Open("", 7*24*time.Hour)
+	s.Record("DISK1", counters(10), base)
+	s.Record("DISK1", counters(12), base.Add(24*time.Hour))
+	s.Record("DISK1", counters(20), base.Add(48*time.Hour))
+	now := base.Add(60 * time.Hour)
+
+	tests := []struct {
+		name     string
+		key      string
+		window   time.Duration
+		expected int64
+	}{
+		{"unknown disk", "DISK2", 24 * time.Hour, 0},
+		{"no change within window", "DISK1", 12 * time.Hour, 0},
+		{"window starts between samples", "DISK1", 24 * time.Hour, 8},
+		{"window starts at a sample", "DISK1", 36 * time.Hour, 8},
+		{"window covers all changes", "DISK1", 60 * time.Hour, 10},
+		{"window before first seen", "DISK1", 7 * 24 * time.Hour, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Increase(tt.key, CounterReallocatedSectors, tt.window, now); got != tt.expected {
+				t.Errorf("Expected increase %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIncreaseIgnoresDecrease(t *testing.T) {
+	s, _ := Open("", 24*time.Hour)
+	s.Record("DISK1", counters(10), base)
+	s.Record("DISK1", counters(3), base.Add(time.Hour))
+
+	if got := s.Increase("DISK1", CounterReallocatedSectors, 24*time.Hour, base.Add(time.Hour)); got != 0 {
+		t.Errorf("Expected counter reset to report 0, got %d", got)
+	}
+}
+
+func TestRate(t *testing.T) {
+	s, _ := Open("", 7*24*time.Hour)
+	s.Record("DISK1", map[string]int64{CounterBytesWritten: 0}, base)
+	s.Record("DISK1", map[string]int64{CounterBytesWritten: 100}, base.Add(24*time.Hour))
+	s.Record("DISK1", map[string]int64{CounterBytesWritten: 400}, base.Add(48*time.Hour))
+
+	tests := []struct {
+		name     string
+		window   time.Duration
+		now      time.Time
+		expected float64
+		ok       bool
+	}{
+		{"history shorter than window", 7 * 24 * time.Hour, base.Add(48 * time.Hour), 200, true},
+		{"window within history", 24 * time.Hour, base.Add(48 * time.Hour), 300, true},
+		{"idle since last change", 24 * time.Hour, base.Add(72 * time.Hour), 0, true},
+		{"too little history", 7 * 24 * time.Hour, base.Add(30 * time.Minute), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := s.Rate("DISK1", CounterBytesWritten, tt.window, tt.now)
+			if ok != tt.ok || rate != tt.expected {
+				t.Errorf("Expected rate %v (ok=%v), got %v (ok=%v)", tt.expected, tt.ok, rate, ok)
+			}
+		})
+	}
+}
+
+func TestRecordCoalescesFrequentChanges(t *testing.T) {
+	s, _ := Open("", 24*time.Hour)
+	for i := range 60 {
+		s.Record("DISK1", map[string]int64{CounterBytesWritten: int64(i)}, base.Add(time.Duration(i)*time.Minute))
+	}
+
+	samples := s.disks["DISK1"].Samples
+	if len(samples) != 5 {
+		t.Errorf("Expected samples spaced by the sample resolution, got %d", len(samples))
+	}
+	if got := samples[len(samples)-1].Counters[CounterBytesWritten]; got != 59 {
+		t.Errorf("Expected latest sample to hold the latest value, got %d", got)
+	}
+}
+
+func TestFirstSeen(t *testing.T) {
+	s, _ := Open("", 24*time.Hour)
+
+	if _, ok := s.FirstSeen("DISK1"); ok {
+		t.Error("Expected unknown disk to have no first seen time")
+	}
+
+	s.Record("DISK1", counters(0), base)
+	s.Record("DISK1", counters(1), base.Add(time.Hour))
+
+	firstSeen, ok := s.FirstSeen("DISK1")
+	if !ok || !firstSeen.Equal(base) {
+		t.Errorf("Expected first seen %v, got %v", base, firstSeen)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s, _ := Open("", 24*time.Hour)
+	s.Record("DISK1", counters(1), base)
+	s.Record("DISK1", counters(2), base.Add(12*time.Hour))
+	s.Record("DISK1", counters(3), base.Add(36*time.Hour))
+	s.Record("GONE", counters(0), base)
+
+	now := base.Add(48 * time.Hour)
+	s.Record("DISK1", counters(3), now)
+	s.Prune(now)
+
+	samples := s.disks["DISK1"].Samples
+	if len(samples) != 2 || samples[0].Counters[CounterReallocatedSectors] != 2 {
+		t.Errorf("Expected pruning to keep the baseline sample, got %+v", samples)
+	}
+	if got := s.Increase("DISK1", CounterReallocatedSectors, 24*time.Hour, now); got != 1 {
+		t.Errorf("Expected increase 1 after pruning, got %d", got)
+	}
+	if _, ok := s.disks["GONE"]; !ok {
+		t.Error("Expected recently seen disk to be kept")
+	}
+
+	s.Prune(base.Add(MinDiskRetention + time.Hour))
+	if _, ok := s.disks["GONE"]; ok {
+		t.Error("Expected disk not seen within retention to be forgotten")
+	}
+}
+
+func TestSaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "state.json")
+
+	s, err := Open(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	s.Record("DISK1", counters(5), base)
+	s.Record("DISK1", counters(7), base.Add(time.Hour))
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	reopened, err := Open(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if got := reopened.Increase("DISK1", CounterReallocatedSectors, 24*time.Hour, base.Add(time.Hour)); got != 2 {
+		t.Errorf("Expected increase 2 after reload, got %d", got)
+	}
+	firstSeen, ok := reopened.FirstSeen("DISK1")
+	if !ok || !firstSeen.Equal(base) {
+		t.Errorf("Expected first seen %v after reload, got %v", base, firstSeen)
+	}
+}
+
+func TestTimestampsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, _ := Open(path, 24*time.Hour)
+	if _, ok := s.Timestamp("patrol_read/MegaCLI/0"); ok {
+		t.Error("Expected no timestamp before it is set")
+	}
+	s.SetTimestamp("patrol_read/MegaCLI/0", base)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	reopened, err := Open(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	at, ok := reopened.Timestamp("patrol_read/MegaCLI/0")
+	if !ok || !at.Equal(base) {
+		t.Errorf("Expected timestamp %v after reload, got %v", base, at)
+	}
+}
+
+func TestOpenInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path, 24*time.Hour)
+	if err == nil {
+		t.Error("Expected error for invalid state file")
+	}
+	if s == nil {
+		t.Fatal("Expected usable store despite error")
+	}
+	s.Record("DISK1", counters(1), base)
+	if _, ok := s.FirstSeen("DISK1"); !ok {
+		t.Error("Expected store to record after load error")
+	}
+}
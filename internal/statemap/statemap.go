@@ -0,0 +1,120 @@
+// Package statemap translates the native health and state strings of each tool
+// into the exporter's normalized values. Every tool declares an explicit table
+// of the strings it knows; a string missing from its table reads as unknown and
+// is recorded, so new firmware wording shows up as disk_health_unmapped_state
+// instead of being guessed at by substring matching.
+package statemap
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxUnmapped bounds the unmapped states remembered, and with them the
+// cardinality of disk_health_unmapped_state
+const maxUnmapped = 100
+
+// Table translates the native state strings of one tool into normalized values
+type Table[T any] struct {
+	tool   string
+	states map[string]T
+}
+
+// New creates the state table of a tool. Keys are written the way the tool
+// prints them; they are matched ignoring case and repeated whitespace.
+func New[T any](tool string, states map[string]T) *Table[T] {
+	t := &Table[T]{tool: tool, states: make(map[string]T, len(states))}
+	for state, value := range states {
+		key := Normalize(state)
+		if _, ok := t.states[key]; ok {
+			panic(fmt.Sprintf("statemap: duplicate %s state %q", tool, state))
+		}
+		t.states[key] = value
+	}
+	return t
+}
+
+// Tool returns the name of the tool the table belongs to
+func (t *Table[T]) Tool() string {
+	return t.tool
+}
+
+// Len returns the number of states in the table
+func (t *Table[T]) Len() int {
+	return len(t.states)
+}
+
+// Lookup returns the value of a state and whether the table knows it
+func (t *Table[T]) Lookup(state string) (T, bool) {
+	value, ok := t.states[Normalize(state)]
+	return value, ok
+}
+
+// Map returns the value of a state. A state missing from the table returns
+// the zero value (unknown) and is recorded as unmapped; an empty state means
+// the tool reported none and is not recorded.
+func (t *Table[T]) Map(state string) T {
+	value, ok := t.Lookup(state)
+	if !ok && strings.TrimSpace(state) != "" {
+		record(t.tool, state)
+	}
+	return value
+}
+
+// Normalize returns the form states are matched in: lower case, with
+// surrounding whitespace removed and inner whitespace collapsed
+func Normalize(state string) string {
+	return strings.ToLower(strings.Join(strings.Fields(state), " "))
+}
+
+// UnmappedState is a state string a tool reported that its table does not know
+type UnmappedState struct {
+	Tool  string
+	State string
+}
+
+var (
+	mu       sync.Mutex
+	unmapped = make(map[UnmappedState]string) // By tool and normalized state, with the state as first reported
+)
+
+// record remembers an unmapped state, logging it the first time it is seen
+func record(tool, state string) {
+	key := UnmappedState{Tool: tool, State: Normalize(state)}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := unmapped[key]; ok || len(unmapped) >= maxUnmapped {
+		return
+	}
+	unmapped[key] = strings.TrimSpace(state)
+	slog.Warn("Unmapped tool state, reporting it as unknown", "tool", tool, "state", state)
+}
+
+// Unmapped returns the unmapped states seen since the exporter started, by tool and state
+func Unmapped() []UnmappedState {
+	mu.Lock()
+	defer mu.Unlock()
+
+	states := make([]UnmappedState, 0, len(unmapped))
+	for key, state := range unmapped {
+		states = append(states, UnmappedState{Tool: key.Tool, State: state})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Tool != states[j].Tool {
+			return states[i].Tool < states[j].Tool
+		}
+		return states[i].State < states[j].State
+	})
+	return states
+}
+
+// Reset forgets the unmapped states seen so far
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	unmapped = make(map[UnmappedState]string)
+}
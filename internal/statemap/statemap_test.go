@@ -0,0 +1,62 @@
+package statemap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	table := New("tool", map[string]int{"Online, Spun Up": 1, "Failed": 3})
+
+	tests := []struct {
+		state    string
+		expected int
+	}{
+		{"Online, Spun Up", 1},
+		{"  online,   spun up ", 1},
+		{"FAILED", 3},
+		// Substrings of known states are not known states
+		{"Online", 0},
+		{"Not Failed", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if value := table.Map(tt.state); value != tt.expected {
+			t.Errorf("Map(%q) = %d, expected %d", tt.state, value, tt.expected)
+		}
+	}
+
+	unmapped := Unmapped()
+	expected := []UnmappedState{{Tool: "tool", State: "Not Failed"}, {Tool: "tool", State: "Online"}}
+	if fmt.Sprint(unmapped) != fmt.Sprint(expected) {
+		t.Errorf("Expected unmapped %v, got %v", expected, unmapped)
+	}
+}
+
+func TestUnmappedBounded(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	table := New("tool", map[string]int{})
+	for i := 0; i < maxUnmapped+10; i++ {
+		table.Map(fmt.Sprintf("state %d", i))
+	}
+	// The same state in another case is remembered once
+	table.Map("STATE 0")
+
+	if count := len(Unmapped()); count != maxUnmapped {
+		t.Errorf("Expected %d unmapped states, got %d", maxUnmapped, count)
+	}
+}
+
+func TestNewDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for states differing only in case")
+		}
+	}()
+	New("tool", map[string]int{"Optimal": 1, "OPTIMAL": 1})
+}
@@ -0,0 +1,210 @@
+// Package thermal keeps the temperature history of each disk, exporting the
+// minimum, maximum and average temperature over rolling windows and counting
+// excursions above configurable thresholds. History is kept in memory and
+// starts empty when the exporter starts.
+package thermal
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+// DefaultWindows are the windows exported when none are configured
+var DefaultWindows = []string{"1h", "24h"}
+
+// DefaultThresholds are the excursion thresholds in Celsius used when none are configured
+var DefaultThresholds = []float64{50, 60}
+
+// DefaultHysteresis is how far, in degrees, a disk must cool below a threshold
+// before rising above it again counts as another excursion
+const DefaultHysteresis = 2.0
+
+// bucketWidth is the span of history aggregated into one bucket, which bounds
+// memory to one bucket per minute of the longest window whatever the
+// collection interval
+const bucketWidth = time.Minute
+
+// window is a rolling window over which statistics are exported
+type window struct {
+	label    string // Window as configured (e.g. "24h")
+	duration time.Duration
+}
+
+// bucket aggregates the temperature readings taken within bucketWidth
+type bucket struct {
+	start time.Time
+	min   float64
+	max   float64
+	sum   float64
+	count int
+}
+
+// diskHistory holds the temperature history of a single disk
+type diskHistory struct {
+	buckets    []bucket
+	above      []bool  // By threshold, whether an excursion is in progress
+	excursions []int64 // By threshold
+}
+
+// History tracks the temperature of each disk
+type History struct {
+	windows    []window  // Shortest first
+	thresholds []float64 // Lowest first
+	hysteresis float64
+
+	mu    sync.Mutex
+	disks map[string]*diskHistory
+}
+
+// New creates a temperature history from configuration
+func New(cfg config.TemperatureConfig) (*History, error) {
+	h := &History{
+		hysteresis: DefaultHysteresis,
+		disks:      make(map[string]*diskHistory),
+	}
+
+	labels := cfg.Windows
+	if len(labels) == 0 {
+		labels = DefaultWindows
+	}
+	for _, label := range labels {
+		duration, err := config.ParseDuration(label)
+		if err != nil || duration < bucketWidth {
+			return nil, fmt.Errorf("invalid temperature window %q, must be at least %s", label, bucketWidth)
+		}
+		h.windows = append(h.windows, window{label: label, duration: duration})
+	}
+	slices.SortStableFunc(h.windows, func(a, b window) int {
+		return cmp.Compare(a.duration, b.duration)
+	})
+
+	h.thresholds = slices.Clone(cfg.Excursions.Thresholds)
+	if len(h.thresholds) == 0 {
+		h.thresholds = slices.Clone(DefaultThresholds)
+	}
+	slices.Sort(h.thresholds)
+	h.thresholds = slices.Compact(h.thresholds)
+
+	if cfg.Excursions.Hysteresis != nil {
+		if *cfg.Excursions.Hysteresis < 0 {
+			return nil, fmt.Errorf("excursion hysteresis must not be negative, got %v", *cfg.Excursions.Hysteresis)
+		}
+		h.hysteresis = *cfg.Excursions.Hysteresis
+	}
+
+	return h, nil
+}
+
+// Record adds a temperature reading of a disk. A disk already above a
+// threshold at its first reading is not counted as an excursion, since it may
+// have risen above it before the exporter started.
+func (h *History) Record(key string, celsius float64, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	disk, ok := h.disks[key]
+	if !ok {
+		disk = &diskHistory{
+			above:      make([]bool, len(h.thresholds)),
+			excursions: make([]int64, len(h.thresholds)),
+		}
+		h.disks[key] = disk
+	}
+
+	for i, threshold := range h.thresholds {
+		switch {
+		case celsius >= threshold && !disk.above[i]:
+			disk.above[i] = true
+			if ok {
+				disk.excursions[i]++
+			}
+		case celsius < threshold-h.hysteresis:
+			disk.above[i] = false
+		}
+	}
+
+	start := now.Truncate(bucketWidth)
+	if n := len(disk.buckets); n > 0 && disk.buckets[n-1].start.Equal(start) {
+		b := &disk.buckets[n-1]
+		b.min = math.Min(b.min, celsius)
+		b.max = math.Max(b.max, celsius)
+		b.sum += celsius
+		b.count++
+	} else {
+		disk.buckets = append(disk.buckets, bucket{start: start, min: celsius, max: celsius, sum: celsius, count: 1})
+	}
+
+	// Drop buckets that fell out of the longest window
+	cutoff := now.Add(-h.windows[len(h.windows)-1].duration)
+	drop := 0
+	for drop < len(disk.buckets) && disk.buckets[drop].start.Add(bucketWidth).Before(cutoff) {
+		drop++
+	}
+	disk.buckets = slices.Delete(disk.buckets, 0, drop)
+}
+
+// Profile returns the temperature history of a disk at now, or nil if no
+// temperature has been recorded for it
+func (h *History) Profile(key string, now time.Time) *types.ThermalInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	disk, ok := h.disks[key]
+	if !ok {
+		return nil
+	}
+
+	info := &types.ThermalInfo{}
+	for _, w := range h.windows {
+		stats := types.TemperatureWindow{Window: w.label}
+		var sum float64
+		// Buckets are in time order, so the ones overlapping the window are at the end
+		for i := len(disk.buckets) - 1; i >= 0; i-- {
+			b := disk.buckets[i]
+			if !b.start.Add(bucketWidth).After(now.Add(-w.duration)) {
+				break
+			}
+			if stats.Samples == 0 {
+				stats.Min, stats.Max = b.min, b.max
+			}
+			stats.Min = math.Min(stats.Min, b.min)
+			stats.Max = math.Max(stats.Max, b.max)
+			sum += b.sum
+			stats.Samples += b.count
+		}
+		if stats.Samples > 0 {
+			stats.Avg = sum / float64(stats.Samples)
+			info.Windows = append(info.Windows, stats)
+		}
+	}
+
+	for i, threshold := range h.thresholds {
+		info.Excursions = append(info.Excursions, types.TemperatureExcursion{
+			Threshold: threshold,
+			Count:     disk.excursions[i],
+			Active:    disk.above[i],
+		})
+	}
+
+	return info
+}
+
+// Prune forgets disks without a reading within the longest window
+func (h *History) Prune(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := now.Add(-h.windows[len(h.windows)-1].duration)
+	for key, disk := range h.disks {
+		if n := len(disk.buckets); n == 0 || disk.buckets[n-1].start.Add(bucketWidth).Before(cutoff) {
+			delete(h.disks, key)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package thermal
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"disk-health-exporter/internal/config"
+	"disk-health-exporter/pkg/types"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestWindows(t *testing.T) {
+	history, err := New(config.TemperatureConfig{})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 30 at the start of the day, then 40 and 44 within the last hour
+	history.Record("DISK1", 30, start)
+	history.Record("DISK1", 40, start.Add(23*time.Hour+30*time.Minute))
+	history.Record("DISK1", 44, start.Add(23*time.Hour+30*time.Minute+10*time.Second))
+	now := start.Add(24 * time.Hour)
+
+	profile := history.Profile("DISK1", now)
+	if profile == nil || len(profile.Windows) != 2 {
+		t.Fatalf("Expected two windows, got %+v", profile)
+	}
+
+	expected := []types.TemperatureWindow{
+		{Window: "1h", Min: 40, Max: 44, Avg: 42, Samples: 2},
+		{Window: "24h", Min: 30, Max: 44, Avg: 38, Samples: 3},
+	}
+	for i, want := range expected {
+		got := profile.Windows[i]
+		if got.Window != want.Window || got.Min != want.Min || got.Max != want.Max || !approxEqual(got.Avg, want.Avg) || got.Samples != want.Samples {
+			t.Errorf("Expected window %+v, got %+v", want, got)
+		}
+	}
+
+	// The first reading falls out of the 24h window, the others out of the 1h window
+	profile = history.Profile("DISK1", now.Add(45*time.Minute))
+	if len(profile.Windows) != 1 || profile.Windows[0].Window != "24h" || profile.Windows[0].Min != 40 {
+		t.Errorf("Expected only the 24h window with a minimum of 40, got %+v", profile.Windows)
+	}
+
+	if history.Profile("OTHER", now) != nil {
+		t.Error("Expected no profile for a disk without readings")
+	}
+}
+
+func TestExcursions(t *testing.T) {
+	history, err := New(config.TemperatureConfig{
+		Excursions: config.TemperatureExcursionsConfig{Thresholds: []float64{60, 50}},
+	})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Already above 50 at the first reading: not counted.
+	// 49 is within the hysteresis, so 51 continues the same excursion.
+	// 47 ends it, 55 is a second excursion above 50, 61 the first above 60.
+	for _, celsius := range []float64{52, 49, 51, 47, 55, 61} {
+		history.Record("DISK1", celsius, now)
+		now = now.Add(time.Minute)
+	}
+
+	profile := history.Profile("DISK1", now)
+	expected := []types.TemperatureExcursion{
+		{Threshold: 50, Count: 1, Active: true},
+		{Threshold: 60, Count: 1, Active: true},
+	}
+	if len(profile.Excursions) != len(expected) {
+		t.Fatalf("Expected %d excursion thresholds, got %+v", len(expected), profile.Excursions)
+	}
+	for i, want := range expected {
+		if profile.Excursions[i] != want {
+			t.Errorf("Expected excursion %+v, got %+v", want, profile.Excursions[i])
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	history, err := New(config.TemperatureConfig{Windows: []string{"1h"}})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	history.Record("GONE", 35, now)
+	history.Record("KEPT", 35, now.Add(time.Hour))
+	history.Prune(now.Add(90 * time.Minute))
+
+	if history.Profile("GONE", now) != nil {
+		t.Error("Expected a disk without readings within the window to be pruned")
+	}
+	if history.Profile("KEPT", now) == nil {
+		t.Error("Expected a disk with recent readings to be kept")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	negative := -1.0
+	tests := []config.TemperatureConfig{
+		{Windows: []string{"soon"}},
+		{Windows: []string{"30s"}},
+		{Excursions: config.TemperatureExcursionsConfig{Hysteresis: &negative}},
+	}
+	for _, cfg := range tests {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("Expected an error for %+v", cfg)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"disk-health-exporter/internal/metrics"
+	"disk-health-exporter/pkg/types"
+)
+
+// UpdateRAIDControllerMetrics updates status, temperature and error metrics for a RAID controller
+func UpdateRAIDControllerMetrics(controller *types.RAIDControllerInfo, m *metrics.Metrics) {
+	if controller == nil {
+		return
+	}
+
+	adapterIDStr := strconv.Itoa(controller.AdapterID)
+	toolName := controller.ToolName
+	if toolName == "" {
+		toolName = "Unknown"
+	}
+	labels := []string{adapterIDStr, toolName}
+
+	m.RaidControllerInfo.WithLabelValues(adapterIDStr, toolName, controller.Model, controller.SerialNumber,
+		controller.FirmwareVersion, controller.FirmwarePackage, controller.DriverVersion, controller.BIOSVersion).Set(1)
+	m.RaidControllerStatus.WithLabelValues(adapterIDStr, controller.Status, toolName).Set(float64(GetControllerStatusValue(controller.Status)))
+
+	if controller.ROCTemperature > 0 {
+		m.RaidControllerTemperature.WithLabelValues(labels...).Set(float64(controller.ROCTemperature))
+	}
+
+	if controller.CacheSize > 0 {
+		m.RaidControllerCacheSize.WithLabelValues(labels...).Set(float64(controller.CacheSize))
+	}
+
+	if controller.CacheStatus != "" {
+		m.RaidControllerCacheStatus.WithLabelValues(adapterIDStr, controller.CacheStatus, toolName).Set(float64(GetCacheStatusValue(controller.CacheStatus)))
+	}
+
+	m.RaidControllerMemoryCorrectable.WithLabelValues(labels...).Set(float64(controller.MemoryCorrectableErrors))
+	m.RaidControllerMemoryUncorrectable.WithLabelValues(labels...).Set(float64(controller.MemoryUncorrectableErrors))
+	m.RaidControllerVirtualDrives.WithLabelValues(labels...).Set(float64(controller.NumVirtualDrives))
+	m.RaidControllerPhysicalDrives.WithLabelValues(labels...).Set(float64(controller.NumPhysicalDrives))
+	m.RaidControllerForeignConfigs.WithLabelValues(labels...).Set(float64(controller.ForeignConfigs))
+	m.RaidControllerPendingFlash.WithLabelValues(labels...).Set(boolToFloat(controller.PendingFlashImage))
+
+	if controller.AlarmState != "" {
+		m.RaidControllerAlarm.WithLabelValues(adapterIDStr, controller.AlarmState, toolName).Set(float64(GetAlarmStateValue(controller.AlarmState)))
+	}
+
+	if controller.PatrolReadMode != "" || controller.PatrolReadState != "" {
+		active := controller.PatrolReadState == "Active"
+		m.RaidControllerPatrolReadActive.WithLabelValues(adapterIDStr, controller.PatrolReadMode, controller.PatrolReadState, toolName).Set(boolToFloat(active))
+		if active {
+			m.RaidControllerPatrolReadProgress.WithLabelValues(labels...).Set(float64(controller.PatrolReadProgress))
+		}
+	}
+
+	// Only MegaCLI and StorCLI count patrol read iterations
+	if controller.PatrolReadState != "" {
+		m.RaidControllerPatrolReadIterations.WithLabelValues(labels...).Set(float64(controller.PatrolReadIterations))
+	}
+
+	if !controller.PatrolReadNextRun.IsZero() {
+		m.RaidControllerPatrolReadNextRun.WithLabelValues(labels...).Set(float64(controller.PatrolReadNextRun.Unix()))
+	}
+}
+
+// UpdateRAIDDriveMetrics updates error counter, state and link speed metrics of a physical drive behind a RAID controller
+func UpdateRAIDDriveMetrics(disk *types.DiskInfo, m *metrics.Metrics) {
+	if disk == nil || disk.RaidDrive == nil {
+		return
+	}
+
+	drive := disk.RaidDrive
+	labels := []string{disk.Device, disk.Serial, disk.Model, drive.ToolName}
+
+	// ssacli does not count errors per drive
+	if drive.ToolName != "SSACLI" {
+		m.DiskRaidMediaErrors.WithLabelValues(labels...).Set(float64(drive.MediaErrorCount))
+		m.DiskRaidOtherErrors.WithLabelValues(labels...).Set(float64(drive.OtherErrorCount))
+		m.DiskRaidPredictiveFailures.WithLabelValues(labels...).Set(float64(drive.PredictiveFailureCount))
+	}
+	// arcconf and ssacli do not report a shield counter
+	if drive.ToolName != "Arcconf" && drive.ToolName != "SSACLI" {
+		m.DiskRaidShieldCounter.WithLabelValues(labels...).Set(float64(drive.ShieldCounter))
+	}
+
+	if drive.FirmwareState != "" {
+		m.DiskRaidFirmwareState.WithLabelValues(disk.Device, disk.Serial, disk.Model, drive.ToolName,
+			drive.FirmwareState, drive.ForeignState).Set(1)
+	}
+
+	if speed := ParseLinkSpeedGbps(drive.LinkSpeed); speed > 0 {
+		m.DiskRaidLinkSpeed.WithLabelValues(labels...).Set(speed)
+	}
+	if speed := ParseLinkSpeedGbps(drive.DeviceSpeed); speed > 0 {
+		m.DiskRaidDeviceSpeed.WithLabelValues(labels...).Set(speed)
+	}
+}
+
+// GetControllerStatusValue converts a RAID controller status to a numeric value
+func GetControllerStatusValue(status string) int {
+	switch strings.ToLower(status) {
+	case "optimal", "ok":
+		return 1
+	case "needs attention", "degraded", "warning":
+		return 2
+	case "failed", "fault":
+		return 3
+	default:
+		return 0
+	}
+}
+
+// GetCacheStatusValue converts a RAID controller cache module status to a numeric value
+func GetCacheStatusValue(status string) int {
+	switch strings.ToLower(status) {
+	case "ok":
+		return 1
+	case "temporarily disabled", "not configured", "not present":
+		return 2
+	case "permanently disabled", "failed":
+		return 3
+	default:
+		return 0
+	}
+}
+
+// GetAlarmStateValue converts a RAID controller alarm state to a numeric value
+func GetAlarmStateValue(state string) int {
+	switch strings.ToLower(state) {
+	case "present", "on", "enabled":
+		return 1
+	default:
+		return 0
+	}
+}
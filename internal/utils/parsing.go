@@ -1,45 +1,26 @@
 package utils
 
 import (
+	"regexp"
 	"strconv"
 	"strings"
 
 	"disk-health-exporter/pkg/types"
 )
 
-// GetHealthStatusValue converts health string to numeric value
-func GetHealthStatusValue(health string) int {
-	health = strings.ToUpper(strings.TrimSpace(health))
-
-	switch {
-	case strings.Contains(health, "OK") || strings.Contains(health, "ONLINE") || strings.Contains(health, "OPTIMAL") ||
-		strings.Contains(health, "SPUN UP") || strings.Contains(health, "HOTSPARE") || strings.Contains(health, "SPARE") ||
-		strings.Contains(health, "UNCONFIGURED(GOOD)"):
-		return int(types.HealthStatusOK)
-	case strings.Contains(health, "WARNING") || strings.Contains(health, "REBUILDING") || strings.Contains(health, "SPUN DOWN"):
-		return int(types.HealthStatusWarning)
-	case strings.Contains(health, "CRITICAL") || strings.Contains(health, "FAILED") || strings.Contains(health, "OFFLINE") ||
-		strings.Contains(health, "UNCONFIGURED(BAD)"):
-		return int(types.HealthStatusCritical)
-	default:
-		return int(types.HealthStatusUnknown)
-	}
-}
-
-// GetRaidStatusValue converts RAID state string to numeric value
-func GetRaidStatusValue(state string) int {
-	state = strings.ToUpper(strings.TrimSpace(state))
-
-	switch {
-	case strings.Contains(state, "OPTIMAL") || strings.Contains(state, "OPTL") || strings.Contains(state, "OK"):
-		return 1
-	case strings.Contains(state, "DEGRADED") || strings.Contains(state, "REBUILDING") || strings.Contains(state, "DGRD"):
-		return 2
-	case strings.Contains(state, "FAILED") || strings.Contains(state, "OFFLINE") || strings.Contains(state, "FAIL"):
-		return 3
-	default:
-		return 0
+// GetDiskHealthStatusValue converts the health of a disk to a numeric value,
+// raised to the most severe threshold rule firing for the disk
+func GetDiskHealthStatusValue(disk types.DiskInfo) int {
+	status := int(disk.HealthStatus)
+	for _, alert := range disk.RuleAlerts {
+		switch alert.Severity {
+		case types.RuleSeverityCritical:
+			status = max(status, int(types.HealthStatusCritical))
+		case types.RuleSeverityWarning:
+			status = max(status, int(types.HealthStatusWarning))
+		}
 	}
+	return status
 }
 
 // ParseSizeToBytes converts human-readable size strings to bytes
@@ -89,20 +70,20 @@ func ParseSizeToBytes(sizeStr string) int64 {
 	}
 }
 
-// GetSoftwareRAIDStatusValue converts software RAID state to numeric value
-func GetSoftwareRAIDStatusValue(state string) int {
-	state = strings.ToLower(strings.TrimSpace(state))
+// linkSpeedRe matches link speeds such as "12.0Gb/s" (StorCLI, MegaCLI), "SAS 12.0 Gb/s" (arcconf) or "6.0Gbps" (ssacli)
+var linkSpeedRe = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*Gb(?:/s|ps)`)
 
-	switch state {
-	case "clean", "active":
-		return 1
-	case "degraded", "recovering", "resyncing":
-		return 2
-	case "failed", "inactive":
-		return 3
-	default:
+// ParseLinkSpeedGbps converts a link speed string to Gb/s, returning 0 if unknown
+func ParseLinkSpeedGbps(speed string) float64 {
+	matches := linkSpeedRe.FindStringSubmatch(speed)
+	if matches == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
 		return 0
 	}
+	return value
 }
 
 // GetBatteryStatusValue converts battery status string to numeric value
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+
+	"disk-health-exporter/pkg/types"
+)
+
+func TestGetDiskHealthStatusValue(t *testing.T) {
+	warning := types.RuleAlert{Rule: "temperature", Severity: types.RuleSeverityWarning}
+	critical := types.RuleAlert{Rule: "wear", Severity: types.RuleSeverityCritical}
+
+	tests := []struct {
+		name     string
+		disk     types.DiskInfo
+		expected types.HealthStatus
+	}{
+		{"healthy", types.DiskInfo{Health: "OK", HealthStatus: types.HealthStatusOK}, types.HealthStatusOK},
+		{"raised to warning", types.DiskInfo{Health: "OK", HealthStatus: types.HealthStatusOK, RuleAlerts: []types.RuleAlert{warning}}, types.HealthStatusWarning},
+		{"most severe rule", types.DiskInfo{Health: "OK", HealthStatus: types.HealthStatusOK, RuleAlerts: []types.RuleAlert{warning, critical}}, types.HealthStatusCritical},
+		{"never lowered", types.DiskInfo{Health: "FAILED", HealthStatus: types.HealthStatusCritical, RuleAlerts: []types.RuleAlert{warning}}, types.HealthStatusCritical},
+		{"unknown health", types.DiskInfo{Health: "", RuleAlerts: []types.RuleAlert{warning}}, types.HealthStatusWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := GetDiskHealthStatusValue(tt.disk); status != int(tt.expected) {
+				t.Errorf("Expected %d, got %d", tt.expected, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"disk-health-exporter/internal/metrics"
+	"disk-health-exporter/internal/zfsevents"
+	"disk-health-exporter/pkg/types"
+)
+
+// UpdateZFSMetrics updates pool space and per-vdev metrics for a ZFS pool
+func UpdateZFSMetrics(pool *types.ZFSPoolInfo, m *metrics.Metrics) {
+	if pool == nil {
+		return
+	}
+
+	if pool.Size > 0 {
+		m.ZFSPoolSizeBytes.WithLabelValues(pool.Name).Set(float64(pool.Size))
+		m.ZFSPoolAllocatedBytes.WithLabelValues(pool.Name).Set(float64(pool.Allocated))
+		m.ZFSPoolFreeBytes.WithLabelValues(pool.Name).Set(float64(pool.Free))
+		m.ZFSPoolCapacity.WithLabelValues(pool.Name).Set(float64(pool.Capacity))
+	}
+	if pool.Fragmentation >= 0 {
+		m.ZFSPoolFragmentation.WithLabelValues(pool.Name).Set(float64(pool.Fragmentation))
+	}
+	if pool.DedupRatio > 0 {
+		m.ZFSPoolDedupRatio.WithLabelValues(pool.Name).Set(pool.DedupRatio)
+	}
+
+	for _, vdev := range pool.AllVdevs() {
+		m.ZFSVdevState.WithLabelValues(pool.Name, vdev.Name, vdev.Type, vdev.Class, vdev.State).Set(float64(vdev.Status))
+
+		labels := []string{pool.Name, vdev.Name, vdev.Type, vdev.Class}
+		m.ZFSVdevReadErrors.WithLabelValues(labels...).Set(float64(vdev.ReadErrors))
+		m.ZFSVdevWriteErrors.WithLabelValues(labels...).Set(float64(vdev.WriteErrors))
+		m.ZFSVdevChecksumErrors.WithLabelValues(labels...).Set(float64(vdev.ChecksumErrors))
+	}
+
+	if !pool.LastScrub.IsZero() {
+		m.ZFSPoolLastScrubTimestamp.WithLabelValues(pool.Name).Set(float64(pool.LastScrub.Unix()))
+	}
+
+	if scan := pool.Scan; scan != nil {
+		m.ZFSPoolScanState.WithLabelValues(pool.Name, scan.Function, scan.State).Set(float64(GetZFSScanStateValue(scan.State)))
+
+		labels := []string{pool.Name, scan.Function}
+		m.ZFSPoolScanProgress.WithLabelValues(labels...).Set(scan.PercentDone)
+		m.ZFSPoolScanRepairedBytes.WithLabelValues(labels...).Set(float64(scan.Repaired))
+		m.ZFSPoolScanErrors.WithLabelValues(labels...).Set(float64(scan.Errors))
+		if scan.TotalBytes > 0 {
+			m.ZFSPoolScanTotalBytes.WithLabelValues(labels...).Set(float64(scan.TotalBytes))
+		}
+		if scan.Examined > 0 || scan.Issued > 0 {
+			m.ZFSPoolScanExaminedBytes.WithLabelValues(labels...).Set(float64(scan.Examined))
+			m.ZFSPoolScanIssuedBytes.WithLabelValues(labels...).Set(float64(scan.Issued))
+		}
+		if scan.Remaining > 0 {
+			m.ZFSPoolScanRemainingSeconds.WithLabelValues(labels...).Set(scan.Remaining.Seconds())
+		}
+	}
+}
+
+// UpdateZFSDatasetMetrics updates usage metrics for ZFS datasets
+func UpdateZFSDatasetMetrics(datasets []types.ZFSDatasetInfo, m *metrics.Metrics) {
+	for _, dataset := range datasets {
+		labels := []string{dataset.Pool, dataset.Name, dataset.Type}
+		m.ZFSDatasetUsedBytes.WithLabelValues(labels...).Set(float64(dataset.Used))
+		m.ZFSDatasetAvailableBytes.WithLabelValues(labels...).Set(float64(dataset.Available))
+		m.ZFSDatasetReferencedBytes.WithLabelValues(labels...).Set(float64(dataset.Referenced))
+		m.ZFSDatasetQuotaBytes.WithLabelValues(labels...).Set(float64(dataset.Quota))
+		m.ZFSDatasetReservationBytes.WithLabelValues(labels...).Set(float64(dataset.Reservation))
+		if dataset.CompressRatio > 0 {
+			m.ZFSDatasetCompressRatio.WithLabelValues(labels...).Set(dataset.CompressRatio)
+		}
+	}
+}
+
+// UpdateZFSARCMetrics updates ARC and L2ARC metrics
+func UpdateZFSARCMetrics(arc *types.ZFSARCStats, m *metrics.Metrics) {
+	if arc == nil {
+		return
+	}
+
+	m.ZFSARCSizeBytes.WithLabelValues().Set(float64(arc.Size))
+	m.ZFSARCTargetSizeBytes.WithLabelValues().Set(float64(arc.TargetSize))
+	m.ZFSARCMinSizeBytes.WithLabelValues().Set(float64(arc.MinSize))
+	m.ZFSARCMaxSizeBytes.WithLabelValues().Set(float64(arc.MaxSize))
+	m.ZFSARCHits.WithLabelValues().Set(float64(arc.Hits))
+	m.ZFSARCMisses.WithLabelValues().Set(float64(arc.Misses))
+	if total := arc.Hits + arc.Misses; total > 0 {
+		m.ZFSARCHitRatio.WithLabelValues().Set(float64(arc.Hits) / float64(total))
+	}
+
+	// L2ARC statistics are only meaningful when a cache device is present
+	if arc.L2Size > 0 || arc.L2Hits > 0 || arc.L2Misses > 0 {
+		m.ZFSL2ARCSizeBytes.WithLabelValues().Set(float64(arc.L2Size))
+		m.ZFSL2ARCHits.WithLabelValues().Set(float64(arc.L2Hits))
+		m.ZFSL2ARCMisses.WithLabelValues().Set(float64(arc.L2Misses))
+		if total := arc.L2Hits + arc.L2Misses; total > 0 {
+			m.ZFSL2ARCHitRatio.WithLabelValues().Set(float64(arc.L2Hits) / float64(total))
+		}
+	}
+}
+
+// UpdateZFSEventMetrics updates the ZFS error report counters
+func UpdateZFSEventMetrics(counters map[zfsevents.Key]zfsevents.Counter, m *metrics.Metrics) {
+	for key, counter := range counters {
+		m.ZFSEventsTotal.WithLabelValues(key.Pool, key.Vdev, key.Class).Set(float64(counter.Count))
+		if !counter.Last.IsZero() {
+			m.ZFSEventLastTimestamp.WithLabelValues(key.Pool, key.Vdev, key.Class).Set(float64(counter.Last.Unix()))
+		}
+	}
+}
+
+// GetZFSScanStateValue converts a ZFS scan state to a numeric value
+func GetZFSScanStateValue(state string) int {
+	switch state {
+	case types.ZFSScanFinished:
+		return 1
+	case types.ZFSScanActive:
+		return 2
+	case types.ZFSScanPaused:
+		return 3
+	case types.ZFSScanCanceled:
+		return 4
+	default:
+		return 0
+	}
+}
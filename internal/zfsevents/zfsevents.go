@@ -0,0 +1,90 @@
+package zfsevents
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"disk-health-exporter/pkg/types"
+)
+
+// classPrefix is the prefix of ZFS error report classes
+const classPrefix = "ereport.fs.zfs."
+
+// Key identifies an event counter
+type Key struct {
+	Pool  string
+	Vdev  string // Empty for pool-level events
+	Class string // Error report class without prefix (e.g. "checksum", "io", "delay")
+}
+
+// Counter holds the number of events seen for a key and when the latest occurred
+type Counter struct {
+	Count int64
+	Last  time.Time
+}
+
+// Tracker accumulates ZFS error reports across polls of the kernel event queue.
+// The queue is a bounded ring buffer, so events are deduplicated by event ID
+// and counted from the first poll onward.
+type Tracker struct {
+	mu       sync.Mutex
+	lastEID  int64
+	counters map[Key]*Counter
+}
+
+// New creates an empty tracker
+func New() *Tracker {
+	return &Tracker{counters: make(map[Key]*Counter)}
+}
+
+// Update counts the error reports not seen by a previous update
+func (t *Tracker) Update(events []types.ZFSEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Event IDs restart when the ZFS module is reloaded, while an emptied
+	// queue (zpool events -c) keeps numbering where it left off
+	var maxEID int64
+	for _, event := range events {
+		maxEID = max(maxEID, event.EID)
+	}
+	if len(events) > 0 && maxEID < t.lastEID {
+		t.lastEID = 0
+	}
+
+	for _, event := range events {
+		if event.EID <= t.lastEID {
+			continue
+		}
+		class, ok := strings.CutPrefix(event.Class, classPrefix)
+		if !ok || event.Pool == "" {
+			continue
+		}
+
+		key := Key{Pool: event.Pool, Vdev: event.Vdev, Class: class}
+		counter := t.counters[key]
+		if counter == nil {
+			counter = &Counter{}
+			t.counters[key] = counter
+		}
+		counter.Count++
+		if event.Time.After(counter.Last) {
+			counter.Last = event.Time
+		}
+	}
+
+	t.lastEID = max(t.lastEID, maxEID)
+}
+
+// Counters returns a copy of the accumulated counters
+func (t *Tracker) Counters() map[Key]Counter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counters := make(map[Key]Counter, len(t.counters))
+	for key, counter := range t.counters {
+		counters[key] = *counter
+	}
+	return counters
+}
@@ -0,0 +1,74 @@
+package zfsevents
+
+import (
+	"testing"
+	"time"
+
+	"disk-health-exporter/pkg/types"
+)
+
+func TestUpdate(t *testing.T) {
+	base := time.Unix(1752364800, 0)
+	tracker := New()
+
+	tracker.Update([]types.ZFSEvent{
+		{EID: 1, Time: base, Class: "sysevent.fs.zfs.scrub_start", Pool: "tank"},
+		{EID: 2, Time: base.Add(time.Minute), Class: "ereport.fs.zfs.checksum", Pool: "tank", Vdev: "sda"},
+		{EID: 3, Time: base.Add(2 * time.Minute), Class: "ereport.fs.zfs.checksum", Pool: "tank", Vdev: "sda"},
+		{EID: 4, Time: base.Add(3 * time.Minute), Class: "ereport.fs.zfs.data", Pool: "tank"},
+	})
+
+	// The queue still holds the earlier events on the next poll
+	tracker.Update([]types.ZFSEvent{
+		{EID: 3, Time: base.Add(2 * time.Minute), Class: "ereport.fs.zfs.checksum", Pool: "tank", Vdev: "sda"},
+		{EID: 4, Time: base.Add(3 * time.Minute), Class: "ereport.fs.zfs.data", Pool: "tank"},
+		{EID: 5, Time: base.Add(4 * time.Minute), Class: "ereport.fs.zfs.checksum", Pool: "tank", Vdev: "sda"},
+		{EID: 6, Time: base.Add(5 * time.Minute), Class: "ereport.fs.zfs.delay", Pool: "tank", Vdev: "sdb"},
+	})
+
+	counters := tracker.Counters()
+	expected := map[Key]Counter{
+		{Pool: "tank", Vdev: "sda", Class: "checksum"}: {Count: 3, Last: base.Add(4 * time.Minute)},
+		{Pool: "tank", Class: "data"}:                  {Count: 1, Last: base.Add(3 * time.Minute)},
+		{Pool: "tank", Vdev: "sdb", Class: "delay"}:    {Count: 1, Last: base.Add(5 * time.Minute)},
+	}
+	if len(counters) != len(expected) {
+		t.Fatalf("Expected %d counters, got %v", len(expected), counters)
+	}
+	for key, want := range expected {
+		got, ok := counters[key]
+		if !ok || got.Count != want.Count || !got.Last.Equal(want.Last) {
+			t.Errorf("Counter %+v: expected %+v, got %+v", key, want, got)
+		}
+	}
+}
+
+func TestUpdateAfterModuleReload(t *testing.T) {
+	tracker := New()
+	tracker.Update([]types.ZFSEvent{
+		{EID: 100, Class: "ereport.fs.zfs.io", Pool: "tank", Vdev: "sda"},
+	})
+
+	// Event IDs start over after the module is reloaded
+	tracker.Update([]types.ZFSEvent{
+		{EID: 1, Class: "ereport.fs.zfs.io", Pool: "tank", Vdev: "sda"},
+		{EID: 2, Class: "ereport.fs.zfs.io", Pool: "tank", Vdev: "sda"},
+	})
+
+	if got := tracker.Counters()[Key{Pool: "tank", Vdev: "sda", Class: "io"}].Count; got != 3 {
+		t.Errorf("Expected 3 io events, got %d", got)
+	}
+}
+
+func TestUpdateEmptyQueue(t *testing.T) {
+	tracker := New()
+	tracker.Update([]types.ZFSEvent{{EID: 7, Class: "ereport.fs.zfs.io", Pool: "tank"}})
+
+	// zpool events -c clears the queue without resetting event IDs
+	tracker.Update(nil)
+	tracker.Update([]types.ZFSEvent{{EID: 8, Class: "ereport.fs.zfs.io", Pool: "tank"}})
+
+	if got := tracker.Counters()[Key{Pool: "tank", Class: "io"}].Count; got != 2 {
+		t.Errorf("Expected 2 io events, got %d", got)
+	}
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // HealthStatus represents disk health status values
 type HealthStatus int
 
@@ -10,6 +12,16 @@ const (
 	HealthStatusCritical HealthStatus = 3
 )
 
+// RAIDState represents the normalized state of a RAID array or pool
+type RAIDState int
+
+const (
+	RAIDStateUnknown  RAIDState = 0
+	RAIDStateOptimal  RAIDState = 1
+	RAIDStateDegraded RAIDState = 2
+	RAIDStateFailed   RAIDState = 3
+)
+
 // RaidRole represents disk role in RAID configuration
 type RaidRole int
 
@@ -29,6 +41,7 @@ type DiskInfo struct {
 	Model               string
 	Vendor              string
 	Health              string
+	HealthStatus        HealthStatus // Health translated by the reporting tool's state table
 	Temperature         float64
 	Type                string  // "raid", "regular", "macos-smart", etc.
 	Location            string  // physical location or slot
@@ -39,8 +52,10 @@ type DiskInfo struct {
 	UncorrectableErrors int64   // Uncorrectable error count
 	TotalLBAsWritten    int64   // Total LBAs written
 	TotalLBAsRead       int64   // Total LBAs read
-	DriveTemperatureMax float64 // Maximum recorded temperature
-	DriveTemperatureMin float64 // Minimum recorded temperature
+	BytesWritten        int64   // Host bytes written, normalized across ATA, NVMe and SCSI
+	BytesRead           int64   // Host bytes read, normalized across ATA, NVMe and SCSI
+	DriveTemperatureMax float64 // Highest temperature the drive recorded over its lifetime
+	DriveTemperatureMin float64 // Lowest temperature the drive recorded over its lifetime
 	Interface           string  // SATA, NVMe, SAS, etc.
 	Capacity            int64   // Disk capacity in bytes
 	UsedBytes           int64   // Used space in bytes
@@ -60,6 +75,12 @@ type DiskInfo struct {
 	MediaErrors     int64 // NVMe media errors
 	ErrorLogEntries int64 // Number of error log entries
 
+	// Readings of the additional temperature sensors of NVMe drives
+	TemperatureSensors []TemperatureSensor
+
+	// SMART self-test log, nil if the drive reports none
+	SelfTests *SelfTestInfo
+
 	// RAID role and status information
 	RaidRole            string // "active", "spare", "hot_spare", "failed", "rebuilding", "unconfigured"
 	RaidArrayID         string // Which RAID array this disk belongs to (if any)
@@ -68,6 +89,204 @@ type DiskInfo struct {
 	IsEmergencySpare    bool   // Whether this is an emergency spare drive
 	IsGlobalSpare       bool   // Whether this is a global spare (can replace any failed drive)
 	IsDedicatedSpare    bool   // Whether this is dedicated to a specific array
+	SmartDevice         string // Device smartctl opens to reach a drive behind a RAID controller (e.g. /dev/bus/0)
+	SmartDeviceType     string // smartctl -d type addressing the drive behind the controller (e.g. megaraid,8)
+
+	// Physical drive state and error counters reported by a hardware RAID controller
+	RaidDrive *RAIDDriveInfo
+
+	// Tool that supplied each merged field, keyed by field name (e.g. "health": "smartctl")
+	Provenance map[string]string
+
+	// Fields on which tools disagreed while merging
+	SourceConflicts []SourceConflict
+
+	// Predictive failure assessment (computed by the collector)
+	FailureRisk *FailureRiskInfo
+
+	// SSD endurance estimate (computed by the collector)
+	Endurance *EnduranceInfo
+
+	// Temperature history (computed by the collector)
+	Thermal *ThermalInfo
+
+	// Threshold rule class and firing rules (computed by the collector)
+	RuleClass  string
+	RuleAlerts []RuleAlert
+}
+
+// SourceConflict records two tools reporting different values for the same disk field
+type SourceConflict struct {
+	Field            string // Field name (e.g. "health", "smart_healthy")
+	Source           string // Tool whose value was kept
+	Value            string
+	OverriddenSource string // Tool whose value was discarded
+	OverriddenValue  string
+}
+
+// RAIDDriveInfo represents physical drive state and error counters reported by a hardware RAID controller
+type RAIDDriveInfo struct {
+	ToolName               string // Tool that reported the drive (MegaCLI, StoreCLI, Arcconf, SSACLI)
+	FirmwareState          string // Controller firmware state (e.g. "Online, Spun Up", "Unconfigured(bad)")
+	ForeignState           string // Foreign configuration state ("None", "Foreign")
+	MediaErrorCount        int64  // Media errors counted by the controller
+	OtherErrorCount        int64  // Other (link, timeout, protocol) errors counted by the controller
+	PredictiveFailureCount int64  // Predictive failure (SMART trip) events counted by the controller
+	ShieldCounter          int64  // Times the controller shielded the drive after transient errors
+	LinkSpeed              string // Negotiated link speed (e.g. "12.0Gb/s")
+	DeviceSpeed            string // Maximum speed supported by the drive
+}
+
+// RiskLevel represents the categorical failure risk of a disk
+type RiskLevel int
+
+const (
+	RiskLevelLow      RiskLevel = 0
+	RiskLevelMedium   RiskLevel = 1
+	RiskLevelHigh     RiskLevel = 2
+	RiskLevelCritical RiskLevel = 3
+)
+
+// String returns the lowercase name of the risk level
+func (r RiskLevel) String() string {
+	switch r {
+	case RiskLevelMedium:
+		return "medium"
+	case RiskLevelHigh:
+		return "high"
+	case RiskLevelCritical:
+		return "critical"
+	default:
+		return "low"
+	}
+}
+
+// MarshalText encodes the risk level by name in JSON output
+func (r RiskLevel) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// FailureRiskInfo represents the predictive failure assessment of a disk
+type FailureRiskInfo struct {
+	Score   float64      // Combined risk score (0-1)
+	Level   RiskLevel    // Categorical risk level derived from the score
+	Factors []RiskFactor // Factors that contributed to the score
+}
+
+// Threshold rule severities
+const (
+	RuleSeverityWarning  = "warning"
+	RuleSeverityCritical = "critical"
+)
+
+// RuleAlert is a threshold rule firing for a disk, RAID array or controller battery
+type RuleAlert struct {
+	Rule      string  // Rule name (e.g. "temperature")
+	Severity  string  // RuleSeverityWarning or RuleSeverityCritical
+	Value     float64 // Observed value
+	Threshold float64 // Threshold the value reached
+	Device    string  // Disk device, array key (controller/array ID) or battery key (tool/adapter ID)
+	Serial    string  // Disk serial number, empty for arrays and batteries
+}
+
+// RiskFactor represents a single signal contributing to a failure risk score
+type RiskFactor struct {
+	Name         string  // Factor name (e.g. "pending_sectors")
+	Value        float64 // Raw observed value
+	Contribution float64 // Weighted contribution to the score (0-1)
+}
+
+// EnduranceInfo represents the write endurance estimate of an SSD
+type EnduranceInfo struct {
+	WriteRate     float64 // Average host bytes written per day over the rate window
+	HasWriteRate  bool    // Whether enough history exists to compute WriteRate
+	RatedBytes    int64   // Rated write endurance in bytes (0 if unknown)
+	RemainingDays float64 // Estimated days until rated endurance is exhausted
+	Method        string  // Estimation method ("rated_tbw", "percentage_used", "percentage_used_lifetime"), empty if no estimate
+}
+
+// TemperatureSensor is a reading of one of the temperature sensors of a drive
+type TemperatureSensor struct {
+	Sensor  int     // Sensor number as numbered by the drive, starting at 1
+	Celsius float64 // Temperature in Celsius
+}
+
+// ThermalInfo represents the temperature history of a disk kept by the exporter
+type ThermalInfo struct {
+	Windows    []TemperatureWindow    // Statistics over each configured window, longest last
+	Excursions []TemperatureExcursion // Excursions above each configured threshold, lowest first
+}
+
+// TemperatureWindow holds temperature statistics over a window ending at the latest collection
+type TemperatureWindow struct {
+	Window  string  // Window as configured (e.g. "24h")
+	Min     float64 // Lowest temperature in Celsius
+	Max     float64 // Highest temperature in Celsius
+	Avg     float64 // Average temperature in Celsius
+	Samples int     // Temperature readings in the window
+}
+
+// TemperatureExcursion counts the times a disk rose to or above a temperature threshold
+type TemperatureExcursion struct {
+	Threshold float64 // Threshold in Celsius
+	Count     int64   // Excursions since the exporter started
+	Active    bool    // Whether the disk is above the threshold now
+}
+
+// SelfTestResult represents the outcome of a drive self-test
+type SelfTestResult int
+
+const (
+	SelfTestResultUnknown SelfTestResult = 0
+	SelfTestResultPassed  SelfTestResult = 1
+	SelfTestResultAborted SelfTestResult = 2
+	SelfTestResultFailed  SelfTestResult = 3
+)
+
+// String returns the lowercase name of the self-test result
+func (r SelfTestResult) String() string {
+	switch r {
+	case SelfTestResultPassed:
+		return "passed"
+	case SelfTestResultAborted:
+		return "aborted"
+	case SelfTestResultFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the self-test result by name in JSON output
+func (r SelfTestResult) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Self-test types, named as smartctl -t names them
+const (
+	SelfTestShort      = "short"
+	SelfTestLong       = "long"
+	SelfTestConveyance = "conveyance"
+	SelfTestSelective  = "selective"
+	SelfTestVendor     = "vendor"
+)
+
+// SelfTestInfo represents the self-test log of a drive
+type SelfTestInfo struct {
+	Running         bool            // Whether a self-test is in progress
+	RunningType     string          // Type of the test in progress, empty if the drive does not say
+	RunningProgress int             // Percentage of the test in progress completed
+	Latest          []SelfTestEntry // Most recent completed test of each type, most recent first
+}
+
+// SelfTestEntry is a completed self-test from the log of a drive
+type SelfTestEntry struct {
+	Type          string         // Test type (e.g. "short", "long")
+	Result        SelfTestResult // Outcome of the test
+	Status        string         // Outcome as worded by smartctl
+	PowerOnHours  int64          // Drive power-on hours when the test ran
+	AgeHours      int64          // Power-on hours since the test ran
+	FirstErrorLBA int64          // LBA of the first error, -1 if none was reported
 }
 
 // RAIDInfo represents RAID array information
@@ -75,7 +294,7 @@ type RAIDInfo struct {
 	ArrayID         string
 	RaidLevel       string
 	State           string
-	Status          int
+	Status          RAIDState        // State translated by the reporting tool's state table
 	Size            int64            // Array size in bytes
 	UsedSize        int64            // Used space in bytes
 	NumDrives       int              // Number of drives in array
@@ -83,10 +302,15 @@ type RAIDInfo struct {
 	NumSpareDrives  int              // Number of spare drives
 	NumFailedDrives int              // Number of failed drives
 	RebuildProgress int              // Rebuild progress percentage (0-100)
+	Rebuilding      bool             // Rebuild or resilver running, as reported by the tool
 	ScrubProgress   int              // Scrub progress percentage (0-100)
+	CheckRunning    bool             // Consistency check (verify) running on a hardware array, progress in ScrubProgress
 	Type            string           // "hardware", "software", "zfs", etc.
 	Controller      string           // Controller model/name
 	Battery         *RAIDBatteryInfo // Battery information (if available)
+	ZFS             *ZFSPoolInfo     // ZFS pool details (for Type "zfs")
+	Members         []string         // Member devices or partitions, for tools that list them with the array (software RAID)
+	RuleAlerts      []RuleAlert      // Threshold rules firing for the array or its battery (computed by the collector)
 
 	// Filesystem usage information (for virtual disks presented by RAID)
 	VirtualDevice     string  // Virtual device path (e.g., /dev/sda)
@@ -97,6 +321,123 @@ type RAIDInfo struct {
 	Filesystem        string  // Filesystem type
 }
 
+// ZFSPoolInfo represents a ZFS pool with its vdev tree
+type ZFSPoolInfo struct {
+	Name          string
+	State         string        // Pool health (ONLINE, DEGRADED, FAULTED, ...)
+	Size          int64         // Pool size in bytes
+	Allocated     int64         // Allocated bytes
+	Free          int64         // Free bytes
+	Fragmentation int           // Free space fragmentation percentage (-1 if unknown)
+	Capacity      int           // Used capacity percentage
+	DedupRatio    float64       // Deduplication ratio (1.0 = no dedup)
+	Root          ZFSVdevInfo   // Root vdev; its children are the data, log, special and dedup vdevs
+	Spares        []ZFSVdevInfo // Hot spares
+	Cache         []ZFSVdevInfo // L2ARC cache devices
+	Scan          *ZFSScanInfo  // Latest scrub or resilver (nil if never run)
+	LastScrub     time.Time     // Completion time of the last finished scrub (zero if unknown)
+}
+
+// ZFS scan states
+const (
+	ZFSScanNone     = "none"
+	ZFSScanActive   = "scanning"
+	ZFSScanPaused   = "paused"
+	ZFSScanFinished = "finished"
+	ZFSScanCanceled = "canceled"
+)
+
+// ZFSScanInfo represents the latest scrub or resilver of a ZFS pool
+type ZFSScanInfo struct {
+	Function    string        // "scrub", "resilver" or "error_scrub"
+	State       string        // One of the ZFSScan* states
+	StartTime   time.Time     // When the scan started
+	EndTime     time.Time     // When the scan finished or was canceled
+	TotalBytes  int64         // Bytes to examine
+	Examined    int64         // Bytes scanned (metadata traversal)
+	Issued      int64         // Bytes issued for verification
+	Repaired    int64         // Bytes repaired (scrub) or resilvered (resilver)
+	Errors      int64         // Errors encountered
+	PercentDone float64       // Completion percentage (0-100)
+	Remaining   time.Duration // Estimated time remaining (0 if unknown)
+}
+
+// AllVdevs returns every vdev in the pool in depth-first order
+func (p ZFSPoolInfo) AllVdevs() []ZFSVdevInfo {
+	var vdevs []ZFSVdevInfo
+	var walk func(v ZFSVdevInfo)
+	walk = func(v ZFSVdevInfo) {
+		vdevs = append(vdevs, v)
+		for _, child := range v.Children {
+			walk(child)
+		}
+	}
+
+	walk(p.Root)
+	for _, v := range p.Cache {
+		walk(v)
+	}
+	for _, v := range p.Spares {
+		walk(v)
+	}
+	return vdevs
+}
+
+// ZFSDatasetInfo represents a ZFS filesystem or volume
+type ZFSDatasetInfo struct {
+	Name          string  // Full dataset name (e.g., tank/home)
+	Pool          string  // Pool the dataset belongs to
+	Type          string  // "filesystem" or "volume"
+	Used          int64   // Bytes used by the dataset and its descendants
+	Available     int64   // Bytes available to the dataset
+	Referenced    int64   // Bytes referenced by the dataset
+	CompressRatio float64 // Compression ratio (1.0 = uncompressed)
+	Quota         int64   // Quota in bytes (0 = none)
+	Reservation   int64   // Reservation in bytes (0 = none)
+}
+
+// ZFSEvent represents an event from the ZFS kernel event queue (zpool events)
+type ZFSEvent struct {
+	EID      int64     // Event ID, increasing while the ZFS module is loaded
+	Time     time.Time // When the event was posted
+	Class    string    // Full event class (e.g., ereport.fs.zfs.checksum)
+	Pool     string    // Pool name (if any)
+	Vdev     string    // Vdev name as shown by zpool status (if any)
+	VdevPath string    // Device path of the vdev (if any)
+}
+
+// ZFSARCStats represents ZFS adaptive replacement cache statistics from arcstats
+type ZFSARCStats struct {
+	Size       int64 // Current ARC size in bytes
+	TargetSize int64 // Target ARC size in bytes
+	MinSize    int64 // Minimum ARC size in bytes
+	MaxSize    int64 // Maximum ARC size in bytes
+	Hits       int64 // ARC hits since boot
+	Misses     int64 // ARC misses since boot
+	L2Size     int64 // L2ARC size in bytes
+	L2Hits     int64 // L2ARC hits since boot
+	L2Misses   int64 // L2ARC misses since boot
+}
+
+// ZFSVdevInfo represents a ZFS virtual device and its children
+type ZFSVdevInfo struct {
+	Name           string
+	Type           string        // "root", "mirror", "raidz1", "raidz2", "raidz3", "draid", "spare", "replacing", "disk", "file"
+	Class          string        // "normal", "log", "cache", "spare", "special", "dedup"
+	State          string        // ONLINE, DEGRADED, FAULTED, OFFLINE, UNAVAIL, REMOVED, AVAIL, INUSE
+	Status         HealthStatus  // State as translated by the zpool state table
+	Path           string        // Device path for leaf vdevs (if reported)
+	ReadErrors     int64         // Read I/O errors
+	WriteErrors    int64         // Write I/O errors
+	ChecksumErrors int64         // Checksum errors
+	Children       []ZFSVdevInfo // Child vdevs (empty for leaves)
+}
+
+// IsLeaf returns whether the vdev is a physical device or file
+func (v ZFSVdevInfo) IsLeaf() bool {
+	return v.Type == "disk" || v.Type == "file"
+}
+
 // SmartCtlOutput represents smartctl JSON output structure
 type SmartCtlOutput struct {
 	Device struct {
@@ -127,9 +468,11 @@ type SmartCtlOutput struct {
 		Enabled   bool `json:"enabled"`
 	} `json:"smart_support"`
 	Temperature struct {
-		Current  int `json:"current"`
-		Power    int `json:"power_cycle_min_max"`
-		Lifetime int `json:"lifetime_min_max"`
+		Current       int `json:"current"`
+		PowerCycleMin int `json:"power_cycle_min"`
+		PowerCycleMax int `json:"power_cycle_max"`
+		LifetimeMin   int `json:"lifetime_min"`
+		LifetimeMax   int `json:"lifetime_max"`
 	} `json:"temperature"`
 	PowerOnTime struct {
 		Hours int `json:"hours"`
@@ -159,36 +502,87 @@ type SmartCtlOutput struct {
 			} `json:"raw"`
 		} `json:"table"`
 	} `json:"ata_smart_attributes"`
+	AtaSmartData struct {
+		SelfTest struct {
+			Status struct {
+				Value            int    `json:"value"`
+				String           string `json:"string"`
+				RemainingPercent int    `json:"remaining_percent"`
+			} `json:"status"`
+		} `json:"self_test"`
+	} `json:"ata_smart_data"`
+	AtaSmartSelfTestLog *struct {
+		Standard struct {
+			Table []struct {
+				Type struct {
+					Value  int    `json:"value"`
+					String string `json:"string"`
+				} `json:"type"`
+				Status struct {
+					Value  int    `json:"value"`
+					String string `json:"string"`
+				} `json:"status"`
+				LifetimeHours int64  `json:"lifetime_hours"`
+				LBA           *int64 `json:"lba"`
+			} `json:"table"`
+		} `json:"standard"`
+	} `json:"ata_smart_self_test_log"`
+	NvmeSelfTestLog *struct {
+		CurrentSelfTestOperation struct {
+			Value  int    `json:"value"`
+			String string `json:"string"`
+		} `json:"current_self_test_operation"`
+		CurrentSelfTestCompletionPercent int `json:"current_self_test_completion_percent"`
+		Table                            []struct {
+			SelfTestCode struct {
+				Value  int    `json:"value"`
+				String string `json:"string"`
+			} `json:"self_test_code"`
+			SelfTestResult struct {
+				Value  int    `json:"value"`
+				String string `json:"string"`
+			} `json:"self_test_result"`
+			PowerOnHours int64  `json:"power_on_hours"`
+			LBA          *int64 `json:"lba"`
+		} `json:"table"`
+	} `json:"nvme_self_test_log"`
 	AtaSmartErrorLog struct {
 		Summary struct {
 			Revision int `json:"revision"`
 			Count    int `json:"count"`
 		} `json:"summary"`
 	} `json:"ata_smart_error_log"`
+	ScsiErrorCounterLog struct {
+		Read struct {
+			GigabytesProcessed string `json:"gigabytes_processed"`
+		} `json:"read"`
+		Write struct {
+			GigabytesProcessed string `json:"gigabytes_processed"`
+		} `json:"write"`
+	} `json:"scsi_error_counter_log"`
 	NvmeSmartHealthInformationLog struct {
-		CriticalWarning               int   `json:"critical_warning"`
-		Temperature                   int   `json:"temperature"`
-		AvailableSpare                int   `json:"available_spare"`
-		AvailableSpareThreshold       int   `json:"available_spare_threshold"`
-		PercentageUsed                int   `json:"percentage_used"`
-		DataUnitsRead                 int64 `json:"data_units_read"`
-		DataUnitsWritten              int64 `json:"data_units_written"`
-		HostReadCommands              int64 `json:"host_read_commands"`
-		HostWriteCommands             int64 `json:"host_write_commands"`
-		ControllerBusyTime            int64 `json:"controller_busy_time"`
-		PowerCycles                   int64 `json:"power_cycles"`
-		PowerOnHours                  int64 `json:"power_on_hours"`
-		UnsafeShutdowns               int64 `json:"unsafe_shutdowns"`
-		MediaErrors                   int64 `json:"media_errors"`
-		NumErrLogEntries              int64 `json:"num_err_log_entries"`
-		WarningTempTime               int   `json:"warning_temp_time"`
-		CriticalCompTime              int   `json:"critical_comp_time"`
-		TemperatureSensor1            int   `json:"temperature_sensor_1"`
-		TemperatureSensor2            int   `json:"temperature_sensor_2"`
-		ThermalManagementT1TransCount int   `json:"thermal_mgmt_t1_trans_count"`
-		ThermalManagementT2TransCount int   `json:"thermal_mgmt_t2_trans_count"`
-		ThermalManagementT1TotalTime  int   `json:"thermal_mgmt_t1_total_time"`
-		ThermalManagementT2TotalTime  int   `json:"thermal_mgmt_t2_total_time"`
+		CriticalWarning               int    `json:"critical_warning"`
+		Temperature                   int    `json:"temperature"`
+		AvailableSpare                int    `json:"available_spare"`
+		AvailableSpareThreshold       int    `json:"available_spare_threshold"`
+		PercentageUsed                int    `json:"percentage_used"`
+		DataUnitsRead                 int64  `json:"data_units_read"`
+		DataUnitsWritten              int64  `json:"data_units_written"`
+		HostReadCommands              int64  `json:"host_read_commands"`
+		HostWriteCommands             int64  `json:"host_write_commands"`
+		ControllerBusyTime            int64  `json:"controller_busy_time"`
+		PowerCycles                   int64  `json:"power_cycles"`
+		PowerOnHours                  int64  `json:"power_on_hours"`
+		UnsafeShutdowns               int64  `json:"unsafe_shutdowns"`
+		MediaErrors                   int64  `json:"media_errors"`
+		NumErrLogEntries              int64  `json:"num_err_log_entries"`
+		WarningTempTime               int    `json:"warning_temp_time"`
+		CriticalCompTime              int    `json:"critical_comp_time"`
+		TemperatureSensors            []*int `json:"temperature_sensors"` // null for sensors the drive does not implement
+		ThermalManagementT1TransCount int    `json:"thermal_mgmt_t1_trans_count"`
+		ThermalManagementT2TransCount int    `json:"thermal_mgmt_t2_trans_count"`
+		ThermalManagementT1TotalTime  int    `json:"thermal_mgmt_t1_total_time"`
+		ThermalManagementT2TotalTime  int    `json:"thermal_mgmt_t2_total_time"`
 	} `json:"nvme_smart_health_information_log"`
 }
 
@@ -198,6 +592,7 @@ type ToolInfo struct {
 	MegaCLI         bool // MegaCLI available
 	Mdadm           bool // mdadm available
 	Arcconf         bool // arcconf (Adaptec) available
+	Ssacli          bool // ssacli (HPE Smart Array) available
 	Storcli         bool // storcli (Broadcom) available
 	Zpool           bool // zpool (ZFS) available
 	Diskutil        bool // diskutil (macOS) available
@@ -209,33 +604,36 @@ type ToolInfo struct {
 	StorCLIVersion  string
 	HdparmVersion   string
 	ArcconfVersion  string
+	SsacliVersion   string
 	ZpoolVersion    string
+	Tools           map[string]string // Version of every available tool, keyed by tool name
 }
 
 // SoftwareRAIDInfo represents software RAID information
 type SoftwareRAIDInfo struct {
-	Device        string   // /dev/md0, /dev/md1, etc.
-	Level         string   // raid0, raid1, raid5, raid6, raid10
-	State         string   // clean, active, degraded, etc.
-	ArraySize     int64    // Array size in KB
-	UsedDevSize   int64    // Used device size in KB
-	RaidDevices   int      // Number of RAID devices
-	TotalDevices  int      // Total devices (including spares)
-	Persistence   string   // Superblock persistence
-	UpdateTime    string   // Last update time
-	ActiveDevices []string // List of active devices
-	SpareDevices  []string // List of spare devices
-	FailedDevices []string // List of failed devices
-	SyncAction    string   // Current sync action (resync, recover, etc.)
-	SyncProgress  float64  // Sync progress percentage
-	Bitmap        string   // Bitmap information
-	UUID          string   // Array UUID
+	Device        string    // /dev/md0, /dev/md1, etc.
+	Level         string    // raid0, raid1, raid5, raid6, raid10
+	State         string    // clean, active, degraded, etc.
+	Status        RAIDState // State translated by mdadm's state table
+	ArraySize     int64     // Array size in KB
+	UsedDevSize   int64     // Used device size in KB
+	RaidDevices   int       // Number of RAID devices
+	TotalDevices  int       // Total devices (including spares)
+	Persistence   string    // Superblock persistence
+	UpdateTime    string    // Last update time
+	ActiveDevices []string  // List of active devices
+	SpareDevices  []string  // List of spare devices
+	FailedDevices []string  // List of failed devices
+	SyncAction    string    // Current sync action (resync, recover, etc.)
+	SyncProgress  float64   // Sync progress percentage
+	Bitmap        string    // Bitmap information
+	UUID          string    // Array UUID
 }
 
 // RAIDBatteryInfo represents RAID controller battery information
 type RAIDBatteryInfo struct {
 	AdapterID            int    // Adapter ID
-	ToolName             string // Tool used for detection (MegaCLI, StoreCLI, Arcconf)
+	ToolName             string // Tool used for detection (MegaCLI, StoreCLI, Arcconf, SSACLI)
 	BatteryType          string // Battery type (e.g., CVPM02)
 	Voltage              int    // Voltage in mV
 	Current              int    // Current in mA
@@ -263,3 +661,31 @@ type RAIDBatteryInfo struct {
 	AutoLearnPeriod      int    // Auto learn period in days
 	NextLearnTime        string // Next learn time
 }
+
+// RAIDControllerInfo represents a hardware RAID controller
+type RAIDControllerInfo struct {
+	AdapterID                 int       // Controller number as addressed by the tool (e.g. 0 for /c0)
+	ToolName                  string    // Tool used for detection (MegaCLI, StoreCLI, Arcconf, SSACLI)
+	Model                     string    // Controller model (e.g., MegaRAID SAS 9361-8i)
+	SerialNumber              string    // Controller serial number
+	FirmwareVersion           string    // Firmware version
+	FirmwarePackage           string    // Firmware package build
+	DriverVersion             string    // Operating system driver version
+	BIOSVersion               string    // Option ROM/BIOS version
+	CacheSize                 int64     // Controller cache memory in bytes
+	CacheStatus               string    // Cache module status where reported separately (OK, Temporarily Disabled, ...)
+	Status                    string    // Controller status (Optimal, Needs Attention, ...)
+	ROCTemperature            int       // RAID-on-chip temperature in Celsius (0 if no sensor)
+	MemoryCorrectableErrors   int64     // Correctable controller memory (ECC) errors
+	MemoryUncorrectableErrors int64     // Uncorrectable controller memory (ECC) errors
+	NumVirtualDrives          int       // Configured virtual drives (logical devices)
+	NumPhysicalDrives         int       // Attached physical drives
+	PendingFlashImage         bool      // A flashed firmware image is waiting for a reboot to become active
+	ForeignConfigs            int       // Foreign configurations found on attached drives
+	AlarmState                string    // Audible alarm (Present, Absent, On, Off, ...)
+	PatrolReadMode            string    // Patrol read mode (Auto, Manual, Disabled, ...)
+	PatrolReadState           string    // Patrol read state (Active, Stopped, Paused, ...)
+	PatrolReadProgress        int       // Patrol read progress percentage while active (0-100)
+	PatrolReadIterations      int64     // Patrol reads completed since the controller counter was reset
+	PatrolReadNextRun         time.Time // Next scheduled patrol read (zero if not scheduled)
+}